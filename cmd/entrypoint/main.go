@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -29,12 +30,14 @@ import (
 
 	"github.com/containerd/containerd/platforms"
 	"github.com/tektoncd/pipeline/cmd/entrypoint/subcommands"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/credentials"
 	"github.com/tektoncd/pipeline/pkg/credentials/dockercreds"
 	"github.com/tektoncd/pipeline/pkg/credentials/gitcreds"
 	"github.com/tektoncd/pipeline/pkg/entrypoint"
 	"github.com/tektoncd/pipeline/pkg/termination"
+	"github.com/tektoncd/pipeline/pkg/tracing"
 )
 
 var (
@@ -45,15 +48,25 @@ var (
 	terminationPath     = flag.String("termination_path", "/tekton/termination", "If specified, file to write upon termination")
 	results             = flag.String("results", "", "If specified, list of file names that might contain task results")
 	timeout             = flag.Duration("timeout", time.Duration(0), "If specified, sets timeout for step")
+	gracePeriod         = flag.Duration("grace_period", time.Duration(0), "If specified, time given to the step to exit after SIGTERM when it times out, before it is killed")
 	breakpointOnFailure = flag.Bool("breakpoint_on_failure", false, "If specified, expect steps to not skip on failure")
 	onError             = flag.String("on_error", "", "Set to \"continue\" to ignore an error and continue when a container terminates with a non-zero exit code."+
 		" Set to \"stopAndFail\" to declare a failure with a step error and stop executing the rest of the steps.")
 	stepMetadataDir = flag.String("step_metadata_dir", "", "If specified, create directory to store the step metadata e.g. /tekton/steps/<step-name>/")
+	tracingExporter = flag.String("tracing_exporter", "", "If specified, exporter (otlp or stdout) used to export a span for the step")
+	tracingEndpoint = flag.String("tracing_endpoint", "", "If specified, URL of the OTLP collector the step span is exported to")
+	spanContext     = flag.String("span_context", "", "If specified, trace context of the TaskRun used as the parent of the step span")
+	stepName        = flag.String("step_name", "", "If specified, name of the step recorded in the step span")
+	cancelFile      = flag.String("cancel_file", "", "If specified, file which gets content when the TaskRun is cancelled, to terminate the step gracefully")
+	onCancel        = flag.String("on_cancel", "", "If specified, script to run once the step is cancelled")
+	stopFile        = flag.String("stop_file", "", "If specified, file which gets content when the sidecar must stop, to terminate the sidecar gracefully")
 )
 
 const (
 	defaultWaitPollingInterval = time.Second
 	breakpointExitSuffix       = ".breakpointexit"
+	// tracingShutdownTimeout bounds how long the exit of a step is delayed to export its span
+	tracingShutdownTimeout = 500 * time.Millisecond
 )
 
 func checkForBreakpointOnFailure(e entrypoint.Entrypointer, breakpointExitPostFile string) {
@@ -129,22 +142,34 @@ func main() {
 		PostFile:            *postFile,
 		TerminationPath:     *terminationPath,
 		Waiter:              &realWaiter{waitPollingInterval: defaultWaitPollingInterval, breakpointOnFailure: *breakpointOnFailure},
-		Runner:              &realRunner{},
+		Runner:              &realRunner{gracePeriod: *gracePeriod, reportExitStatus: *stopFile != ""},
 		PostWriter:          &realPostWriter{},
 		Results:             strings.Split(*results, ","),
 		Timeout:             timeout,
 		BreakpointOnFailure: *breakpointOnFailure,
 		OnError:             *onError,
 		StepMetadataDir:     *stepMetadataDir,
+		CancelFile:          *cancelFile,
+		OnCancel:            *onCancel,
+		StopFile:            *stopFile,
 	}
 
+	shutdownTracing := initTracing(&e)
+
 	// Copy any creds injected by the controller into the $HOME directory of the current
 	// user so that they're discoverable by git / ssh.
 	if err := credentials.CopyCredsToHome(credentials.CredsInitCredentials); err != nil {
 		log.Printf("non-fatal error copying credentials: %q", err)
 	}
 
-	if err := e.Go(); err != nil {
+	var err error
+	if e.StopFile != "" {
+		err = e.GoSidecar()
+	} else {
+		err = e.Go()
+	}
+	shutdownTracing()
+	if err != nil {
 		breakpointExitPostFile := e.PostFile + breakpointExitSuffix
 		switch t := err.(type) {
 		case skipError:
@@ -178,6 +203,41 @@ func main() {
 	}
 }
 
+// initTracing sets up the tracer of the entrypointer when tracing flags were
+// passed, returning a function flushing the recorded spans. Tracing errors are
+// not fatal to the step.
+func initTracing(e *entrypoint.Entrypointer) func() {
+	if *tracingExporter == "" {
+		return func() {}
+	}
+	carrier, err := tracing.ParseSpanContext(*spanContext)
+	if err != nil {
+		log.Printf("non-fatal error initializing tracing: %v", err)
+		return func() {}
+	}
+	// The stdout exporter prints to stderr, so that the step span is not mixed
+	// with the output of the step, which may be parsed by other tools.
+	tp, err := tracing.NewSDKTracerProvider(context.Background(), "Entrypoint", &config.Tracing{
+		Enabled:  true,
+		Exporter: *tracingExporter,
+		Endpoint: *tracingEndpoint,
+	}, os.Stderr)
+	if err != nil {
+		log.Printf("non-fatal error initializing tracing: %v", err)
+		return func() {}
+	}
+	e.Tracer = tp.Tracer("Entrypoint")
+	e.SpanContext = carrier
+	e.StepName = *stepName
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
+			log.Printf("non-fatal error exporting step span: %v", err)
+		}
+	}
+}
+
 func selectCommandForPlatform(cmds map[string][]string, plat string) ([]string, error) {
 	cmd, found := cmds[plat]
 	if found {
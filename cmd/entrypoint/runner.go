@@ -20,10 +20,13 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/tektoncd/pipeline/pkg/entrypoint"
 	"github.com/tektoncd/pipeline/pkg/pod"
@@ -35,6 +38,15 @@ import (
 // realRunner actually runs commands.
 type realRunner struct {
 	signals chan os.Signal
+	// gracePeriod is the time given to the command to exit after it is
+	// sent a SIGTERM when the context is done. If zero, the command is
+	// killed right away when it times out, and given the
+	// entrypoint.StepCancelGracePeriod when it is cancelled.
+	gracePeriod time.Duration
+	// reportExitStatus makes Run return the error of the command, instead
+	// of the error of the context, when the command exits after the context
+	// is done, so that a stopped sidecar reports how it exited.
+	reportExitStatus bool
 }
 
 var _ entrypoint.Runner = (*realRunner)(nil)
@@ -50,11 +62,16 @@ func (rr *realRunner) Run(ctx context.Context, args ...string) error {
 	if rr.signals == nil {
 		rr.signals = make(chan os.Signal, 1)
 	}
-	defer close(rr.signals)
+	defer func() {
+		close(rr.signals)
+		// Runs after the first one, e.g. of an onCancel script, need a new channel.
+		rr.signals = nil
+	}()
 	signal.Notify(rr.signals)
 	defer signal.Reset()
 
-	cmd := exec.CommandContext(ctx, name, args...)
+	// The command is terminated by terminateOnDone once the context is done.
+	cmd := exec.Command(name, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	// dedicated PID group used to forward signals to
@@ -67,8 +84,8 @@ func (rr *realRunner) Run(ctx context.Context, args ...string) error {
 
 	// Start defined command
 	if err := cmd.Start(); err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return context.DeadlineExceeded
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
 		return err
 	}
@@ -83,13 +100,62 @@ func (rr *realRunner) Run(ctx context.Context, args ...string) error {
 		}
 	}()
 
-	// Wait for command to exit
-	if err := cmd.Wait(); err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return context.DeadlineExceeded
+	exited := make(chan struct{})
+	defer close(exited)
+	go rr.terminateOnDone(ctx, cmd, exited)
+
+	// Wait for command to exit. A command which exits cleanly after it was
+	// terminated still timed out or was cancelled.
+	err := cmd.Wait()
+	if ctx.Err() != nil {
+		if !rr.reportExitStatus {
+			return ctx.Err()
 		}
-		return err
+		// A sidecar killed by the SIGTERM it was sent to stop stopped as
+		// requested, instead of failing with an exit status of -1, but a
+		// sidecar which had to be killed after its grace period failed.
+		switch {
+		case killedBySignal(err, syscall.SIGTERM):
+			return nil
+		case killedBySignal(err, syscall.SIGKILL):
+			return fmt.Errorf("%w: %v", entrypoint.ErrGracePeriodExceeded, err)
+		}
+	}
+	return err
+}
+
+// killedBySignal returns whether err reports a command killed by sig.
+func killedBySignal(err error, sig syscall.Signal) bool {
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		return false
 	}
+	status, ok := ee.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == sig
+}
 
-	return nil
+// terminateOnDone sends a SIGTERM to the process group of cmd once ctx is
+// done, then a SIGKILL if it has not exited within the grace period.
+func (rr *realRunner) terminateOnDone(ctx context.Context, cmd *exec.Cmd, exited <-chan struct{}) {
+	select {
+	case <-exited:
+		return
+	case <-ctx.Done():
+	}
+	gracePeriod := rr.gracePeriod
+	if gracePeriod == 0 && ctx.Err() == context.Canceled {
+		gracePeriod = entrypoint.StepCancelGracePeriod
+	}
+	if gracePeriod == 0 {
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		return
+	}
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+	select {
+	case <-exited:
+	case <-timer.C:
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
 }
@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
+	"os/exec"
 	"syscall"
 	"testing"
 	"time"
+
+	"github.com/tektoncd/pipeline/pkg/entrypoint"
 )
 
 // TestRealRunnerSignalForwarding will artificially put an interrupt signal (SIGINT) in the rr.signals chan.
@@ -37,3 +41,65 @@ func TestRealRunnerTimeout(t *testing.T) {
 		t.Fatalf("step didn't timeout")
 	}
 }
+
+// TestRealRunnerTimeoutGracePeriod tests whether cmd is sent a SIGTERM when it times out and given
+// the grace period to exit: the trap lets the shell exit cleanly before it would be killed.
+func TestRealRunnerTimeoutGracePeriod(t *testing.T) {
+	rr := realRunner{gracePeriod: 10 * time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := rr.Run(ctx, "sh", "-c", "trap 'exit 0' TERM; while true; do sleep 0.01; done"); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if d := time.Since(start); d >= 10*time.Second {
+		t.Fatalf("step was killed after the grace period (%v) instead of exiting on SIGTERM", d)
+	}
+}
+
+// TestRealRunnerTimeoutGracePeriodExceeded tests whether cmd is killed once the grace period is over
+// when it ignores the SIGTERM.
+func TestRealRunnerTimeoutGracePeriodExceeded(t *testing.T) {
+	rr := realRunner{gracePeriod: 100 * time.Millisecond}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := rr.Run(ctx, "sh", "-c", "trap '' TERM; while true; do sleep 0.01; done"); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// TestRealRunnerReportExitStatus tests whether a stopped sidecar reports the exit status of
+// its command instead of the error of the context.
+func TestRealRunnerReportExitStatus(t *testing.T) {
+	rr := realRunner{gracePeriod: 10 * time.Second, reportExitStatus: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	err := rr.Run(ctx, "sh", "-c", "trap 'exit 3' TERM; while true; do sleep 0.01; done")
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) || ee.ExitCode() != 3 {
+		t.Fatalf("expected the exit status 3 of the command, got %v", err)
+	}
+}
+
+// TestRealRunnerReportExitStatusSignalled tests whether a stopped sidecar killed by the
+// SIGTERM it was sent reports success.
+func TestRealRunnerReportExitStatusSignalled(t *testing.T) {
+	rr := realRunner{gracePeriod: 10 * time.Second, reportExitStatus: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	if err := rr.Run(ctx, "sleep", "3600"); err != nil {
+		t.Fatalf("expected a sidecar killed by SIGTERM to succeed, got %v", err)
+	}
+}
+
+// TestRealRunnerReportExitStatusGracePeriodExceeded tests whether a stopped sidecar which
+// ignores the SIGTERM and is killed after its grace period fails.
+func TestRealRunnerReportExitStatusGracePeriodExceeded(t *testing.T) {
+	rr := realRunner{gracePeriod: 100 * time.Millisecond, reportExitStatus: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	err := rr.Run(ctx, "sh", "-c", "trap '' TERM; while true; do sleep 0.01; done")
+	if !errors.Is(err, entrypoint.ErrGracePeriodExceeded) {
+		t.Fatalf("expected a sidecar killed after its grace period to fail with %v, got %v", entrypoint.ErrGracePeriodExceeded, err)
+	}
+}
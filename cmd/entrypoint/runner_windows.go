@@ -23,6 +23,7 @@ import (
 	"context"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/tektoncd/pipeline/pkg/entrypoint"
 )
@@ -32,6 +33,13 @@ import (
 
 // realRunner actually runs commands.
 type realRunner struct {
+	// gracePeriod is not supported on Windows, a timed out or cancelled
+	// step is always killed.
+	gracePeriod time.Duration
+	// reportExitStatus makes Run return the error of the command, instead
+	// of the error of the context, when the command exits after the context
+	// is done, so that a stopped sidecar reports how it exited.
+	reportExitStatus bool
 }
 
 var _ entrypoint.Runner = (*realRunner)(nil)
@@ -47,7 +55,7 @@ func (rr *realRunner) Run(ctx context.Context, args ...string) error {
 	cmd.Stderr = os.Stderr
 
 	// Run the defined command
-	if err := cmd.Run(); err != nil {
+	if err := cmd.Run(); (err != nil && ctx.Err() == nil) || rr.reportExitStatus {
 		return err
 	}
 	return ctx.Err()
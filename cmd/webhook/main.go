@@ -91,7 +91,10 @@ func newValidationAdmissionController(ctx context.Context, cmw configmap.Watcher
 	// Decorate contexts with the current state of the config.
 	store := defaultconfig.NewStore(logging.FromContext(ctx).Named("config-store"))
 	store.WatchConfigs(cmw)
-	return validation.NewAdmissionController(ctx,
+	toContext := func(ctx context.Context) context.Context {
+		return store.ToContext(ctx)
+	}
+	impl := validation.NewAdmissionController(ctx,
 
 		// Name of the resource webhook.
 		"validation.webhook.pipeline.tekton.dev",
@@ -103,13 +106,17 @@ func newValidationAdmissionController(ctx context.Context, cmw configmap.Watcher
 		types,
 
 		// A function that infuses the context passed to Validate/SetDefaults with custom metadata.
-		func(ctx context.Context) context.Context {
-			return store.ToContext(ctx)
-		},
+		toContext,
 
 		// Whether to disallow unknown fields.
 		true,
 	)
+	// Return the warnings of the admitted resources along with their errors.
+	impl.Reconciler = &warningAdmissionController{
+		admissionReconciler: impl.Reconciler.(admissionReconciler),
+		toContext:           toContext,
+	}
+	return impl
 }
 
 func newConfigValidationController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/webhook"
+)
+
+// admissionReconciler is the reconciler of the validation admission controller.
+type admissionReconciler interface {
+	controller.Reconciler
+	reconciler.LeaderAware
+	webhook.AdmissionController
+}
+
+// warningAdmissionController wraps the validation admission controller to
+// return the warnings of the admitted resources to the client, since the
+// validation admission controller only returns their errors.
+type warningAdmissionController struct {
+	admissionReconciler
+	toContext func(context.Context) context.Context
+}
+
+var _ webhook.StatelessAdmissionController = (*warningAdmissionController)(nil)
+
+// ThisTypeDoesNotDependOnInformerState implements webhook.StatelessAdmissionController
+func (*warningAdmissionController) ThisTypeDoesNotDependOnInformerState() {}
+
+// Admit implements webhook.AdmissionController
+func (ac *warningAdmissionController) Admit(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	resp := ac.admissionReconciler.Admit(ctx, req)
+	if !resp.Allowed || (req.Operation != admissionv1.Create && req.Operation != admissionv1.Update) {
+		return resp
+	}
+	warnings, err := paramsInScriptsWarnings(ac.toContext(ctx), req)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Failed to compute the warnings of the admitted resource: %v", err)
+		return resp
+	}
+	if warnings != nil {
+		resp.Warnings = append(resp.Warnings, strings.Split(warnings.Error(), "\n")...)
+	}
+	return resp
+}
+
+// paramsInScriptsWarnings returns the warnings of the TaskSpecs of the
+// v1beta1 resource in the request about params substituted inline in scripts.
+func paramsInScriptsWarnings(ctx context.Context, req *admissionv1.AdmissionRequest) (*apis.FieldError, error) {
+	if req.Kind.Group != v1beta1.SchemeGroupVersion.Group || req.Kind.Version != v1beta1.SchemeGroupVersion.Version {
+		return nil, nil
+	}
+	switch req.Kind.Kind {
+	case "Task", "ClusterTask":
+		var t v1beta1.Task
+		if err := json.Unmarshal(req.Object.Raw, &t); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", req.Kind.Kind, err)
+		}
+		return t.Spec.ParamsInScriptsWarnings(ctx).ViaField("spec"), nil
+	case "TaskRun":
+		var tr v1beta1.TaskRun
+		if err := json.Unmarshal(req.Object.Raw, &tr); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", req.Kind.Kind, err)
+		}
+		if tr.Spec.TaskSpec == nil {
+			return nil, nil
+		}
+		return tr.Spec.TaskSpec.ParamsInScriptsWarnings(ctx).ViaField("spec", "taskSpec"), nil
+	case "Pipeline":
+		var p v1beta1.Pipeline
+		if err := json.Unmarshal(req.Object.Raw, &p); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", req.Kind.Kind, err)
+		}
+		return pipelineParamsInScriptsWarnings(ctx, &p.Spec).ViaField("spec"), nil
+	case "PipelineRun":
+		var pr v1beta1.PipelineRun
+		if err := json.Unmarshal(req.Object.Raw, &pr); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", req.Kind.Kind, err)
+		}
+		if pr.Spec.PipelineSpec == nil {
+			return nil, nil
+		}
+		return pipelineParamsInScriptsWarnings(ctx, pr.Spec.PipelineSpec).ViaField("spec", "pipelineSpec"), nil
+	}
+	return nil, nil
+}
+
+func pipelineParamsInScriptsWarnings(ctx context.Context, ps *v1beta1.PipelineSpec) (warnings *apis.FieldError) {
+	for idx, pt := range ps.Tasks {
+		if pt.TaskSpec != nil {
+			warnings = warnings.Also(pt.TaskSpec.TaskSpec.ParamsInScriptsWarnings(ctx).ViaField("taskSpec").ViaFieldIndex("tasks", idx))
+		}
+	}
+	for idx, pt := range ps.Finally {
+		if pt.TaskSpec != nil {
+			warnings = warnings.Also(pt.TaskSpec.TaskSpec.ParamsInScriptsWarnings(ctx).ViaField("taskSpec").ViaFieldIndex("finally", idx))
+		}
+	}
+	return warnings
+}
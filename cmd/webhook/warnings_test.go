@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/test/diff"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// fakeAdmissionReconciler admits the requests with the response it is given.
+type fakeAdmissionReconciler struct {
+	admissionReconciler
+	resp admissionv1.AdmissionResponse
+}
+
+func (f *fakeAdmissionReconciler) Admit(context.Context, *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	resp := f.resp
+	return &resp
+}
+
+func TestWarningAdmissionControllerAdmit(t *testing.T) {
+	taskSpec := v1beta1.TaskSpec{
+		Steps: []v1beta1.Step{{
+			Image:  "my-image",
+			Script: "curl $(params.url)",
+		}},
+	}
+	pipelineSpec := v1beta1.PipelineSpec{
+		Tasks: []v1beta1.PipelineTask{{
+			Name:     "inline",
+			TaskSpec: &v1beta1.EmbeddedTask{TaskSpec: taskSpec},
+		}, {
+			Name:    "referenced",
+			TaskRef: &v1beta1.TaskRef{Name: "my-task"},
+		}},
+		Finally: []v1beta1.PipelineTask{{
+			Name:     "final",
+			TaskSpec: &v1beta1.EmbeddedTask{TaskSpec: taskSpec},
+		}},
+	}
+	for _, tc := range []struct {
+		desc         string
+		featureFlags map[string]string
+		kind         string
+		operation    admissionv1.Operation
+		object       runtime.Object
+		resp         admissionv1.AdmissionResponse
+		want         []string
+	}{{
+		desc:         "task",
+		featureFlags: map[string]string{"enable-params-as-env-in-scripts": "true"},
+		kind:         "Task",
+		operation:    admissionv1.Create,
+		object:       &v1beta1.Task{Spec: taskSpec},
+		resp:         admissionv1.AdmissionResponse{Allowed: true},
+		want:         []string{"script substitutes params inline, read them from the PARAMS_<NAME> env vars instead: spec.steps[0].script"},
+	}, {
+		desc:         "taskrun",
+		featureFlags: map[string]string{"enable-params-as-env-in-scripts": "true"},
+		kind:         "TaskRun",
+		operation:    admissionv1.Update,
+		object:       &v1beta1.TaskRun{Spec: v1beta1.TaskRunSpec{TaskSpec: &taskSpec}},
+		resp:         admissionv1.AdmissionResponse{Allowed: true},
+		want:         []string{"script substitutes params inline, read them from the PARAMS_<NAME> env vars instead: spec.taskSpec.steps[0].script"},
+	}, {
+		desc:         "pipelinerun",
+		featureFlags: map[string]string{"enable-params-as-env-in-scripts": "true"},
+		kind:         "PipelineRun",
+		operation:    admissionv1.Create,
+		object:       &v1beta1.PipelineRun{Spec: v1beta1.PipelineRunSpec{PipelineSpec: &pipelineSpec}},
+		resp:         admissionv1.AdmissionResponse{Allowed: true, Warnings: []string{"existing warning"}},
+		want: []string{
+			"existing warning",
+			"script substitutes params inline, read them from the PARAMS_<NAME> env vars instead: spec.pipelineSpec.finally[0].taskSpec.steps[0].script, spec.pipelineSpec.tasks[0].taskSpec.steps[0].script",
+		},
+	}, {
+		desc:      "params as env in scripts disabled",
+		kind:      "Task",
+		operation: admissionv1.Create,
+		object:    &v1beta1.Task{Spec: taskSpec},
+		resp:      admissionv1.AdmissionResponse{Allowed: true},
+	}, {
+		desc:         "denied",
+		featureFlags: map[string]string{"enable-params-as-env-in-scripts": "true"},
+		kind:         "Task",
+		operation:    admissionv1.Create,
+		object:       &v1beta1.Task{Spec: taskSpec},
+		resp:         admissionv1.AdmissionResponse{Allowed: false},
+	}, {
+		desc:         "delete",
+		featureFlags: map[string]string{"enable-params-as-env-in-scripts": "true"},
+		kind:         "Task",
+		operation:    admissionv1.Delete,
+		object:       &v1beta1.Task{Spec: taskSpec},
+		resp:         admissionv1.AdmissionResponse{Allowed: true},
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			featureFlags, err := config.NewFeatureFlagsFromMap(tc.featureFlags)
+			if err != nil {
+				t.Fatalf("NewFeatureFlagsFromMap: %v", err)
+			}
+			raw, err := json.Marshal(tc.object)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			ac := &warningAdmissionController{
+				admissionReconciler: &fakeAdmissionReconciler{resp: tc.resp},
+				toContext: func(ctx context.Context) context.Context {
+					return config.ToContext(ctx, &config.Config{FeatureFlags: featureFlags})
+				},
+			}
+			resp := ac.Admit(context.Background(), &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   v1beta1.SchemeGroupVersion.Group,
+					Version: v1beta1.SchemeGroupVersion.Version,
+					Kind:    tc.kind,
+				},
+				Operation: tc.operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			if d := cmp.Diff(tc.want, resp.Warnings); d != "" {
+				t.Errorf("warnings %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
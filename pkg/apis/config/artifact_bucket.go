@@ -18,6 +18,7 @@ package config
 
 import (
 	"os"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 )
@@ -45,6 +46,24 @@ const (
 	// the field name that should be used for the service account.
 	// Valid values: GOOGLE_APPLICATION_CREDENTIALS, BOTO_CONFIG.
 	BucketServiceAccountFieldNameKey = "bucket.service.account.field.name"
+
+	// BucketS3EndpointKey is the name of the configmap entry that specifies the
+	// endpoint of an S3-compatible service, such as a MinIO server.
+	BucketS3EndpointKey = "bucket.s3.endpoint"
+
+	// BucketS3RegionKey is the name of the configmap entry that specifies the
+	// region of the S3 bucket.
+	BucketS3RegionKey = "bucket.s3.region"
+
+	// BucketS3CredentialsSecretNameKey is the name of the configmap entry that specifies
+	// the name of the secret holding the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	// used to access the S3 bucket.
+	BucketS3CredentialsSecretNameKey = "bucket.s3.credentials.secret.name"
+
+	// BucketS3ImageKey is the name of the configmap entry that overrides the
+	// container image providing the aws CLI used to copy artifacts to and from
+	// the S3 bucket, which defaults to the s3-image flag of the controller.
+	BucketS3ImageKey = "bucket.s3.image"
 )
 
 // ArtifactBucket holds the configurations for the artifacts PVC
@@ -54,6 +73,10 @@ type ArtifactBucket struct {
 	ServiceAccountSecretName string
 	ServiceAccountSecretKey  string
 	ServiceAccountFieldName  string
+	S3Endpoint               string
+	S3Region                 string
+	S3CredentialsSecretName  string
+	S3Image                  string
 }
 
 // GetArtifactBucketConfigName returns the name of the configmap containing all
@@ -78,7 +101,21 @@ func (cfg *ArtifactBucket) Equals(other *ArtifactBucket) bool {
 	return other.Location == cfg.Location &&
 		other.ServiceAccountSecretName == cfg.ServiceAccountSecretName &&
 		other.ServiceAccountSecretKey == cfg.ServiceAccountSecretKey &&
-		other.ServiceAccountFieldName == cfg.ServiceAccountFieldName
+		other.ServiceAccountFieldName == cfg.ServiceAccountFieldName &&
+		other.S3Endpoint == cfg.S3Endpoint &&
+		other.S3Region == cfg.S3Region &&
+		other.S3CredentialsSecretName == cfg.S3CredentialsSecretName &&
+		other.S3Image == cfg.S3Image
+}
+
+// UsesS3 returns true if the bucket is an S3 bucket configured through the
+// bucket.s3.* entries, in which case artifacts are copied with the aws CLI
+// instead of gsutil.
+func (cfg *ArtifactBucket) UsesS3() bool {
+	if cfg == nil || !strings.HasPrefix(strings.TrimSpace(cfg.Location), "s3://") {
+		return false
+	}
+	return cfg.S3Endpoint != "" || cfg.S3Region != "" || cfg.S3CredentialsSecretName != ""
 }
 
 // NewArtifactBucketFromMap returns a Config given a map corresponding to a ConfigMap
@@ -103,6 +140,22 @@ func NewArtifactBucketFromMap(cfgMap map[string]string) (*ArtifactBucket, error)
 		tc.ServiceAccountFieldName = serviceAccountFieldName
 	}
 
+	if s3Endpoint, ok := cfgMap[BucketS3EndpointKey]; ok {
+		tc.S3Endpoint = s3Endpoint
+	}
+
+	if s3Region, ok := cfgMap[BucketS3RegionKey]; ok {
+		tc.S3Region = s3Region
+	}
+
+	if s3CredentialsSecretName, ok := cfgMap[BucketS3CredentialsSecretNameKey]; ok {
+		tc.S3CredentialsSecretName = s3CredentialsSecretName
+	}
+
+	if s3Image, ok := cfgMap[BucketS3ImageKey]; ok {
+		tc.S3Image = s3Image
+	}
+
 	return &tc, nil
 }
 
@@ -49,6 +49,17 @@ func TestNewArtifactBucketFromConfigMap(t *testing.T) {
 			},
 			fileName: "config-artifact-bucket-all-set",
 		},
+		{
+			expectedConfig: &config.ArtifactBucket{
+				Location:                "s3://test-bucket",
+				ServiceAccountFieldName: "GOOGLE_APPLICATION_CREDENTIALS",
+				S3Endpoint:              "http://minio.minio.svc:9000",
+				S3Region:                "eu-west-1",
+				S3CredentialsSecretName: "minio-credentials",
+				S3Image:                 "example.com/aws-cli:latest",
+			},
+			fileName: "config-artifact-bucket-s3",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -95,6 +106,47 @@ func TestGetArtifactBucketConfigName(t *testing.T) {
 	}
 }
 
+func TestArtifactBucketUsesS3(t *testing.T) {
+	for _, tc := range []struct {
+		description string
+		bucket      *config.ArtifactBucket
+		expected    bool
+	}{{
+		description: "nil config",
+		expected:    false,
+	}, {
+		description: "gcs bucket",
+		bucket:      &config.ArtifactBucket{Location: "gs://my-bucket"},
+		expected:    false,
+	}, {
+		description: "s3 bucket accessed through gsutil",
+		bucket:      &config.ArtifactBucket{Location: "s3://my-bucket", ServiceAccountFieldName: "BOTO_CONFIG"},
+		expected:    false,
+	}, {
+		description: "s3 bucket with endpoint",
+		bucket:      &config.ArtifactBucket{Location: "s3://my-bucket", S3Endpoint: "http://localhost:9000"},
+		expected:    true,
+	}, {
+		description: "s3 bucket with region",
+		bucket:      &config.ArtifactBucket{Location: "s3://my-bucket", S3Region: "us-west-2"},
+		expected:    true,
+	}, {
+		description: "s3 bucket with credentials",
+		bucket:      &config.ArtifactBucket{Location: "s3://my-bucket", S3CredentialsSecretName: "creds"},
+		expected:    true,
+	}, {
+		description: "s3 settings on a gcs bucket",
+		bucket:      &config.ArtifactBucket{Location: "gs://my-bucket", S3Region: "us-west-2"},
+		expected:    false,
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := tc.bucket.UsesS3(); got != tc.expected {
+				t.Errorf("UsesS3() = %t, want %t", got, tc.expected)
+			}
+		})
+	}
+}
+
 func verifyConfigFileWithExpectedArtifactBucketConfig(t *testing.T, fileName string, expectedConfig *config.ArtifactBucket) {
 	cm := test.ConfigMapFromTestFile(t, fileName)
 	if ab, err := config.NewArtifactBucketFromConfigMap(cm); err == nil {
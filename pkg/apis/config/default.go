@@ -133,6 +133,9 @@ func NewDefaultsFromMap(cfgMap map[string]string) (*Defaults, error) {
 		if err := yamlUnmarshal(defaultAAPodTemplate, defaultAAPodTemplateKey, &podTemplate); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal %v", defaultAAPodTemplate)
 		}
+		if podTemplate.Mode != "" && !podTemplate.Mode.IsValid() {
+			return nil, fmt.Errorf("invalid affinity assistant mode %q in %s", podTemplate.Mode, defaultAAPodTemplateKey)
+		}
 		tc.DefaultAAPodTemplate = &podTemplate
 	}
 
@@ -91,6 +91,22 @@ func TestNewDefaultsFromConfigMap(t *testing.T) {
 				DefaultMaxMatrixCombinationsCount: 256,
 			},
 		},
+		{
+			expectedConfig: &config.Defaults{
+				DefaultTimeoutMinutes:      50,
+				DefaultServiceAccount:      "tekton",
+				DefaultManagedByLabelValue: config.DefaultManagedByLabelValue,
+				DefaultAAPodTemplate: &pod.AffinityAssistantTemplate{
+					Mode: pod.AffinityAssistantIsolatePipelineRun,
+				},
+				DefaultMaxMatrixCombinationsCount: 256,
+			},
+			fileName: "config-defaults-aa-mode",
+		},
+		{
+			expectedError: true,
+			fileName:      "config-defaults-aa-mode-err",
+		},
 		{
 			expectedError: true,
 			fileName:      "config-defaults-matrix-err",
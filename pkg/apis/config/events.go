@@ -0,0 +1,330 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// EventsSinkKey is the name of the configmap entry that specifies the
+	// sink CloudEvents are sent to. It replaces the default-cloud-events-sink
+	// of the config-defaults ConfigMap.
+	EventsSinkKey = "sink"
+
+	// EventsNamespaceSinkKeyPrefix is the prefix of the configmap entries that
+	// specify the sink CloudEvents about the runs of a namespace are sent to,
+	// e.g. "sink.namespace.team-a".
+	EventsNamespaceSinkKeyPrefix = "sink.namespace."
+
+	// EventsAllowSinkAnnotationKey is the name of the configmap entry that
+	// specifies whether runs can select their own sink with an annotation.
+	EventsAllowSinkAnnotationKey = "allow-sink-annotation"
+
+	// EventsFilterTypesKey is the name of the configmap entry that specifies
+	// a comma separated list of the types of the CloudEvents to send.
+	EventsFilterTypesKey = "filter.types"
+
+	// EventsFilterLabelsKey is the name of the configmap entry that specifies
+	// a label selector the runs must match for their CloudEvents to be sent.
+	EventsFilterLabelsKey = "filter.labels"
+
+	// EventsPayloadKey is the name of the configmap entry that specifies the
+	// payload of the CloudEvents.
+	EventsPayloadKey = "payload"
+
+	// EventsFormatKey is the name of the configmap entry that specifies the
+	// format of the CloudEvents.
+	EventsFormatKey = "format"
+
+	// EventsDeliveryKey is the name of the configmap entry that specifies
+	// how CloudEvents are delivered.
+	EventsDeliveryKey = "delivery"
+
+	// EventsKubernetesEventsKey is the name of the configmap entry that
+	// specifies which Kubernetes events are recorded for runs.
+	EventsKubernetesEventsKey = "kubernetes-events"
+
+	// EventsRetriesKey is the name of the configmap entry that specifies how
+	// many times the delivery of a CloudEvent is retried.
+	EventsRetriesKey = "retries"
+
+	// EventsPayloadFull embeds the whole run in the CloudEvents.
+	EventsPayloadFull = "full"
+
+	// EventsPayloadSummary embeds a compact summary of the run in the CloudEvents.
+	EventsPayloadSummary = "summary"
+
+	// EventsFormatTekton sends the Tekton CloudEvents, e.g. dev.tekton.event.taskrun.started.v1.
+	EventsFormatTekton = "tekton"
+
+	// EventsFormatCDEvents sends CDEvents, e.g. dev.cdevents.taskrun.started.0.1.0.
+	EventsFormatCDEvents = "cdevents"
+
+	// DefaultEventsFormat is the format used when none is specified.
+	DefaultEventsFormat = EventsFormatTekton
+
+	// EventsDeliveryBestEffort sends CloudEvents from memory: they are lost if
+	// they could not be delivered after all retries or the controller restarts.
+	EventsDeliveryBestEffort = "best-effort"
+
+	// EventsDeliveryDurable records CloudEvents in an outbox until they are
+	// delivered, so that they survive delivery failures and controller restarts.
+	EventsDeliveryDurable = "durable"
+
+	// DefaultEventsDelivery is the delivery used when none is specified.
+	DefaultEventsDelivery = EventsDeliveryBestEffort
+
+	// EventsKubernetesEventsMinimal records Kubernetes events when the
+	// Succeeded condition of a run changes.
+	EventsKubernetesEventsMinimal = "minimal"
+
+	// EventsKubernetesEventsDetailed also records Kubernetes events when a
+	// PipelineTask is skipped, retried or timed out, when a step fails and
+	// when the sidecars of a TaskRun fail to stop.
+	EventsKubernetesEventsDetailed = "detailed"
+
+	// DefaultEventsKubernetesEvents is the Kubernetes events used when none is specified.
+	DefaultEventsKubernetesEvents = EventsKubernetesEventsMinimal
+
+	// DefaultEventsPayload is the payload used when none is specified.
+	DefaultEventsPayload = EventsPayloadFull
+
+	// DefaultEventsRetries is the number of retries used when none is specified.
+	DefaultEventsRetries = 10
+)
+
+// Events holds the configurations for the CloudEvents sent and the
+// Kubernetes events recorded for runs
+// +k8s:deepcopy-gen=true
+type Events struct {
+	// Sink is the default sink of the CloudEvents.
+	Sink string
+	// NamespaceSinks are the sinks of the CloudEvents about the runs of a
+	// namespace, by namespace.
+	NamespaceSinks map[string]string
+	// AllowSinkAnnotation allows runs to select their own sink with the
+	// tekton.dev/cloudevents-sink annotation.
+	AllowSinkAnnotation bool
+	// Types are the types of the CloudEvents to send. All of them are sent
+	// when it is empty.
+	Types []string
+	// LabelSelector selects the runs whose CloudEvents are sent.
+	LabelSelector string
+	// Payload is either EventsPayloadFull or EventsPayloadSummary.
+	Payload string
+	// Format is either EventsFormatTekton or EventsFormatCDEvents.
+	Format string
+	// Delivery is either EventsDeliveryBestEffort or EventsDeliveryDurable.
+	Delivery string
+	// KubernetesEvents is either EventsKubernetesEventsMinimal or
+	// EventsKubernetesEventsDetailed.
+	KubernetesEvents string
+	// Retries is how many times the delivery of a CloudEvent is retried.
+	Retries int
+}
+
+// GetEventsConfigName returns the name of the configmap containing all
+// customizations for CloudEvents.
+func GetEventsConfigName() string {
+	if e := os.Getenv("CONFIG_EVENTS_NAME"); e != "" {
+		return e
+	}
+	return "config-events"
+}
+
+// SinkFor returns the sink of the CloudEvents about a run in the given
+// namespace with the given sink annotation, or the empty string if they
+// should not be sent.
+func (cfg *Events) SinkFor(namespace, annotation string) string {
+	if cfg == nil {
+		return ""
+	}
+	if cfg.AllowSinkAnnotation && annotation != "" {
+		return annotation
+	}
+	if sink, ok := cfg.NamespaceSinks[namespace]; ok {
+		return sink
+	}
+	return cfg.Sink
+}
+
+// Allows returns true if CloudEvents of the given type about runs with the
+// given labels should be sent.
+func (cfg *Events) Allows(eventType string, runLabels map[string]string) bool {
+	if cfg == nil {
+		return true
+	}
+	if len(cfg.Types) > 0 {
+		found := false
+		for _, t := range cfg.Types {
+			if t == eventType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if cfg.LabelSelector != "" {
+		// The selector is validated when the ConfigMap is loaded.
+		selector, err := labels.Parse(cfg.LabelSelector)
+		if err != nil || !selector.Matches(labels.Set(runLabels)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equals returns true if two Configs are identical
+func (cfg *Events) Equals(other *Events) bool {
+	if cfg == nil && other == nil {
+		return true
+	}
+
+	if cfg == nil || other == nil {
+		return false
+	}
+
+	if len(cfg.NamespaceSinks) != len(other.NamespaceSinks) || len(cfg.Types) != len(other.Types) {
+		return false
+	}
+	for namespace, sink := range cfg.NamespaceSinks {
+		if other.NamespaceSinks[namespace] != sink {
+			return false
+		}
+	}
+	for i, t := range cfg.Types {
+		if other.Types[i] != t {
+			return false
+		}
+	}
+
+	return other.Sink == cfg.Sink &&
+		other.AllowSinkAnnotation == cfg.AllowSinkAnnotation &&
+		other.LabelSelector == cfg.LabelSelector &&
+		other.Payload == cfg.Payload &&
+		other.Format == cfg.Format &&
+		other.Delivery == cfg.Delivery &&
+		other.KubernetesEvents == cfg.KubernetesEvents &&
+		other.Retries == cfg.Retries
+}
+
+// NewEventsFromMap returns a Config given a map corresponding to a ConfigMap
+func NewEventsFromMap(cfgMap map[string]string) (*Events, error) {
+	tc := Events{
+		Payload:          DefaultEventsPayload,
+		Format:           DefaultEventsFormat,
+		Delivery:         DefaultEventsDelivery,
+		KubernetesEvents: DefaultEventsKubernetesEvents,
+		Retries:          DefaultEventsRetries,
+	}
+
+	if sink, ok := cfgMap[EventsSinkKey]; ok {
+		tc.Sink = sink
+	}
+
+	for key, sink := range cfgMap {
+		if namespace := strings.TrimPrefix(key, EventsNamespaceSinkKeyPrefix); namespace != key && namespace != "" {
+			if tc.NamespaceSinks == nil {
+				tc.NamespaceSinks = map[string]string{}
+			}
+			tc.NamespaceSinks[namespace] = sink
+		}
+	}
+
+	if allow, ok := cfgMap[EventsAllowSinkAnnotationKey]; ok {
+		b, err := strconv.ParseBool(allow)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing events config %q: %w", EventsAllowSinkAnnotationKey, err)
+		}
+		tc.AllowSinkAnnotation = b
+	}
+
+	if types, ok := cfgMap[EventsFilterTypesKey]; ok {
+		for _, t := range strings.Split(types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				tc.Types = append(tc.Types, t)
+			}
+		}
+	}
+
+	if selector, ok := cfgMap[EventsFilterLabelsKey]; ok {
+		if _, err := labels.Parse(selector); err != nil {
+			return nil, fmt.Errorf("failed parsing events config %q: %w", EventsFilterLabelsKey, err)
+		}
+		tc.LabelSelector = selector
+	}
+
+	if payload, ok := cfgMap[EventsPayloadKey]; ok {
+		switch payload {
+		case EventsPayloadFull, EventsPayloadSummary:
+			tc.Payload = payload
+		default:
+			return nil, fmt.Errorf("failed parsing events config %q: unknown payload %q", EventsPayloadKey, payload)
+		}
+	}
+
+	if format, ok := cfgMap[EventsFormatKey]; ok {
+		switch format {
+		case EventsFormatTekton, EventsFormatCDEvents:
+			tc.Format = format
+		default:
+			return nil, fmt.Errorf("failed parsing events config %q: unknown format %q", EventsFormatKey, format)
+		}
+	}
+
+	if delivery, ok := cfgMap[EventsDeliveryKey]; ok {
+		switch delivery {
+		case EventsDeliveryBestEffort, EventsDeliveryDurable:
+			tc.Delivery = delivery
+		default:
+			return nil, fmt.Errorf("failed parsing events config %q: unknown delivery %q", EventsDeliveryKey, delivery)
+		}
+	}
+
+	if kubernetesEvents, ok := cfgMap[EventsKubernetesEventsKey]; ok {
+		switch kubernetesEvents {
+		case EventsKubernetesEventsMinimal, EventsKubernetesEventsDetailed:
+			tc.KubernetesEvents = kubernetesEvents
+		default:
+			return nil, fmt.Errorf("failed parsing events config %q: unknown kubernetes events %q", EventsKubernetesEventsKey, kubernetesEvents)
+		}
+	}
+
+	if retries, ok := cfgMap[EventsRetriesKey]; ok {
+		n, err := strconv.Atoi(retries)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("failed parsing events config %q: expected a non-negative integer, got %q", EventsRetriesKey, retries)
+		}
+		tc.Retries = n
+	}
+
+	return &tc, nil
+}
+
+// NewEventsFromConfigMap returns a Config for the given configmap
+func NewEventsFromConfigMap(config *corev1.ConfigMap) (*Events, error) {
+	return NewEventsFromMap(config.Data)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	test "github.com/tektoncd/pipeline/pkg/reconciler/testing"
+	"github.com/tektoncd/pipeline/test/diff"
+)
+
+func TestNewEventsFromConfigMap(t *testing.T) {
+	for _, tc := range []struct {
+		fileName       string
+		expectedConfig *config.Events
+	}{{
+		fileName: config.GetEventsConfigName(),
+		expectedConfig: &config.Events{
+			Sink:                "http://events.example.com",
+			NamespaceSinks:      map[string]string{"team-a": "http://team-a.example.com"},
+			AllowSinkAnnotation: true,
+			Types:               []string{"dev.tekton.event.taskrun.failed.v1", "dev.tekton.event.pipelinerun.failed.v1"},
+			LabelSelector:       "app=foo",
+			Payload:             config.EventsPayloadSummary,
+			Format:              config.EventsFormatCDEvents,
+			Delivery:            config.EventsDeliveryDurable,
+			KubernetesEvents:    config.EventsKubernetesEventsDetailed,
+			Retries:             3,
+		},
+	}, {
+		fileName: "config-events-empty",
+		expectedConfig: &config.Events{
+			Payload:          config.DefaultEventsPayload,
+			Format:           config.DefaultEventsFormat,
+			Delivery:         config.DefaultEventsDelivery,
+			KubernetesEvents: config.DefaultEventsKubernetesEvents,
+			Retries:          config.DefaultEventsRetries,
+		},
+	}} {
+		t.Run(tc.fileName, func(t *testing.T) {
+			cm := test.ConfigMapFromTestFile(t, tc.fileName)
+			got, err := config.NewEventsFromConfigMap(cm)
+			if err != nil {
+				t.Fatalf("NewEventsFromConfigMap(actual) = %v", err)
+			}
+			if d := cmp.Diff(tc.expectedConfig, got); d != "" {
+				t.Errorf("Diff:\n%s", diff.PrintWantGot(d))
+			}
+			if !got.Equals(got.DeepCopy()) {
+				t.Error("expected a deep copy of the config to be equal to it")
+			}
+		})
+	}
+}
+
+func TestNewEventsFromInvalidConfigMap(t *testing.T) {
+	for _, fileName := range []string{
+		"config-events-invalid-allow-sink-annotation",
+		"config-events-invalid-delivery",
+		"config-events-invalid-format",
+		"config-events-invalid-kubernetes-events",
+		"config-events-invalid-labels",
+		"config-events-invalid-payload",
+		"config-events-invalid-retries",
+	} {
+		t.Run(fileName, func(t *testing.T) {
+			cm := test.ConfigMapFromTestFile(t, fileName)
+			if _, err := config.NewEventsFromConfigMap(cm); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestEventsSinkFor(t *testing.T) {
+	cfg := &config.Events{
+		Sink:           "http://default",
+		NamespaceSinks: map[string]string{"team-a": "http://team-a"},
+	}
+	for _, tc := range []struct {
+		description string
+		allow       bool
+		namespace   string
+		annotation  string
+		want        string
+	}{{
+		description: "default sink",
+		namespace:   "other",
+		want:        "http://default",
+	}, {
+		description: "namespace sink",
+		namespace:   "team-a",
+		want:        "http://team-a",
+	}, {
+		description: "annotation ignored when not allowed",
+		namespace:   "team-a",
+		annotation:  "http://annotation",
+		want:        "http://team-a",
+	}, {
+		description: "annotation when allowed",
+		allow:       true,
+		namespace:   "team-a",
+		annotation:  "http://annotation",
+		want:        "http://annotation",
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cfg.DeepCopy()
+			c.AllowSinkAnnotation = tc.allow
+			if got := c.SinkFor(tc.namespace, tc.annotation); got != tc.want {
+				t.Errorf("SinkFor() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEventsAllows(t *testing.T) {
+	cfg := &config.Events{
+		Types:         []string{"dev.tekton.event.taskrun.failed.v1"},
+		LabelSelector: "app=foo",
+	}
+	for _, tc := range []struct {
+		description string
+		eventType   string
+		labels      map[string]string
+		want        bool
+	}{{
+		description: "matching type and labels",
+		eventType:   "dev.tekton.event.taskrun.failed.v1",
+		labels:      map[string]string{"app": "foo"},
+		want:        true,
+	}, {
+		description: "filtered type",
+		eventType:   "dev.tekton.event.taskrun.started.v1",
+		labels:      map[string]string{"app": "foo"},
+	}, {
+		description: "filtered labels",
+		eventType:   "dev.tekton.event.taskrun.failed.v1",
+		labels:      map[string]string{"app": "bar"},
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := cfg.Allows(tc.eventType, tc.labels); got != tc.want {
+				t.Errorf("Allows() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+	if !(&config.Events{}).Allows("any", nil) {
+		t.Error("expected an empty config to allow all events")
+	}
+}
+
+func TestGetEventsConfigName(t *testing.T) {
+	for _, tc := range []struct {
+		description        string
+		eventsEnvValue     string
+		expectedConfigName string
+	}{{
+		description:        "Events config value not set",
+		expectedConfigName: "config-events",
+	}, {
+		description:        "Events config value set",
+		eventsEnvValue:     "config-events-test",
+		expectedConfigName: "config-events-test",
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			original := os.Getenv("CONFIG_EVENTS_NAME")
+			defer t.Cleanup(func() {
+				os.Setenv("CONFIG_EVENTS_NAME", original)
+			})
+			if tc.eventsEnvValue != "" {
+				os.Setenv("CONFIG_EVENTS_NAME", tc.eventsEnvValue)
+			}
+			if got := config.GetEventsConfigName(); got != tc.expectedConfigName {
+				t.Errorf("GetEventsConfigName() = %s, want %s", got, tc.expectedConfigName)
+			}
+		})
+	}
+}
@@ -57,6 +57,16 @@ const (
 	DefaultSendCloudEventsForRuns = false
 	// DefaultEmbeddedStatus is the default value for "embedded-status".
 	DefaultEmbeddedStatus = FullEmbeddedStatus
+	// DefaultEnableRunSummary is the default value for "enable-run-summary".
+	DefaultEnableRunSummary = false
+	// DefaultEnableGracefulCancellation is the default value for "enable-graceful-cancellation".
+	DefaultEnableGracefulCancellation = false
+	// DefaultEnableGracefulSidecarStop is the default value for "enable-graceful-sidecar-stop".
+	DefaultEnableGracefulSidecarStop = false
+	// DefaultEnableParamsAsEnvInScripts is the default value for "enable-params-as-env-in-scripts".
+	DefaultEnableParamsAsEnvInScripts = false
+	// DefaultEnableNativeSidecars is the default value for "enable-native-sidecars".
+	DefaultEnableNativeSidecars = false
 
 	disableAffinityAssistantKey         = "disable-affinity-assistant"
 	disableCredsInitKey                 = "disable-creds-init"
@@ -67,6 +77,11 @@ const (
 	enableAPIFields                     = "enable-api-fields"
 	sendCloudEventsForRuns              = "send-cloudevents-for-runs"
 	embeddedStatus                      = "embedded-status"
+	enableRunSummary                    = "enable-run-summary"
+	enableGracefulCancellation          = "enable-graceful-cancellation"
+	enableGracefulSidecarStop           = "enable-graceful-sidecar-stop"
+	enableParamsAsEnvInScripts          = "enable-params-as-env-in-scripts"
+	enableNativeSidecars                = "enable-native-sidecars"
 )
 
 // FeatureFlags holds the features configurations
@@ -82,6 +97,11 @@ type FeatureFlags struct {
 	EnableAPIFields                  string
 	SendCloudEventsForRuns           bool
 	EmbeddedStatus                   string
+	EnableRunSummary                 bool
+	EnableGracefulCancellation       bool
+	EnableGracefulSidecarStop        bool
+	EnableParamsAsEnvInScripts       bool
+	EnableNativeSidecars             bool
 }
 
 // GetFeatureFlagsConfigName returns the name of the configmap containing all
@@ -130,6 +150,21 @@ func NewFeatureFlagsFromMap(cfgMap map[string]string) (*FeatureFlags, error) {
 	if err := setEmbeddedStatus(cfgMap, DefaultEmbeddedStatus, &tc.EmbeddedStatus); err != nil {
 		return nil, err
 	}
+	if err := setFeature(enableRunSummary, DefaultEnableRunSummary, &tc.EnableRunSummary); err != nil {
+		return nil, err
+	}
+	if err := setFeature(enableGracefulCancellation, DefaultEnableGracefulCancellation, &tc.EnableGracefulCancellation); err != nil {
+		return nil, err
+	}
+	if err := setFeature(enableGracefulSidecarStop, DefaultEnableGracefulSidecarStop, &tc.EnableGracefulSidecarStop); err != nil {
+		return nil, err
+	}
+	if err := setFeature(enableParamsAsEnvInScripts, DefaultEnableParamsAsEnvInScripts, &tc.EnableParamsAsEnvInScripts); err != nil {
+		return nil, err
+	}
+	if err := setFeature(enableNativeSidecars, DefaultEnableNativeSidecars, &tc.EnableNativeSidecars); err != nil {
+		return nil, err
+	}
 
 	// Given that they are alpha features, Tekton Bundles and Custom Tasks should be switched on if
 	// enable-api-fields is "alpha". If enable-api-fields is not "alpha" then fall back to the value of
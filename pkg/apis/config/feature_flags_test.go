@@ -51,6 +51,11 @@ func TestNewFeatureFlagsFromConfigMap(t *testing.T) {
 				EnableAPIFields:                  "alpha",
 				SendCloudEventsForRuns:           true,
 				EmbeddedStatus:                   "both",
+				EnableRunSummary:                 true,
+				EnableGracefulCancellation:       true,
+				EnableGracefulSidecarStop:        true,
+				EnableParamsAsEnvInScripts:       true,
+				EnableNativeSidecars:             true,
 			},
 			fileName: "feature-flags-all-flags-set",
 		},
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// PrunerSuccessfulHistoryLimitKey is the name of the configmap entry that specifies the number
+	// of successful runs to keep for each Task or Pipeline
+	PrunerSuccessfulHistoryLimitKey = "successful-history-limit"
+
+	// PrunerFailedHistoryLimitKey is the name of the configmap entry that specifies the number
+	// of failed runs to keep for each Task or Pipeline
+	PrunerFailedHistoryLimitKey = "failed-history-limit"
+
+	// PrunerTTLAfterFinishedKey is the name of the configmap entry that specifies how long runs
+	// are kept after they complete
+	PrunerTTLAfterFinishedKey = "ttl-after-finished"
+)
+
+// Pruner holds the configurations for the removal of completed TaskRuns and PipelineRuns
+// +k8s:deepcopy-gen=true
+type Pruner struct {
+	// SuccessfulHistoryLimit is the number of successful runs kept for each Task or Pipeline.
+	// All of them are kept when it is nil.
+	SuccessfulHistoryLimit *int
+	// FailedHistoryLimit is the number of failed runs kept for each Task or Pipeline.
+	// All of them are kept when it is nil.
+	FailedHistoryLimit *int
+	// TTLAfterFinished is how long runs are kept after they complete.
+	// Runs are kept regardless of their age when it is nil.
+	TTLAfterFinished *time.Duration
+}
+
+// GetPrunerConfigName returns the name of the configmap containing all
+// customizations for the pruning of completed runs.
+func GetPrunerConfigName() string {
+	if e := os.Getenv("CONFIG_PRUNER_NAME"); e != "" {
+		return e
+	}
+	return "config-pruner"
+}
+
+// IsEnabled returns true if any completed run should be pruned
+func (cfg *Pruner) IsEnabled() bool {
+	return cfg != nil && (cfg.SuccessfulHistoryLimit != nil || cfg.FailedHistoryLimit != nil || cfg.TTLAfterFinished != nil)
+}
+
+// WithOverrides returns a copy of the Config in which the values set in the given map,
+// corresponding to a ConfigMap, replace the existing ones
+func (cfg *Pruner) WithOverrides(cfgMap map[string]string) (*Pruner, error) {
+	tc := cfg.DeepCopy()
+	if tc == nil {
+		tc = &Pruner{}
+	}
+
+	for _, limit := range []struct {
+		key   string
+		value **int
+	}{
+		{key: PrunerSuccessfulHistoryLimitKey, value: &tc.SuccessfulHistoryLimit},
+		{key: PrunerFailedHistoryLimitKey, value: &tc.FailedHistoryLimit},
+	} {
+		if v, ok := cfgMap[limit.key]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("failed parsing pruner config %q: expected a non-negative integer, got %q", limit.key, v)
+			}
+			*limit.value = &n
+		}
+	}
+
+	if v, ok := cfgMap[PrunerTTLAfterFinishedKey]; ok {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("failed parsing pruner config %q: expected a non-negative duration, got %q", PrunerTTLAfterFinishedKey, v)
+		}
+		tc.TTLAfterFinished = &ttl
+	}
+
+	return tc, nil
+}
+
+// NewPrunerFromMap returns a Config given a map corresponding to a ConfigMap
+func NewPrunerFromMap(cfgMap map[string]string) (*Pruner, error) {
+	return (&Pruner{}).WithOverrides(cfgMap)
+}
+
+// NewPrunerFromConfigMap returns a Config for the given configmap
+func NewPrunerFromConfigMap(config *corev1.ConfigMap) (*Pruner, error) {
+	return NewPrunerFromMap(config.Data)
+}
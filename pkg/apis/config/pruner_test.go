@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	test "github.com/tektoncd/pipeline/pkg/reconciler/testing"
+	"github.com/tektoncd/pipeline/test/diff"
+)
+
+func TestNewPrunerFromConfigMap(t *testing.T) {
+	successful, failed, ttl := 5, 10, 24*time.Hour
+	for _, tc := range []struct {
+		fileName       string
+		expectedConfig *config.Pruner
+	}{{
+		fileName: config.GetPrunerConfigName(),
+		expectedConfig: &config.Pruner{
+			SuccessfulHistoryLimit: &successful,
+			FailedHistoryLimit:     &failed,
+			TTLAfterFinished:       &ttl,
+		},
+	}, {
+		fileName:       "config-pruner-empty",
+		expectedConfig: &config.Pruner{},
+	}} {
+		t.Run(tc.fileName, func(t *testing.T) {
+			cm := test.ConfigMapFromTestFile(t, tc.fileName)
+			got, err := config.NewPrunerFromConfigMap(cm)
+			if err != nil {
+				t.Fatalf("NewPrunerFromConfigMap(actual) = %v", err)
+			}
+			if d := cmp.Diff(tc.expectedConfig, got); d != "" {
+				t.Errorf("Diff:\n%s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestNewPrunerFromInvalidConfigMap(t *testing.T) {
+	for _, fileName := range []string{
+		"config-pruner-invalid-limit",
+		"config-pruner-invalid-ttl",
+	} {
+		t.Run(fileName, func(t *testing.T) {
+			cm := test.ConfigMapFromTestFile(t, fileName)
+			if _, err := config.NewPrunerFromConfigMap(cm); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestPrunerWithOverrides(t *testing.T) {
+	successful, failed, overridden := 5, 10, 1
+	ttl := time.Hour
+	cfg := &config.Pruner{
+		SuccessfulHistoryLimit: &successful,
+		FailedHistoryLimit:     &failed,
+	}
+	got, err := cfg.WithOverrides(map[string]string{
+		config.PrunerFailedHistoryLimitKey: "1",
+		config.PrunerTTLAfterFinishedKey:   "1h",
+	})
+	if err != nil {
+		t.Fatalf("WithOverrides() = %v", err)
+	}
+	want := &config.Pruner{
+		SuccessfulHistoryLimit: &successful,
+		FailedHistoryLimit:     &overridden,
+		TTLAfterFinished:       &ttl,
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Diff:\n%s", diff.PrintWantGot(d))
+	}
+	if *cfg.FailedHistoryLimit != failed {
+		t.Errorf("WithOverrides() modified the original config")
+	}
+	if !got.IsEnabled() || (&config.Pruner{}).IsEnabled() {
+		t.Errorf("IsEnabled() returned an unexpected value")
+	}
+}
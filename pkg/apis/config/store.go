@@ -32,6 +32,8 @@ type Config struct {
 	ArtifactBucket *ArtifactBucket
 	ArtifactPVC    *ArtifactPVC
 	Metrics        *Metrics
+	Tracing        *Tracing
+	Events         *Events
 }
 
 // FromContext extracts a Config from the provided context.
@@ -54,12 +56,16 @@ func FromContextOrDefaults(ctx context.Context) *Config {
 	artifactBucket, _ := NewArtifactBucketFromMap(map[string]string{})
 	artifactPVC, _ := NewArtifactPVCFromMap(map[string]string{})
 	metrics, _ := newMetricsFromMap(map[string]string{})
+	tracing, _ := NewTracingFromMap(map[string]string{})
+	events, _ := NewEventsFromMap(map[string]string{})
 	return &Config{
 		Defaults:       defaults,
 		FeatureFlags:   featureFlags,
 		ArtifactBucket: artifactBucket,
 		ArtifactPVC:    artifactPVC,
 		Metrics:        metrics,
+		Tracing:        tracing,
+		Events:         events,
 	}
 }
 
@@ -87,6 +93,8 @@ func NewStore(logger configmap.Logger, onAfterStore ...func(name string, value i
 				GetArtifactBucketConfigName(): NewArtifactBucketFromConfigMap,
 				GetArtifactPVCConfigName():    NewArtifactPVCFromConfigMap,
 				GetMetricsConfigName():        NewMetricsFromConfigMap,
+				GetTracingConfigName():        NewTracingFromConfigMap,
+				GetEventsConfigName():         NewEventsFromConfigMap,
 			},
 			onAfterStore...,
 		),
@@ -123,11 +131,21 @@ func (s *Store) Load() *Config {
 	if metrics == nil {
 		metrics, _ = newMetricsFromMap(map[string]string{})
 	}
+	tracing := s.UntypedLoad(GetTracingConfigName())
+	if tracing == nil {
+		tracing, _ = NewTracingFromMap(map[string]string{})
+	}
+	events := s.UntypedLoad(GetEventsConfigName())
+	if events == nil {
+		events, _ = NewEventsFromMap(map[string]string{})
+	}
 	return &Config{
 		Defaults:       defaults.(*Defaults).DeepCopy(),
 		FeatureFlags:   featureFlags.(*FeatureFlags).DeepCopy(),
 		ArtifactBucket: artifactBucket.(*ArtifactBucket).DeepCopy(),
 		ArtifactPVC:    artifactPVC.(*ArtifactPVC).DeepCopy(),
 		Metrics:        metrics.(*Metrics).DeepCopy(),
+		Tracing:        tracing.(*Tracing).DeepCopy(),
+		Events:         events.(*Events).DeepCopy(),
 	}
 }
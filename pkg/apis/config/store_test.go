@@ -33,12 +33,16 @@ func TestStoreLoadWithContext(t *testing.T) {
 	artifactBucketConfig := test.ConfigMapFromTestFile(t, "config-artifact-bucket")
 	artifactPVCConfig := test.ConfigMapFromTestFile(t, "config-artifact-pvc")
 	metricsConfig := test.ConfigMapFromTestFile(t, "config-observability")
+	tracingConfig := test.ConfigMapFromTestFile(t, "config-tracing")
+	eventsConfig := test.ConfigMapFromTestFile(t, "config-events")
 
 	expectedDefaults, _ := config.NewDefaultsFromConfigMap(defaultConfig)
 	expectedFeatures, _ := config.NewFeatureFlagsFromConfigMap(featuresConfig)
 	expectedArtifactBucket, _ := config.NewArtifactBucketFromConfigMap(artifactBucketConfig)
 	expectedArtifactPVC, _ := config.NewArtifactPVCFromConfigMap(artifactPVCConfig)
 	metrics, _ := config.NewMetricsFromConfigMap(metricsConfig)
+	tracing, _ := config.NewTracingFromConfigMap(tracingConfig)
+	events, _ := config.NewEventsFromConfigMap(eventsConfig)
 
 	expected := &config.Config{
 		Defaults:       expectedDefaults,
@@ -46,6 +50,8 @@ func TestStoreLoadWithContext(t *testing.T) {
 		ArtifactBucket: expectedArtifactBucket,
 		ArtifactPVC:    expectedArtifactPVC,
 		Metrics:        metrics,
+		Tracing:        tracing,
+		Events:         events,
 	}
 
 	store := config.NewStore(logtesting.TestLogger(t))
@@ -54,6 +60,8 @@ func TestStoreLoadWithContext(t *testing.T) {
 	store.OnConfigChanged(artifactBucketConfig)
 	store.OnConfigChanged(artifactPVCConfig)
 	store.OnConfigChanged(metricsConfig)
+	store.OnConfigChanged(tracingConfig)
+	store.OnConfigChanged(eventsConfig)
 
 	cfg := config.FromContext(store.ToContext(context.Background()))
 
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TracingEnabledKey is the name of the configmap entry that specifies
+	// whether tracing is enabled.
+	TracingEnabledKey = "enabled"
+
+	// TracingExporterKey is the name of the configmap entry that specifies
+	// the exporter used to export spans.
+	TracingExporterKey = "exporter"
+
+	// TracingEndpointKey is the name of the configmap entry that specifies
+	// the URL of the OTLP collector spans are sent to.
+	TracingEndpointKey = "endpoint"
+
+	// TracingExporterOTLP exports spans to an OTLP collector over HTTP.
+	TracingExporterOTLP = "otlp"
+
+	// TracingExporterStdout writes spans to the standard output. It is meant
+	// for testing and debugging.
+	TracingExporterStdout = "stdout"
+
+	// DefaultTracingExporter is the exporter used when none is specified.
+	DefaultTracingExporter = TracingExporterOTLP
+)
+
+// Tracing holds the configurations for OpenTelemetry tracing
+// +k8s:deepcopy-gen=true
+type Tracing struct {
+	Enabled  bool
+	Exporter string
+	Endpoint string
+}
+
+// GetTracingConfigName returns the name of the configmap containing all
+// customizations for tracing.
+func GetTracingConfigName() string {
+	if e := os.Getenv("CONFIG_TRACING_NAME"); e != "" {
+		return e
+	}
+	return "config-tracing"
+}
+
+// Equals returns true if two Configs are identical
+func (cfg *Tracing) Equals(other *Tracing) bool {
+	if cfg == nil && other == nil {
+		return true
+	}
+
+	if cfg == nil || other == nil {
+		return false
+	}
+
+	return other.Enabled == cfg.Enabled &&
+		other.Exporter == cfg.Exporter &&
+		other.Endpoint == cfg.Endpoint
+}
+
+// NewTracingFromMap returns a Config given a map corresponding to a ConfigMap
+func NewTracingFromMap(cfgMap map[string]string) (*Tracing, error) {
+	tc := Tracing{
+		Exporter: DefaultTracingExporter,
+	}
+
+	if enabled, ok := cfgMap[TracingEnabledKey]; ok {
+		b, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing tracing config %q: %w", TracingEnabledKey, err)
+		}
+		tc.Enabled = b
+	}
+
+	if exporter, ok := cfgMap[TracingExporterKey]; ok {
+		switch exporter {
+		case TracingExporterOTLP, TracingExporterStdout:
+			tc.Exporter = exporter
+		default:
+			return nil, fmt.Errorf("invalid value for tracing config %q: %q, expected %q or %q", TracingExporterKey, exporter, TracingExporterOTLP, TracingExporterStdout)
+		}
+	}
+
+	if endpoint, ok := cfgMap[TracingEndpointKey]; ok {
+		if _, err := url.Parse(endpoint); err != nil {
+			return nil, fmt.Errorf("failed parsing tracing config %q: %w", TracingEndpointKey, err)
+		}
+		tc.Endpoint = endpoint
+	}
+
+	return &tc, nil
+}
+
+// NewTracingFromConfigMap returns a Config for the given configmap
+func NewTracingFromConfigMap(config *corev1.ConfigMap) (*Tracing, error) {
+	return NewTracingFromMap(config.Data)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	test "github.com/tektoncd/pipeline/pkg/reconciler/testing"
+	"github.com/tektoncd/pipeline/test/diff"
+)
+
+func TestNewTracingFromConfigMap(t *testing.T) {
+	for _, tc := range []struct {
+		fileName       string
+		expectedConfig *config.Tracing
+	}{{
+		fileName: config.GetTracingConfigName(),
+		expectedConfig: &config.Tracing{
+			Enabled:  true,
+			Exporter: config.TracingExporterOTLP,
+			Endpoint: "http://otel-collector.observability.svc:4318",
+		},
+	}, {
+		fileName: "config-tracing-empty",
+		expectedConfig: &config.Tracing{
+			Exporter: config.DefaultTracingExporter,
+		},
+	}} {
+		t.Run(tc.fileName, func(t *testing.T) {
+			cm := test.ConfigMapFromTestFile(t, tc.fileName)
+			got, err := config.NewTracingFromConfigMap(cm)
+			if err != nil {
+				t.Fatalf("NewTracingFromConfigMap(actual) = %v", err)
+			}
+			if d := cmp.Diff(tc.expectedConfig, got); d != "" {
+				t.Errorf("Diff:\n%s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestNewTracingFromInvalidConfigMap(t *testing.T) {
+	for _, fileName := range []string{
+		"config-tracing-invalid-enabled",
+		"config-tracing-invalid-exporter",
+	} {
+		t.Run(fileName, func(t *testing.T) {
+			cm := test.ConfigMapFromTestFile(t, fileName)
+			if _, err := config.NewTracingFromConfigMap(cm); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestGetTracingConfigName(t *testing.T) {
+	for _, tc := range []struct {
+		description        string
+		tracingEnvValue    string
+		expectedConfigName string
+	}{{
+		description:        "Tracing config value not set",
+		expectedConfigName: "config-tracing",
+	}, {
+		description:        "Tracing config value set",
+		tracingEnvValue:    "config-tracing-test",
+		expectedConfigName: "config-tracing-test",
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			original := os.Getenv("CONFIG_TRACING_NAME")
+			defer t.Cleanup(func() {
+				os.Setenv("CONFIG_TRACING_NAME", original)
+			})
+			if tc.tracingEnvValue != "" {
+				os.Setenv("CONFIG_TRACING_NAME", tc.tracingEnvValue)
+			}
+			if got := config.GetTracingConfigName(); got != tc.expectedConfigName {
+				t.Errorf("GetTracingConfigName() = %s, want %s", got, tc.expectedConfigName)
+			}
+		})
+	}
+}
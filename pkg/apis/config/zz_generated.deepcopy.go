@@ -22,6 +22,8 @@ limitations under the License.
 package config
 
 import (
+	time "time"
+
 	pod "github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 )
 
@@ -83,6 +85,34 @@ func (in *Defaults) DeepCopy() *Defaults {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Events) DeepCopyInto(out *Events) {
+	*out = *in
+	if in.NamespaceSinks != nil {
+		in, out := &in.NamespaceSinks, &out.NamespaceSinks
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Events.
+func (in *Events) DeepCopy() *Events {
+	if in == nil {
+		return nil
+	}
+	out := new(Events)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FeatureFlags) DeepCopyInto(out *FeatureFlags) {
 	*out = *in
@@ -114,3 +144,50 @@ func (in *Metrics) DeepCopy() *Metrics {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pruner) DeepCopyInto(out *Pruner) {
+	*out = *in
+	if in.SuccessfulHistoryLimit != nil {
+		in, out := &in.SuccessfulHistoryLimit, &out.SuccessfulHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.FailedHistoryLimit != nil {
+		in, out := &in.FailedHistoryLimit, &out.FailedHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.TTLAfterFinished != nil {
+		in, out := &in.TTLAfterFinished, &out.TTLAfterFinished
+		*out = new(time.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pruner.
+func (in *Pruner) DeepCopy() *Pruner {
+	if in == nil {
+		return nil
+	}
+	out := new(Pruner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tracing) DeepCopyInto(out *Tracing) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tracing.
+func (in *Tracing) DeepCopy() *Tracing {
+	if in == nil {
+		return nil
+	}
+	out := new(Tracing)
+	in.DeepCopyInto(out)
+	return out
+}
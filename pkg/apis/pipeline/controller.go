@@ -36,4 +36,13 @@ const (
 
 	// RunControllerName holds the name of the Custom Task controller
 	RunControllerName = "Run"
+
+	// PipelineRunPrunerControllerName holds the name of the controller pruning completed PipelineRuns
+	PipelineRunPrunerControllerName = "PipelineRunPruner"
+
+	// TaskRunPrunerControllerName holds the name of the controller pruning completed TaskRuns
+	TaskRunPrunerControllerName = "TaskRunPruner"
+
+	// CloudEventOutboxControllerName holds the name of the controller delivering the cloud events of the outbox
+	CloudEventOutboxControllerName = "CloudEventOutbox"
 )
@@ -38,6 +38,8 @@ type Images struct {
 	ShellImageWin string
 	// GsutilImage is the container image containing gsutil.
 	GsutilImage string
+	// S3Image is the container image containing the aws CLI, used to copy artifacts to and from S3 buckets.
+	S3Image string
 	// PRImage is the container image that we use to implement the PR source step.
 	PRImage string
 	// ImageDigestExporterImage is the container image containing our image digest exporter binary.
@@ -61,6 +63,7 @@ func (i Images) Validate() error {
 		{i.ShellImage, "shell-image"},
 		{i.ShellImageWin, "shell-image-win"},
 		{i.GsutilImage, "gsutil-image"},
+		{i.S3Image, "s3-image"},
 		{i.PRImage, "pr-image"},
 		{i.ImageDigestExporterImage, "imagedigest-exporter-image"},
 		{i.WorkingDirInitImage, "workingdirinit-image"},
@@ -15,6 +15,7 @@ func TestValidate(t *testing.T) {
 		ShellImage:               "set",
 		ShellImageWin:            "set",
 		GsutilImage:              "set",
+		S3Image:                  "set",
 		PRImage:                  "set",
 		ImageDigestExporterImage: "set",
 		WorkingDirInitImage:      "set",
@@ -31,10 +32,11 @@ func TestValidate(t *testing.T) {
 		ShellImage:               "", // unset!
 		ShellImageWin:            "set",
 		GsutilImage:              "set",
+		S3Image:                  "", // unset!
 		PRImage:                  "", // unset!
 		ImageDigestExporterImage: "set",
 	}
-	wantErr := "found unset image flags: [git-image pr-image shell-image workingdirinit-image]"
+	wantErr := "found unset image flags: [git-image pr-image s3-image shell-image workingdirinit-image]"
 	if err := invalid.Validate(); err == nil {
 		t.Error("invalid Images expected error, got nil")
 	} else if err.Error() != wantErr {
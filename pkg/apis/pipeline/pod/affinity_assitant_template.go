@@ -42,6 +42,40 @@ type AffinityAssistantTemplate struct {
 	// +optional
 	// +listType=atomic
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Mode is how Affinity Assistants schedule the TaskRun pods of a PipelineRun.
+	// Defaults to "workspace".
+	// +optional
+	Mode AffinityAssistantMode `json:"mode,omitempty"`
+}
+
+// AffinityAssistantMode is how Affinity Assistants schedule the TaskRun pods of a PipelineRun.
+type AffinityAssistantMode string
+
+const (
+	// AffinityAssistantPerWorkspace creates an Affinity Assistant for every PersistentVolumeClaim
+	// workspace of a PipelineRun, co-locating the TaskRun pods which share that workspace.
+	AffinityAssistantPerWorkspace AffinityAssistantMode = "workspace"
+	// AffinityAssistantPerPipelineRun creates a single Affinity Assistant for a PipelineRun,
+	// co-locating all its TaskRun pods whichever PersistentVolumeClaims they use.
+	AffinityAssistantPerPipelineRun AffinityAssistantMode = "pipelineRun"
+	// AffinityAssistantIsolatePipelineRun co-locates all the TaskRun pods of a PipelineRun like
+	// AffinityAssistantPerPipelineRun, on a node where no other Affinity Assistant runs.
+	AffinityAssistantIsolatePipelineRun AffinityAssistantMode = "isolatePipelineRun"
+)
+
+// IsValid returns true if m is a supported Affinity Assistant mode.
+func (m AffinityAssistantMode) IsValid() bool {
+	switch m {
+	case AffinityAssistantPerWorkspace, AffinityAssistantPerPipelineRun, AffinityAssistantIsolatePipelineRun:
+		return true
+	}
+	return false
+}
+
+// IsPerPipelineRun returns true if m creates a single Affinity Assistant for a PipelineRun.
+func (m AffinityAssistantMode) IsPerPipelineRun() bool {
+	return m == AffinityAssistantPerPipelineRun || m == AffinityAssistantIsolatePipelineRun
 }
 
 // Equals checks if this Template is identical to the given Template.
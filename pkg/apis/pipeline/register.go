@@ -46,6 +46,43 @@ const (
 	// MemberOfLabelKey is used as the label identifier for a PipelineTask
 	// Set to Tasks/Finally depending on the position of the PipelineTask
 	MemberOfLabelKey = GroupName + "/memberOf"
+
+	// AllowReferencesFromAnnotationKey is set on a Namespace to allow Tasks and Pipelines
+	// in it to be referenced from other namespaces. Its value is a comma-separated list of
+	// namespaces, or "*" to allow references from any namespace.
+	AllowReferencesFromAnnotationKey = GroupName + "/allow-references-from"
+
+	// CacheAnnotationKey is set on a TaskRun created for a PipelineTask with caching
+	// enabled to the key configured in the cache of the PipelineTask, after substitution
+	CacheAnnotationKey = GroupName + "/cache"
+
+	// CacheKeyLabelKey is used as the label identifier for the cache key the controller
+	// computed for a TaskRun with caching enabled
+	CacheKeyLabelKey = GroupName + "/cacheKey"
+
+	// CachedFromAnnotationKey is set on a TaskRun to the name of the TaskRun whose
+	// results it reuses instead of running its Task
+	CachedFromAnnotationKey = GroupName + "/cachedFrom"
+
+	// PrunerAnnotationPrefix is the prefix of the annotations of a Task, ClusterTask or Pipeline that
+	// override the pruning configuration of its runs, e.g. pruner.tekton.dev/ttl-after-finished
+	PrunerAnnotationPrefix = "pruner." + GroupName + "/"
+
+	// CloudEventsSinkAnnotationKey is set on a PipelineRun or TaskRun to select the sink
+	// its CloudEvents are sent to, when allowed by the config-events ConfigMap
+	CloudEventsSinkAnnotationKey = GroupName + "/cloudevents-sink"
+
+	// PipelineRunSpanContextAnnotationKey holds the W3C traceparent of a PipelineRun, and of
+	// the TaskRuns it creates, when tracing is enabled
+	PipelineRunSpanContextAnnotationKey = GroupName + "/pipelinerunSpanContext"
+
+	// TaskRunSpanContextAnnotationKey holds the W3C traceparent of a TaskRun, and of its Pod,
+	// when tracing is enabled
+	TaskRunSpanContextAnnotationKey = GroupName + "/taskrunSpanContext"
+
+	// WorkspaceArtifactsAnnotationKey is set on a TaskRun created for a PipelineTask binding
+	// artifact workspaces to the paths of the artifact storage they are copied from and to
+	WorkspaceArtifactsAnnotationKey = GroupName + "/workspace-artifacts"
 )
 
 var (
@@ -193,6 +193,14 @@ type Step struct {
 	// +optional
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
 
+	// This is an alpha field. You must set the "enable-api-fields" feature flag to "alpha"
+	// for this field to be supported.
+	//
+	// GracePeriod is the time given to the step to exit after it is sent a SIGTERM
+	// when it times out, before it is killed. Defaults to killing the step when it times out.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+
 	// This is an alpha field. You must set the "enable-api-fields" feature flag to "alpha"
 	// for this field to be supported.
 	//
@@ -209,6 +217,31 @@ type Step struct {
 	// stopAndFail indicates exit the taskRun if the container exits with non-zero exit code
 	// continue indicates continue executing the rest of the steps irrespective of the container exit code
 	OnError string `json:"onError,omitempty"`
+
+	// This is an alpha field. You must set the "enable-api-fields" feature flag to "alpha"
+	// for this field to be supported.
+	//
+	// OnCancel is the contents of an executable file to execute in the step container
+	// when the step is cancelled, once the step process exited after it was sent a SIGTERM.
+	// It is only run when the "enable-graceful-cancellation" feature flag is set to "true".
+	// +optional
+	OnCancel string `json:"onCancel,omitempty"`
+
+	// This is an alpha field. You must set the "enable-api-fields" feature flag to "alpha"
+	// for this field to be supported.
+	//
+	// Ref references a Step declared in another Task, which replaces this Step when
+	// the TaskRun runs. Only the name of this Step can be set along with it.
+	// +optional
+	Ref *StepRef `json:"ref,omitempty"`
+}
+
+// StepRef references a Step declared in another Task.
+type StepRef struct {
+	// Name is the name of the Step in the referenced Task.
+	Name string `json:"name"`
+	// TaskRef references the Task declaring the Step, like the taskRef of a TaskRun.
+	TaskRef *TaskRef `json:"taskRef"`
 }
 
 // ToK8sContainer converts the Step to a Kubernetes Container struct
@@ -677,6 +710,23 @@ type Sidecar struct {
 	// +optional
 	// +listType=atomic
 	Workspaces []WorkspaceUsage `json:"workspaces,omitempty"`
+
+	// This is an alpha field. You must set the "enable-api-fields" feature flag to "alpha"
+	// for this field to be supported.
+	//
+	// ReadinessGate tells whether the first Step waits for the Sidecar to be ready,
+	// as reported by its readinessProbe, before it starts. Defaults to true.
+	// +optional
+	ReadinessGate *bool `json:"readinessGate,omitempty"`
+
+	// This is an alpha field. You must set the "enable-api-fields" feature flag to "alpha"
+	// for this field to be supported.
+	//
+	// GracePeriod is the time given to the Sidecar to exit after it is sent a SIGTERM
+	// when the Steps are done, before it is killed. Defaults to 30s. It is only used
+	// when the "enable-graceful-sidecar-stop" feature flag is "true".
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
 }
 
 // ToK8sContainer converts the Sidecar to a Kubernetes Container struct
@@ -212,6 +212,28 @@ type PipelineTask struct {
 	// Refer Go's ParseDuration documentation for expected format: https://golang.org/pkg/time/#ParseDuration
 	// +optional
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Cache enables reusing the results of a previous successful TaskRun of the
+	// same Task with the same params instead of running the Task again.
+	// This field is only supported when the alpha feature gate is enabled.
+	// +optional
+	Cache *PipelineTaskCache `json:"cache,omitempty"`
+
+	// PodTemplate is the pod template of the TaskRun or Run of this PipelineTask,
+	// merged with the pod template of the PipelineRun. The pod template of the
+	// PipelineTask in the TaskRunSpecs of the PipelineRun takes precedence.
+	// This field is only supported when the alpha feature gate is enabled.
+	// +optional
+	PodTemplate *PodTemplate `json:"podTemplate,omitempty"`
+}
+
+// PipelineTaskCache configures how the results of a PipelineTask are cached.
+type PipelineTaskCache struct {
+	// Key is an additional value included in the cache key, for example a digest
+	// of the content of a workspace produced by a previous task. It supports
+	// params, context and task result variables.
+	// +optional
+	Key string `json:"key,omitempty"`
 }
 
 // validateRefOrSpec validates at least one of taskRef or taskSpec is specified
@@ -249,14 +271,34 @@ func (pt PipelineTask) validateCustomTask() (errs *apis.FieldError) {
 	return errs
 }
 
+// validateCache validates the cache configuration of the PipelineTask
+func (pt PipelineTask) validateCache(ctx context.Context) (errs *apis.FieldError) {
+	if pt.Cache == nil {
+		return nil
+	}
+	errs = errs.Also(ValidateEnabledAPIFields(ctx, "cache", config.AlphaAPIFields))
+	if (pt.TaskRef != nil && pt.TaskRef.APIVersion != "") || (pt.TaskSpec != nil && pt.TaskSpec.APIVersion != "") {
+		errs = errs.Also(apis.ErrInvalidValue("custom tasks do not support caching", "cache"))
+	}
+	return errs
+}
+
+func (pt PipelineTask) validatePodTemplate(ctx context.Context) (errs *apis.FieldError) {
+	if pt.PodTemplate == nil {
+		return nil
+	}
+	return ValidateEnabledAPIFields(ctx, "podTemplate", config.AlphaAPIFields)
+}
+
 // validateBundle validates bundle specifications - checking name and bundle
 func (pt PipelineTask) validateBundle() (errs *apis.FieldError) {
 	// bundle requires a TaskRef to be specified
 	if (pt.TaskRef != nil && pt.TaskRef.Bundle != "") && pt.TaskRef.Name == "" {
 		errs = errs.Also(apis.ErrMissingField("taskRef.name"))
 	}
-	// If a bundle url is specified, ensure it is parsable
-	if pt.TaskRef != nil && pt.TaskRef.Bundle != "" {
+	// If a bundle url is specified, ensure it is parsable. Bundles containing variables are
+	// only parsed once the variables have been substituted at runtime.
+	if pt.TaskRef != nil && pt.TaskRef.Bundle != "" && !strings.Contains(pt.TaskRef.Bundle, "$(") {
 		if _, err := name.ParseReference(pt.TaskRef.Bundle); err != nil {
 			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid bundle reference (%s)", err.Error()), "taskRef.bundle"))
 		}
@@ -293,6 +335,9 @@ func (pt PipelineTask) validateTask(ctx context.Context) (errs *apis.FieldError)
 				errs = errs.Also(apis.ErrDisallowedFields("taskref.resource"))
 			}
 		}
+		if pt.TaskRef.Namespace != "" {
+			errs = errs.Also(pt.TaskRef.validateNamespace(ctx).ViaField("taskRef"))
+		}
 	}
 	return errs
 }
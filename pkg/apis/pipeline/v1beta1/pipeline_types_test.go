@@ -341,6 +341,12 @@ func TestPipelineTask_ValidateRegularTask_Failure(t *testing.T) {
 			TaskRef: &TaskRef{Name: "boo", ResolverRef: ResolverRef{Resource: []ResolverParam{{}}}},
 		},
 		expectedError: *apis.ErrDisallowedFields("taskref.resource"),
+	}, {
+		name: "pipeline task - use of namespace without the feature flag set",
+		task: PipelineTask{
+			TaskRef: &TaskRef{Name: "boo", Namespace: "catalog"},
+		},
+		expectedError: *apis.ErrGeneric(`namespace requires "enable-api-fields" feature gate to be "alpha" but it is "stable"`),
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -824,6 +830,96 @@ func TestPipelineTask_validateMatrix(t *testing.T) {
 	}
 }
 
+func TestPipelineTask_validateCache(t *testing.T) {
+	tests := []struct {
+		name      string
+		pt        *PipelineTask
+		apiFields string
+		wantErrs  *apis.FieldError
+	}{{
+		name: "cache on a task",
+		pt: &PipelineTask{
+			Name:    "task",
+			TaskRef: &TaskRef{Name: "foo"},
+			Cache:   &PipelineTaskCache{Key: "$(params.revision)"},
+		},
+		apiFields: config.AlphaAPIFields,
+	}, {
+		name: "cache requires alpha",
+		pt: &PipelineTask{
+			Name:    "task",
+			TaskRef: &TaskRef{Name: "foo"},
+			Cache:   &PipelineTaskCache{},
+		},
+		apiFields: config.StableAPIFields,
+		wantErrs:  apis.ErrGeneric("cache requires \"enable-api-fields\" feature gate to be \"alpha\" but it is \"stable\""),
+	}, {
+		name: "cache on a custom task",
+		pt: &PipelineTask{
+			Name:    "task",
+			TaskRef: &TaskRef{APIVersion: "example.dev/v0", Kind: "Example"},
+			Cache:   &PipelineTaskCache{},
+		},
+		apiFields: config.AlphaAPIFields,
+		wantErrs:  apis.ErrInvalidValue("custom tasks do not support caching", "cache"),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			featureFlags, _ := config.NewFeatureFlagsFromMap(map[string]string{
+				"enable-api-fields": tt.apiFields,
+			})
+			ctx := config.ToContext(context.Background(), &config.Config{FeatureFlags: featureFlags})
+			if d := cmp.Diff(tt.wantErrs.Error(), tt.pt.validateCache(ctx).Error()); d != "" {
+				t.Errorf("PipelineTask.validateCache() errors diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestPipelineTask_validatePodTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		pt        *PipelineTask
+		apiFields string
+		wantErrs  *apis.FieldError
+	}{{
+		name: "no pod template",
+		pt: &PipelineTask{
+			Name:    "task",
+			TaskRef: &TaskRef{Name: "foo"},
+		},
+		apiFields: config.StableAPIFields,
+	}, {
+		name: "pod template",
+		pt: &PipelineTask{
+			Name:        "task",
+			TaskRef:     &TaskRef{Name: "foo"},
+			PodTemplate: &PodTemplate{NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"}},
+		},
+		apiFields: config.AlphaAPIFields,
+	}, {
+		name: "pod template requires alpha",
+		pt: &PipelineTask{
+			Name:        "task",
+			TaskRef:     &TaskRef{Name: "foo"},
+			PodTemplate: &PodTemplate{NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"}},
+		},
+		apiFields: config.StableAPIFields,
+		wantErrs:  apis.ErrGeneric("podTemplate requires \"enable-api-fields\" feature gate to be \"alpha\" but it is \"stable\""),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			featureFlags, _ := config.NewFeatureFlagsFromMap(map[string]string{
+				"enable-api-fields": tt.apiFields,
+			})
+			ctx := config.ToContext(context.Background(), &config.Config{FeatureFlags: featureFlags})
+			if d := cmp.Diff(tt.wantErrs.Error(), tt.pt.validatePodTemplate(ctx).Error()); d != "" {
+				t.Errorf("PipelineTask.validatePodTemplate() errors diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
 func TestPipelineTask_GetMatrixCombinationsCount(t *testing.T) {
 	tests := []struct {
 		name                    string
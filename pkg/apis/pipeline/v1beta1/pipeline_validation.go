@@ -19,8 +19,10 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 
+	"github.com/tektoncd/pipeline/pkg/apis/config"
 	"github.com/tektoncd/pipeline/pkg/apis/validate"
 	"github.com/tektoncd/pipeline/pkg/list"
 	"github.com/tektoncd/pipeline/pkg/reconciler/pipeline/dag"
@@ -63,11 +65,14 @@ func (ps *PipelineSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 	errs = errs.Also(validatePipelineParameterVariables(ctx, ps.Finally, ps.Params).ViaField("finally"))
 	errs = errs.Also(validatePipelineContextVariables(ps.Tasks).ViaField("tasks"))
 	errs = errs.Also(validatePipelineContextVariables(ps.Finally).ViaField("finally"))
+	errs = errs.Also(validateTaskRefVariables(ps.Tasks).ViaField("tasks"))
+	errs = errs.Also(validateTaskRefVariables(ps.Finally).ViaField("finally"))
 	errs = errs.Also(validateExecutionStatusVariables(ps.Tasks, ps.Finally))
 	// Validate the pipeline's workspaces.
 	errs = errs.Also(validatePipelineWorkspacesDeclarations(ps.Workspaces))
 	errs = errs.Also(validatePipelineWorkspacesUsage(ps.Workspaces, ps.Tasks).ViaField("tasks"))
 	errs = errs.Also(validatePipelineWorkspacesUsage(ps.Workspaces, ps.Finally).ViaField("finally"))
+	errs = errs.Also(validatePipelineWorkspacesAccess(ctx, ps.Tasks, ps.Finally))
 	// Validate the pipeline's results
 	errs = errs.Also(validatePipelineResults(ps.Results))
 	errs = errs.Also(validateTasksAndFinallySection(ps))
@@ -75,6 +80,10 @@ func (ps *PipelineSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 	errs = errs.Also(validateWhenExpressions(ps.Tasks, ps.Finally))
 	errs = errs.Also(validateMatrix(ctx, ps.Tasks).ViaField("tasks"))
 	errs = errs.Also(validateMatrix(ctx, ps.Finally).ViaField("finally"))
+	errs = errs.Also(validateCache(ctx, ps.Tasks).ViaField("tasks"))
+	errs = errs.Also(validateCache(ctx, ps.Finally).ViaField("finally"))
+	errs = errs.Also(validatePodTemplates(ctx, ps.Tasks).ViaField("tasks"))
+	errs = errs.Also(validatePodTemplates(ctx, ps.Finally).ViaField("finally"))
 	errs = errs.Also(validateResultsFromMatrixedPipelineTasksNotConsumed(ps.Tasks, ps.Finally))
 	return errs
 }
@@ -122,6 +131,110 @@ func validatePipelineWorkspacesUsage(wss []PipelineWorkspaceDeclaration, pts []P
 	return errs
 }
 
+// validatePipelineWorkspacesAccess validates the access declared by the workspace bindings of the pipeline tasks,
+// and that no pipeline task writes to a workspace accessed by another pipeline task which may run in parallel.
+func validatePipelineWorkspacesAccess(ctx context.Context, tasks []PipelineTask, finalTasks []PipelineTask) (errs *apis.FieldError) {
+	for _, section := range []struct {
+		field string
+		tasks []PipelineTask
+	}{{"tasks", tasks}, {"finally", finalTasks}} {
+		for i, pt := range section.tasks {
+			for j, ws := range pt.Workspaces {
+				if ws.Access == "" {
+					continue
+				}
+				if err := ValidateEnabledAPIFields(ctx, "workspace access", config.AlphaAPIFields); err != nil {
+					errs = errs.Also(err)
+					continue
+				}
+				if ws.Access != WorkspaceAccessReadOnly && ws.Access != WorkspaceAccessReadWrite {
+					errs = errs.Also(apis.ErrInvalidValue(ws.Access, "access").ViaFieldIndex("workspaces", j).ViaFieldIndex(section.field, i))
+				} else if ws.Access == WorkspaceAccessReadWrite && len(pt.Matrix) != 0 {
+					errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("the TaskRuns of matrixed pipeline task %q run in parallel and cannot all write to workspace %q", pt.Name, pipelineWorkspaceName(ws)), "access").ViaFieldIndex("workspaces", j).ViaFieldIndex(section.field, i))
+				}
+			}
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+
+	// Final tasks all run in parallel once the tasks are done. An invalid graph is reported by validateGraph.
+	if g, err := dag.Build(PipelineTaskList(tasks), PipelineTaskList(tasks).Deps()); err == nil {
+		errs = errs.Also(validateWorkspaceAccessConflicts(tasks, g, "tasks"))
+	}
+	errs = errs.Also(validateWorkspaceAccessConflicts(finalTasks, nil, "finally"))
+	return errs
+}
+
+// validateWorkspaceAccessConflicts reports each pipeline task writing to a workspace which an earlier pipeline
+// task of the list also accesses, unless one runs after the other in the graph g. With a nil g, all the pipeline
+// tasks run in parallel.
+func validateWorkspaceAccessConflicts(tasks []PipelineTask, g *dag.Graph, field string) (errs *apis.FieldError) {
+	ancestors := map[string]sets.String{}
+	isOrdered := func(a, b string) bool {
+		if g == nil {
+			return false
+		}
+		for _, name := range []string{a, b} {
+			if _, ok := ancestors[name]; !ok {
+				ancestors[name] = getAncestors(g.Nodes[name], sets.NewString())
+			}
+		}
+		return ancestors[a].Has(b) || ancestors[b].Has(a)
+	}
+	for i, pt := range tasks {
+		for j, ws := range pt.Workspaces {
+			if ws.Access == "" {
+				continue
+			}
+			for _, other := range tasks[:i] {
+				for _, otherWs := range other.Workspaces {
+					if otherWs.Access == "" || (ws.Access == WorkspaceAccessReadOnly && otherWs.Access == WorkspaceAccessReadOnly) {
+						continue
+					}
+					if pipelineWorkspaceName(ws) != pipelineWorkspaceName(otherWs) || !subPathsOverlap(ws.SubPath, otherWs.SubPath) || isOrdered(pt.Name, other.Name) {
+						continue
+					}
+					errs = errs.Also(apis.ErrInvalidValue(
+						fmt.Sprintf("pipeline tasks %q and %q may run in parallel but one of them writes to workspace %q which both access, use runAfter to order them", other.Name, pt.Name, pipelineWorkspaceName(ws)),
+						"access",
+					).ViaFieldIndex("workspaces", j).ViaFieldIndex(field, i))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// getAncestors returns the names of all the tasks which the task of node n runs after.
+func getAncestors(n *dag.Node, ancestors sets.String) sets.String {
+	if n == nil {
+		return ancestors
+	}
+	for _, prev := range n.Prev {
+		if !ancestors.Has(prev.Task.HashKey()) {
+			ancestors.Insert(prev.Task.HashKey())
+			getAncestors(prev, ancestors)
+		}
+	}
+	return ancestors
+}
+
+// pipelineWorkspaceName returns the name of the pipeline workspace that ws binds.
+func pipelineWorkspaceName(ws WorkspacePipelineTaskBinding) string {
+	if ws.Workspace != "" {
+		return ws.Workspace
+	}
+	return ws.Name
+}
+
+// subPathsOverlap returns true if a directory is at or below both subPaths of a workspace.
+func subPathsOverlap(a, b string) bool {
+	a, b = filepath.Clean("/"+a)+"/", filepath.Clean("/"+b)+"/"
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
 // validatePipelineParameterVariables validates parameters with those specified by each pipeline task,
 // (1) it validates the type of parameter is either string or array (2) parameter default value matches
 // with the type of that param (3) ensures that the referenced param variable is defined is part of the param declarations
@@ -151,6 +264,22 @@ func validatePipelineParametersVariables(tasks []PipelineTask, prefix string, pa
 		errs = errs.Also(validatePipelineParametersVariablesInTaskParameters(task.Params, prefix, paramNames, arrayParamNames).ViaIndex(idx))
 		errs = errs.Also(validatePipelineParametersVariablesInMatrixParameters(task.Matrix, prefix, paramNames, arrayParamNames).ViaIndex(idx))
 		errs = errs.Also(task.WhenExpressions.validatePipelineParametersVariables(prefix, paramNames, arrayParamNames).ViaIndex(idx))
+		if task.TaskRef != nil {
+			errs = errs.Also(validatePipelineParametersVariablesInRef(task.TaskRef.Bundle, task.TaskRef.ResolverRef, prefix, paramNames, arrayParamNames).ViaField("taskRef").ViaIndex(idx))
+		}
+		if task.Cache != nil {
+			errs = errs.Also(validateStringVariable(task.Cache.Key, prefix, paramNames, arrayParamNames).ViaField("cache.key").ViaIndex(idx))
+		}
+	}
+	return errs
+}
+
+// validatePipelineParametersVariablesInRef validates that the params referenced in the bundle
+// and resolver parameters of a reference are declared string params.
+func validatePipelineParametersVariablesInRef(bundle string, ref ResolverRef, prefix string, paramNames sets.String, arrayParamNames sets.String) (errs *apis.FieldError) {
+	errs = errs.Also(validateStringVariable(bundle, prefix, paramNames, arrayParamNames).ViaField("bundle"))
+	for i, rp := range ref.Resource {
+		errs = errs.Also(validateStringVariable(rp.Value, prefix, paramNames, arrayParamNames).ViaField("value").ViaFieldIndex("resource", i))
 	}
 	return errs
 }
@@ -173,6 +302,12 @@ func validatePipelineContextVariables(tasks []PipelineTask) *apis.FieldError {
 			paramValues = append(paramValues, param.Value.StringVal)
 			paramValues = append(paramValues, param.Value.ArrayVal...)
 		}
+		if task.TaskRef != nil {
+			paramValues = append(paramValues, refValues(task.TaskRef.Bundle, task.TaskRef.ResolverRef)...)
+		}
+		if task.Cache != nil {
+			paramValues = append(paramValues, task.Cache.Key)
+		}
 	}
 	errs := validatePipelineContextVariablesInParamValues(paramValues, "context\\.pipelineRun", pipelineRunContextNames).
 		Also(validatePipelineContextVariablesInParamValues(paramValues, "context\\.pipeline", pipelineContextNames)).
@@ -180,6 +315,46 @@ func validatePipelineContextVariables(tasks []PipelineTask) *apis.FieldError {
 	return errs
 }
 
+// validateTaskRefVariables ensures that the bundle and resolver parameters of each pipeline task's
+// taskRef only reference params and context variables, since the referenced Task is resolved
+// before any other variable is known.
+func validateTaskRefVariables(tasks []PipelineTask) (errs *apis.FieldError) {
+	for idx, task := range tasks {
+		if task.TaskRef != nil {
+			errs = errs.Also(validateRefVariables(task.TaskRef.Bundle, task.TaskRef.ResolverRef).ViaField("taskRef").ViaIndex(idx))
+		}
+	}
+	return errs
+}
+
+// validateRefVariables returns errors for any variable in the bundle or resolver parameters of a
+// reference that is neither a param nor a context variable.
+func validateRefVariables(bundle string, ref ResolverRef) (errs *apis.FieldError) {
+	errs = errs.Also(validateRefVariable(bundle).ViaField("bundle"))
+	for i, rp := range ref.Resource {
+		errs = errs.Also(validateRefVariable(rp.Value).ViaField("value").ViaFieldIndex("resource", i))
+	}
+	return errs
+}
+
+func validateRefVariable(value string) (errs *apis.FieldError) {
+	for _, expression := range validateString(value) {
+		if !strings.HasPrefix(expression, "params.") && !strings.HasPrefix(expression, "params[") && !strings.HasPrefix(expression, "context.") {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("only params and context variables can be used in a reference, got $(%s)", expression), ""))
+		}
+	}
+	return errs
+}
+
+// refValues returns the values of a reference that are subject to variable substitution.
+func refValues(bundle string, ref ResolverRef) []string {
+	values := []string{bundle}
+	for _, rp := range ref.Resource {
+		values = append(values, rp.Value)
+	}
+	return values
+}
+
 func containsExecutionStatusRef(p string) bool {
 	if strings.HasPrefix(p, "tasks.") && strings.HasSuffix(p, ".status") {
 		return true
@@ -463,6 +638,20 @@ func validateMatrix(ctx context.Context, tasks []PipelineTask) (errs *apis.Field
 	return errs
 }
 
+func validateCache(ctx context.Context, tasks []PipelineTask) (errs *apis.FieldError) {
+	for idx, task := range tasks {
+		errs = errs.Also(task.validateCache(ctx).ViaIndex(idx))
+	}
+	return errs
+}
+
+func validatePodTemplates(ctx context.Context, tasks []PipelineTask) (errs *apis.FieldError) {
+	for idx, task := range tasks {
+		errs = errs.Also(task.validatePodTemplate(ctx).ViaIndex(idx))
+	}
+	return errs
+}
+
 func validateResultsFromMatrixedPipelineTasksNotConsumed(tasks []PipelineTask, finally []PipelineTask) (errs *apis.FieldError) {
 	matrixedPipelineTasks := sets.String{}
 	for _, pt := range tasks {
@@ -696,6 +696,63 @@ func TestPipelineSpec_Validate_Failure(t *testing.T) {
 	}
 }
 
+func TestValidateTaskRefVariables(t *testing.T) {
+	tests := []struct {
+		name          string
+		tasks         []PipelineTask
+		expectedError *apis.FieldError
+	}{{
+		name: "params and context variables in task reference",
+		tasks: []PipelineTask{{
+			Name: "foo",
+			TaskRef: &TaskRef{ResolverRef: ResolverRef{
+				Resolver: "git",
+				Resource: []ResolverParam{
+					{Name: "revision", Value: "$(params.revision)"},
+					{Name: "path", Value: "$(context.pipelineRun.namespace)/task.yaml"},
+				},
+			}},
+		}},
+	}, {
+		name: "params in bracket notation in task reference",
+		tasks: []PipelineTask{{
+			Name: "foo",
+			TaskRef: &TaskRef{ResolverRef: ResolverRef{
+				Resolver: "git",
+				Resource: []ResolverParam{
+					{Name: "revision", Value: `$(params["revision"])`},
+					{Name: "path", Value: "$(params['path'])"},
+				},
+			}},
+		}},
+	}, {
+		name: "task result in resolver parameter",
+		tasks: []PipelineTask{{
+			Name: "foo",
+			TaskRef: &TaskRef{ResolverRef: ResolverRef{
+				Resolver: "git",
+				Resource: []ResolverParam{{Name: "revision", Value: "$(tasks.bar.results.sha)"}},
+			}},
+		}},
+		expectedError: apis.ErrInvalidValue("only params and context variables can be used in a reference, got $(tasks.bar.results.sha)", "[0].taskRef.resource[0].value"),
+	}, {
+		name: "workspace variable in bundle",
+		tasks: []PipelineTask{{
+			Name:    "foo",
+			TaskRef: &TaskRef{Name: "foo-task", Bundle: "registry.io/$(workspaces.source.path)"},
+		}},
+		expectedError: apis.ErrInvalidValue("only params and context variables can be used in a reference, got $(workspaces.source.path)", "[0].taskRef.bundle"),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTaskRefVariables(tt.tasks)
+			if d := cmp.Diff(tt.expectedError.Error(), err.Error()); d != "" {
+				t.Errorf("validateTaskRefVariables() errors diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
 func TestPipelineSpec_Validate_Failure_CycleDAG(t *testing.T) {
 	name := "invalid pipeline spec with DAG having cyclic dependency"
 	ps := &PipelineSpec{
@@ -1285,6 +1342,23 @@ func TestValidatePipelineParameterVariables_Success(t *testing.T) {
 				Name: "a-param", Value: ArrayOrString{Type: ParamTypeArray, ArrayVal: []string{"$(params.baz[*])", "and", "$(params.foo-is-baz[*])"}},
 			}},
 		}},
+	}, {
+		name: "valid string parameter variables in task reference",
+		params: []ParamSpec{{
+			Name: "revision", Type: ParamTypeString,
+		}, {
+			Name: "registry", Type: ParamTypeString,
+		}},
+		tasks: []PipelineTask{{
+			Name: "bar",
+			TaskRef: &TaskRef{ResolverRef: ResolverRef{
+				Resolver: "git",
+				Resource: []ResolverParam{{Name: "revision", Value: "$(params.revision)"}},
+			}},
+		}, {
+			Name:    "baz",
+			TaskRef: &TaskRef{Name: "baz-task", Bundle: "$(params.registry)/catalog:$(params.revision)"},
+		}},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1316,6 +1390,17 @@ func TestValidatePipelineParameterVariables_Failure(t *testing.T) {
 			Message: `non-existent variable in "$(params.does-not-exist)"`,
 			Paths:   []string{"[0].params[a-param]"},
 		},
+	}, {
+		name: "invalid pipeline task with a cache key using a parameter which is missing from the param declarations",
+		tasks: []PipelineTask{{
+			Name:    "foo",
+			TaskRef: &TaskRef{Name: "foo-task"},
+			Cache:   &PipelineTaskCache{Key: "$(params.does-not-exist)"},
+		}},
+		expectedError: apis.FieldError{
+			Message: `non-existent variable in "$(params.does-not-exist)"`,
+			Paths:   []string{"[0].cache.key"},
+		},
 	}, {
 		name: "invalid string parameter variables in when expression, missing input param from the param declarations",
 		tasks: []PipelineTask{{
@@ -1611,6 +1696,32 @@ func TestValidatePipelineParameterVariables_Failure(t *testing.T) {
 			Message: `non-existent variable in "$(params.does-not-exist)"`,
 			Paths:   []string{"[0].matrix[b-param].value[0]"},
 		},
+	}, {
+		name: "invalid task reference with a parameter which is missing from the param declarations",
+		tasks: []PipelineTask{{
+			Name: "foo",
+			TaskRef: &TaskRef{ResolverRef: ResolverRef{
+				Resolver: "git",
+				Resource: []ResolverParam{{Name: "revision", Value: "$(params.does-not-exist)"}},
+			}},
+		}},
+		expectedError: apis.FieldError{
+			Message: `non-existent variable in "$(params.does-not-exist)"`,
+			Paths:   []string{"[0].taskRef.resource[0].value"},
+		},
+	}, {
+		name: "invalid task reference with an array parameter",
+		params: []ParamSpec{{
+			Name: "revisions", Type: ParamTypeArray,
+		}},
+		tasks: []PipelineTask{{
+			Name:    "foo",
+			TaskRef: &TaskRef{Name: "foo-task", Bundle: "registry.io/catalog:$(params.revisions)"},
+		}},
+		expectedError: apis.FieldError{
+			Message: `variable type invalid in "registry.io/catalog:$(params.revisions)"`,
+			Paths:   []string{"[0].taskRef.bundle"},
+		},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1777,6 +1888,174 @@ func TestValidatePipelineWorkspacesUsage_Failure(t *testing.T) {
 	}
 }
 
+func TestValidatePipelineWorkspacesAccess_Success(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   []PipelineTask
+		finally []PipelineTask
+	}{{
+		name: "parallel readers",
+		tasks: []PipelineTask{{
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadOnly}},
+		}, {
+			Name: "bar", TaskRef: &TaskRef{Name: "bar"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "src", Workspace: "source", Access: WorkspaceAccessReadOnly}},
+		}},
+	}, {
+		name: "writer ordered before readers",
+		tasks: []PipelineTask{{
+			Name: "fetch", TaskRef: &TaskRef{Name: "fetch"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadWrite}},
+		}, {
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			RunAfter:   []string{"fetch"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadOnly}},
+		}, {
+			Name: "bar", TaskRef: &TaskRef{Name: "bar"},
+			RunAfter:   []string{"foo"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadWrite}},
+		}},
+	}, {
+		name: "parallel writers of distinct subPaths",
+		tasks: []PipelineTask{{
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", SubPath: "foo", Access: WorkspaceAccessReadWrite}},
+		}, {
+			Name: "bar", TaskRef: &TaskRef{Name: "bar"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", SubPath: "foobar", Access: WorkspaceAccessReadWrite}},
+		}},
+	}, {
+		name: "parallel writer without declared access",
+		tasks: []PipelineTask{{
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadWrite}},
+		}, {
+			Name: "bar", TaskRef: &TaskRef{Name: "bar"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source"}},
+		}},
+	}, {
+		name: "final task writing after the tasks",
+		tasks: []PipelineTask{{
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadWrite}},
+		}},
+		finally: []PipelineTask{{
+			Name: "cleanup", TaskRef: &TaskRef{Name: "cleanup"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadWrite}},
+		}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := config.ToContext(context.Background(), &config.Config{FeatureFlags: &config.FeatureFlags{EnableAPIFields: config.AlphaAPIFields}})
+			if errs := validatePipelineWorkspacesAccess(ctx, tt.tasks, tt.finally); errs != nil {
+				t.Errorf("Pipeline.validatePipelineWorkspacesAccess() returned error for valid pipeline workspaces: %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidatePipelineWorkspacesAccess_Failure(t *testing.T) {
+	tests := []struct {
+		name          string
+		tasks         []PipelineTask
+		finally       []PipelineTask
+		apiFields     string
+		expectedError apis.FieldError
+	}{{
+		name: "access requires alpha",
+		tasks: []PipelineTask{{
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadOnly}},
+		}},
+		apiFields: config.StableAPIFields,
+		expectedError: apis.FieldError{
+			Message: `workspace access requires "enable-api-fields" feature gate to be "alpha" but it is "stable"`,
+		},
+	}, {
+		name: "invalid access",
+		tasks: []PipelineTask{{
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: "writeOnly"}},
+		}},
+		expectedError: apis.FieldError{
+			Message: `invalid value: writeOnly`,
+			Paths:   []string{"tasks[0].workspaces[0].access"},
+		},
+	}, {
+		name: "matrixed writer",
+		tasks: []PipelineTask{{
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			Matrix:     []Param{{Name: "platform", Value: ArrayOrString{Type: ParamTypeArray, ArrayVal: []string{"linux", "mac"}}}},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadWrite}},
+		}},
+		expectedError: apis.FieldError{
+			Message: `invalid value: the TaskRuns of matrixed pipeline task "foo" run in parallel and cannot all write to workspace "source"`,
+			Paths:   []string{"tasks[0].workspaces[0].access"},
+		},
+	}, {
+		name: "parallel writers",
+		tasks: []PipelineTask{{
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadWrite}},
+		}, {
+			Name: "bar", TaskRef: &TaskRef{Name: "bar"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "src", Workspace: "source", Access: WorkspaceAccessReadWrite}},
+		}},
+		expectedError: apis.FieldError{
+			Message: `invalid value: pipeline tasks "foo" and "bar" may run in parallel but one of them writes to workspace "source" which both access, use runAfter to order them`,
+			Paths:   []string{"tasks[1].workspaces[0].access"},
+		},
+	}, {
+		name: "reader in parallel with a writer of a parent subPath",
+		tasks: []PipelineTask{{
+			Name: "fetch", TaskRef: &TaskRef{Name: "fetch"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadWrite}},
+		}, {
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			RunAfter:   []string{"fetch"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", SubPath: "foo/bar", Access: WorkspaceAccessReadOnly}},
+		}, {
+			Name: "bar", TaskRef: &TaskRef{Name: "bar"},
+			RunAfter:   []string{"fetch"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", SubPath: "foo", Access: WorkspaceAccessReadWrite}},
+		}},
+		expectedError: apis.FieldError{
+			Message: `invalid value: pipeline tasks "foo" and "bar" may run in parallel but one of them writes to workspace "source" which both access, use runAfter to order them`,
+			Paths:   []string{"tasks[2].workspaces[0].access"},
+		},
+	}, {
+		name: "parallel final writers",
+		finally: []PipelineTask{{
+			Name: "foo", TaskRef: &TaskRef{Name: "foo"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadOnly}},
+		}, {
+			Name: "bar", TaskRef: &TaskRef{Name: "bar"},
+			Workspaces: []WorkspacePipelineTaskBinding{{Name: "source", Access: WorkspaceAccessReadWrite}},
+		}},
+		expectedError: apis.FieldError{
+			Message: `invalid value: pipeline tasks "foo" and "bar" may run in parallel but one of them writes to workspace "source" which both access, use runAfter to order them`,
+			Paths:   []string{"finally[1].workspaces[0].access"},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiFields := tt.apiFields
+			if apiFields == "" {
+				apiFields = config.AlphaAPIFields
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{FeatureFlags: &config.FeatureFlags{EnableAPIFields: apiFields}})
+			errs := validatePipelineWorkspacesAccess(ctx, tt.tasks, tt.finally)
+			if errs == nil {
+				t.Fatalf("Pipeline.validatePipelineWorkspacesAccess() did not return error for invalid pipeline workspaces")
+			}
+			if d := cmp.Diff(tt.expectedError.Error(), errs.Error()); d != "" {
+				t.Errorf("PipelineSpec.validatePipelineWorkspacesAccess() errors diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
 func TestValidatePipelineWithFinalTasks_Success(t *testing.T) {
 	tests := []struct {
 		name string
@@ -18,9 +18,11 @@ package v1beta1
 
 import (
 	"context"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
@@ -56,7 +58,9 @@ func (ref *PipelineRef) validateInTreeRef(ctx context.Context) (errs *apis.Field
 		if ref.Bundle != "" && ref.Name == "" {
 			errs = errs.Also(apis.ErrMissingField("name"))
 		}
-		if ref.Bundle != "" {
+		// Bundles containing variables are only parsed once the variables have been
+		// substituted at runtime.
+		if ref.Bundle != "" && !strings.Contains(ref.Bundle, "$(") {
 			if _, err := name.ParseReference(ref.Bundle); err != nil {
 				errs = errs.Also(apis.ErrInvalidValue("invalid bundle reference", "bundle", err.Error()))
 			}
@@ -64,6 +68,9 @@ func (ref *PipelineRef) validateInTreeRef(ctx context.Context) (errs *apis.Field
 	} else if ref.Bundle != "" {
 		errs = errs.Also(apis.ErrDisallowedFields("bundle"))
 	}
+	if ref.Namespace != "" {
+		errs = errs.Also(ref.validateNamespace(ctx))
+	}
 	return
 }
 
@@ -83,6 +90,25 @@ func (ref *PipelineRef) validateAlphaRef(ctx context.Context) (errs *apis.FieldE
 		if ref.Bundle != "" {
 			errs = errs.Also(apis.ErrMultipleOneOf("bundle", "resolver"))
 		}
+		if ref.Namespace != "" {
+			errs = errs.Also(apis.ErrMultipleOneOf("namespace", "resolver"))
+		}
+	}
+	return
+}
+
+// validateNamespace returns errors if a cross-namespace reference is made
+// without the alpha feature gate or is combined with fields that do not
+// resolve from a namespace.
+func (ref *PipelineRef) validateNamespace(ctx context.Context) (errs *apis.FieldError) {
+	if err := ValidateEnabledAPIFields(ctx, "namespace", config.AlphaAPIFields); err != nil {
+		return errs.Also(err.ViaField("namespace"))
+	}
+	if ref.Bundle != "" {
+		errs = errs.Also(apis.ErrMultipleOneOf("namespace", "bundle"))
+	}
+	if verrs := validation.IsDNS1123Label(ref.Namespace); len(verrs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(verrs, ", "), "namespace"))
 	}
 	return
 }
@@ -226,6 +226,11 @@ type PipelineRunSpec struct {
 	// +optional
 	// +listType=atomic
 	TaskRunSpecs []PipelineTaskRunSpec `json:"taskRunSpecs,omitempty"`
+	// AffinityAssistantMode is how Affinity Assistants schedule the TaskRun pods
+	// of the PipelineRun. Defaults to the mode of the default Affinity Assistant
+	// pod template, or "workspace".
+	// +optional
+	AffinityAssistantMode AffinityAssistantMode `json:"affinityAssistantMode,omitempty"`
 }
 
 // TimeoutFields allows granular specification of pipeline, task, and finally timeouts
@@ -236,6 +241,8 @@ type TimeoutFields struct {
 	Tasks *metav1.Duration `json:"tasks,omitempty"`
 	// Finally sets the maximum allowed duration of this pipeline's finally
 	Finally *metav1.Duration `json:"finally,omitempty"`
+	// GracePeriod sets the time given to the TaskRuns of this pipeline to terminate gracefully when they time out
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
 }
 
 // PipelineRunSpecStatus defines the pipelinerun spec status the user can provide
@@ -275,6 +282,11 @@ type PipelineRef struct {
 	// Bundle url reference to a Tekton Bundle.
 	// +optional
 	Bundle string `json:"bundle,omitempty"`
+	// Namespace of the referent, when it differs from the namespace of
+	// the run. The referenced namespace must opt in to sharing. This
+	// field is only supported when the alpha feature gate is enabled.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 
 	// ResolverRef allows referencing a Pipeline in a remote location
 	// like a git repo. This field is only supported when the alpha
@@ -544,6 +556,7 @@ type PipelineTaskRunSpec struct {
 
 // GetTaskRunSpec returns the task specific spec for a given
 // PipelineTask if configured, otherwise it returns the PipelineRun's default.
+// The pod template of the PipelineTask is merged with the one of the PipelineRun.
 func (pr *PipelineRun) GetTaskRunSpec(pipelineTaskName string) PipelineTaskRunSpec {
 	s := PipelineTaskRunSpec{
 		PipelineTaskName:       pipelineTaskName,
@@ -553,7 +566,7 @@ func (pr *PipelineRun) GetTaskRunSpec(pipelineTaskName string) PipelineTaskRunSp
 	for _, task := range pr.Spec.TaskRunSpecs {
 		if task.PipelineTaskName == pipelineTaskName {
 			if task.TaskPodTemplate != nil {
-				s.TaskPodTemplate = task.TaskPodTemplate
+				s.TaskPodTemplate = MergePodTemplateWithDefault(task.TaskPodTemplate.DeepCopy(), pr.Spec.PodTemplate)
 			}
 			if task.TaskServiceAccountName != "" {
 				s.TaskServiceAccountName = task.TaskServiceAccountName
@@ -427,6 +427,33 @@ func TestPipelineRunGetPodSpecSABackcompatibility(t *testing.T) {
 	}
 }
 
+func TestPipelineRunGetTaskRunSpecMergesPodTemplate(t *testing.T) {
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "pr"},
+		Spec: v1beta1.PipelineRunSpec{
+			PodTemplate: &pod.Template{
+				SchedulerName: "scheduleTest",
+				NodeSelector:  map[string]string{"disktype": "ssd"},
+			},
+			PipelineRef: &v1beta1.PipelineRef{Name: "prs"},
+			TaskRunSpecs: []v1beta1.PipelineTaskRunSpec{{
+				PipelineTaskName: "taskNameOne",
+				TaskPodTemplate:  &pod.Template{SchedulerName: "scheduleTestOne"},
+			}},
+		},
+	}
+	want := &pod.Template{
+		SchedulerName: "scheduleTestOne",
+		NodeSelector:  map[string]string{"disktype": "ssd"},
+	}
+	if d := cmp.Diff(want, pr.GetTaskRunSpec("taskNameOne").TaskPodTemplate); d != "" {
+		t.Errorf("wrong task podtemplate %s", diff.PrintWantGot(d))
+	}
+	if pr.Spec.TaskRunSpecs[0].TaskPodTemplate.NodeSelector != nil {
+		t.Error("expected the pod template of the TaskRunSpecs not to be modified")
+	}
+}
+
 func TestPipelineRunGetPodSpec(t *testing.T) {
 	for _, tt := range []struct {
 		name                 string
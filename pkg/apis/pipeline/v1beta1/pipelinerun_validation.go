@@ -25,6 +25,7 @@ import (
 	apisconfig "github.com/tektoncd/pipeline/pkg/apis/config"
 	"github.com/tektoncd/pipeline/pkg/apis/validate"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/apis"
 )
 
@@ -58,6 +59,7 @@ func (ps *PipelineRunSpec) Validate(ctx context.Context) (errs *apis.FieldError)
 	// Validate PipelineRef if it's present
 	if ps.PipelineRef != nil {
 		errs = errs.Also(ps.PipelineRef.Validate(ctx).ViaField("pipelineRef"))
+		errs = errs.Also(validatePipelineRefVariables(ps.PipelineRef, ps.Params).ViaField("pipelineRef"))
 	}
 
 	// Validate PipelineSpec if it's present
@@ -89,6 +91,15 @@ func (ps *PipelineRunSpec) Validate(ctx context.Context) (errs *apis.FieldError)
 		// pipeline timeout should be a valid duration of at least 0.
 		errs = errs.Also(validateTimeoutDuration("pipeline", ps.Timeouts.Pipeline))
 
+		if ps.Timeouts.GracePeriod != nil {
+			errs = errs.Also(ValidateEnabledAPIFields(ctx, "timeouts.gracePeriod", config.AlphaAPIFields))
+			// grace period should be a valid duration of at least 1s, a shorter
+			// grace period would force delete the pods of the TaskRuns.
+			if ps.Timeouts.GracePeriod.Duration < time.Second {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s should be >= 1s", ps.Timeouts.GracePeriod.Duration.String()), "timeouts.gracePeriod"))
+			}
+		}
+
 		if ps.Timeouts.Pipeline != nil {
 			errs = errs.Also(ps.validatePipelineTimeout(ps.Timeouts.Pipeline.Duration, "should be <= pipeline duration"))
 		} else {
@@ -99,6 +110,13 @@ func (ps *PipelineRunSpec) Validate(ctx context.Context) (errs *apis.FieldError)
 
 	errs = errs.Also(validateSpecStatus(ps.Status))
 
+	if ps.AffinityAssistantMode != "" {
+		errs = errs.Also(ValidateEnabledAPIFields(ctx, "affinityAssistantMode", config.AlphaAPIFields))
+		if !ps.AffinityAssistantMode.IsValid() {
+			errs = errs.Also(apis.ErrInvalidValue(ps.AffinityAssistantMode, "affinityAssistantMode"))
+		}
+	}
+
 	if ps.Workspaces != nil {
 		wsNames := make(map[string]int)
 		for idx, ws := range ps.Workspaces {
@@ -117,6 +135,26 @@ func (ps *PipelineRunSpec) Validate(ctx context.Context) (errs *apis.FieldError)
 	return errs
 }
 
+// validatePipelineRefVariables ensures that the bundle and resolver parameters of a pipelineRef
+// only reference params provided by the PipelineRun and the PipelineRun's context variables.
+func validatePipelineRefVariables(ref *PipelineRef, params []Param) (errs *apis.FieldError) {
+	paramNames := sets.NewString()
+	arrayParamNames := sets.NewString()
+	for _, p := range params {
+		paramNames.Insert(p.Name)
+		if p.Value.Type == ParamTypeArray {
+			arrayParamNames.Insert(p.Name)
+		}
+	}
+	errs = errs.Also(validateRefVariables(ref.Bundle, ref.ResolverRef))
+	errs = errs.Also(validatePipelineParametersVariablesInRef(ref.Bundle, ref.ResolverRef, "params", paramNames, arrayParamNames))
+	// Only the PipelineRun context is known before the Pipeline has been resolved.
+	values := refValues(ref.Bundle, ref.ResolverRef)
+	return errs.Also(validatePipelineContextVariablesInParamValues(values, "context\\.pipelineRun", sets.NewString("name", "namespace", "uid"))).
+		Also(validatePipelineContextVariablesInParamValues(values, "context\\.pipeline", sets.NewString())).
+		Also(validatePipelineContextVariablesInParamValues(values, "context\\.pipelineTask", sets.NewString()))
+}
+
 func validateSpecStatus(status PipelineRunSpecStatus) *apis.FieldError {
 	switch status {
 	case "":
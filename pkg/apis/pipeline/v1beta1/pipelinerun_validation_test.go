@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/test/diff"
 	corev1 "k8s.io/api/core/v1"
@@ -375,6 +376,27 @@ func TestPipelineRun_Validate(t *testing.T) {
 			},
 		},
 		wc: enableAlphaAPIFields,
+	}, {
+		name: "alpha feature: params and context variables in pipelineRef resolver parameters",
+		pr: v1beta1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pr",
+			},
+			Spec: v1beta1.PipelineRunSpec{
+				PipelineRef: &v1beta1.PipelineRef{ResolverRef: v1beta1.ResolverRef{
+					Resolver: "git",
+					Resource: []v1beta1.ResolverParam{
+						{Name: "revision", Value: "$(params.branch)"},
+						{Name: "path", Value: "$(context.pipelineRun.namespace)/pipeline.yaml"},
+					},
+				}},
+				Params: []v1beta1.Param{{
+					Name:  "branch",
+					Value: *v1beta1.NewArrayOrString("main"),
+				}},
+			},
+		},
+		wc: enableAlphaAPIFields,
 	}}
 
 	for _, ts := range tests {
@@ -449,8 +471,11 @@ func TestPipelineRunSpec_Invalidate(t *testing.T) {
 			Message: "expected exactly one, got neither",
 			Paths: []string{
 				"workspaces[0].configmap",
+				"workspaces[0].csi",
 				"workspaces[0].emptydir",
+				"workspaces[0].ephemeral",
 				"workspaces[0].persistentvolumeclaim",
+				"workspaces[0].projected",
 				"workspaces[0].secret",
 				"workspaces[0].volumeclaimtemplate",
 			},
@@ -512,6 +537,57 @@ func TestPipelineRunSpec_Invalidate(t *testing.T) {
 		},
 		wantErr:     apis.ErrMultipleOneOf("bundle", "resolver").ViaField("pipelineRef"),
 		withContext: enableAlphaAPIFields,
+	}, {
+		name: "pipelineref namespace disallowed without alpha feature gate",
+		spec: v1beta1.PipelineRunSpec{
+			PipelineRef: &v1beta1.PipelineRef{
+				Name:      "foo",
+				Namespace: "catalog",
+			},
+		},
+		wantErr: apis.ErrGeneric("namespace requires \"enable-api-fields\" feature gate to be \"alpha\" but it is \"stable\""),
+	}, {
+		name: "pipelineref namespace disallowed in conjunction with pipelineref resolver",
+		spec: v1beta1.PipelineRunSpec{
+			PipelineRef: &v1beta1.PipelineRef{
+				Namespace: "catalog",
+				ResolverRef: v1beta1.ResolverRef{
+					Resolver: "git",
+				},
+			},
+		},
+		wantErr:     apis.ErrMultipleOneOf("namespace", "resolver").ViaField("pipelineRef"),
+		withContext: enableAlphaAPIFields,
+	}, {
+		name: "pipelineref resolver parameter references a param the pipelinerun does not provide",
+		spec: v1beta1.PipelineRunSpec{
+			PipelineRef: &v1beta1.PipelineRef{
+				ResolverRef: v1beta1.ResolverRef{
+					Resolver: "git",
+					Resource: []v1beta1.ResolverParam{{Name: "revision", Value: "$(params.branch)"}},
+				},
+			},
+		},
+		wantErr: &apis.FieldError{
+			Message: `non-existent variable in "$(params.branch)"`,
+			Paths:   []string{"pipelineRef.resource[0].value"},
+		},
+		withContext: enableAlphaAPIFields,
+	}, {
+		name: "pipelineref resolver parameter references the pipeline context",
+		spec: v1beta1.PipelineRunSpec{
+			PipelineRef: &v1beta1.PipelineRef{
+				ResolverRef: v1beta1.ResolverRef{
+					Resolver: "git",
+					Resource: []v1beta1.ResolverParam{{Name: "path", Value: "$(context.pipeline.name).yaml"}},
+				},
+			},
+		},
+		wantErr: &apis.FieldError{
+			Message: `non-existent variable in "$(context.pipeline.name).yaml"`,
+			Paths:   []string{"pipelineRef.value"},
+		},
+		withContext: enableAlphaAPIFields,
 	}, {
 		name: "duplicate stepOverride names",
 		spec: v1beta1.PipelineRunSpec{
@@ -610,6 +686,21 @@ func TestPipelineRunSpec_Invalidate(t *testing.T) {
 		},
 		wantErr:     apis.ErrMissingField("taskRunSpecs[0].sidecarOverrides[0].name"),
 		withContext: enableAlphaAPIFields,
+	}, {
+		name: "affinityAssistantMode disallowed without alpha feature gate",
+		spec: v1beta1.PipelineRunSpec{
+			PipelineRef:           &v1beta1.PipelineRef{Name: "foo"},
+			AffinityAssistantMode: pod.AffinityAssistantPerPipelineRun,
+		},
+		wantErr: apis.ErrGeneric(`affinityAssistantMode requires "enable-api-fields" feature gate to be "alpha" but it is "stable"`),
+	}, {
+		name: "invalid affinityAssistantMode",
+		spec: v1beta1.PipelineRunSpec{
+			PipelineRef:           &v1beta1.PipelineRef{Name: "foo"},
+			AffinityAssistantMode: "node",
+		},
+		wantErr:     apis.ErrInvalidValue("node", "affinityAssistantMode"),
+		withContext: enableAlphaAPIFields,
 	}}
 	for _, ps := range tests {
 		t.Run(ps.name, func(t *testing.T) {
@@ -627,8 +718,9 @@ func TestPipelineRunSpec_Invalidate(t *testing.T) {
 
 func TestPipelineRunSpec_Validate(t *testing.T) {
 	tests := []struct {
-		name string
-		spec v1beta1.PipelineRunSpec
+		name        string
+		spec        v1beta1.PipelineRunSpec
+		withContext func(context.Context) context.Context
 	}{{
 		name: "PipelineRun without pipelineRef",
 		spec: v1beta1.PipelineRunSpec{
@@ -641,10 +733,21 @@ func TestPipelineRunSpec_Validate(t *testing.T) {
 				}},
 			},
 		},
+	}, {
+		name: "PipelineRun with affinityAssistantMode",
+		spec: v1beta1.PipelineRunSpec{
+			PipelineRef:           &v1beta1.PipelineRef{Name: "foo"},
+			AffinityAssistantMode: pod.AffinityAssistantIsolatePipelineRun,
+		},
+		withContext: enableAlphaAPIFields,
 	}}
 	for _, ps := range tests {
 		t.Run(ps.name, func(t *testing.T) {
-			if err := ps.spec.Validate(context.Background()); err != nil {
+			ctx := context.Background()
+			if ps.withContext != nil {
+				ctx = ps.withContext(ctx)
+			}
+			if err := ps.spec.Validate(ctx); err != nil {
 				t.Error(err)
 			}
 		})
@@ -704,6 +807,40 @@ func TestPipelineRun_InvalidTimeouts(t *testing.T) {
 			},
 		},
 		want: apis.ErrInvalidValue("-48h0m0s should be >= 0", "spec.timeouts.finally"),
+	}, {
+		name: "negative pipeline gracePeriod",
+		pr: v1beta1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pipelinelinename",
+			},
+			Spec: v1beta1.PipelineRunSpec{
+				PipelineRef: &v1beta1.PipelineRef{
+					Name: "prname",
+				},
+				Timeouts: &v1beta1.TimeoutFields{
+					GracePeriod: &metav1.Duration{Duration: -48 * time.Hour},
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("-48h0m0s should be >= 1s", "spec.timeouts.gracePeriod").Also(
+			apis.ErrGeneric(`timeouts.gracePeriod requires "enable-api-fields" feature gate to be "alpha" but it is "stable"`)),
+	}, {
+		name: "pipeline gracePeriod under a second",
+		pr: v1beta1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pipelinelinename",
+			},
+			Spec: v1beta1.PipelineRunSpec{
+				PipelineRef: &v1beta1.PipelineRef{
+					Name: "prname",
+				},
+				Timeouts: &v1beta1.TimeoutFields{
+					GracePeriod: &metav1.Duration{Duration: 500 * time.Millisecond},
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("500ms should be >= 1s", "spec.timeouts.gracePeriod").Also(
+			apis.ErrGeneric(`timeouts.gracePeriod requires "enable-api-fields" feature gate to be "alpha" but it is "stable"`)),
 	}, {
 		name: "pipeline tasks Timeout > pipeline Timeout",
 		pr: v1beta1.PipelineRun{
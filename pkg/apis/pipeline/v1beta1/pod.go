@@ -16,14 +16,19 @@ limitations under the License.
 
 package v1beta1
 
-import "github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+import (
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+	corev1 "k8s.io/api/core/v1"
+)
 
 // PodTemplate holds pod specific configuration
 type PodTemplate = pod.Template
 
 // MergePodTemplateWithDefault merges 2 PodTemplates together. If the same
 // field is set on both templates, the value from tpl will overwrite the value
-// from defaultTpl.
+// from defaultTpl. The environment variables, volumes and image pull secrets
+// are merged by name and the tolerations by key and effect, so that setting one
+// of them in tpl does not drop the ones of defaultTpl.
 func MergePodTemplateWithDefault(tpl, defaultTpl *PodTemplate) *PodTemplate {
 	switch {
 	case defaultTpl == nil:
@@ -34,21 +39,18 @@ func MergePodTemplateWithDefault(tpl, defaultTpl *PodTemplate) *PodTemplate {
 		return defaultTpl
 	default:
 		// Otherwise, merge fields
+		tpl.Env = mergeEnvs(tpl.Env, defaultTpl.Env)
 		if tpl.NodeSelector == nil {
 			tpl.NodeSelector = defaultTpl.NodeSelector
 		}
-		if tpl.Tolerations == nil {
-			tpl.Tolerations = defaultTpl.Tolerations
-		}
+		tpl.Tolerations = mergeTolerations(tpl.Tolerations, defaultTpl.Tolerations)
 		if tpl.Affinity == nil {
 			tpl.Affinity = defaultTpl.Affinity
 		}
 		if tpl.SecurityContext == nil {
 			tpl.SecurityContext = defaultTpl.SecurityContext
 		}
-		if tpl.Volumes == nil {
-			tpl.Volumes = defaultTpl.Volumes
-		}
+		tpl.Volumes = mergeVolumes(tpl.Volumes, defaultTpl.Volumes)
 		if tpl.RuntimeClassName == nil {
 			tpl.RuntimeClassName = defaultTpl.RuntimeClassName
 		}
@@ -70,9 +72,7 @@ func MergePodTemplateWithDefault(tpl, defaultTpl *PodTemplate) *PodTemplate {
 		if tpl.SchedulerName == "" {
 			tpl.SchedulerName = defaultTpl.SchedulerName
 		}
-		if tpl.ImagePullSecrets == nil {
-			tpl.ImagePullSecrets = defaultTpl.ImagePullSecrets
-		}
+		tpl.ImagePullSecrets = mergeImagePullSecrets(tpl.ImagePullSecrets, defaultTpl.ImagePullSecrets)
 		if tpl.HostAliases == nil {
 			tpl.HostAliases = defaultTpl.HostAliases
 		}
@@ -83,9 +83,105 @@ func MergePodTemplateWithDefault(tpl, defaultTpl *PodTemplate) *PodTemplate {
 	}
 }
 
+// mergeEnvs returns the environment variables of defaultEnvs that have no
+// environment variable of envs with the same name, followed by envs.
+func mergeEnvs(envs, defaultEnvs []corev1.EnvVar) []corev1.EnvVar {
+	if len(defaultEnvs) == 0 {
+		return envs
+	}
+	if len(envs) == 0 {
+		return defaultEnvs
+	}
+	names := map[string]bool{}
+	for _, e := range envs {
+		names[e.Name] = true
+	}
+	merged := make([]corev1.EnvVar, 0, len(envs)+len(defaultEnvs))
+	for _, e := range defaultEnvs {
+		if !names[e.Name] {
+			merged = append(merged, e)
+		}
+	}
+	return append(merged, envs...)
+}
+
+// mergeVolumes returns the volumes of defaultVolumes that have no volume of
+// volumes with the same name, followed by volumes.
+func mergeVolumes(volumes, defaultVolumes []corev1.Volume) []corev1.Volume {
+	if len(defaultVolumes) == 0 {
+		return volumes
+	}
+	if len(volumes) == 0 {
+		return defaultVolumes
+	}
+	names := map[string]bool{}
+	for _, v := range volumes {
+		names[v.Name] = true
+	}
+	merged := make([]corev1.Volume, 0, len(volumes)+len(defaultVolumes))
+	for _, v := range defaultVolumes {
+		if !names[v.Name] {
+			merged = append(merged, v)
+		}
+	}
+	return append(merged, volumes...)
+}
+
+// mergeImagePullSecrets returns the secrets of defaultSecrets that have no
+// secret of secrets with the same name, followed by secrets.
+func mergeImagePullSecrets(secrets, defaultSecrets []corev1.LocalObjectReference) []corev1.LocalObjectReference {
+	if len(defaultSecrets) == 0 {
+		return secrets
+	}
+	if len(secrets) == 0 {
+		return defaultSecrets
+	}
+	names := map[string]bool{}
+	for _, s := range secrets {
+		names[s.Name] = true
+	}
+	merged := make([]corev1.LocalObjectReference, 0, len(secrets)+len(defaultSecrets))
+	for _, s := range defaultSecrets {
+		if !names[s.Name] {
+			merged = append(merged, s)
+		}
+	}
+	return append(merged, secrets...)
+}
+
+// mergeTolerations returns tolerations followed by the tolerations of
+// defaultTolerations that have no toleration of tolerations with the same key
+// and effect.
+func mergeTolerations(tolerations, defaultTolerations []corev1.Toleration) []corev1.Toleration {
+	if len(defaultTolerations) == 0 {
+		return tolerations
+	}
+	if len(tolerations) == 0 {
+		return defaultTolerations
+	}
+	merged := make([]corev1.Toleration, 0, len(tolerations)+len(defaultTolerations))
+	merged = append(merged, tolerations...)
+	for _, d := range defaultTolerations {
+		found := false
+		for _, t := range tolerations {
+			if t.Key == d.Key && t.Effect == d.Effect {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, d)
+		}
+	}
+	return merged
+}
+
 // AAPodTemplate holds pod specific configuration for the affinity-assistant
 type AAPodTemplate = pod.AffinityAssistantTemplate
 
+// AffinityAssistantMode is how Affinity Assistants schedule the TaskRun pods of a PipelineRun
+type AffinityAssistantMode = pod.AffinityAssistantMode
+
 // MergeAAPodTemplateWithDefault is the same as MergePodTemplateWithDefault but
 // for AffinityAssistantPodTemplates.
 func MergeAAPodTemplateWithDefault(tpl, defaultTpl *AAPodTemplate) *AAPodTemplate {
@@ -107,6 +203,9 @@ func MergeAAPodTemplateWithDefault(tpl, defaultTpl *AAPodTemplate) *AAPodTemplat
 		if tpl.ImagePullSecrets == nil {
 			tpl.ImagePullSecrets = defaultTpl.ImagePullSecrets
 		}
+		if tpl.Mode == "" {
+			tpl.Mode = defaultTpl.Mode
+		}
 		return tpl
 	}
 }
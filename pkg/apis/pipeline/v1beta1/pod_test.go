@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMergePodTemplateWithDefault(t *testing.T) {
+	priorityClassName := "high"
+	defaultPriorityClassName := "low"
+	newDefaultTpl := func() *PodTemplate {
+		return &PodTemplate{
+			NodeSelector: map[string]string{"pool": "general"},
+			Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "proxy:3128"}, {Name: "LOG_LEVEL", Value: "info"}},
+			Tolerations: []corev1.Toleration{{
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "ci",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+			Volumes: []corev1.Volume{{
+				Name:         "cache",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}},
+			ImagePullSecrets:  []corev1.LocalObjectReference{{Name: "registry"}},
+			PriorityClassName: &defaultPriorityClassName,
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		tpl        *PodTemplate
+		defaultTpl *PodTemplate
+		want       *PodTemplate
+	}{{
+		name: "no default",
+		tpl:  &PodTemplate{SchedulerName: "custom"},
+		want: &PodTemplate{SchedulerName: "custom"},
+	}, {
+		name:       "no template",
+		defaultTpl: newDefaultTpl(),
+		want:       newDefaultTpl(),
+	}, {
+		name: "lists are merged with the default",
+		tpl: &PodTemplate{
+			NodeSelector: map[string]string{"accelerator": "gpu"},
+			Env:          []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+			Tolerations: []corev1.Toleration{{
+				Key:      "nvidia.com/gpu",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+			Volumes: []corev1.Volume{{
+				Name:         "cache",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "cache"}},
+			}},
+			ImagePullSecrets:  []corev1.LocalObjectReference{{Name: "private-registry"}},
+			PriorityClassName: &priorityClassName,
+		},
+		defaultTpl: newDefaultTpl(),
+		want: &PodTemplate{
+			NodeSelector: map[string]string{"accelerator": "gpu"},
+			Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "proxy:3128"}, {Name: "LOG_LEVEL", Value: "debug"}},
+			Tolerations: []corev1.Toleration{{
+				Key:      "nvidia.com/gpu",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}, {
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "ci",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+			Volumes: []corev1.Volume{{
+				Name:         "cache",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "cache"}},
+			}},
+			ImagePullSecrets:  []corev1.LocalObjectReference{{Name: "registry"}, {Name: "private-registry"}},
+			PriorityClassName: &priorityClassName,
+		},
+	}, {
+		name: "toleration with the same key and effect overrides the default",
+		tpl: &PodTemplate{
+			Tolerations: []corev1.Toleration{{
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "release",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+		},
+		defaultTpl: newDefaultTpl(),
+		want: &PodTemplate{
+			NodeSelector: map[string]string{"pool": "general"},
+			Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "proxy:3128"}, {Name: "LOG_LEVEL", Value: "info"}},
+			Tolerations: []corev1.Toleration{{
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "release",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+			Volumes: []corev1.Volume{{
+				Name:         "cache",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}},
+			ImagePullSecrets:  []corev1.LocalObjectReference{{Name: "registry"}},
+			PriorityClassName: &defaultPriorityClassName,
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			defaultTpl := tc.defaultTpl.DeepCopy()
+			got := MergePodTemplateWithDefault(tc.tpl, tc.defaultTpl)
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("PodTemplate diff %s", diff.PrintWantGot(d))
+			}
+			if d := cmp.Diff(defaultTpl, tc.defaultTpl); d != "" {
+				t.Errorf("default PodTemplate was modified %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/substitution"
+)
+
+// refParamPatterns are the patterns of the variables a string param can be
+// referenced with in a TaskRef or PipelineRef.
+var refParamPatterns = []string{
+	"params.%s",
+	"params[%q]",
+	"params['%s']",
+}
+
+// AddRefParamReplacements adds the values of the string params to the
+// replacements applied on a TaskRef or PipelineRef.
+func AddRefParamReplacements(stringReplacements map[string]string, params []Param) {
+	for _, p := range params {
+		if p.Value.Type == ParamTypeString {
+			for _, pattern := range refParamPatterns {
+				stringReplacements[fmt.Sprintf(pattern, p.Name)] = p.Value.StringVal
+			}
+		}
+	}
+}
+
+// ApplyTaskRefReplacements applies variable interpolation on the bundle and
+// resolver parameters of a TaskRef.
+func ApplyTaskRefReplacements(ref *TaskRef, stringReplacements map[string]string) {
+	ref.Bundle = substitution.ApplyReplacements(ref.Bundle, stringReplacements)
+	applyResolverRefReplacements(&ref.ResolverRef, stringReplacements)
+}
+
+// ApplyPipelineRefReplacements applies variable interpolation on the bundle
+// and resolver parameters of a PipelineRef.
+func ApplyPipelineRefReplacements(ref *PipelineRef, stringReplacements map[string]string) {
+	ref.Bundle = substitution.ApplyReplacements(ref.Bundle, stringReplacements)
+	applyResolverRefReplacements(&ref.ResolverRef, stringReplacements)
+}
+
+func applyResolverRefReplacements(ref *ResolverRef, stringReplacements map[string]string) {
+	for i := range ref.Resource {
+		ref.Resource[i].Value = substitution.ApplyReplacements(ref.Resource[i].Value, stringReplacements)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/test/diff"
+)
+
+func TestApplyTaskRefReplacements(t *testing.T) {
+	replacements := map[string]string{
+		"params.branch":            "main",
+		"context.pipelineRun.name": "release-run",
+	}
+
+	ref := v1beta1.TaskRef{
+		Name:   "$(params.branch)",
+		Bundle: "registry.io/catalog:$(params.branch)",
+		ResolverRef: v1beta1.ResolverRef{
+			Resolver: "git",
+			Resource: []v1beta1.ResolverParam{{
+				Name:  "revision",
+				Value: "$(params.branch)",
+			}, {
+				Name:  "path",
+				Value: "tasks/$(context.pipelineRun.name).yaml",
+			}},
+		},
+	}
+
+	expected := v1beta1.TaskRef{
+		Name:   "$(params.branch)",
+		Bundle: "registry.io/catalog:main",
+		ResolverRef: v1beta1.ResolverRef{
+			Resolver: "git",
+			Resource: []v1beta1.ResolverParam{{
+				Name:  "revision",
+				Value: "main",
+			}, {
+				Name:  "path",
+				Value: "tasks/release-run.yaml",
+			}},
+		},
+	}
+	v1beta1.ApplyTaskRefReplacements(&ref, replacements)
+	if d := cmp.Diff(expected, ref); d != "" {
+		t.Errorf("TaskRef replacements failed: %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestApplyPipelineRefReplacements(t *testing.T) {
+	replacements := map[string]string{
+		"params.branch": "main",
+	}
+
+	ref := v1beta1.PipelineRef{
+		Bundle: "registry.io/pipelines:$(params.branch)",
+		ResolverRef: v1beta1.ResolverRef{
+			Resolver: "git",
+			Resource: []v1beta1.ResolverParam{{
+				Name:  "revision",
+				Value: "$(params.branch)",
+			}},
+		},
+	}
+
+	expected := v1beta1.PipelineRef{
+		Bundle: "registry.io/pipelines:main",
+		ResolverRef: v1beta1.ResolverRef{
+			Resolver: "git",
+			Resource: []v1beta1.ResolverParam{{
+				Name:  "revision",
+				Value: "main",
+			}},
+		},
+	}
+	v1beta1.ApplyPipelineRefReplacements(&ref, replacements)
+	if d := cmp.Diff(expected, ref); d != "" {
+		t.Errorf("PipelineRef replacements failed: %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestAddRefParamReplacements(t *testing.T) {
+	replacements := map[string]string{"context.pipelineRun.name": "release-run"}
+	v1beta1.AddRefParamReplacements(replacements, []v1beta1.Param{{
+		Name:  "branch",
+		Value: *v1beta1.NewArrayOrString("main"),
+	}, {
+		Name:  "paths",
+		Value: *v1beta1.NewArrayOrString("a", "b"),
+	}})
+
+	expected := map[string]string{
+		"context.pipelineRun.name": "release-run",
+		"params.branch":            "main",
+		`params["branch"]`:         "main",
+		"params['branch']":         "main",
+	}
+	if d := cmp.Diff(expected, replacements); d != "" {
+		t.Errorf("AddRefParamReplacements() %s", diff.PrintWantGot(d))
+	}
+}
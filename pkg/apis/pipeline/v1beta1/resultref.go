@@ -191,5 +191,9 @@ func PipelineTaskResultRefs(pt *PipelineTask) []*ResultRef {
 		refs = append(refs, NewResultRefs(expressions)...)
 	}
 
+	if pt.Cache != nil {
+		refs = append(refs, NewResultRefs(validateString(pt.Cache.Key))...)
+	}
+
 	return refs
 }
@@ -652,6 +652,7 @@ func TestPipelineTaskResultRefs(t *testing.T) {
 				"$(tasks.pt4.results.r4)",
 			},
 		}},
+		Cache: &v1beta1.PipelineTaskCache{Key: "$(tasks.pt5.results.r5)"},
 	}
 	refs := v1beta1.PipelineTaskResultRefs(&pt)
 	expectedRefs := []*v1beta1.ResultRef{{
@@ -666,6 +667,9 @@ func TestPipelineTaskResultRefs(t *testing.T) {
 	}, {
 		PipelineTask: "pt4",
 		Result:       "r4",
+	}, {
+		PipelineTask: "pt5",
+		Result:       "r5",
 	}}
 	if d := cmp.Diff(refs, expectedRefs); d != "" {
 		t.Errorf("%v", d)
@@ -124,6 +124,12 @@ type TaskSpec struct {
 	// Results are values that this Task can output
 	// +listType=atomic
 	Results []TaskResult `json:"results,omitempty"`
+
+	// ComputeResources are the compute resources of the whole Task, spread
+	// across its steps instead of being specified on each step.
+	// This field is only supported when the alpha feature gate is enabled.
+	// +optional
+	ComputeResources *corev1.ResourceRequirements `json:"computeResources,omitempty"`
 }
 
 // TaskList contains a list of Task
@@ -148,6 +154,11 @@ type TaskRef struct {
 	// Bundle url reference to a Tekton Bundle.
 	// +optional
 	Bundle string `json:"bundle,omitempty"`
+	// Namespace of the referent, when it differs from the namespace of
+	// the run. The referenced namespace must opt in to sharing. This
+	// field is only supported when the alpha feature gate is enabled.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 
 	// ResolverRef allows referencing a Task in a remote location
 	// like a git repo. This field is only supported when the alpha
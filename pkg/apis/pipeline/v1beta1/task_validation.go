@@ -29,6 +29,7 @@ import (
 	"github.com/tektoncd/pipeline/pkg/list"
 	"github.com/tektoncd/pipeline/pkg/substitution"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
@@ -64,6 +65,8 @@ func (ts *TaskSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 	errs = errs.Also(ValidateVolumes(ts.Volumes).ViaField("volumes"))
 	errs = errs.Also(validateDeclaredWorkspaces(ts.Workspaces, ts.Steps, ts.StepTemplate).ViaField("workspaces"))
 	errs = errs.Also(validateWorkspaceUsages(ctx, ts))
+	// Step refs are validated before the steps are merged with the step template.
+	errs = errs.Also(validateStepRefs(ctx, ts.Steps).ViaField("steps"))
 	mergedSteps, err := MergeStepsWithStepTemplate(ts.StepTemplate, ts.Steps)
 	if err != nil {
 		errs = errs.Also(&apis.FieldError{
@@ -74,12 +77,34 @@ func (ts *TaskSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 	}
 
 	errs = errs.Also(validateSteps(ctx, mergedSteps).ViaField("steps"))
+	errs = errs.Also(validateSidecars(ctx, ts.Sidecars).ViaField("sidecars"))
+	errs = errs.Also(validateScriptEnvNames(ctx, ts))
 	errs = errs.Also(ts.Resources.Validate(ctx).ViaField("resources"))
 	errs = errs.Also(ValidateParameterTypes(ctx, ts.Params).ViaField("params"))
 	errs = errs.Also(ValidateParameterVariables(ctx, ts.Steps, ts.Params))
 	errs = errs.Also(ValidateResourcesVariables(ctx, ts.Steps, ts.Resources))
 	errs = errs.Also(validateTaskContextVariables(ctx, ts.Steps))
 	errs = errs.Also(validateResults(ctx, ts.Results).ViaField("results"))
+	if ts.ComputeResources != nil {
+		errs = errs.Also(ValidateEnabledAPIFields(ctx, "computeResources", config.AlphaAPIFields).ViaField("computeResources"))
+		errs = errs.Also(validateComputeResources(ts.ComputeResources).ViaField("computeResources"))
+		for i, step := range mergedSteps {
+			if len(step.Resources.Requests) > 0 || len(step.Resources.Limits) > 0 {
+				errs = errs.Also(apis.ErrMultipleOneOf("computeResources", fmt.Sprintf("steps[%d].resources", i)))
+			}
+		}
+	}
+	return errs
+}
+
+// validateComputeResources validates that the requests of the compute resources of a Task
+// are not larger than their limits.
+func validateComputeResources(computeResources *corev1.ResourceRequirements) (errs *apis.FieldError) {
+	for name, request := range computeResources.Requests {
+		if limit, ok := computeResources.Limits[name]; ok && limit.Cmp(request) < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s should be <= limit %s", request.String(), limit.String()), fmt.Sprintf("requests.%s", name)))
+		}
+	}
 	return errs
 }
 
@@ -186,8 +211,116 @@ func validateSteps(ctx context.Context, steps []Step) (errs *apis.FieldError) {
 	return errs
 }
 
+// paramReferenceInScript matches the references to params substituted inline in scripts.
+var paramReferenceInScript = regexp.MustCompile(`\$\(params(\.|\[)`)
+
+// envNameDisallowedChars matches the characters of param and result names
+// which can't be used in the name of a shell variable.
+var envNameDisallowedChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// ScriptEnvName returns the name used in the env vars exposing a param or a
+// result to scripts, e.g. "GIT_URL" for "git-url".
+func ScriptEnvName(name string) string {
+	return strings.ToUpper(envNameDisallowedChars.ReplaceAllString(name, "_"))
+}
+
+// validateScriptEnvNames rejects the params, and the results, which would be
+// exposed to scripts by the same env var, e.g. "git-url" and "git_url".
+func validateScriptEnvNames(ctx context.Context, ts *TaskSpec) (errs *apis.FieldError) {
+	if !config.FromContextOrDefaults(ctx).FeatureFlags.EnableParamsAsEnvInScripts {
+		return nil
+	}
+	params := map[string]string{}
+	for idx, p := range ts.Params {
+		env := "PARAMS_" + ScriptEnvName(p.Name)
+		if other, ok := params[env]; ok && other != p.Name {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("params %q and %q are both exposed to scripts as %s", other, p.Name, env), "name").ViaFieldIndex("params", idx))
+			continue
+		}
+		params[env] = p.Name
+	}
+	results := map[string]string{}
+	for idx, r := range ts.Results {
+		env := "RESULTS_" + ScriptEnvName(r.Name) + "_PATH"
+		if other, ok := results[env]; ok && other != r.Name {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("results %q and %q are both exposed to scripts as %s", other, r.Name, env), "name").ViaFieldIndex("results", idx))
+			continue
+		}
+		results[env] = r.Name
+	}
+	return errs
+}
+
+// ParamsInScriptsWarnings returns a warning for each step or sidecar whose
+// script substitutes params inline, which can inject code in the script, when
+// params can be read from env vars instead. The warnings don't reject the
+// TaskSpec, so they aren't returned by Validate: the webhook returns them to
+// the client as admission warnings.
+func (ts *TaskSpec) ParamsInScriptsWarnings(ctx context.Context) (warnings *apis.FieldError) {
+	if !config.FromContextOrDefaults(ctx).FeatureFlags.EnableParamsAsEnvInScripts {
+		return nil
+	}
+	const message = "script substitutes params inline, read them from the PARAMS_<NAME> env vars instead"
+	for idx, s := range ts.Steps {
+		if paramReferenceInScript.MatchString(s.Script) {
+			warnings = warnings.Also(apis.ErrGeneric(message, "script").ViaFieldIndex("steps", idx))
+		}
+	}
+	for idx, s := range ts.Sidecars {
+		if paramReferenceInScript.MatchString(s.Script) {
+			warnings = warnings.Also(apis.ErrGeneric(message, "script").ViaFieldIndex("sidecars", idx))
+		}
+	}
+	return warnings
+}
+
+// validateStepRefs validates the Steps referencing a Step of another Task,
+// which can only set their name along with the reference.
+func validateStepRefs(ctx context.Context, steps []Step) (errs *apis.FieldError) {
+	for idx, s := range steps {
+		if s.Ref == nil {
+			continue
+		}
+		errs = errs.Also(ValidateEnabledAPIFields(ctx, "step ref", config.AlphaAPIFields).ViaField("ref").ViaIndex(idx))
+		if s.Ref.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("name").ViaField("ref").ViaIndex(idx))
+		}
+		if s.Ref.TaskRef == nil {
+			errs = errs.Also(apis.ErrMissingField("taskRef").ViaField("ref").ViaIndex(idx))
+		} else {
+			errs = errs.Also(s.Ref.TaskRef.Validate(ctx).ViaField("taskRef").ViaField("ref").ViaIndex(idx))
+		}
+		if !equality.Semantic.DeepEqual(s, Step{Name: s.Name, Ref: s.Ref}) {
+			errs = errs.Also(apis.ErrGeneric("a Step with a ref can only set its name along with it", "ref").ViaIndex(idx))
+		}
+	}
+	return errs
+}
+
+// validateSidecars validates the alpha readinessGate and gracePeriod fields of the sidecars,
+// which can't have a gracePeriod when they are run as native sidecar containers.
+func validateSidecars(ctx context.Context, sidecars []Sidecar) (errs *apis.FieldError) {
+	for idx, s := range sidecars {
+		if s.ReadinessGate != nil {
+			errs = errs.Also(ValidateEnabledAPIFields(ctx, "sidecar readinessGate", config.AlphaAPIFields).ViaField("readinessGate").ViaIndex(idx))
+		}
+		if s.GracePeriod != nil {
+			errs = errs.Also(ValidateEnabledAPIFields(ctx, "sidecar gracePeriod", config.AlphaAPIFields).ViaField("gracePeriod").ViaIndex(idx))
+			if s.GracePeriod.Duration < time.Duration(0) {
+				errs = errs.Also(apis.ErrInvalidValue(s.GracePeriod.Duration, "negative gracePeriod").ViaIndex(idx))
+			}
+			// Native sidecar containers are stopped by the kubelet within the grace period of the pod.
+			if config.FromContextOrDefaults(ctx).FeatureFlags.EnableNativeSidecars {
+				errs = errs.Also(apis.ErrGeneric("gracePeriod can't be set when \"enable-native-sidecars\" is \"true\"", "gracePeriod").ViaIndex(idx))
+			}
+		}
+	}
+	return errs
+}
+
 func validateStep(ctx context.Context, s Step, names sets.String) (errs *apis.FieldError) {
-	if s.Image == "" {
+	// The image of a Step with a ref comes from the referenced Step.
+	if s.Image == "" && s.Ref == nil {
 		errs = errs.Also(apis.ErrMissingField("Image"))
 	}
 
@@ -220,6 +353,17 @@ func validateStep(ctx context.Context, s Step, names sets.String) (errs *apis.Fi
 		}
 	}
 
+	if s.OnCancel != "" {
+		errs = errs.Also(ValidateEnabledAPIFields(ctx, "step onCancel", config.AlphaAPIFields).ViaField("onCancel"))
+	}
+
+	if s.GracePeriod != nil {
+		errs = errs.Also(ValidateEnabledAPIFields(ctx, "step gracePeriod", config.AlphaAPIFields).ViaField("gracePeriod"))
+		if s.GracePeriod.Duration < time.Duration(0) {
+			errs = errs.Also(apis.ErrInvalidValue(s.GracePeriod.Duration, "negative gracePeriod"))
+		}
+	}
+
 	for j, vm := range s.VolumeMounts {
 		if strings.HasPrefix(vm.MountPath, "/tekton/") &&
 			!strings.HasPrefix(vm.MountPath, "/tekton/home") {
@@ -342,6 +486,24 @@ func ValidateParameterVariables(ctx context.Context, steps []Step, params []Para
 	return errs.Also(validateObjectUsage(ctx, steps, objectParamSpecs))
 }
 
+// ValidateResolvedStepRefs validates the variables used by the Steps of a TaskSpec once the Steps
+// referencing the Steps of other Tasks were inlined: the params, results and workspaces they use
+// must be declared by the TaskSpec.
+func ValidateResolvedStepRefs(ctx context.Context, ts *TaskSpec) *apis.FieldError {
+	resultNames := sets.NewString()
+	for _, r := range ts.Results {
+		resultNames.Insert(r.Name)
+	}
+	workspaceNames := sets.NewString()
+	for _, w := range ts.Workspaces {
+		workspaceNames.Insert(w.Name)
+	}
+	errs := ValidateParameterVariables(ctx, ts.Steps, ts.Params)
+	errs = errs.Also(validateVariables(ctx, ts.Steps, "results", resultNames))
+	errs = errs.Also(validateVariables(ctx, ts.Steps, "workspaces", workspaceNames))
+	return errs.Also(validateWorkspaceUsages(ctx, ts))
+}
+
 func validateTaskContextVariables(ctx context.Context, steps []Step) *apis.FieldError {
 	taskRunContextNames := sets.NewString().Insert(
 		"name",
@@ -28,6 +28,7 @@ import (
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/test/diff"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 )
@@ -84,12 +85,13 @@ func TestTaskValidate(t *testing.T) {
 
 func TestTaskSpecValidate(t *testing.T) {
 	type fields struct {
-		Params       []v1beta1.ParamSpec
-		Resources    *v1beta1.TaskResources
-		Steps        []v1beta1.Step
-		StepTemplate *v1beta1.StepTemplate
-		Workspaces   []v1beta1.WorkspaceDeclaration
-		Results      []v1beta1.TaskResult
+		Params           []v1beta1.ParamSpec
+		Resources        *v1beta1.TaskResources
+		Steps            []v1beta1.Step
+		StepTemplate     *v1beta1.StepTemplate
+		Workspaces       []v1beta1.WorkspaceDeclaration
+		Results          []v1beta1.TaskResult
+		ComputeResources *corev1.ResourceRequirements
 	}
 	tests := []struct {
 		name   string
@@ -416,16 +418,45 @@ func TestTaskSpecValidate(t *testing.T) {
 				hello "$(context.taskRun.namespace)"`,
 			}},
 		},
+	}, {
+		name: "compute resources",
+		fields: fields{
+			Steps: []v1beta1.Step{{
+				Image: "my-image",
+				Args:  []string{"arg"},
+			}},
+			ComputeResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+		},
+	}, {
+		name: "step ref",
+		fields: fields{
+			Steps: []v1beta1.Step{{
+				Name: "clone",
+				Ref: &v1beta1.StepRef{
+					Name:    "clone",
+					TaskRef: &v1beta1.TaskRef{Name: "git-clone"},
+				},
+			}, {
+				Image: "my-image",
+			}},
+			StepTemplate: &v1beta1.StepTemplate{
+				Image: "template-image",
+			},
+		},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ts := &v1beta1.TaskSpec{
-				Params:       tt.fields.Params,
-				Resources:    tt.fields.Resources,
-				Steps:        tt.fields.Steps,
-				StepTemplate: tt.fields.StepTemplate,
-				Workspaces:   tt.fields.Workspaces,
-				Results:      tt.fields.Results,
+				Params:           tt.fields.Params,
+				Resources:        tt.fields.Resources,
+				Steps:            tt.fields.Steps,
+				StepTemplate:     tt.fields.StepTemplate,
+				Workspaces:       tt.fields.Workspaces,
+				Results:          tt.fields.Results,
+				ComputeResources: tt.fields.ComputeResources,
 			}
 			ctx := getContextBasedOnFeatureFlag("alpha")
 			ts.SetDefaults(ctx)
@@ -438,13 +469,15 @@ func TestTaskSpecValidate(t *testing.T) {
 
 func TestTaskSpecValidateError(t *testing.T) {
 	type fields struct {
-		Params       []v1beta1.ParamSpec
-		Resources    *v1beta1.TaskResources
-		Steps        []v1beta1.Step
-		Volumes      []corev1.Volume
-		StepTemplate *v1beta1.StepTemplate
-		Workspaces   []v1beta1.WorkspaceDeclaration
-		Results      []v1beta1.TaskResult
+		Params           []v1beta1.ParamSpec
+		Resources        *v1beta1.TaskResources
+		Steps            []v1beta1.Step
+		Volumes          []corev1.Volume
+		StepTemplate     *v1beta1.StepTemplate
+		Workspaces       []v1beta1.WorkspaceDeclaration
+		Results          []v1beta1.TaskResult
+		ComputeResources *corev1.ResourceRequirements
+		Sidecars         []v1beta1.Sidecar
 	}
 	tests := []struct {
 		name          string
@@ -1267,17 +1300,118 @@ func TestTaskSpecValidateError(t *testing.T) {
 			Message: "invalid value: -10s",
 			Paths:   []string{"steps[0].negative timeout"},
 		},
+	}, {
+		name: "negative grace period",
+		fields: fields{
+			Steps: []v1beta1.Step{{
+				Image:       "my-image",
+				GracePeriod: &metav1.Duration{Duration: -10 * time.Second},
+			}},
+		},
+		expectedError: apis.FieldError{
+			Message: "invalid value: -10s",
+			Paths:   []string{"steps[0].negative gracePeriod"},
+		},
+	}, {
+		name: "step ref with other fields",
+		fields: fields{
+			Steps: []v1beta1.Step{{
+				Image: "my-image",
+				Ref: &v1beta1.StepRef{
+					Name:    "clone",
+					TaskRef: &v1beta1.TaskRef{Name: "git-clone"},
+				},
+			}},
+		},
+		expectedError: apis.FieldError{
+			Message: "a Step with a ref can only set its name along with it",
+			Paths:   []string{"steps[0].ref"},
+		},
+	}, {
+		name: "step ref without step name and task",
+		fields: fields{
+			Steps: []v1beta1.Step{{
+				Ref: &v1beta1.StepRef{},
+			}},
+		},
+		expectedError: apis.FieldError{
+			Message: "missing field(s)",
+			Paths:   []string{"steps[0].ref.name", "steps[0].ref.taskRef"},
+		},
+	}, {
+		name: "step ref without task name",
+		fields: fields{
+			Steps: []v1beta1.Step{{
+				Ref: &v1beta1.StepRef{
+					Name:    "clone",
+					TaskRef: &v1beta1.TaskRef{},
+				},
+			}},
+		},
+		expectedError: apis.FieldError{
+			Message: "missing field(s)",
+			Paths:   []string{"steps[0].ref.taskRef.name"},
+		},
+	}, {
+		name: "negative sidecar grace period",
+		fields: fields{
+			Steps: validSteps,
+			Sidecars: []v1beta1.Sidecar{{
+				Image:       "my-image",
+				GracePeriod: &metav1.Duration{Duration: -10 * time.Second},
+			}},
+		},
+		expectedError: apis.FieldError{
+			Message: "invalid value: -10s",
+			Paths:   []string{"sidecars[0].negative gracePeriod"},
+		},
+	}, {
+		name: "compute resources request larger than limit",
+		fields: fields{
+			Steps: []v1beta1.Step{{
+				Image: "my-image",
+			}},
+			ComputeResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+		expectedError: apis.FieldError{
+			Message: "invalid value: 2 should be <= limit 1",
+			Paths:   []string{"computeResources.requests.cpu"},
+		},
+	}, {
+		name: "compute resources and step resources",
+		fields: fields{
+			Steps: []v1beta1.Step{{
+				Image: "my-image",
+			}},
+			StepTemplate: &v1beta1.StepTemplate{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+			},
+			ComputeResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			},
+		},
+		expectedError: apis.FieldError{
+			Message: "expected exactly one, got both",
+			Paths:   []string{"computeResources", "steps[0].resources"},
+		},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ts := &v1beta1.TaskSpec{
-				Params:       tt.fields.Params,
-				Resources:    tt.fields.Resources,
-				Steps:        tt.fields.Steps,
-				Volumes:      tt.fields.Volumes,
-				StepTemplate: tt.fields.StepTemplate,
-				Workspaces:   tt.fields.Workspaces,
-				Results:      tt.fields.Results,
+				Params:           tt.fields.Params,
+				Resources:        tt.fields.Resources,
+				Steps:            tt.fields.Steps,
+				Volumes:          tt.fields.Volumes,
+				StepTemplate:     tt.fields.StepTemplate,
+				Workspaces:       tt.fields.Workspaces,
+				Results:          tt.fields.Results,
+				ComputeResources: tt.fields.ComputeResources,
+				Sidecars:         tt.fields.Sidecars,
 			}
 			ctx := getContextBasedOnFeatureFlag("alpha")
 			ts.SetDefaults(ctx)
@@ -1397,6 +1531,75 @@ func TestStepAndSidecarWorkspacesErrors(t *testing.T) {
 	}
 }
 
+func TestValidateResolvedStepRefs(t *testing.T) {
+	declared := v1beta1.TaskSpec{
+		Params:     []v1beta1.ParamSpec{{Name: "url", Type: v1beta1.ParamTypeString}},
+		Results:    []v1beta1.TaskResult{{Name: "commit"}},
+		Workspaces: []v1beta1.WorkspaceDeclaration{{Name: "source"}},
+	}
+	tests := []struct {
+		name          string
+		step          v1beta1.Step
+		expectedError *apis.FieldError
+	}{{
+		name: "declared variables",
+		step: v1beta1.Step{
+			Image:  "git",
+			Args:   []string{"$(params.url)"},
+			Script: "git -C $(workspaces.source.path) rev-parse HEAD > $(results.commit.path)",
+		},
+	}, {
+		name: "undeclared param",
+		step: v1beta1.Step{
+			Image: "git",
+			Args:  []string{"$(params.revision)"},
+		},
+		expectedError: &apis.FieldError{
+			Message: `non-existent variable in "$(params.revision)"`,
+			Paths:   []string{"steps[0].args[0]"},
+		},
+	}, {
+		name: "undeclared result",
+		step: v1beta1.Step{
+			Image:  "git",
+			Script: "git rev-parse HEAD > $(results.sha.path)",
+		},
+		expectedError: &apis.FieldError{
+			Message: `non-existent variable in "git rev-parse HEAD > $(results.sha.path)"`,
+			Paths:   []string{"steps[0].script"},
+		},
+	}, {
+		name: "undeclared workspace",
+		step: v1beta1.Step{
+			Image:      "git",
+			WorkingDir: "$(workspaces.output.path)",
+		},
+		expectedError: &apis.FieldError{
+			Message: `non-existent variable in "$(workspaces.output.path)"`,
+			Paths:   []string{"steps[0].workingDir"},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := declared.DeepCopy()
+			ts.Steps = []v1beta1.Step{tt.step}
+			err := v1beta1.ValidateResolvedStepRefs(getContextBasedOnFeatureFlag("alpha"), ts)
+			if tt.expectedError == nil {
+				if err != nil {
+					t.Fatalf("Unexpected error %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected an error, got nothing for %v", ts)
+			}
+			if d := cmp.Diff(tt.expectedError.Error(), err.Error()); d != "" {
+				t.Errorf("ValidateResolvedStepRefs() errors diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
 func TestStepOnError(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -1450,6 +1653,7 @@ func TestStepOnError(t *testing.T) {
 // TestIncompatibleAPIVersions exercises validation of fields that
 // require a specific feature gate version in order to work.
 func TestIncompatibleAPIVersions(t *testing.T) {
+	readinessGate := false
 	tests := []struct {
 		name            string
 		requiredVersion string
@@ -1496,6 +1700,60 @@ func TestIncompatibleAPIVersions(t *testing.T) {
 				script-1`,
 			}},
 		},
+	}, {
+		name:            "step gracePeriod requires alpha",
+		requiredVersion: "alpha",
+		spec: v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Image:       "my-image",
+				Timeout:     &metav1.Duration{Duration: time.Minute},
+				GracePeriod: &metav1.Duration{Duration: 10 * time.Second},
+			}},
+		},
+	}, {
+		name:            "step ref requires alpha",
+		requiredVersion: "alpha",
+		spec: v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Ref: &v1beta1.StepRef{
+					Name:    "clone",
+					TaskRef: &v1beta1.TaskRef{Name: "git-clone"},
+				},
+			}},
+		},
+	}, {
+		name:            "sidecar readinessGate requires alpha",
+		requiredVersion: "alpha",
+		spec: v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Image: "my-image",
+			}},
+			Sidecars: []v1beta1.Sidecar{{
+				Image:         "my-sidecar",
+				ReadinessGate: &readinessGate,
+			}},
+		},
+	}, {
+		name:            "sidecar gracePeriod requires alpha",
+		requiredVersion: "alpha",
+		spec: v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Image: "my-image",
+			}},
+			Sidecars: []v1beta1.Sidecar{{
+				Image:       "my-sidecar",
+				GracePeriod: &metav1.Duration{Duration: 10 * time.Second},
+			}},
+		},
+	}, {
+		name:            "step onCancel requires alpha",
+		requiredVersion: "alpha",
+		spec: v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Image:    "my-image",
+				OnCancel: "echo cleanup",
+			}},
+		},
 	}}
 	versions := []string{"alpha", "stable"}
 	for _, tt := range tests {
@@ -1520,6 +1778,33 @@ func TestIncompatibleAPIVersions(t *testing.T) {
 	}
 }
 
+func TestTaskSpecValidateNativeSidecars(t *testing.T) {
+	featureFlags, _ := config.NewFeatureFlagsFromMap(map[string]string{
+		"enable-api-fields":      "alpha",
+		"enable-native-sidecars": "true",
+	})
+	ctx := config.ToContext(context.Background(), &config.Config{FeatureFlags: featureFlags})
+	ts := v1beta1.TaskSpec{
+		Steps: []v1beta1.Step{{
+			Image: "my-image",
+		}},
+		Sidecars: []v1beta1.Sidecar{{
+			Image: "my-sidecar",
+		}, {
+			Image:       "my-sidecar",
+			GracePeriod: &metav1.Duration{Duration: 10 * time.Second},
+		}},
+	}
+	err := ts.Validate(ctx)
+	want := &apis.FieldError{
+		Message: `gracePeriod can't be set when "enable-native-sidecars" is "true"`,
+		Paths:   []string{"sidecars[1].gracePeriod"},
+	}
+	if d := cmp.Diff(want.Error(), err.Error()); d != "" {
+		t.Errorf("TaskSpec.Validate() errors diff %s", diff.PrintWantGot(d))
+	}
+}
+
 func getContextBasedOnFeatureFlag(featureFlag string) context.Context {
 	featureFlags, _ := config.NewFeatureFlagsFromMap(map[string]string{
 		"enable-api-fields": featureFlag,
@@ -1612,3 +1897,99 @@ func TestSubstitutedContext(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskSpecParamsInScriptsWarnings(t *testing.T) {
+	ts := &v1beta1.TaskSpec{
+		Params: []v1beta1.ParamSpec{{
+			Name: "url",
+			Type: v1beta1.ParamTypeString,
+		}},
+		Steps: []v1beta1.Step{{
+			Image:  "my-image",
+			Script: "curl $(params.url)",
+		}, {
+			Image:  "my-image",
+			Script: "curl \"$PARAMS_URL\"",
+		}},
+		Sidecars: []v1beta1.Sidecar{{
+			Image:  "my-image",
+			Script: `echo "$(params['url'])"`,
+		}},
+	}
+	for _, tc := range []struct {
+		desc         string
+		featureFlags map[string]string
+		want         *apis.FieldError
+	}{{
+		desc: "params as env in scripts disabled",
+	}, {
+		desc:         "params as env in scripts enabled",
+		featureFlags: map[string]string{"enable-params-as-env-in-scripts": "true"},
+		want: &apis.FieldError{
+			Message: "script substitutes params inline, read them from the PARAMS_<NAME> env vars instead",
+			Paths:   []string{"sidecars[0].script", "steps[0].script"},
+		},
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			featureFlags, err := config.NewFeatureFlagsFromMap(tc.featureFlags)
+			if err != nil {
+				t.Fatalf("NewFeatureFlagsFromMap: %v", err)
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{FeatureFlags: featureFlags})
+			if err := ts.Validate(ctx); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			got := ts.ParamsInScriptsWarnings(ctx)
+			if d := cmp.Diff(tc.want.Error(), got.Error()); d != "" {
+				t.Errorf("ParamsInScriptsWarnings %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestTaskSpecValidateScriptEnvNames(t *testing.T) {
+	ts := &v1beta1.TaskSpec{
+		Params: []v1beta1.ParamSpec{{
+			Name: "git-url",
+			Type: v1beta1.ParamTypeString,
+		}, {
+			Name: "git_url",
+			Type: v1beta1.ParamTypeString,
+		}},
+		Results: []v1beta1.TaskResult{{
+			Name: "image.digest",
+			Type: v1beta1.ResultsTypeString,
+		}, {
+			Name: "image-digest",
+			Type: v1beta1.ResultsTypeString,
+		}},
+		Steps: []v1beta1.Step{{
+			Image:  "my-image",
+			Script: "git clone \"$PARAMS_GIT_URL\"",
+		}},
+	}
+	for _, tc := range []struct {
+		desc         string
+		featureFlags map[string]string
+		want         *apis.FieldError
+	}{{
+		desc: "params as env in scripts disabled",
+	}, {
+		desc:         "params as env in scripts enabled",
+		featureFlags: map[string]string{"enable-params-as-env-in-scripts": "true"},
+		want: apis.ErrGeneric(`params "git-url" and "git_url" are both exposed to scripts as PARAMS_GIT_URL`, "params[1].name").Also(
+			apis.ErrGeneric(`results "image.digest" and "image-digest" are both exposed to scripts as RESULTS_IMAGE_DIGEST_PATH`, "results[1].name")),
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			featureFlags, err := config.NewFeatureFlagsFromMap(tc.featureFlags)
+			if err != nil {
+				t.Fatalf("NewFeatureFlagsFromMap: %v", err)
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{FeatureFlags: featureFlags})
+			got := ts.Validate(ctx)
+			if d := cmp.Diff(tc.want.Error(), got.Error()); d != "" {
+				t.Errorf("Validate %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
@@ -18,9 +18,11 @@ package v1beta1
 
 import (
 	"context"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
@@ -64,6 +66,9 @@ func (ref *TaskRef) validateInTreeRef(ctx context.Context) (errs *apis.FieldErro
 	} else if ref.Bundle != "" {
 		errs = errs.Also(apis.ErrDisallowedFields("bundle"))
 	}
+	if ref.Namespace != "" {
+		errs = errs.Also(ref.validateNamespace(ctx))
+	}
 	return
 }
 
@@ -83,6 +88,9 @@ func (ref *TaskRef) validateAlphaRef(ctx context.Context) (errs *apis.FieldError
 			errs = errs.Also(apis.ErrMultipleOneOf("name", "resource"))
 		}
 	}
+	if hasResolver && ref.Namespace != "" {
+		errs = errs.Also(apis.ErrMultipleOneOf("namespace", "resolver"))
+	}
 	if hasBundle {
 		if hasResolver {
 			errs = errs.Also(apis.ErrMultipleOneOf("bundle", "resolver"))
@@ -100,3 +108,22 @@ func (ref *TaskRef) validateAlphaRef(ctx context.Context) (errs *apis.FieldError
 	}
 	return
 }
+
+// validateNamespace returns errors if a cross-namespace reference is made
+// without the alpha feature gate or is combined with fields that do not
+// resolve from a namespace.
+func (ref *TaskRef) validateNamespace(ctx context.Context) (errs *apis.FieldError) {
+	if err := ValidateEnabledAPIFields(ctx, "namespace", config.AlphaAPIFields); err != nil {
+		return errs.Also(err.ViaField("namespace"))
+	}
+	if ref.Kind == ClusterTaskKind {
+		errs = errs.Also(apis.ErrMultipleOneOf("namespace", "kind"))
+	}
+	if ref.Bundle != "" {
+		errs = errs.Also(apis.ErrMultipleOneOf("namespace", "bundle"))
+	}
+	if verrs := validation.IsDNS1123Label(ref.Namespace); len(verrs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(verrs, ", "), "namespace"))
+	}
+	return
+}
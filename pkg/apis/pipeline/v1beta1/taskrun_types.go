@@ -57,6 +57,12 @@ type TaskRunSpec struct {
 	// Refer Go's ParseDuration documentation for expected format: https://golang.org/pkg/time/#ParseDuration
 	// +optional
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// GracePeriod is the time given to the pod of the TaskRun to terminate
+	// gracefully when the TaskRun times out. Defaults to the termination grace
+	// period of the pod.
+	// This field is only supported when the alpha feature gate is enabled.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
 	// PodTemplate holds pod specific configuration
 	PodTemplate *PodTemplate `json:"podTemplate,omitempty"`
 	// Workspaces is a list of WorkspaceBindings from volumes to workspaces.
@@ -77,6 +83,11 @@ type TaskRunSpec struct {
 	// +optional
 	// +listType=atomic
 	SidecarOverrides []TaskRunSidecarOverride `json:"sidecarOverrides,omitempty"`
+	// ComputeResources are the compute resources of the whole Task, spread
+	// across its steps. They take precedence over the compute resources of the Task.
+	// This field is only supported when the alpha feature gate is enabled.
+	// +optional
+	ComputeResources *corev1.ResourceRequirements `json:"computeResources,omitempty"`
 }
 
 // TaskRunSpecStatus defines the taskrun spec status the user can provide
@@ -145,6 +156,9 @@ const (
 	TaskRunReasonResolvingTaskRef = "ResolvingTaskRef"
 	// TaskRunReasonImagePullFailed is the reason set when the step of a task fails due to image not being pulled
 	TaskRunReasonImagePullFailed TaskRunReason = "TaskRunImagePullFailed"
+	// TaskRunReasonCacheHit is the reason set when the TaskRun reused the results
+	// of a previous TaskRun instead of running its Task
+	TaskRunReasonCacheHit TaskRunReason = "CacheHit"
 )
 
 func (t TaskRunReason) String() string {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tektoncd/pipeline/pkg/apis/config"
 	"github.com/tektoncd/pipeline/pkg/apis/validate"
@@ -71,6 +72,15 @@ func (ts *TaskRunSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 		errs = errs.Also(ValidateEnabledAPIFields(ctx, "sidecarOverrides", config.AlphaAPIFields).ViaField("sidecarOverrides"))
 		errs = errs.Also(validateSidecarOverrides(ts.SidecarOverrides).ViaField("sidecarOverrides"))
 	}
+	if ts.ComputeResources != nil {
+		errs = errs.Also(ValidateEnabledAPIFields(ctx, "computeResources", config.AlphaAPIFields).ViaField("computeResources"))
+		errs = errs.Also(validateComputeResources(ts.ComputeResources).ViaField("computeResources"))
+		for i, o := range ts.StepOverrides {
+			if len(o.Resources.Requests) > 0 || len(o.Resources.Limits) > 0 {
+				errs = errs.Also(apis.ErrMultipleOneOf("computeResources", fmt.Sprintf("stepOverrides[%d].resources", i)))
+			}
+		}
+	}
 
 	if ts.Status != "" {
 		if ts.Status != TaskRunSpecStatusCancelled {
@@ -83,6 +93,14 @@ func (ts *TaskRunSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s should be >= 0", ts.Timeout.Duration.String()), "timeout"))
 		}
 	}
+	if ts.GracePeriod != nil {
+		errs = errs.Also(ValidateEnabledAPIFields(ctx, "gracePeriod", config.AlphaAPIFields).ViaField("gracePeriod"))
+		// gracePeriod should be a valid duration of at least 1s, a shorter
+		// grace period would force delete the pod.
+		if ts.GracePeriod.Duration < time.Second {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s should be >= 1s", ts.GracePeriod.Duration.String()), "gracePeriod"))
+		}
+	}
 
 	return errs
 }
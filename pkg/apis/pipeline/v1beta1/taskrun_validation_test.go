@@ -101,6 +101,17 @@ func TestTaskRun_Validate(t *testing.T) {
 			},
 		},
 		wc: enableAlphaAPIFields,
+	}, {
+		name: "alpha feature: valid cross-namespace taskref",
+		taskRun: &v1beta1.TaskRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "tr",
+			},
+			Spec: v1beta1.TaskRunSpec{
+				TaskRef: &v1beta1.TaskRef{Name: "task", Namespace: "catalog"},
+			},
+		},
+		wc: enableAlphaAPIFields,
 	}, {
 		name: "alpha feature: valid step and sidecar overrides",
 		taskRun: &v1beta1.TaskRun{
@@ -223,6 +234,16 @@ func TestTaskRunSpec_Invalidate(t *testing.T) {
 			Timeout: &metav1.Duration{Duration: -48 * time.Hour},
 		},
 		wantErr: apis.ErrInvalidValue("-48h0m0s should be >= 0", "timeout"),
+	}, {
+		name: "gracePeriod under a second",
+		spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{
+				Name: "taskrefname",
+			},
+			GracePeriod: &metav1.Duration{Duration: 500 * time.Millisecond},
+		},
+		wantErr: apis.ErrInvalidValue("500ms should be >= 1s", "gracePeriod"),
+		wc:      enableAlphaAPIFields,
 	}, {
 		name: "wrong taskrun cancel",
 		spec: v1beta1.TaskRunSpec{
@@ -427,6 +448,48 @@ func TestTaskRunSpec_Invalidate(t *testing.T) {
 		wantErr: apis.ErrMultipleOneOf("bundle", "resource").ViaField("taskRef").Also(
 			apis.ErrMissingField("resolver").ViaField("taskRef")),
 		wc: enableAlphaAPIFields,
+	}, {
+		name: "taskref namespace disallowed without alpha feature gate",
+		spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{
+				Name:      "foo",
+				Namespace: "catalog",
+			},
+		},
+		wantErr: apis.ErrGeneric("namespace requires \"enable-api-fields\" feature gate to be \"alpha\" but it is \"stable\""),
+	}, {
+		name: "taskref namespace disallowed in conjunction with cluster task kind",
+		spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{
+				Name:      "foo",
+				Kind:      v1beta1.ClusterTaskKind,
+				Namespace: "catalog",
+			},
+		},
+		wantErr: apis.ErrMultipleOneOf("namespace", "kind").ViaField("taskRef"),
+		wc:      enableAlphaAPIFields,
+	}, {
+		name: "taskref namespace disallowed in conjunction with taskref resolver",
+		spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{
+				Namespace: "catalog",
+				ResolverRef: v1beta1.ResolverRef{
+					Resolver: "git",
+				},
+			},
+		},
+		wantErr: apis.ErrMultipleOneOf("namespace", "resolver").ViaField("taskRef"),
+		wc:      enableAlphaAPIFields,
+	}, {
+		name: "taskref namespace must be a valid namespace name",
+		spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{
+				Name:      "foo",
+				Namespace: "Catalog",
+			},
+		},
+		wantErr: apis.ErrInvalidValue("a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')", "taskRef.namespace"),
+		wc:      enableAlphaAPIFields,
 	}, {
 		name: "stepOverride disallowed without alpha feature gate",
 		spec: v1beta1.TaskRunSpec{
@@ -515,6 +578,42 @@ func TestTaskRunSpec_Invalidate(t *testing.T) {
 		},
 		wantErr: apis.ErrMissingField("sidecarOverrides[0].name"),
 		wc:      enableAlphaAPIFields,
+	}, {
+		name: "computeResources disallowed without alpha feature gate",
+		spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{Name: "task"},
+			ComputeResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: corev1resources.MustParse("1Gi")},
+			},
+		},
+		wantErr: apis.ErrGeneric(`computeResources requires "enable-api-fields" feature gate to be "alpha" but it is "stable"`),
+	}, {
+		name: "computeResources request larger than limit",
+		spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{Name: "task"},
+			ComputeResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: corev1resources.MustParse("2Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: corev1resources.MustParse("1Gi")},
+			},
+		},
+		wantErr: apis.ErrInvalidValue("2Gi should be <= limit 1Gi", "computeResources.requests.memory"),
+		wc:      enableAlphaAPIFields,
+	}, {
+		name: "computeResources and stepOverride resources",
+		spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{Name: "task"},
+			StepOverrides: []v1beta1.TaskRunStepOverride{{
+				Name: "foo",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: corev1resources.MustParse("1Gi")},
+				},
+			}},
+			ComputeResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: corev1resources.MustParse("2Gi")},
+			},
+		},
+		wantErr: apis.ErrMultipleOneOf("computeResources", "stepOverrides[0].resources"),
+		wc:      enableAlphaAPIFields,
 	}}
 	for _, ts := range tests {
 		t.Run(ts.name, func(t *testing.T) {
@@ -534,6 +633,7 @@ func TestTaskRunSpec_Validate(t *testing.T) {
 	tests := []struct {
 		name string
 		spec v1beta1.TaskRunSpec
+		wc   func(context.Context) context.Context
 	}{{
 		name: "taskspec without a taskRef",
 		spec: v1beta1.TaskRunSpec{
@@ -581,10 +681,24 @@ func TestTaskRunSpec_Validate(t *testing.T) {
 				}},
 			},
 		},
+	}, {
+		name: "compute resources",
+		spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{Name: "task"},
+			ComputeResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: corev1resources.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: corev1resources.MustParse("2Gi")},
+			},
+		},
+		wc: enableAlphaAPIFields,
 	}}
 	for _, ts := range tests {
 		t.Run(ts.name, func(t *testing.T) {
-			if err := ts.spec.Validate(context.Background()); err != nil {
+			ctx := context.Background()
+			if ts.wc != nil {
+				ctx = ts.wc(ctx)
+			}
+			if err := ts.spec.Validate(ctx); err != nil {
 				t.Error(err)
 			}
 		})
@@ -77,8 +77,41 @@ type WorkspaceBinding struct {
 	// Secret represents a secret that should populate this workspace.
 	// +optional
 	Secret *corev1.SecretVolumeSource `json:"secret,omitempty"`
+	// Projected represents a projected volume, combining secrets, configmaps
+	// and service account tokens, that should populate this workspace.
+	// This is an alpha field.
+	// +optional
+	Projected *corev1.ProjectedVolumeSource `json:"projected,omitempty"`
+	// CSI represents ephemeral storage that is provided by an external CSI
+	// driver, such as a secrets store, and that should populate this workspace.
+	// This is an alpha field.
+	// +optional
+	CSI *corev1.CSIVolumeSource `json:"csi,omitempty"`
+	// Ephemeral represents a generic ephemeral volume, backed by a
+	// PersistentVolumeClaim which shares the lifetime of the TaskRun's pod.
+	// This is an alpha field.
+	// +optional
+	Ephemeral *corev1.EphemeralVolumeSource `json:"ephemeral,omitempty"`
+	// Artifact, when set on the binding of a PipelineRun workspace, passes the
+	// content of the workspace between the PipelineRun's TaskRuns through the
+	// artifact storage instead of a volume shared by their pods.
+	// This is an alpha field.
+	// +optional
+	Artifact *WorkspaceArtifact `json:"artifact,omitempty"`
+	// ReadOnly mounts the workspace read-only, even if the Task declares it
+	// writable. The PipelineRun controller sets it on the bindings of the
+	// TaskRuns it creates for the PipelineTasks with readOnly access.
+	// This is an alpha field.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
 }
 
+// WorkspaceArtifact marks a workspace whose content is passed between TaskRuns
+// through the artifact storage. The PipelineRun controller sets the paths of the
+// artifact storage of the TaskRuns it creates in their
+// tekton.dev/workspace-artifacts annotation.
+type WorkspaceArtifact struct{}
+
 // WorkspacePipelineDeclaration creates a named slot in a Pipeline that a PipelineRun
 // is expected to populate with a workspace binding.
 // Deprecated: use PipelineWorkspaceDeclaration type instead
@@ -111,8 +144,24 @@ type WorkspacePipelineTaskBinding struct {
 	// for this binding (i.e. the volume will be mounted at this sub directory).
 	// +optional
 	SubPath string `json:"subPath,omitempty"`
+	// Access declares whether the task only reads the workspace or also writes
+	// to it. Tasks which may run in parallel must not write to a workspace that
+	// the other one accesses.
+	// This is an alpha field.
+	// +optional
+	Access WorkspaceAccess `json:"access,omitempty"`
 }
 
+// WorkspaceAccess is the access of a PipelineTask to a workspace.
+type WorkspaceAccess string
+
+const (
+	// WorkspaceAccessReadOnly declares that the PipelineTask only reads the workspace.
+	WorkspaceAccessReadOnly WorkspaceAccess = "readOnly"
+	// WorkspaceAccessReadWrite declares that the PipelineTask writes to the workspace.
+	WorkspaceAccessReadWrite WorkspaceAccess = "readWrite"
+)
+
 // WorkspaceUsage is used by a Step or Sidecar to declare that it wants isolated access
 // to a Workspace defined in a Task.
 type WorkspaceUsage struct {
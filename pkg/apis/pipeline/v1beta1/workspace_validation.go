@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"context"
 
+	"github.com/tektoncd/pipeline/pkg/apis/config"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"knative.dev/pkg/apis"
 )
@@ -31,12 +32,15 @@ var allVolumeSourceFields = []string{
 	"emptydir",
 	"configmap",
 	"secret",
+	"projected",
+	"csi",
+	"ephemeral",
 }
 
 // Validate looks at the Volume provided in wb and makes sure that it is valid.
 // This means that only one VolumeSource can be specified, and also that the
 // supported VolumeSource is itself valid.
-func (b *WorkspaceBinding) Validate(context.Context) *apis.FieldError {
+func (b *WorkspaceBinding) Validate(ctx context.Context) *apis.FieldError {
 	if equality.Semantic.DeepEqual(b, &WorkspaceBinding{}) || b == nil {
 		return apis.ErrMissingField(apis.CurrentField)
 	}
@@ -66,6 +70,53 @@ func (b *WorkspaceBinding) Validate(context.Context) *apis.FieldError {
 		return apis.ErrMissingField("secret.secretName")
 	}
 
+	// For a Projected volume to work, you must provide at least one source.
+	if b.Projected != nil {
+		if err := ValidateEnabledAPIFields(ctx, "projected workspace type", config.AlphaAPIFields); err != nil {
+			return err
+		}
+		if len(b.Projected.Sources) == 0 {
+			return apis.ErrMissingField("projected.sources")
+		}
+	}
+
+	// For a CSI volume to work, you must provide the driver to use.
+	if b.CSI != nil {
+		if err := ValidateEnabledAPIFields(ctx, "csi workspace type", config.AlphaAPIFields); err != nil {
+			return err
+		}
+		if b.CSI.Driver == "" {
+			return apis.ErrMissingField("csi.driver")
+		}
+	}
+
+	// For an Ephemeral volume to work, you must provide the template of its claim.
+	if b.Ephemeral != nil {
+		if err := ValidateEnabledAPIFields(ctx, "ephemeral workspace type", config.AlphaAPIFields); err != nil {
+			return err
+		}
+		if b.Ephemeral.VolumeClaimTemplate == nil {
+			return apis.ErrMissingField("ephemeral.volumeClaimTemplate")
+		}
+	}
+
+	// An artifact workspace is populated from the artifact storage, so its
+	// volume must start out empty.
+	if b.Artifact != nil {
+		if err := ValidateEnabledAPIFields(ctx, "artifact workspaces", config.AlphaAPIFields); err != nil {
+			return err
+		}
+		if b.EmptyDir == nil && b.Ephemeral == nil {
+			return apis.ErrGeneric("artifact workspaces must be bound to an emptyDir or an ephemeral volume", "artifact")
+		}
+	}
+
+	if b.ReadOnly {
+		if err := ValidateEnabledAPIFields(ctx, "readOnly workspaces", config.AlphaAPIFields); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -88,5 +139,14 @@ func (b *WorkspaceBinding) numSources() int {
 	if b.Secret != nil {
 		n++
 	}
+	if b.Projected != nil {
+		n++
+	}
+	if b.CSI != nil {
+		n++
+	}
+	if b.Ephemeral != nil {
+		n++
+	}
 	return n
 }
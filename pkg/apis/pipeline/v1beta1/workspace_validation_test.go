@@ -20,6 +20,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/tektoncd/pipeline/pkg/apis/config"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +30,7 @@ func TestWorkspaceBindingValidateValid(t *testing.T) {
 	for _, tc := range []struct {
 		name    string
 		binding *WorkspaceBinding
+		alpha   bool
 	}{{
 		name: "Valid PVC",
 		binding: &WorkspaceBinding{
@@ -79,9 +81,70 @@ func TestWorkspaceBindingValidateValid(t *testing.T) {
 				SecretName: "my-secret",
 			},
 		},
+	}, {
+		name: "Valid projected",
+		binding: &WorkspaceBinding{
+			Name: "beth",
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ConfigMap: &corev1.ConfigMapProjection{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "a-configmap-name",
+						},
+					},
+				}, {
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Path: "token",
+					},
+				}},
+			},
+		},
+		alpha: true,
+	}, {
+		name: "Valid csi",
+		binding: &WorkspaceBinding{
+			Name: "beth",
+			CSI: &corev1.CSIVolumeSource{
+				Driver: "secrets-store.csi.k8s.io",
+			},
+		},
+		alpha: true,
+	}, {
+		name: "Valid ephemeral",
+		binding: &WorkspaceBinding{
+			Name: "beth",
+			Ephemeral: &corev1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					},
+				},
+			},
+		},
+		alpha: true,
+	}, {
+		name: "Valid artifact emptydir",
+		binding: &WorkspaceBinding{
+			Name:     "beth",
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+			Artifact: &WorkspaceArtifact{},
+		},
+		alpha: true,
+	}, {
+		name: "Valid readOnly emptydir",
+		binding: &WorkspaceBinding{
+			Name:     "beth",
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+			ReadOnly: true,
+		},
+		alpha: true,
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
-			if err := tc.binding.Validate(context.Background()); err != nil {
+			ctx := context.Background()
+			if tc.alpha {
+				ctx = config.ToContext(ctx, &config.Config{FeatureFlags: &config.FeatureFlags{EnableAPIFields: config.AlphaAPIFields}})
+			}
+			if err := tc.binding.Validate(ctx); err != nil {
 				t.Errorf("didnt expect error for valid binding but got: %v", err)
 			}
 		})
@@ -93,6 +156,7 @@ func TestWorkspaceBindingValidateInvalid(t *testing.T) {
 	for _, tc := range []struct {
 		name    string
 		binding *WorkspaceBinding
+		alpha   bool
 	}{{
 		name:    "no binding provided",
 		binding: nil,
@@ -128,9 +192,84 @@ func TestWorkspaceBindingValidateInvalid(t *testing.T) {
 			Name:   "beth",
 			Secret: &corev1.SecretVolumeSource{},
 		},
+	}, {
+		name: "Provide projected without sources",
+		binding: &WorkspaceBinding{
+			Name:      "beth",
+			Projected: &corev1.ProjectedVolumeSource{},
+		},
+		alpha: true,
+	}, {
+		name: "Provide csi without a driver",
+		binding: &WorkspaceBinding{
+			Name: "beth",
+			CSI:  &corev1.CSIVolumeSource{},
+		},
+		alpha: true,
+	}, {
+		name: "Provide ephemeral without a volumeClaimTemplate",
+		binding: &WorkspaceBinding{
+			Name:      "beth",
+			Ephemeral: &corev1.EphemeralVolumeSource{},
+		},
+		alpha: true,
+	}, {
+		name: "csi requires alpha",
+		binding: &WorkspaceBinding{
+			Name: "beth",
+			CSI: &corev1.CSIVolumeSource{
+				Driver: "secrets-store.csi.k8s.io",
+			},
+		},
+	}, {
+		name: "artifact requires alpha",
+		binding: &WorkspaceBinding{
+			Name:     "beth",
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+			Artifact: &WorkspaceArtifact{},
+		},
+	}, {
+		name: "readOnly requires alpha",
+		binding: &WorkspaceBinding{
+			Name:     "beth",
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+			ReadOnly: true,
+		},
+	}, {
+		name: "Provide artifact with a pvc",
+		binding: &WorkspaceBinding{
+			Name: "beth",
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: "pool-party",
+			},
+			Artifact: &WorkspaceArtifact{},
+		},
+		alpha: true,
+	}, {
+		name: "Provide both csi and projected",
+		binding: &WorkspaceBinding{
+			Name: "beth",
+			CSI: &corev1.CSIVolumeSource{
+				Driver: "secrets-store.csi.k8s.io",
+			},
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					Secret: &corev1.SecretProjection{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "my-secret",
+						},
+					},
+				}},
+			},
+		},
+		alpha: true,
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
-			if err := tc.binding.Validate(context.Background()); err == nil {
+			ctx := context.Background()
+			if tc.alpha {
+				ctx = config.ToContext(ctx, &config.Config{FeatureFlags: &config.FeatureFlags{EnableAPIFields: config.AlphaAPIFields}})
+			}
+			if err := tc.binding.Validate(ctx); err == nil {
 				t.Errorf("expected error for invalid binding but didn't get any!")
 			}
 		})
@@ -852,6 +852,16 @@ func (in *PipelineTask) DeepCopyInto(out *PipelineTask) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(PipelineTaskCache)
+		**out = **in
+	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(pod.Template)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -865,6 +875,22 @@ func (in *PipelineTask) DeepCopy() *PipelineTask {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineTaskCache) DeepCopyInto(out *PipelineTaskCache) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineTaskCache.
+func (in *PipelineTaskCache) DeepCopy() *PipelineTaskCache {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineTaskCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineTaskInputResource) DeepCopyInto(out *PipelineTaskInputResource) {
 	*out = *in
@@ -1214,6 +1240,16 @@ func (in *Sidecar) DeepCopyInto(out *Sidecar) {
 		*out = make([]WorkspaceUsage, len(*in))
 		copy(*out, *in)
 	}
+	if in.ReadinessGate != nil {
+		in, out := &in.ReadinessGate, &out.ReadinessGate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -1342,11 +1378,21 @@ func (in *Step) DeepCopyInto(out *Step) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.Workspaces != nil {
 		in, out := &in.Workspaces, &out.Workspaces
 		*out = make([]WorkspaceUsage, len(*in))
 		copy(*out, *in)
 	}
+	if in.Ref != nil {
+		in, out := &in.Ref, &out.Ref
+		*out = new(StepRef)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1360,6 +1406,27 @@ func (in *Step) DeepCopy() *Step {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepRef) DeepCopyInto(out *StepRef) {
+	*out = *in
+	if in.TaskRef != nil {
+		in, out := &in.TaskRef, &out.TaskRef
+		*out = new(TaskRef)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepRef.
+func (in *StepRef) DeepCopy() *StepRef {
+	if in == nil {
+		return nil
+	}
+	out := new(StepRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepState) DeepCopyInto(out *StepState) {
 	*out = *in
@@ -1852,6 +1919,11 @@ func (in *TaskRunSpec) DeepCopyInto(out *TaskRunSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.PodTemplate != nil {
 		in, out := &in.PodTemplate, &out.PodTemplate
 		*out = new(pod.Template)
@@ -1878,6 +1950,11 @@ func (in *TaskRunSpec) DeepCopyInto(out *TaskRunSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ComputeResources != nil {
+		in, out := &in.ComputeResources, &out.ComputeResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2046,6 +2123,11 @@ func (in *TaskSpec) DeepCopyInto(out *TaskSpec) {
 		*out = make([]TaskResult, len(*in))
 		copy(*out, *in)
 	}
+	if in.ComputeResources != nil {
+		in, out := &in.ComputeResources, &out.ComputeResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2077,6 +2159,11 @@ func (in *TimeoutFields) DeepCopyInto(out *TimeoutFields) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -2133,6 +2220,22 @@ func (in WhenExpressions) DeepCopy() WhenExpressions {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceArtifact) DeepCopyInto(out *WorkspaceArtifact) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceArtifact.
+func (in *WorkspaceArtifact) DeepCopy() *WorkspaceArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceBinding) DeepCopyInto(out *WorkspaceBinding) {
 	*out = *in
@@ -2161,6 +2264,26 @@ func (in *WorkspaceBinding) DeepCopyInto(out *WorkspaceBinding) {
 		*out = new(v1.SecretVolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Projected != nil {
+		in, out := &in.Projected, &out.Projected
+		*out = new(v1.ProjectedVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(v1.CSIVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ephemeral != nil {
+		in, out := &in.Ephemeral, &out.Ephemeral
+		*out = new(v1.EphemeralVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Artifact != nil {
+		in, out := &in.Artifact, &out.Artifact
+		*out = new(WorkspaceArtifact)
+		**out = **in
+	}
 	return
 }
 
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/names"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// S3AccessKeyIDKey is the key in the credentials secret holding the access key ID.
+	S3AccessKeyIDKey = "AWS_ACCESS_KEY_ID"
+	// S3SecretAccessKeyKey is the key in the credentials secret holding the secret access key.
+	S3SecretAccessKeyKey = "AWS_SECRET_ACCESS_KEY"
+)
+
+// ArtifactS3Bucket contains the configuration of an S3-compatible bucket, such
+// as AWS S3 or MinIO, used to share artifacts between Tasks. Artifacts are
+// copied with the aws CLI.
+// +k8s:deepcopy-gen=true
+type ArtifactS3Bucket struct {
+	Location string
+	// Endpoint is the URL of the S3-compatible service. When empty the aws
+	// CLI default for the region is used.
+	Endpoint string
+	Region   string
+	// CredentialsSecretName is the name of a secret holding the
+	// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY keys.
+	CredentialsSecretName string
+
+	ShellImage string
+	S3Image    string
+}
+
+// GetType returns the type of the artifact storage
+func (b *ArtifactS3Bucket) GetType() string {
+	return pipeline.ArtifactStorageBucketType
+}
+
+// StorageBasePath returns the path to be used to store artifacts in a pipelinerun temporary storage
+func (b *ArtifactS3Bucket) StorageBasePath(pr *v1beta1.PipelineRun) string {
+	return fmt.Sprintf("%s-%s-bucket", pr.Name, pr.Namespace)
+}
+
+// GetCopyFromStorageToSteps returns a container used to download artifacts from temporary storage
+func (b *ArtifactS3Bucket) GetCopyFromStorageToSteps(name, sourcePath, destinationPath string) []v1beta1.Step {
+	return []v1beta1.Step{{
+		Name:    names.SimpleNameGenerator.RestrictLengthWithRandomSuffix(fmt.Sprintf("artifact-dest-mkdir-%s", name)),
+		Image:   b.ShellImage,
+		Command: []string{"mkdir", "-p", destinationPath},
+	}, {
+		Name:    names.SimpleNameGenerator.RestrictLengthWithRandomSuffix(fmt.Sprintf("artifact-copy-from-%s", name)),
+		Image:   b.S3Image,
+		Command: []string{"aws"},
+		Args:    b.copyArgs(fmt.Sprintf("%s/%s", b.Location, sourcePath), destinationPath),
+		Env:     b.envVars(),
+	}}
+}
+
+// GetCopyToStorageFromSteps returns a container used to upload artifacts for temporary storage
+func (b *ArtifactS3Bucket) GetCopyToStorageFromSteps(name, sourcePath, destinationPath string) []v1beta1.Step {
+	return []v1beta1.Step{{
+		Name:    names.SimpleNameGenerator.RestrictLengthWithRandomSuffix(fmt.Sprintf("artifact-copy-to-%s", name)),
+		Image:   b.S3Image,
+		Command: []string{"aws"},
+		Args:    b.copyArgs(sourcePath, fmt.Sprintf("%s/%s", b.Location, destinationPath)),
+		Env:     b.envVars(),
+	}}
+}
+
+// GetSecretsVolumes returns no volumes because the credentials are passed to
+// the copy steps as environment variables.
+func (b *ArtifactS3Bucket) GetSecretsVolumes() []corev1.Volume {
+	return nil
+}
+
+func (b *ArtifactS3Bucket) copyArgs(source, destination string) []string {
+	args := []string{"s3", "cp", "--recursive", "--only-show-errors"}
+	if b.Endpoint != "" {
+		args = append(args, "--endpoint-url", b.Endpoint)
+	}
+	return append(args, source, destination)
+}
+
+func (b *ArtifactS3Bucket) envVars() []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	if b.Region != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: "AWS_DEFAULT_REGION", Value: b.Region})
+	}
+	if b.CredentialsSecretName != "" {
+		for _, key := range []string{S3AccessKeyIDKey, S3SecretAccessKeyKey} {
+			envVars = append(envVars, corev1.EnvVar{
+				Name: key,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: b.CredentialsSecretName},
+						Key:                  key,
+					},
+				},
+			})
+		}
+	}
+	return envVars
+}
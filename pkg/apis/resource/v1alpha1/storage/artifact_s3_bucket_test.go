@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/apis/resource/v1alpha1/storage"
+	"github.com/tektoncd/pipeline/test/diff"
+	"github.com/tektoncd/pipeline/test/names"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	s3Bucket = storage.ArtifactS3Bucket{
+		Location:              "s3://fake-bucket",
+		Endpoint:              "http://localhost:9000",
+		Region:                "us-east-1",
+		CredentialsSecretName: "minio-credentials",
+		ShellImage:            "busybox",
+		S3Image:               "amazon/aws-cli",
+	}
+
+	s3EnvVars = []corev1.EnvVar{{
+		Name:  "AWS_DEFAULT_REGION",
+		Value: "us-east-1",
+	}, {
+		Name: "AWS_ACCESS_KEY_ID",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "minio-credentials"},
+				Key:                  "AWS_ACCESS_KEY_ID",
+			},
+		},
+	}, {
+		Name: "AWS_SECRET_ACCESS_KEY",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "minio-credentials"},
+				Key:                  "AWS_SECRET_ACCESS_KEY",
+			},
+		},
+	}}
+)
+
+func TestS3BucketGetCopyFromContainerSpec(t *testing.T) {
+	names.TestingSeed()
+
+	want := []v1beta1.Step{{
+		Name:    "artifact-dest-mkdir-workspace-9l9zj",
+		Image:   "busybox",
+		Command: []string{"mkdir", "-p", "/workspace/destination"},
+	}, {
+		Name:    "artifact-copy-from-workspace-mz4c7",
+		Image:   "amazon/aws-cli",
+		Command: []string{"aws"},
+		Args:    []string{"s3", "cp", "--recursive", "--only-show-errors", "--endpoint-url", "http://localhost:9000", "s3://fake-bucket/src-path", "/workspace/destination"},
+		Env:     s3EnvVars,
+	}}
+
+	got := s3Bucket.GetCopyFromStorageToSteps("workspace", "src-path", "/workspace/destination")
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Diff:\n%s", diff.PrintWantGot(d))
+	}
+}
+
+func TestS3BucketGetCopyToContainerSpec(t *testing.T) {
+	names.TestingSeed()
+
+	want := []v1beta1.Step{{
+		Name:    "artifact-copy-to-workspace-9l9zj",
+		Image:   "amazon/aws-cli",
+		Command: []string{"aws"},
+		Args:    []string{"s3", "cp", "--recursive", "--only-show-errors", "--endpoint-url", "http://localhost:9000", "src-path", "s3://fake-bucket/workspace/destination"},
+		Env:     s3EnvVars,
+	}}
+
+	got := s3Bucket.GetCopyToStorageFromSteps("workspace", "src-path", "workspace/destination")
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Diff:\n%s", diff.PrintWantGot(d))
+	}
+}
+
+func TestS3BucketWithoutEndpointOrCredentials(t *testing.T) {
+	names.TestingSeed()
+	b := storage.ArtifactS3Bucket{
+		Location: "s3://fake-bucket",
+		S3Image:  "amazon/aws-cli",
+	}
+
+	want := []v1beta1.Step{{
+		Name:    "artifact-copy-to-workspace-9l9zj",
+		Image:   "amazon/aws-cli",
+		Command: []string{"aws"},
+		Args:    []string{"s3", "cp", "--recursive", "--only-show-errors", "src-path", "s3://fake-bucket/workspace/destination"},
+	}}
+
+	got := b.GetCopyToStorageFromSteps("workspace", "src-path", "workspace/destination")
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Diff:\n%s", diff.PrintWantGot(d))
+	}
+	if vols := b.GetSecretsVolumes(); len(vols) != 0 {
+		t.Errorf("expected no secret volumes, got %v", vols)
+	}
+}
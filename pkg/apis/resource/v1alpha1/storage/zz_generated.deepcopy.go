@@ -67,3 +67,19 @@ func (in *ArtifactPVC) DeepCopy() *ArtifactPVC {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactS3Bucket) DeepCopyInto(out *ArtifactS3Bucket) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactS3Bucket.
+func (in *ArtifactS3Bucket) DeepCopy() *ArtifactS3Bucket {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactS3Bucket)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -44,6 +44,7 @@ var (
 		KubeconfigWriterImage:    "override-with-kubeconfig-writer:latest",
 		ShellImage:               "busybox",
 		GsutilImage:              "gcr.io/google.com/cloudsdktool/cloud-sdk",
+		S3Image:                  "docker.io/amazon/aws-cli",
 		PRImage:                  "override-with-pr:latest",
 		ImageDigestExporterImage: "override-with-imagedigest-exporter-image:latest",
 	}
@@ -276,6 +277,23 @@ func TestInitializeArtifactStorage(t *testing.T) {
 				SecretName: "secret1",
 			}},
 		},
+	}, {
+		desc: "s3 compatible bucket",
+		storageConfig: map[string]string{
+			config.BucketLocationKey:                "s3://fake-bucket",
+			config.BucketS3EndpointKey:              "http://localhost:9000",
+			config.BucketS3RegionKey:                "us-east-1",
+			config.BucketS3CredentialsSecretNameKey: "minio-credentials",
+		},
+		storagetype: "bucket",
+		expectedArtifactStorage: &storage.ArtifactS3Bucket{
+			Location:              "s3://fake-bucket",
+			Endpoint:              "http://localhost:9000",
+			Region:                "us-east-1",
+			CredentialsSecretName: "minio-credentials",
+			ShellImage:            "busybox",
+			S3Image:               "docker.io/amazon/aws-cli",
+		},
 	}} {
 		t.Run(c.desc, func(t *testing.T) {
 			fakekubeclient := fakek8s.NewSimpleClientset()
@@ -418,6 +436,58 @@ func TestInitializeArtifactStorageNoStorageNeeded(t *testing.T) {
 	}
 }
 
+func TestInitializeArtifactStorageWithArtifactWorkspace(t *testing.T) {
+	// This Pipeline has no output resources, but the PipelineRun passes a
+	// workspace between its Tasks through the artifact storage.
+	pipeline := &v1beta1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+			Name:      "pipelineruntest",
+		},
+		Spec: v1beta1.PipelineSpec{
+			Workspaces: []v1beta1.PipelineWorkspaceDeclaration{{Name: "source"}},
+			Tasks: []v1beta1.PipelineTask{{
+				Name:       "task1",
+				TaskRef:    &v1beta1.TaskRef{Name: "task"},
+				Workspaces: []v1beta1.WorkspacePipelineTaskBinding{{Name: "source", Workspace: "source"}},
+			}},
+		},
+	}
+	pr := pipelinerun.DeepCopy()
+	pr.Spec.Workspaces = []v1beta1.WorkspaceBinding{{
+		Name:     "source",
+		EmptyDir: &corev1.EmptyDirVolumeSource{},
+		Artifact: &v1beta1.WorkspaceArtifact{},
+	}}
+
+	t.Run("bucket", func(t *testing.T) {
+		fakekubeclient := fakek8s.NewSimpleClientset()
+		configs := config.Config{ArtifactBucket: &config.ArtifactBucket{Location: "gs://fake-bucket"}}
+		ctx := config.ToContext(context.Background(), &configs)
+		artifactStorage, err := InitializeArtifactStorage(ctx, images, pr, &pipeline.Spec, fakekubeclient)
+		if err != nil {
+			t.Fatalf("Somehow had error initializing artifact storage run out of fake client: %s", err)
+		}
+		if artifactStorage.GetType() != "bucket" {
+			t.Errorf("Expected bucket artifact storage for an artifact workspace but got %s", artifactStorage.GetType())
+		}
+	})
+
+	t.Run("pvc", func(t *testing.T) {
+		// A PVC can't be attached to the pods of TaskRuns running on different nodes.
+		fakekubeclient := fakek8s.NewSimpleClientset()
+		configs := config.Config{}
+		configs.ArtifactPVC, _ = config.NewArtifactPVCFromMap(map[string]string{})
+		ctx := config.ToContext(context.Background(), &configs)
+		if _, err := InitializeArtifactStorage(ctx, images, pr, &pipeline.Spec, fakekubeclient); err != ErrArtifactWorkspacesRequireBucket {
+			t.Errorf("Expected error %v but got %v", ErrArtifactWorkspacesRequireBucket, err)
+		}
+		if _, err := fakekubeclient.CoreV1().PersistentVolumeClaims(pr.Namespace).Get(ctx, GetPVCName(pr), metav1.GetOptions{}); !errors.IsNotFound(err) {
+			t.Errorf("Expected PVC %s for PipelineRun %s not to be created but got %v", GetPVCName(pr), pr.Name, err)
+		}
+	})
+}
+
 func TestCleanupArtifactStorage(t *testing.T) {
 	pipelinerun := &v1beta1.PipelineRun{
 		ObjectMeta: metav1.ObjectMeta{
@@ -525,6 +595,19 @@ func TestGetArtifactStorageWithConfig(t *testing.T) {
 			ShellImage:  "busybox",
 			GsutilImage: "gcr.io/google.com/cloudsdktool/cloud-sdk",
 		},
+	}, {
+		desc: "s3 compatible bucket with custom image",
+		storageConfig: map[string]string{
+			config.BucketLocationKey:   "s3://fake-bucket",
+			config.BucketS3EndpointKey: "http://localhost:9000",
+			config.BucketS3ImageKey:    "example.com/aws-cli:latest",
+		},
+		expectedArtifactStorage: &storage.ArtifactS3Bucket{
+			Location:   "s3://fake-bucket",
+			Endpoint:   "http://localhost:9000",
+			ShellImage: "busybox",
+			S3Image:    "example.com/aws-cli:latest",
+		},
 	}, {
 		desc: "location empty",
 		storageConfig: map[string]string{
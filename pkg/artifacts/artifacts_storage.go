@@ -18,6 +18,7 @@ package artifacts
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"strings"
 
@@ -46,6 +47,11 @@ type ArtifactStorageInterface interface {
 	StorageBasePath(pr *v1beta1.PipelineRun) string
 }
 
+// ErrArtifactWorkspacesRequireBucket is returned when a PipelineRun binds artifact workspaces
+// while the artifact storage is a PVC: a PVC can't be attached to the pods of TaskRuns running
+// on different nodes, which artifact workspaces let them do.
+var ErrArtifactWorkspacesRequireBucket = goerrors.New("artifact workspaces require a bucket to be configured as artifact storage")
+
 // ArtifactStorageNone is used when no storage is needed.
 type ArtifactStorageNone struct{}
 
@@ -78,9 +84,10 @@ func (a *ArtifactStorageNone) StorageBasePath(pr *v1beta1.PipelineRun) string {
 // InitializeArtifactStorage will check if there is there is a
 // bucket configured, create a PVC or return nil if no storage is required.
 func InitializeArtifactStorage(ctx context.Context, images pipeline.Images, pr *v1beta1.PipelineRun, ps *v1beta1.PipelineSpec, c kubernetes.Interface) (ArtifactStorageInterface, error) {
-	// Artifact storage is needed under the following condition:
+	// Artifact storage is needed under the following conditions:
 	//  Any Task in the pipeline contains an Output resource
 	//  AND that Output resource is one of the AllowedOutputResource types.
+	//  OR any workspace of the PipelineRun is an artifact workspace.
 
 	needStorage := false
 	// Build an index of resources used in the pipeline that are an AllowedOutputResource
@@ -101,11 +108,20 @@ func InitializeArtifactStorage(ctx context.Context, images pipeline.Images, pr *
 			}
 		}
 	}
-	if !needStorage {
+	artifactWorkspaces := false
+	for _, w := range pr.Spec.Workspaces {
+		if w.Artifact != nil {
+			artifactWorkspaces = true
+		}
+	}
+	if !needStorage && !artifactWorkspaces {
 		return &ArtifactStorageNone{}, nil
 	}
 
 	if needsPVC(ctx) {
+		if artifactWorkspaces {
+			return nil, ErrArtifactWorkspacesRequireBucket
+		}
 		pvc, err := createPVC(ctx, pr, c)
 		if err != nil {
 			return nil, err
@@ -153,13 +169,17 @@ func GetArtifactStorage(ctx context.Context, images pipeline.Images, prName stri
 }
 
 // newArtifactBucketFromConfig creates a Bucket from the supplied ConfigMap
-func newArtifactBucketFromConfig(ctx context.Context, images pipeline.Images) *storage.ArtifactBucket {
+func newArtifactBucketFromConfig(ctx context.Context, images pipeline.Images) ArtifactStorageInterface {
+	bucketConfig := config.FromContextOrDefaults(ctx).ArtifactBucket
+	if bucketConfig.UsesS3() {
+		return newArtifactS3BucketFromConfig(bucketConfig, images)
+	}
+
 	c := &storage.ArtifactBucket{
 		ShellImage:  images.ShellImage,
 		GsutilImage: images.GsutilImage,
 	}
 
-	bucketConfig := config.FromContextOrDefaults(ctx).ArtifactBucket
 	c.Location = bucketConfig.Location
 	sp := resourcev1alpha1.SecretParam{}
 	if bucketConfig.ServiceAccountSecretName != "" && bucketConfig.ServiceAccountSecretKey != "" {
@@ -171,6 +191,22 @@ func newArtifactBucketFromConfig(ctx context.Context, images pipeline.Images) *s
 	return c
 }
 
+// newArtifactS3BucketFromConfig creates an S3 bucket from the supplied ConfigMap
+func newArtifactS3BucketFromConfig(bucketConfig *config.ArtifactBucket, images pipeline.Images) *storage.ArtifactS3Bucket {
+	s3Image := bucketConfig.S3Image
+	if s3Image == "" {
+		s3Image = images.S3Image
+	}
+	return &storage.ArtifactS3Bucket{
+		Location:              bucketConfig.Location,
+		Endpoint:              bucketConfig.S3Endpoint,
+		Region:                bucketConfig.S3Region,
+		CredentialsSecretName: bucketConfig.S3CredentialsSecretName,
+		ShellImage:            images.ShellImage,
+		S3Image:               s3Image,
+	}
+}
+
 func createPVC(ctx context.Context, pr *v1beta1.PipelineRun, c kubernetes.Interface) (*corev1.PersistentVolumeClaim, error) {
 	if _, err := c.CoreV1().PersistentVolumeClaims(pr.Namespace).Get(ctx, GetPVCName(pr), metav1.GetOptions{}); err != nil {
 		if errors.IsNotFound(err) {
@@ -32,6 +32,10 @@ import (
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/termination"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -40,8 +44,25 @@ const (
 	timeFormat      = "2006-01-02T15:04:05.000Z07:00"
 	ContinueOnError = "continue"
 	FailOnError     = "stopAndFail"
+
+	// defaultScriptPreamble is prepended to an OnCancel script without a shebang,
+	// like it is to step scripts.
+	defaultScriptPreamble = "#!/bin/sh\nset -e\n"
+	onCancelScriptFile    = "on-cancel"
+
+	// StepCancelGracePeriod is the time given to a cancelled step without a
+	// grace period to exit after it is sent a SIGTERM.
+	StepCancelGracePeriod = 15 * time.Second
+	// OnCancelTimeout is the time given to the OnCancel script to run once the
+	// step exited. The controller only deletes the pod of a TaskRun cancelled
+	// gracefully once both are over, see pod.CancelGracePeriod.
+	OnCancelTimeout = 15 * time.Second
 )
 
+// ErrGracePeriodExceeded is returned by the Runner of a sidecar which did not
+// exit within its grace period after it was stopped, and had to be killed.
+var ErrGracePeriodExceeded = errors.New("killed after the grace period")
+
 // Entrypointer holds fields for running commands with redirected
 // entrypoints.
 type Entrypointer struct {
@@ -80,6 +101,23 @@ type Entrypointer struct {
 	OnError string
 	// StepMetadataDir is the directory for a step where the step related metadata can be stored
 	StepMetadataDir string
+
+	// Tracer, if set, records a span around the execution of the step
+	Tracer trace.Tracer
+	// SpanContext is the trace context of the TaskRun, used as the parent of the step span
+	SpanContext propagation.MapCarrier
+	// StepName is the name of the step, recorded in the step span
+	StepName string
+
+	// CancelFile is the file which gets content when the TaskRun is cancelled.
+	// If empty, the step is not cancelled gracefully.
+	CancelFile string
+	// OnCancel is the script run once the step is cancelled.
+	OnCancel string
+
+	// StopFile is the file which gets content when the sidecar must stop.
+	// It is only used by GoSidecar.
+	StopFile string
 }
 
 // Waiter encapsulates waiting for files to exist.
@@ -113,8 +151,9 @@ func (e Entrypointer) Go() error {
 		_ = logger.Sync()
 	}()
 
+	cancelled := e.waitForCancellation()
 	for _, f := range e.WaitFiles {
-		if err := e.Waiter.Wait(f, e.WaitFileContent, e.BreakpointOnFailure); err != nil {
+		if err := e.wait(f, cancelled); err != nil {
 			// An error happened while waiting, so we bail
 			// *but* we write postfile to make next steps bail too.
 			// In case of breakpoint on failure do not write post file.
@@ -126,6 +165,9 @@ func (e Entrypointer) Go() error {
 				Value:      time.Now().Format(timeFormat),
 				ResultType: v1beta1.InternalTektonResultType,
 			})
+			if err == context.Canceled {
+				output = append(output, cancelledResult)
+			}
 			return err
 		}
 	}
@@ -148,13 +190,30 @@ func (e Entrypointer) Go() error {
 			ctx, cancel = context.WithTimeout(ctx, *e.Timeout)
 			defer cancel()
 		}
-		err = e.Runner.Run(ctx, e.Command...)
-		if err == context.DeadlineExceeded {
+		if cancelled != nil {
+			ctx, cancel = context.WithCancel(ctx)
+			defer cancel()
+			go func() {
+				select {
+				case <-cancelled:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+		}
+		err = e.run(ctx)
+		switch err {
+		case context.DeadlineExceeded:
 			output = append(output, v1beta1.PipelineResourceResult{
 				Key:        "Reason",
 				Value:      "TimeoutExceeded",
 				ResultType: v1beta1.InternalTektonResultType,
 			})
+		case context.Canceled:
+			output = append(output, cancelledResult)
+			if onCancelErr := e.runOnCancel(); onCancelErr != nil {
+				logger.Errorf("Error running onCancel script: %v", onCancelErr)
+			}
 		}
 	}
 
@@ -192,6 +251,114 @@ func (e Entrypointer) Go() error {
 	return err
 }
 
+// GoSidecar runs the command of a sidecar until it exits, or until the
+// StopFile gets content, in which case the command is terminated gracefully.
+// Sidecars start right away and write no post file, and the error returned is
+// the one of the command, so that the sidecar container terminates with the
+// exit code of the command. A sidecar killed after its grace period reports
+// the GracePeriodExceeded reason in its termination message.
+func (e Entrypointer) GoSidecar() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if e.StopFile != "" {
+		go func() {
+			if err := e.Waiter.Wait(e.StopFile, true, false); err == nil {
+				cancel()
+			}
+		}()
+	}
+	err := e.Runner.Run(ctx, e.Command...)
+	if errors.Is(err, ErrGracePeriodExceeded) {
+		if wErr := termination.WriteMessage(e.TerminationPath, []v1beta1.PipelineResourceResult{gracePeriodExceededResult}); wErr != nil {
+			return wErr
+		}
+	}
+	return err
+}
+
+// gracePeriodExceededResult is reported by a sidecar which was killed after
+// its grace period.
+var gracePeriodExceededResult = v1beta1.PipelineResourceResult{
+	Key:        "Reason",
+	Value:      "GracePeriodExceeded",
+	ResultType: v1beta1.InternalTektonResultType,
+}
+
+// cancelledResult is reported by a step which was cancelled.
+var cancelledResult = v1beta1.PipelineResourceResult{
+	Key:        "Reason",
+	Value:      "Cancelled",
+	ResultType: v1beta1.InternalTektonResultType,
+}
+
+// waitForCancellation returns a channel which is closed once the CancelFile
+// has content, or nil if the step is not cancelled gracefully.
+func (e Entrypointer) waitForCancellation() <-chan struct{} {
+	if e.CancelFile == "" {
+		return nil
+	}
+	cancelled := make(chan struct{})
+	go func() {
+		if err := e.Waiter.Wait(e.CancelFile, true, false); err == nil {
+			close(cancelled)
+		}
+	}()
+	return cancelled
+}
+
+// wait waits for the file, returning context.Canceled if the step is
+// cancelled first.
+func (e Entrypointer) wait(file string, cancelled <-chan struct{}) error {
+	if cancelled == nil {
+		return e.Waiter.Wait(file, e.WaitFileContent, e.BreakpointOnFailure)
+	}
+	waited := make(chan error, 1)
+	go func() {
+		waited <- e.Waiter.Wait(file, e.WaitFileContent, e.BreakpointOnFailure)
+	}()
+	select {
+	case err := <-waited:
+		return err
+	case <-cancelled:
+		return context.Canceled
+	}
+}
+
+// runOnCancel runs the OnCancel script, if any, once the step was cancelled.
+// The script is written next to the post file of the step, which is writable.
+func (e Entrypointer) runOnCancel() error {
+	if e.OnCancel == "" {
+		return nil
+	}
+	script := strings.TrimSpace(e.OnCancel)
+	if !strings.HasPrefix(script, "#!") {
+		script = defaultScriptPreamble + script
+	}
+	scriptFile := filepath.Join(filepath.Dir(e.PostFile), onCancelScriptFile)
+	if err := ioutil.WriteFile(scriptFile, []byte(script), 0755); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), OnCancelTimeout)
+	defer cancel()
+	return e.Runner.Run(ctx, scriptFile)
+}
+
+// run runs the command, within a span when a Tracer is set.
+func (e Entrypointer) run(ctx context.Context) error {
+	if e.Tracer == nil {
+		return e.Runner.Run(ctx, e.Command...)
+	}
+	ctx = propagation.TraceContext{}.Extract(ctx, e.SpanContext)
+	ctx, span := e.Tracer.Start(ctx, "Step:Run", trace.WithAttributes(attribute.String("step", e.StepName)))
+	defer span.End()
+	err := e.Runner.Run(ctx, e.Command...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
 func (e Entrypointer) readResultsFromDisk(resultDir string) error {
 	output := []v1beta1.PipelineResourceResult{}
 	for _, resultFile := range e.Results {
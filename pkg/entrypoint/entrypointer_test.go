@@ -34,6 +34,11 @@ import (
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/termination"
 	"github.com/tektoncd/pipeline/test/diff"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"knative.dev/pkg/logging"
 )
 
@@ -435,6 +440,204 @@ func TestEntrypointer_OnError(t *testing.T) {
 	}
 }
 
+func TestEntrypointer_Tracing(t *testing.T) {
+	spanContext := propagation.MapCarrier{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}
+	for _, c := range []struct {
+		desc       string
+		runner     Runner
+		wantStatus codes.Code
+	}{{
+		desc:       "successful step",
+		runner:     &fakeRunner{},
+		wantStatus: codes.Unset,
+	}, {
+		desc:       "failed step",
+		runner:     &fakeErrorRunner{},
+		wantStatus: codes.Error,
+	}} {
+		t.Run(c.desc, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			_ = Entrypointer{
+				Command:     []string{"echo", "some", "args"},
+				Runner:      c.runner,
+				Tracer:      tp.Tracer("test"),
+				SpanContext: spanContext,
+				StepName:    "step-one",
+			}.run(context.Background())
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+			span := spans[0]
+			if span.Name() != "Step:Run" {
+				t.Errorf("expected span Step:Run, got %s", span.Name())
+			}
+			if got := span.Parent().TraceID().String(); got != "0af7651916cd43dd8448eb211c80319c" {
+				t.Errorf("expected span in trace of the TaskRun, got trace %s", got)
+			}
+			if got := span.Parent().SpanID().String(); got != "b7ad6b7169203331" {
+				t.Errorf("expected span to be a child of the TaskRun span, got parent %s", got)
+			}
+			if d := cmp.Diff([]attribute.KeyValue{attribute.String("step", "step-one")}, span.Attributes(), cmp.AllowUnexported(attribute.Value{})); d != "" {
+				t.Errorf("span attributes %s", diff.PrintWantGot(d))
+			}
+			if span.Status().Code != c.wantStatus {
+				t.Errorf("expected span status %v, got %v", c.wantStatus, span.Status().Code)
+			}
+		})
+	}
+}
+
+func TestEntrypointer_Cancel(t *testing.T) {
+	for _, c := range []struct {
+		desc      string
+		waitFiles []string
+		onCancel  string
+		wantRuns  int
+	}{{
+		desc:     "running step is terminated and its onCancel script is run",
+		onCancel: "echo cancelled",
+		wantRuns: 2,
+	}, {
+		desc:     "running step without onCancel script is terminated",
+		wantRuns: 1,
+	}, {
+		desc:      "waiting step is not run",
+		waitFiles: []string{"previous-step"},
+		onCancel:  "echo cancelled",
+		wantRuns:  0,
+	}} {
+		t.Run(c.desc, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "cancel")
+			if err != nil {
+				t.Fatalf("unexpected error creating temporary directory: %v", err)
+			}
+			defer os.RemoveAll(dir)
+			terminationPath := filepath.Join(dir, "termination")
+			postFile := filepath.Join(dir, "out")
+			fpw := &fakePostWriter{}
+			fr := &fakeCancelledRunner{}
+
+			err = Entrypointer{
+				Command:         []string{"echo", "some", "args"},
+				WaitFiles:       c.waitFiles,
+				PostFile:        postFile,
+				Waiter:          &fakeCancelWaiter{cancelFile: "cancel"},
+				Runner:          fr,
+				PostWriter:      fpw,
+				TerminationPath: terminationPath,
+				OnError:         ContinueOnError,
+				CancelFile:      "cancel",
+				OnCancel:        c.onCancel,
+			}.Go()
+			if err != context.Canceled {
+				t.Fatalf("expected %v, got %v", context.Canceled, err)
+			}
+
+			if fpw.wrote == nil || *fpw.wrote != postFile+".err" {
+				t.Errorf("expected post file %q to be written", postFile+".err")
+			}
+			if len(fr.runs) != c.wantRuns {
+				t.Fatalf("expected %d runs, got %v", c.wantRuns, fr.runs)
+			}
+			if c.wantRuns == 2 {
+				scriptFile := filepath.Join(dir, onCancelScriptFile)
+				if d := cmp.Diff([]string{scriptFile}, fr.runs[1]); d != "" {
+					t.Errorf("onCancel script not run %s", diff.PrintWantGot(d))
+				}
+				if !fr.hadDeadlines[1] {
+					t.Error("expected the onCancel script to be run with a deadline")
+				}
+				script, err := ioutil.ReadFile(scriptFile)
+				if err != nil {
+					t.Fatalf("unexpected error reading onCancel script: %v", err)
+				}
+				if d := cmp.Diff(defaultScriptPreamble+c.onCancel, string(script)); d != "" {
+					t.Errorf("onCancel script %s", diff.PrintWantGot(d))
+				}
+			}
+
+			fileContents, err := ioutil.ReadFile(terminationPath)
+			if err != nil {
+				t.Fatalf("unexpected error reading termination message: %v", err)
+			}
+			var results []v1beta1.PipelineResourceResult
+			if err := json.Unmarshal(fileContents, &results); err != nil {
+				t.Fatalf("unexpected error unmarshalling termination message: %v", err)
+			}
+			found := false
+			for _, r := range results {
+				if r.Key == "Reason" && r.Value == "Cancelled" {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected the step to report it was cancelled, got %v", results)
+			}
+		})
+	}
+}
+
+func TestEntrypointer_GoSidecar(t *testing.T) {
+	fpw := &fakePostWriter{}
+	fr := &fakeStoppedRunner{}
+	err := Entrypointer{
+		Command:    []string{"proxy", "--port", "8080"},
+		Waiter:     &fakeCancelWaiter{cancelFile: "stop"},
+		Runner:     fr,
+		PostWriter: fpw,
+		StopFile:   "stop",
+	}.GoSidecar()
+	if err != nil {
+		t.Fatalf("expected the sidecar to exit cleanly once stopped, got %v", err)
+	}
+	if d := cmp.Diff([][]string{{"proxy", "--port", "8080"}}, fr.runs); d != "" {
+		t.Errorf("sidecar runs %s", diff.PrintWantGot(d))
+	}
+	if !fr.stopped {
+		t.Error("expected the sidecar to be stopped")
+	}
+	if fpw.wrote != nil {
+		t.Errorf("expected no post file to be written, got %q", *fpw.wrote)
+	}
+}
+
+// TestEntrypointer_GoSidecarGracePeriodExceeded tests whether a sidecar killed after its
+// grace period fails and reports the GracePeriodExceeded reason in its termination message.
+func TestEntrypointer_GoSidecarGracePeriodExceeded(t *testing.T) {
+	terminationFile, err := ioutil.TempFile("", "termination")
+	if err != nil {
+		t.Fatalf("unexpected error creating temporary termination file: %v", err)
+	}
+	defer os.Remove(terminationFile.Name())
+	err = Entrypointer{
+		Command:         []string{"proxy"},
+		Waiter:          &fakeCancelWaiter{cancelFile: "stop"},
+		Runner:          &fakeKilledRunner{},
+		PostWriter:      &fakePostWriter{},
+		StopFile:        "stop",
+		TerminationPath: terminationFile.Name(),
+	}.GoSidecar()
+	if !errors.Is(err, ErrGracePeriodExceeded) {
+		t.Fatalf("expected the sidecar to fail with %v, got %v", ErrGracePeriodExceeded, err)
+	}
+	msg, err := ioutil.ReadFile(terminationFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger, _ := logging.NewLogger("", "status")
+	got, err := termination.ParseMessage(logger, string(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]v1beta1.PipelineResourceResult{gracePeriodExceededResult}, got); d != "" {
+		t.Errorf("termination message %s", diff.PrintWantGot(d))
+	}
+}
+
 type fakeWaiter struct{ waited []string }
 
 func (f *fakeWaiter) Wait(file string, _ bool, _ bool) error {
@@ -504,3 +707,53 @@ func (f *fakeExitErrorRunner) Run(ctx context.Context, args ...string) error {
 	f.args = &args
 	return exec.Command("ls", "/bogus/path").Run()
 }
+
+// fakeCancelWaiter returns right away for the cancel file, and blocks for
+// any other file.
+type fakeCancelWaiter struct{ cancelFile string }
+
+func (f *fakeCancelWaiter) Wait(file string, _ bool, _ bool) error {
+	if file != f.cancelFile {
+		select {}
+	}
+	return nil
+}
+
+// fakeCancelledRunner runs the step until it is cancelled, and any other
+// command right away, recording whether it had a deadline.
+type fakeCancelledRunner struct {
+	runs         [][]string
+	hadDeadlines []bool
+}
+
+func (f *fakeCancelledRunner) Run(ctx context.Context, args ...string) error {
+	f.runs = append(f.runs, args)
+	_, hasDeadline := ctx.Deadline()
+	f.hadDeadlines = append(f.hadDeadlines, hasDeadline)
+	if len(f.runs) > 1 {
+		return nil
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// fakeStoppedRunner runs the sidecar until it is stopped, and then reports
+// that it exited cleanly.
+type fakeKilledRunner struct{}
+
+func (f *fakeKilledRunner) Run(ctx context.Context, args ...string) error {
+	<-ctx.Done()
+	return fmt.Errorf("%w: signal: killed", ErrGracePeriodExceeded)
+}
+
+type fakeStoppedRunner struct {
+	runs    [][]string
+	stopped bool
+}
+
+func (f *fakeStoppedRunner) Run(ctx context.Context, args ...string) error {
+	f.runs = append(f.runs, args)
+	<-ctx.Done()
+	f.stopped = true
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crossnamespace checks whether a run may reference a Task or
+// Pipeline that lives in a namespace other than its own.
+package crossnamespace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+const defaultServiceAccountName = "default"
+
+// Reference describes a request, made on behalf of a service account in
+// Namespace, to read the named Resource from TargetNamespace.
+type Reference struct {
+	Namespace          string
+	ServiceAccountName string
+	TargetNamespace    string
+	Resource           schema.GroupResource
+	Name               string
+}
+
+// Authorize returns an error unless ref may be resolved. The target
+// namespace must list the referring namespace in its
+// pipeline.AllowReferencesFromAnnotationKey annotation, and a
+// SubjectAccessReview must confirm that the referring service account is
+// allowed to get the resource in the target namespace.
+func Authorize(ctx context.Context, k8s kubernetes.Interface, ref Reference) error {
+	ns, err := k8s.CoreV1().Namespaces().Get(ctx, ref.TargetNamespace, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get namespace %s: %w", ref.TargetNamespace, err)
+	}
+	if !allowsReferencesFrom(ns.Annotations[pipeline.AllowReferencesFromAnnotationKey], ref.Namespace) {
+		return fmt.Errorf("namespace %s does not allow references from namespace %s", ref.TargetNamespace, ref.Namespace)
+	}
+
+	saName := ref.ServiceAccountName
+	if saName == "" {
+		saName = defaultServiceAccountName
+	}
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   fmt.Sprintf("system:serviceaccount:%s:%s", ref.Namespace, saName),
+			Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + ref.Namespace},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: ref.TargetNamespace,
+				Verb:      "get",
+				Group:     ref.Resource.Group,
+				Resource:  ref.Resource.Resource,
+				Name:      ref.Name,
+			},
+		},
+	}
+	sar, err = k8s.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to review access to %s %s/%s: %w", ref.Resource.String(), ref.TargetNamespace, ref.Name, err)
+	}
+	if !sar.Status.Allowed {
+		return fmt.Errorf("service account %s/%s is not allowed to get %s %s/%s: %s", ref.Namespace, saName, ref.Resource.String(), ref.TargetNamespace, ref.Name, sar.Status.Reason)
+	}
+	return nil
+}
+
+// allowsReferencesFrom reports whether the comma-separated list of
+// namespaces in value contains namespace or the "*" wildcard.
+func allowsReferencesFrom(value, namespace string) bool {
+	for _, allowed := range strings.Split(value, ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossnamespace_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/internal/crossnamespace"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakek8s "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	ref := crossnamespace.Reference{
+		Namespace:       "team",
+		TargetNamespace: "catalog",
+		Resource:        pipeline.TaskResource,
+		Name:            "git-clone",
+	}
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		allowedUser string
+		wantErr     string
+	}{{
+		name:        "namespace allows all namespaces",
+		annotations: map[string]string{pipeline.AllowReferencesFromAnnotationKey: "*"},
+		allowedUser: "system:serviceaccount:team:default",
+	}, {
+		name:        "namespace allows the referring namespace",
+		annotations: map[string]string{pipeline.AllowReferencesFromAnnotationKey: "other, team"},
+		allowedUser: "system:serviceaccount:team:default",
+	}, {
+		name:        "namespace has not opted in",
+		allowedUser: "system:serviceaccount:team:default",
+		wantErr:     "namespace catalog does not allow references from namespace team",
+	}, {
+		name:        "namespace allows other namespaces only",
+		annotations: map[string]string{pipeline.AllowReferencesFromAnnotationKey: "other"},
+		allowedUser: "system:serviceaccount:team:default",
+		wantErr:     "namespace catalog does not allow references from namespace team",
+	}, {
+		name:        "service account is not allowed to get the task",
+		annotations: map[string]string{pipeline.AllowReferencesFromAnnotationKey: "*"},
+		allowedUser: "system:serviceaccount:team:builder",
+		wantErr:     "service account team/default is not allowed to get tasks.tekton.dev catalog/git-clone",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			k8s := fakek8s.NewSimpleClientset(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "catalog", Annotations: tc.annotations},
+			})
+			k8s.PrependReactor("create", "subjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+				sar := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				attrs := sar.Spec.ResourceAttributes
+				sar.Status.Allowed = sar.Spec.User == tc.allowedUser && attrs.Verb == "get" &&
+					attrs.Namespace == "catalog" && attrs.Resource == "tasks" && attrs.Name == "git-clone"
+				return true, sar, nil
+			})
+
+			err := crossnamespace.Authorize(context.Background(), k8s, ref)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("Authorize() = %v, want no error", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Fatalf("Authorize() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthorize_MissingNamespace(t *testing.T) {
+	k8s := fakek8s.NewSimpleClientset()
+	err := crossnamespace.Authorize(context.Background(), k8s, crossnamespace.Reference{
+		Namespace:       "team",
+		TargetNamespace: "catalog",
+		Resource:        pipeline.PipelineResource,
+		Name:            "release",
+	})
+	if err == nil {
+		t.Fatal("expected an error for a missing target namespace")
+	}
+}
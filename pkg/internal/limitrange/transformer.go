@@ -58,6 +58,7 @@ func NewTransformer(ctx context.Context, namespace string, lister corev1listers.
 		defaultContainerLimits := getDefaultLimits(limitRange)
 		defaultContainerRequests := getDefaultContainerRequest(limitRange)
 		defaultStepContainerRequests := getDefaultStepContainerRequest(limitRange, nbStepContainers)
+		minContainerRequests := getMinContainerRequest(limitRange)
 
 		for i := range p.Spec.InitContainers {
 			// We are trying to set the smallest requests possible
@@ -88,6 +89,12 @@ func NewTransformer(ctx context.Context, namespace string, lister corev1listers.
 				p.Spec.Containers[i].Resources.Requests = defaultRequests
 			} else {
 				for _, name := range resourceNames {
+					// A step explicitly requesting nothing, like the steps which do not hold the
+					// compute resources of the whole Task, is only given the minimal request.
+					if request, ok := p.Spec.Containers[i].Resources.Requests[name]; ok && isZero(request) && pod.IsContainerStep(c.Name) {
+						setRequestsOrLimits(name, p.Spec.Containers[i].Resources.Requests, minContainerRequests)
+						continue
+					}
 					setRequestsOrLimits(name, p.Spec.Containers[i].Resources.Requests, defaultRequests)
 				}
 			}
@@ -165,6 +172,17 @@ func getDefaultContainerRequest(limitRange *corev1.LimitRange) corev1.ResourceLi
 	return r
 }
 
+// Returns the minimal requests of the LimitRange for containers
+func getMinContainerRequest(limitRange *corev1.LimitRange) corev1.ResourceList {
+	var r corev1.ResourceList
+	for _, item := range limitRange.Spec.Limits {
+		if item.Type == corev1.LimitTypeContainer && item.Min != nil {
+			r = item.Min
+		}
+	}
+	return r
+}
+
 func takeTheMax(requestQ, defaultQ, maxQ resource.Quantity) resource.Quantity {
 	var q resource.Quantity = requestQ
 	if defaultQ.Cmp(q) > 0 {
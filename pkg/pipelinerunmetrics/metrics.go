@@ -43,6 +43,7 @@ var (
 	pipelineTag    = tag.MustNewKey("pipeline")
 	namespaceTag   = tag.MustNewKey("namespace")
 	statusTag      = tag.MustNewKey("status")
+	reasonTag      = tag.MustNewKey("reason")
 
 	prDuration = stats.Float64(
 		"pipelinerun_duration_seconds",
@@ -59,6 +60,26 @@ var (
 		"Number of pipelineruns executing currently",
 		stats.UnitDimensionless)
 	runningPRsCountView *view.View
+
+	firstTaskLatency = stats.Float64("pipelinerun_first_task_latency_seconds",
+		"The time from the creation of a pipelinerun to the creation of its first taskrun or run in seconds",
+		stats.UnitDimensionless)
+	firstTaskLatencyView *view.View
+
+	resolutionDuration = stats.Float64("pipelinerun_resolution_duration_seconds",
+		"The time a pipelinerun waited for its pipeline or tasks to be resolved in seconds",
+		stats.UnitDimensionless)
+	resolutionDurationView *view.View
+
+	pendingDuration = stats.Float64("pipelinerun_pending_duration_seconds",
+		"The time a pipelinerun spent pending in seconds",
+		stats.UnitDimensionless)
+	pendingDurationView *view.View
+
+	schedulingDelay = stats.Float64("pipelinerun_task_scheduling_delay_seconds",
+		"The time from a pipeline task being ready to be scheduled to the creation of its taskrun or run in seconds",
+		stats.UnitDimensionless)
+	schedulingDelayView *view.View
 )
 
 const (
@@ -76,6 +97,8 @@ type Recorder struct {
 	insertTag func(pipeline,
 		pipelinerun string) []tag.Mutator
 
+	insertReasonTag func(reason string) []tag.Mutator
+
 	ReportingPeriod time.Duration
 }
 
@@ -115,6 +138,10 @@ func viewRegister(cfg *config.Metrics) error {
 	defer r.mutex.Unlock()
 
 	prunTag := []tag.Key{}
+	// The reason tag is only added when metrics are not aggregated at
+	// namespace level, to keep the cardinality low.
+	statusTags := []tag.Key{statusTag, reasonTag}
+	r.insertReasonTag = reasonInsertTag
 
 	switch cfg.PipelinerunLevel {
 	case config.PipelinerunLevelAtPipelinerun:
@@ -126,6 +153,8 @@ func viewRegister(cfg *config.Metrics) error {
 	case config.PipelinerunLevelAtNS:
 		prunTag = []tag.Key{}
 		r.insertTag = nilInsertTag
+		statusTags = []tag.Key{statusTag}
+		r.insertReasonTag = nilReasonInsertTag
 	default:
 		return errors.New("invalid config for PipelinerunLevel: " + cfg.PipelinerunLevel)
 	}
@@ -144,18 +173,25 @@ func viewRegister(cfg *config.Metrics) error {
 		}
 	}
 
+	// Latencies are expected to be much shorter than the runs themselves, so
+	// their histograms have finer buckets.
+	latencyDistribution := view.Distribution(0.1, 0.5, 1, 2, 5, 10, 30, 60, 300, 900, 1800, 3600)
+	if distribution.Type == view.AggTypeLastValue {
+		latencyDistribution = distribution
+	}
+
 	prDurationView = &view.View{
 		Description: prDuration.Description(),
 		Measure:     prDuration,
 		Aggregation: distribution,
-		TagKeys:     append([]tag.Key{statusTag, namespaceTag}, prunTag...),
+		TagKeys:     append([]tag.Key{namespaceTag}, append(statusTags, prunTag...)...),
 	}
 
 	prCountView = &view.View{
 		Description: prCount.Description(),
 		Measure:     prCount,
 		Aggregation: view.Count(),
-		TagKeys:     []tag.Key{statusTag},
+		TagKeys:     statusTags,
 	}
 	runningPRsCountView = &view.View{
 		Description: runningPRsCount.Description(),
@@ -163,15 +199,45 @@ func viewRegister(cfg *config.Metrics) error {
 		Aggregation: view.LastValue(),
 	}
 
+	firstTaskLatencyView = &view.View{
+		Description: firstTaskLatency.Description(),
+		Measure:     firstTaskLatency,
+		Aggregation: latencyDistribution,
+		TagKeys:     append([]tag.Key{namespaceTag}, prunTag...),
+	}
+	resolutionDurationView = &view.View{
+		Description: resolutionDuration.Description(),
+		Measure:     resolutionDuration,
+		Aggregation: latencyDistribution,
+		TagKeys:     append([]tag.Key{namespaceTag}, prunTag...),
+	}
+	pendingDurationView = &view.View{
+		Description: pendingDuration.Description(),
+		Measure:     pendingDuration,
+		Aggregation: latencyDistribution,
+		TagKeys:     append([]tag.Key{namespaceTag}, prunTag...),
+	}
+	schedulingDelayView = &view.View{
+		Description: schedulingDelay.Description(),
+		Measure:     schedulingDelay,
+		Aggregation: latencyDistribution,
+		TagKeys:     append([]tag.Key{namespaceTag}, prunTag...),
+	}
+
 	return view.Register(
 		prDurationView,
 		prCountView,
 		runningPRsCountView,
+		firstTaskLatencyView,
+		resolutionDurationView,
+		pendingDurationView,
+		schedulingDelayView,
 	)
 }
 
 func viewUnregister() {
-	view.Unregister(prDurationView, prCountView, runningPRsCountView)
+	view.Unregister(prDurationView, prCountView, runningPRsCountView,
+		firstTaskLatencyView, resolutionDurationView, pendingDurationView, schedulingDelayView)
 }
 
 // MetricsOnStore returns a function that checks if metrics are configured for a config.Store, and registers it if so
@@ -207,6 +273,14 @@ func nilInsertTag(task, taskrun string) []tag.Mutator {
 	return []tag.Mutator{}
 }
 
+func reasonInsertTag(reason string) []tag.Mutator {
+	return []tag.Mutator{tag.Insert(reasonTag, reason)}
+}
+
+func nilReasonInsertTag(reason string) []tag.Mutator {
+	return []tag.Mutator{}
+}
+
 // DurationAndCount logs the duration of PipelineRun execution and
 // count for number of PipelineRuns succeed or failed
 // returns an error if its failed to log the metrics
@@ -248,7 +322,9 @@ func (r *Recorder) DurationAndCount(pr *v1beta1.PipelineRun, beforeCondition *ap
 	ctx, err := tag.New(
 		context.Background(),
 		append([]tag.Mutator{tag.Insert(namespaceTag, pr.Namespace),
-			tag.Insert(statusTag, status)}, r.insertTag(pipelineName, pr.Name)...)...)
+			tag.Insert(statusTag, status)},
+			append(r.insertReasonTag(afterCondition.GetReason()),
+				r.insertTag(pipelineName, pr.Name)...)...)...)
 	if err != nil {
 		return err
 	}
@@ -259,6 +335,60 @@ func (r *Recorder) DurationAndCount(pr *v1beta1.PipelineRun, beforeCondition *ap
 	return nil
 }
 
+// FirstTaskLatency logs the time from the creation of the PipelineRun to the
+// creation of its first TaskRun or Run
+// returns an error if its failed to log the metrics
+func (r *Recorder) FirstTaskLatency(pr *v1beta1.PipelineRun, latency time.Duration) error {
+	return r.recordLatency(pr, firstTaskLatency, latency)
+}
+
+// ResolutionDuration logs the time the PipelineRun waited for the remote
+// resolution of its Pipeline or Tasks
+// returns an error if its failed to log the metrics
+func (r *Recorder) ResolutionDuration(pr *v1beta1.PipelineRun, duration time.Duration) error {
+	return r.recordLatency(pr, resolutionDuration, duration)
+}
+
+// PendingDuration logs the time the PipelineRun spent pending before it started
+// returns an error if its failed to log the metrics
+func (r *Recorder) PendingDuration(pr *v1beta1.PipelineRun, duration time.Duration) error {
+	return r.recordLatency(pr, pendingDuration, duration)
+}
+
+// SchedulingDelay logs the time from a pipeline task of the PipelineRun being
+// ready to be scheduled to the creation of its TaskRun or Run
+// returns an error if its failed to log the metrics
+func (r *Recorder) SchedulingDelay(pr *v1beta1.PipelineRun, delay time.Duration) error {
+	return r.recordLatency(pr, schedulingDelay, delay)
+}
+
+func (r *Recorder) recordLatency(pr *v1beta1.PipelineRun, measure *stats.Float64Measure, latency time.Duration) error {
+	if !r.initialized {
+		return fmt.Errorf("ignoring the metrics recording for %s , failed to initialize the metrics recorder", pr.Name)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	pipelineName := "anonymous"
+	if pr.Spec.PipelineRef != nil && pr.Spec.PipelineRef.Name != "" {
+		pipelineName = pr.Spec.PipelineRef.Name
+	}
+	ctx, err := tag.New(
+		context.Background(),
+		append([]tag.Mutator{tag.Insert(namespaceTag, pr.Namespace)},
+			r.insertTag(pipelineName, pr.Name)...)...)
+	if err != nil {
+		return err
+	}
+
+	if latency < 0 {
+		latency = 0
+	}
+	metrics.Record(ctx, measure.M(latency.Seconds()))
+	return nil
+}
+
 // RunningPipelineRuns logs the number of PipelineRuns running right now
 // returns an error if its failed to log the metrics
 func (r *Recorder) RunningPipelineRuns(lister listers.PipelineRunLister) error {
@@ -64,6 +64,9 @@ func TestUninitializedMetrics(t *testing.T) {
 	if err := metrics.RunningPipelineRuns(nil); err == nil {
 		t.Error("Current PR count recording expected to return error but got nil")
 	}
+	if err := metrics.SchedulingDelay(&v1beta1.PipelineRun{}, time.Second); err == nil {
+		t.Error("Scheduling delay recording expected to return error but got nil")
+	}
 }
 
 func TestMetricsOnStore(t *testing.T) {
@@ -129,6 +132,7 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionTrue,
+						Reason: "Succeeded",
 					}},
 				},
 				PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
@@ -142,9 +146,11 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 			"pipelinerun": "pipelinerun-1",
 			"namespace":   "ns",
 			"status":      "success",
+			"reason":      "Succeeded",
 		},
 		expectedCountTags: map[string]string{
 			"status": "success",
+			"reason": "Succeeded",
 		},
 		expectedDuration: 60,
 		expectedCount:    1,
@@ -161,6 +167,7 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionTrue,
+						Reason: "Succeeded",
 					}},
 				},
 				PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
@@ -174,9 +181,11 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 			"pipelinerun": "pipelinerun-1",
 			"namespace":   "ns",
 			"status":      "success",
+			"reason":      "Succeeded",
 		},
 		expectedCountTags: map[string]string{
 			"status": "success",
+			"reason": "Succeeded",
 		},
 		expectedDuration: 60,
 		expectedCount:    1,
@@ -196,6 +205,7 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionTrue,
+						Reason: "Succeeded",
 					}},
 				},
 				PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
@@ -211,6 +221,7 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 		beforeCondition: &apis.Condition{
 			Type:   apis.ConditionSucceeded,
 			Status: corev1.ConditionTrue,
+			Reason: "Succeeded",
 		},
 	}, {
 		name: "for cancelled pipeline",
@@ -238,9 +249,11 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 			"pipelinerun": "pipelinerun-1",
 			"namespace":   "ns",
 			"status":      "cancelled",
+			"reason":      "Cancelled",
 		},
 		expectedCountTags: map[string]string{
 			"status": "cancelled",
+			"reason": "Cancelled",
 		},
 		expectedDuration: 60,
 		expectedCount:    1,
@@ -257,6 +270,7 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionFalse,
+						Reason: "Failed",
 					}},
 				},
 				PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
@@ -270,9 +284,11 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 			"pipelinerun": "pipelinerun-1",
 			"namespace":   "ns",
 			"status":      "failed",
+			"reason":      "Failed",
 		},
 		expectedCountTags: map[string]string{
 			"status": "failed",
+			"reason": "Failed",
 		},
 		expectedDuration: 60,
 		expectedCount:    1,
@@ -290,6 +306,7 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionFalse,
+						Reason: "Failed",
 					}},
 				},
 			},
@@ -299,9 +316,11 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 			"pipelinerun": "pipelinerun-1",
 			"namespace":   "ns",
 			"status":      "failed",
+			"reason":      "Failed",
 		},
 		expectedCountTags: map[string]string{
 			"status": "failed",
+			"reason": "Failed",
 		},
 		expectedDuration: 0,
 		expectedCount:    1,
@@ -334,6 +353,120 @@ func TestRecordPipelineRunDurationCount(t *testing.T) {
 	}
 }
 
+func TestRecordPipelineRunDurationCountAtNamespaceLevel(t *testing.T) {
+	pipelineRun := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipelinerun-1", Namespace: "ns"},
+		Spec: v1beta1.PipelineRunSpec{
+			PipelineRef: &v1beta1.PipelineRef{Name: "pipeline-1"},
+		},
+		Status: v1beta1.PipelineRunStatus{
+			Status: duckv1beta1.Status{
+				Conditions: duckv1beta1.Conditions{{
+					Type:   apis.ConditionSucceeded,
+					Status: corev1.ConditionFalse,
+					Reason: "PipelineRunTimeout",
+				}},
+			},
+			PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
+				StartTime:      &startTime,
+				CompletionTime: &completionTime,
+			},
+		},
+	}
+
+	unregisterMetrics()
+	ctx := config.ToContext(context.Background(), &config.Config{
+		Metrics: &config.Metrics{
+			TaskrunLevel:            config.TaskrunLevelAtNS,
+			PipelinerunLevel:        config.PipelinerunLevelAtNS,
+			DurationTaskrunType:     config.DurationTaskrunTypeLastValue,
+			DurationPipelinerunType: config.DurationPipelinerunTypeLastValue,
+		},
+	})
+	metrics, err := NewRecorder(ctx)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	if err := metrics.DurationAndCount(pipelineRun, nil); err != nil {
+		t.Errorf("DurationAndCount: %v", err)
+	}
+	// The reason tag is dropped at namespace level.
+	metricstest.CheckLastValueData(t, "pipelinerun_duration_seconds", map[string]string{
+		"namespace": "ns",
+		"status":    "failed",
+	}, 60)
+	metricstest.CheckCountData(t, "pipelinerun_count", map[string]string{
+		"status": "failed",
+	}, 1)
+}
+
+func TestRecordPipelineRunLatencies(t *testing.T) {
+	pipelineRun := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipelinerun-1", Namespace: "ns"},
+		Spec: v1beta1.PipelineRunSpec{
+			PipelineRef: &v1beta1.PipelineRef{Name: "pipeline-1"},
+		},
+	}
+	expectedTags := map[string]string{
+		"pipeline":    "pipeline-1",
+		"pipelinerun": "pipelinerun-1",
+		"namespace":   "ns",
+	}
+
+	for _, tc := range []struct {
+		metricName string
+		record     func(*Recorder) error
+		expected   float64
+	}{{
+		metricName: "pipelinerun_first_task_latency_seconds",
+		record: func(r *Recorder) error {
+			return r.FirstTaskLatency(pipelineRun, 1500*time.Millisecond)
+		},
+		expected: 1.5,
+	}, {
+		metricName: "pipelinerun_resolution_duration_seconds",
+		record: func(r *Recorder) error {
+			return r.ResolutionDuration(pipelineRun, 20*time.Second)
+		},
+		expected: 20,
+	}, {
+		metricName: "pipelinerun_pending_duration_seconds",
+		record: func(r *Recorder) error {
+			return r.PendingDuration(pipelineRun, time.Minute)
+		},
+		expected: 60,
+	}, {
+		metricName: "pipelinerun_task_scheduling_delay_seconds",
+		record: func(r *Recorder) error {
+			return r.SchedulingDelay(pipelineRun, 250*time.Millisecond)
+		},
+		expected: 0.25,
+	}, {
+		metricName: "pipelinerun_task_scheduling_delay_seconds",
+		record: func(r *Recorder) error {
+			// Negative latencies caused by clock skew are recorded as zero.
+			return r.SchedulingDelay(pipelineRun, -time.Second)
+		},
+		expected: 0,
+	}} {
+		t.Run(tc.metricName, func(t *testing.T) {
+			unregisterMetrics()
+
+			ctx := getConfigContext()
+			metrics, err := NewRecorder(ctx)
+			if err != nil {
+				t.Fatalf("NewRecorder: %v", err)
+			}
+
+			if err := tc.record(metrics); err != nil {
+				t.Errorf("recording %s: %v", tc.metricName, err)
+			}
+			metricstest.CheckLastValueData(t, tc.metricName, expectedTags, tc.expected)
+		})
+	}
+}
+
 func TestRecordRunningPipelineRunsCount(t *testing.T) {
 	unregisterMetrics()
 
@@ -378,7 +511,9 @@ func TestRecordRunningPipelineRunsCount(t *testing.T) {
 }
 
 func unregisterMetrics() {
-	metricstest.Unregister("pipelinerun_duration_seconds", "pipelinerun_count", "running_pipelineruns_count")
+	metricstest.Unregister("pipelinerun_duration_seconds", "pipelinerun_count", "running_pipelineruns_count",
+		"pipelinerun_first_task_latency_seconds", "pipelinerun_resolution_duration_seconds",
+		"pipelinerun_pending_duration_seconds", "pipelinerun_task_scheduling_delay_seconds")
 
 	// Allow the recorder singleton to be recreated.
 	once = sync.Once{}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/entrypoint"
+	"gomodules.xyz/jsonpatch/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// addCancellationArgs configures the entrypoint of each step to watch for the
+// cancel annotation projected via the Downward API when graceful cancellation
+// is enabled, and to run the onCancel script of the step once it is cancelled.
+func addCancellationArgs(ctx context.Context, taskSpec *v1beta1.TaskSpec, steps []corev1.Container) []corev1.Container {
+	if !config.FromContextOrDefaults(ctx).FeatureFlags.EnableGracefulCancellation {
+		return steps
+	}
+	for i, s := range steps {
+		args := []string{"-cancel_file", filepath.Join(downwardMountPoint, downwardMountCancelFile)}
+		if i < len(taskSpec.Steps) && taskSpec.Steps[i].OnCancel != "" {
+			args = append(args, "-on_cancel", taskSpec.Steps[i].OnCancel)
+		}
+		// The entrypoint flags precede the "--" separating them from the
+		// step's own args, so the cancellation flags can be prepended.
+		steps[i].Args = append(args, s.Args...)
+		// The first step already mounts the Downward volume to wait for
+		// the ready annotation.
+		if i > 0 {
+			steps[i].VolumeMounts = append(steps[i].VolumeMounts, downwardMount)
+		}
+	}
+	return steps
+}
+
+// makeDownwardVolume returns the Downward volume of the pod, which also
+// projects the cancel annotation when graceful cancellation is enabled, and
+// the stop sidecars annotation when graceful sidecar stop is enabled.
+func makeDownwardVolume(ctx context.Context) corev1.Volume {
+	cfg := config.FromContextOrDefaults(ctx)
+	if !cfg.FeatureFlags.EnableGracefulCancellation && !cfg.FeatureFlags.EnableGracefulSidecarStop {
+		return downwardVolume
+	}
+	items := append([]corev1.DownwardAPIVolumeFile{}, downwardVolume.DownwardAPI.Items...)
+	if cfg.FeatureFlags.EnableGracefulCancellation {
+		items = append(items, corev1.DownwardAPIVolumeFile{
+			Path: downwardMountCancelFile,
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: fmt.Sprintf("metadata.annotations['%s']", cancelAnnotation),
+			},
+		})
+	}
+	if cfg.FeatureFlags.EnableGracefulSidecarStop {
+		items = append(items, corev1.DownwardAPIVolumeFile{
+			Path: downwardMountStopSidecarsFile,
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: fmt.Sprintf("metadata.annotations['%s']", stopSidecarsAnnotation),
+			},
+		})
+	}
+	return corev1.Volume{
+		Name: downwardVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{Items: items},
+		},
+	}
+}
+
+// cancelPropagationDelay is the time allowed for the kubelet to project the
+// cancel annotation into the steps, which it does when it syncs the pod.
+const cancelPropagationDelay = time.Minute
+
+// CancelGracePeriod returns the time given to the steps of a TaskRun cancelled
+// gracefully to terminate and run their onCancel script, after which the pod
+// of the TaskRun is deleted. It leaves the running step its grace period and
+// its onCancel script its timeout once the cancel annotation reached the step.
+func CancelGracePeriod(taskSpec *v1beta1.TaskSpec) time.Duration {
+	stepGracePeriod := entrypoint.StepCancelGracePeriod
+	if taskSpec != nil {
+		for _, s := range taskSpec.Steps {
+			if s.GracePeriod != nil && s.GracePeriod.Duration > stepGracePeriod {
+				stepGracePeriod = s.GracePeriod.Duration
+			}
+		}
+	}
+	return cancelPropagationDelay + stepGracePeriod + entrypoint.OnCancelTimeout
+}
+
+// CancelPod updates the Pod's annotations to signal its steps that the
+// TaskRun was cancelled by projecting the cancel annotation via the Downward
+// API.
+func CancelPod(ctx context.Context, kubeclient kubernetes.Interface, namespace, name string) error {
+	_, err := kubeclient.CoreV1().Pods(namespace).Patch(ctx, name, types.JSONPatchType, addCancelPatchBytes, metav1.PatchOptions{})
+	return err
+}
+
+var addCancelPatchBytes []byte
+
+func init() {
+	// https://stackoverflow.com/questions/55573724/create-a-patch-to-add-a-kubernetes-annotation
+	cancelAnnotationPath := "/metadata/annotations/" + strings.Replace(cancelAnnotation, "/", "~1", 1)
+	var err error
+	addCancelPatchBytes, err = json.Marshal([]jsonpatch.JsonPatchOperation{{
+		Operation: "add",
+		Path:      cancelAnnotationPath,
+		Value:     cancelAnnotationValue,
+	}})
+	if err != nil {
+		log.Fatalf("failed to marshal add cancel patch bytes: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/entrypoint"
+	"github.com/tektoncd/pipeline/test/diff"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakek8s "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAddCancellationArgs(t *testing.T) {
+	taskSpec := &v1beta1.TaskSpec{
+		Steps: []v1beta1.Step{{
+			Name:     "foo",
+			OnCancel: "echo cleanup",
+		}, {
+			Name: "bar",
+		}},
+	}
+	steps := func() []corev1.Container {
+		return []corev1.Container{{
+			Name:         "step-foo",
+			Args:         []string{"-post_file", "/tekton/run/0/out", "--", "arg"},
+			VolumeMounts: []corev1.VolumeMount{downwardMount},
+		}, {
+			Name: "step-bar",
+			Args: []string{"-post_file", "/tekton/run/1/out", "--", "arg"},
+		}}
+	}
+
+	for _, tc := range []struct {
+		desc         string
+		featureFlags *config.FeatureFlags
+		want         []corev1.Container
+	}{{
+		desc:         "graceful cancellation disabled",
+		featureFlags: &config.FeatureFlags{},
+		want:         steps(),
+	}, {
+		desc:         "graceful cancellation enabled",
+		featureFlags: &config.FeatureFlags{EnableGracefulCancellation: true},
+		want: []corev1.Container{{
+			Name: "step-foo",
+			Args: []string{
+				"-cancel_file", "/tekton/downward/cancel",
+				"-on_cancel", "echo cleanup",
+				"-post_file", "/tekton/run/0/out", "--", "arg",
+			},
+			VolumeMounts: []corev1.VolumeMount{downwardMount},
+		}, {
+			Name: "step-bar",
+			Args: []string{
+				"-cancel_file", "/tekton/downward/cancel",
+				"-post_file", "/tekton/run/1/out", "--", "arg",
+			},
+			VolumeMounts: []corev1.VolumeMount{downwardMount},
+		}},
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := config.ToContext(context.Background(), &config.Config{FeatureFlags: tc.featureFlags})
+			got := addCancellationArgs(ctx, taskSpec, steps())
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("Diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestMakeDownwardVolume(t *testing.T) {
+	ctx := config.ToContext(context.Background(), &config.Config{FeatureFlags: &config.FeatureFlags{}})
+	if d := cmp.Diff(downwardVolume, makeDownwardVolume(ctx)); d != "" {
+		t.Errorf("Diff %s", diff.PrintWantGot(d))
+	}
+
+	ctx = config.ToContext(context.Background(), &config.Config{FeatureFlags: &config.FeatureFlags{EnableGracefulCancellation: true}})
+	want := corev1.Volume{
+		Name: downwardVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{{
+					Path:     "ready",
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations['tekton.dev/ready']"},
+				}, {
+					Path:     "cancel",
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations['tekton.dev/cancel']"},
+				}},
+			},
+		},
+	}
+	if d := cmp.Diff(want, makeDownwardVolume(ctx)); d != "" {
+		t.Errorf("Diff %s", diff.PrintWantGot(d))
+	}
+
+	ctx = config.ToContext(context.Background(), &config.Config{FeatureFlags: &config.FeatureFlags{EnableGracefulSidecarStop: true}})
+	want = corev1.Volume{
+		Name: downwardVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{{
+					Path:     "ready",
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations['tekton.dev/ready']"},
+				}, {
+					Path:     "stop-sidecars",
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations['tekton.dev/stop-sidecars']"},
+				}},
+			},
+		},
+	}
+	if d := cmp.Diff(want, makeDownwardVolume(ctx)); d != "" {
+		t.Errorf("Diff %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestCancelGracePeriod(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		taskSpec        *v1beta1.TaskSpec
+		stepGracePeriod time.Duration
+	}{{
+		name:            "no task spec",
+		stepGracePeriod: entrypoint.StepCancelGracePeriod,
+	}, {
+		name: "steps without grace period",
+		taskSpec: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{Name: "foo"}},
+		},
+		stepGracePeriod: entrypoint.StepCancelGracePeriod,
+	}, {
+		name: "steps with grace periods",
+		taskSpec: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Name:        "foo",
+				GracePeriod: &metav1.Duration{Duration: 2 * time.Minute},
+			}, {
+				Name:        "bar",
+				GracePeriod: &metav1.Duration{Duration: time.Second},
+			}},
+		},
+		stepGracePeriod: 2 * time.Minute,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			// The pod must not be deleted before the running step was given its
+			// grace period and its onCancel script its timeout.
+			if got, min := CancelGracePeriod(tc.taskSpec), tc.stepGracePeriod+entrypoint.OnCancelTimeout; got <= min {
+				t.Errorf("CancelGracePeriod() = %s, want more than %s", got, min)
+			}
+		})
+	}
+}
+
+func TestCancelPod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pod",
+			Annotations: map[string]string{
+				"something": "else",
+			},
+		},
+	}
+	kubeclient := fakek8s.NewSimpleClientset(pod)
+
+	if err := CancelPod(ctx, kubeclient, pod.Namespace, pod.Name); err != nil {
+		t.Fatalf("CancelPod: %v", err)
+	}
+
+	got, err := kubeclient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Getting pod %q after update: %v", pod.Name, err)
+	}
+	wantAnnotations := map[string]string{
+		"something":      "else",
+		cancelAnnotation: cancelAnnotationValue,
+	}
+	if d := cmp.Diff(wantAnnotations, got.Annotations); d != "" {
+		t.Errorf("Annotations Diff %s", diff.PrintWantGot(d))
+	}
+}
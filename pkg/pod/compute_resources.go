@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// getComputeResources returns the compute resources of the whole Task, taking the ones
+// of the TaskRun over the ones of the Task.
+func getComputeResources(taskRun *v1beta1.TaskRun, taskSpec v1beta1.TaskSpec) *corev1.ResourceRequirements {
+	if taskRun.Spec.ComputeResources != nil {
+		return taskRun.Spec.ComputeResources
+	}
+	return taskSpec.ComputeResources
+}
+
+// applyComputeResources spreads the compute resources of the whole Task across its steps,
+// replacing the resources of the steps. The steps run one after the other, so each of them
+// is limited by the limits of the Task. The first step requests the requests of the Task and
+// the other steps request nothing, so that the requests of the pod, which add up the requests
+// of its containers, are the requests of the Task.
+func applyComputeResources(steps []v1beta1.Step, computeResources *corev1.ResourceRequirements) []v1beta1.Step {
+	if computeResources == nil || len(steps) == 0 {
+		return steps
+	}
+
+	// Kubernetes defaults the requests of a container to its limits, so the
+	// other steps explicitly request nothing of the limited resources too.
+	noRequests := corev1.ResourceList{}
+	for name := range computeResources.Requests {
+		noRequests[name] = resource.MustParse("0")
+	}
+	for name := range computeResources.Limits {
+		noRequests[name] = resource.MustParse("0")
+	}
+
+	applied := make([]v1beta1.Step, len(steps))
+	for i, s := range steps {
+		s.Resources = corev1.ResourceRequirements{
+			Limits: computeResources.Limits.DeepCopy(),
+		}
+		if i == 0 {
+			s.Resources.Requests = computeResources.Requests.DeepCopy()
+		} else if len(noRequests) > 0 {
+			s.Resources.Requests = noRequests.DeepCopy()
+		}
+		applied[i] = s
+	}
+	return applied
+}
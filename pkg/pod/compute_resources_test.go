@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/test/diff"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestApplyComputeResources(t *testing.T) {
+	steps := []v1beta1.Step{{
+		Name:  "clone",
+		Image: "git",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		},
+	}, {
+		Name:  "build",
+		Image: "golang",
+	}, {
+		Name:  "push",
+		Image: "ko",
+	}}
+
+	for _, tc := range []struct {
+		desc             string
+		computeResources *corev1.ResourceRequirements
+		want             []v1beta1.Step
+	}{{
+		desc: "no compute resources",
+		want: steps,
+	}, {
+		desc: "requests and limits",
+		computeResources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		},
+		want: []v1beta1.Step{{
+			Name:  "clone",
+			Image: "git",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+		}, {
+			Name:  "build",
+			Image: "golang",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("0"),
+					corev1.ResourceMemory: resource.MustParse("0"),
+				},
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+		}, {
+			Name:  "push",
+			Image: "ko",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("0"),
+					corev1.ResourceMemory: resource.MustParse("0"),
+				},
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+		}},
+	}, {
+		desc: "limits only",
+		computeResources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("1"),
+			},
+		},
+		want: []v1beta1.Step{{
+			Name:  "clone",
+			Image: "git",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		}, {
+			Name:  "build",
+			Image: "golang",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		}, {
+			Name:  "push",
+			Image: "ko",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		}},
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := applyComputeResources(steps, tc.computeResources)
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("Steps diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+
+	// The steps of the Task must not be modified.
+	if d := cmp.Diff(resource.MustParse("100m"), steps[0].Resources.Requests[corev1.ResourceCPU]); d != "" {
+		t.Errorf("Task steps were modified %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestGetComputeResources(t *testing.T) {
+	taskResources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	taskRunResources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	taskSpec := v1beta1.TaskSpec{ComputeResources: taskResources}
+
+	if got := getComputeResources(&v1beta1.TaskRun{}, taskSpec); got != taskResources {
+		t.Errorf("expected the compute resources of the Task, got %v", got)
+	}
+	taskRun := &v1beta1.TaskRun{Spec: v1beta1.TaskRunSpec{ComputeResources: taskRunResources}}
+	if got := getComputeResources(taskRun, taskSpec); got != taskRunResources {
+		t.Errorf("expected the compute resources of the TaskRun, got %v", got)
+	}
+}
@@ -50,6 +50,16 @@ const (
 	downwardMountReadyFile = "ready"
 	readyAnnotation        = "tekton.dev/ready"
 	readyAnnotationValue   = "READY"
+	// The cancel annotation is projected via the Downward API to signal
+	// steps that the TaskRun was cancelled.
+	downwardMountCancelFile = "cancel"
+	cancelAnnotation        = "tekton.dev/cancel"
+	cancelAnnotationValue   = "CANCEL"
+	// The stop sidecars annotation is projected via the Downward API to
+	// signal sidecars run by the entrypoint that the steps are done.
+	downwardMountStopSidecarsFile = "stop-sidecars"
+	stopSidecarsAnnotation        = "tekton.dev/stop-sidecars"
+	stopSidecarsAnnotationValue   = "STOP"
 
 	stepPrefix    = "step-"
 	sidecarPrefix = "sidecar-"
@@ -107,7 +117,7 @@ var (
 // Containers must have Command specified; if the user didn't specify a
 // command, we must have fetched the image's ENTRYPOINT before calling this
 // method, using entrypoint_lookup.go.
-// Additionally, Step timeouts are added as entrypoint flag.
+// Additionally, Step timeouts and grace periods are added as entrypoint flags.
 func orderContainers(commonExtraEntrypointArgs []string, steps []corev1.Container, taskSpec *v1beta1.TaskSpec, breakpointConfig *v1beta1.TaskRunDebug) ([]corev1.Container, error) {
 	if len(steps) == 0 {
 		return nil, errors.New("No steps specified")
@@ -142,6 +152,9 @@ func orderContainers(commonExtraEntrypointArgs []string, steps []corev1.Containe
 				if taskSpec.Steps[i].Timeout != nil {
 					argsForEntrypoint = append(argsForEntrypoint, "-timeout", taskSpec.Steps[i].Timeout.Duration.String())
 				}
+				if taskSpec.Steps[i].GracePeriod != nil {
+					argsForEntrypoint = append(argsForEntrypoint, "-grace_period", taskSpec.Steps[i].GracePeriod.Duration.String())
+				}
 				if taskSpec.Steps[i].OnError != "" {
 					argsForEntrypoint = append(argsForEntrypoint, "-on_error", taskSpec.Steps[i].OnError)
 				}
@@ -225,7 +238,8 @@ func UpdateReady(ctx context.Context, kubeclient kubernetes.Interface, pod corev
 }
 
 // StopSidecars updates sidecar containers in the Pod to a nop image, which
-// exits successfully immediately.
+// exits successfully immediately. Sidecars run by the entrypoint are instead
+// signalled to stop gracefully through the stop sidecars annotation.
 func StopSidecars(ctx context.Context, nopImage string, kubeclient kubernetes.Interface, namespace, name string) (*corev1.Pod, error) {
 	newPod, err := kubeclient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if k8serrors.IsNotFound(err) {
@@ -244,7 +258,20 @@ func StopSidecars(ctx context.Context, nopImage string, kubeclient kubernetes.In
 			// prefix.
 			if !IsContainerStep(s.Name) && s.State.Running != nil {
 				for j, c := range newPod.Spec.Containers {
-					if c.Name == s.Name && c.Image != nopImage {
+					if c.Name != s.Name {
+						continue
+					}
+					// Sidecars run by the entrypoint are sent a SIGTERM once
+					// the stop sidecars annotation is projected.
+					if isRunByEntrypoint(c) {
+						if newPod.Annotations[stopSidecarsAnnotation] != stopSidecarsAnnotationValue {
+							updated = true
+							if newPod.Annotations == nil {
+								newPod.Annotations = map[string]string{}
+							}
+							newPod.Annotations[stopSidecarsAnnotation] = stopSidecarsAnnotationValue
+						}
+					} else if c.Image != nopImage {
 						updated = true
 						newPod.Spec.Containers[j].Image = nopImage
 					}
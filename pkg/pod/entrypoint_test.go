@@ -492,10 +492,20 @@ func TestStopSidecars(t *testing.T) {
 		Image: nopImage,
 	}
 
+	// This is a sidecar run by the entrypoint, which is stopped gracefully
+	// through the stop sidecars annotation instead of the nop image.
+	entrypointSidecar := corev1.Container{
+		Name:    sidecarPrefix + "my-entrypoint-sidecar",
+		Image:   "original-image",
+		Command: []string{entrypointBinary},
+		Args:    []string{"-stop_file", "/tekton/downward/stop-sidecars", "-entrypoint", "cmd", "--"},
+	}
+
 	for _, c := range []struct {
-		desc           string
-		pod            corev1.Pod
-		wantContainers []corev1.Container
+		desc            string
+		pod             corev1.Pod
+		wantContainers  []corev1.Container
+		wantAnnotations map[string]string
 	}{{
 		desc: "Running sidecars (incl injected) should be stopped",
 		pod: corev1.Pod{
@@ -559,6 +569,32 @@ func TestStopSidecars(t *testing.T) {
 			},
 		},
 		wantContainers: []corev1.Container{stepContainer, sidecarContainer, injectedSidecar},
+	}, {
+		desc: "Running sidecars run by the entrypoint should be signalled to stop",
+		pod: corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-pod",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{stepContainer, entrypointSidecar, injectedSidecar},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					// Step state doesn't matter.
+				}, {
+					Name: entrypointSidecar.Name,
+					// Sidecar is running.
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Now())}},
+				}, {
+					Name: injectedSidecar.Name,
+					// Injected sidecar is running.
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Now())}},
+				}},
+			},
+		},
+		wantContainers:  []corev1.Container{stepContainer, entrypointSidecar, stoppedInjectedSidecar},
+		wantAnnotations: map[string]string{stopSidecarsAnnotation: stopSidecarsAnnotationValue},
 	}} {
 		t.Run(c.desc, func(t *testing.T) {
 			ctx := context.Background()
@@ -567,8 +603,13 @@ func TestStopSidecars(t *testing.T) {
 			kubeclient := fakek8s.NewSimpleClientset(&c.pod)
 			if got, err := StopSidecars(ctx, nopImage, kubeclient, c.pod.Namespace, c.pod.Name); err != nil {
 				t.Errorf("error stopping sidecar: %v", err)
-			} else if d := cmp.Diff(c.wantContainers, got.Spec.Containers); d != "" {
-				t.Errorf("Containers Diff %s", diff.PrintWantGot(d))
+			} else {
+				if d := cmp.Diff(c.wantContainers, got.Spec.Containers); d != "" {
+					t.Errorf("Containers Diff %s", diff.PrintWantGot(d))
+				}
+				if d := cmp.Diff(c.wantAnnotations, got.Annotations); d != "" {
+					t.Errorf("Annotations Diff %s", diff.PrintWantGot(d))
+				}
 			}
 		})
 	}
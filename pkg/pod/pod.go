@@ -141,6 +141,9 @@ func (b *Builder) Build(ctx context.Context, taskRun *v1beta1.TaskRun, taskSpec
 	if err != nil {
 		return nil, err
 	}
+	if alphaAPIEnabled {
+		steps = applyComputeResources(steps, getComputeResources(taskRun, taskSpec))
+	}
 	sidecars, err := v1beta1.MergeSidecarsWithOverrides(taskSpec.Sidecars, taskRun.Spec.SidecarOverrides)
 	if err != nil {
 		return nil, err
@@ -170,11 +173,12 @@ func (b *Builder) Build(ctx context.Context, taskRun *v1beta1.TaskRun, taskSpec
 		initContainers = append(initContainers, *workingDirInit)
 	}
 
-	// By default, use an empty pod template and take the one defined in the task run spec if any
+	// By default, use an empty pod template and take the one defined in the task run spec if any,
+	// merged with the default pod template in case the defaults changed since the task run was created.
 	podTemplate := pod.Template{}
-
-	if taskRun.Spec.PodTemplate != nil {
-		podTemplate = *taskRun.Spec.PodTemplate
+	defaultPodTemplate := config.FromContextOrDefaults(ctx).Defaults.DefaultPodTemplate
+	if tpl := v1beta1.MergePodTemplateWithDefault(taskRun.Spec.PodTemplate.DeepCopy(), defaultPodTemplate); tpl != nil {
+		podTemplate = *tpl
 	}
 
 	// Resolve entrypoint for any steps that don't specify command.
@@ -191,7 +195,20 @@ func (b *Builder) Build(ctx context.Context, taskRun *v1beta1.TaskRun, taskSpec
 	if err != nil {
 		return nil, err
 	}
-	volumes = append(volumes, binVolume, downwardVolume)
+	stepContainers = addTracingArgs(ctx, taskRun, stepContainers)
+	stepContainers = addCancellationArgs(ctx, &taskSpec, stepContainers)
+
+	// Run sidecars as native sidecar containers, which the kubelet stops once the steps are done,
+	// when the cluster supports them, or else with the entrypoint so they can be stopped gracefully.
+	nativeSidecars := config.FromContextOrDefaults(ctx).FeatureFlags.EnableNativeSidecars && len(sidecarContainers) > 0 && nativeSidecarsSupported(b.KubeClient)
+	if !nativeSidecars && config.FromContextOrDefaults(ctx).FeatureFlags.EnableGracefulSidecarStop {
+		sidecarContainers, err = resolveEntrypoints(ctx, b.EntrypointCache, taskRun.Namespace, taskRun.Spec.ServiceAccountName, podTemplate.ImagePullSecrets, sidecarContainers)
+		if err != nil {
+			return nil, err
+		}
+		sidecarContainers = runSidecarsWithEntrypoint(sidecars, sidecarContainers)
+	}
+	volumes = append(volumes, binVolume, makeDownwardVolume(ctx))
 
 	// Add implicit env vars.
 	// They're prepended to the list, so that if the user specified any
@@ -212,6 +229,31 @@ func (b *Builder) Build(ctx context.Context, taskRun *v1beta1.TaskRun, taskSpec
 		}
 	}
 
+	// Expose the params and result paths to the scripts of the steps and sidecars.
+	if config.FromContextOrDefaults(ctx).FeatureFlags.EnableParamsAsEnvInScripts {
+		env, err := scriptEnvVars(taskSpec, taskRun)
+		if err != nil {
+			return nil, err
+		}
+		stepScripts := make([]string, 0, len(steps))
+		for _, s := range steps {
+			stepScripts = append(stepScripts, s.Script)
+		}
+		sidecarScripts := make([]string, 0, len(sidecars))
+		for _, s := range sidecars {
+			sidecarScripts = append(sidecarScripts, s.Script)
+		}
+		stepContainers = addScriptEnvVars(stepContainers, stepScripts, env)
+		sidecarContainers = addScriptEnvVars(sidecarContainers, sidecarScripts, env)
+	}
+
+	// Add podTemplate env vars to steps and sidecars, overriding the ones they
+	// declare with the same name.
+	if len(podTemplate.Env) > 0 {
+		stepContainers = addPodTemplateEnv(stepContainers, podTemplate.Env)
+		sidecarContainers = addPodTemplateEnv(sidecarContainers, podTemplate.Env)
+	}
+
 	// Add implicit volume mounts to each step, unless the step specifies
 	// its own volume mount at that path.
 	for i, s := range stepContainers {
@@ -266,10 +308,15 @@ func (b *Builder) Build(ctx context.Context, taskRun *v1beta1.TaskRun, taskSpec
 
 	mergedPodContainers := stepContainers
 
-	// Merge sidecar containers with step containers.
+	// Merge sidecar containers with step containers, or run them after the
+	// other init containers when they are native sidecar containers.
 	for _, sc := range sidecarContainers {
 		sc.Name = names.SimpleNameGenerator.RestrictLength(fmt.Sprintf("%v%v", sidecarPrefix, sc.Name))
-		mergedPodContainers = append(mergedPodContainers, sc)
+		if nativeSidecars {
+			initContainers = append(initContainers, sc)
+		} else {
+			mergedPodContainers = append(mergedPodContainers, sc)
+		}
 	}
 
 	var dnsPolicy corev1.DNSPolicy
@@ -374,11 +421,13 @@ func makeLabels(s *v1beta1.TaskRun) map[string]string {
 // shouldAddReadyAnnotationOnPodCreate returns a bool indicating whether the
 // controller should add the `Ready` annotation when creating the Pod. We cannot
 // add the annotation if Tekton is running in a cluster with injected sidecars
-// or if the Task specifies any sidecars.
+// or if the Task specifies any sidecars the steps wait for.
 func shouldAddReadyAnnotationOnPodCreate(ctx context.Context, sidecars []v1beta1.Sidecar) bool {
-	// If the TaskRun has sidecars, we cannot set the READY annotation early
-	if len(sidecars) > 0 {
-		return false
+	// If the TaskRun has sidecars the steps wait for, we cannot set the READY annotation early
+	for _, s := range sidecars {
+		if isReadinessGate(s) {
+			return false
+		}
 	}
 	// If the TaskRun has no sidecars, check if we are running in a cluster where sidecars can be injected by other
 	// controllers.
@@ -386,6 +435,25 @@ func shouldAddReadyAnnotationOnPodCreate(ctx context.Context, sidecars []v1beta1
 	return !cfg.FeatureFlags.RunningInEnvWithInjectedSidecars
 }
 
+// addPodTemplateEnv appends the env vars of the pod template to the env of the containers,
+// dropping the env vars of the containers with the same name.
+func addPodTemplateEnv(containers []corev1.Container, podTemplateEnv []corev1.EnvVar) []corev1.Container {
+	names := map[string]bool{}
+	for _, e := range podTemplateEnv {
+		names[e.Name] = true
+	}
+	for i, c := range containers {
+		var env []corev1.EnvVar
+		for _, e := range c.Env {
+			if !names[e.Name] {
+				env = append(env, e)
+			}
+		}
+		containers[i].Env = append(env, podTemplateEnv...)
+	}
+	return containers
+}
+
 func runMount(i int, ro bool) corev1.VolumeMount {
 	return corev1.VolumeMount{
 		Name:      fmt.Sprintf("%s-%d", runVolumeName, i),
@@ -100,6 +100,7 @@ func TestPodBuild(t *testing.T) {
 		trName          string
 		ts              v1beta1.TaskSpec
 		featureFlags    map[string]string
+		defaults        map[string]string
 		want            *corev1.PodSpec
 		wantAnnotations map[string]string
 		wantPodName     string
@@ -368,6 +369,90 @@ func TestPodBuild(t *testing.T) {
 			PriorityClassName:     priorityClassName,
 			ActiveDeadlineSeconds: &defaultActiveDeadlineSeconds,
 		},
+	}, {
+		desc: "with-pod-template-merged-with-default-pod-template",
+		ts: v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Name:    "name",
+				Image:   "image",
+				Command: []string{"cmd"}, // avoid entrypoint lookup.
+				Env:     []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}, {Name: "GOFLAGS", Value: "-mod=vendor"}},
+			}},
+			Sidecars: []v1beta1.Sidecar{{
+				Name:  "sc-name",
+				Image: "sidecar-image",
+			}},
+		},
+		trs: v1beta1.TaskRunSpec{
+			PodTemplate: &pod.Template{
+				Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+				Tolerations: []corev1.Toleration{{
+					Key:      "nvidia.com/gpu",
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				}},
+			},
+		},
+		defaults: map[string]string{
+			"default-pod-template": `env: [{name: HTTP_PROXY, value: "proxy:3128"}]
+tolerations: [{key: dedicated, operator: Equal, value: ci, effect: NoSchedule}]`,
+		},
+		wantAnnotations: map[string]string{},
+		want: &corev1.PodSpec{
+			RestartPolicy:  corev1.RestartPolicyNever,
+			InitContainers: []corev1.Container{entrypointInitContainer(images.EntrypointImage, []v1beta1.Step{{Name: "name"}})},
+			Containers: []corev1.Container{{
+				Name:    "step-name",
+				Image:   "image",
+				Command: []string{"/tekton/bin/entrypoint"},
+				Args: []string{
+					"-wait_file",
+					"/tekton/downward/ready",
+					"-wait_file_content",
+					"-post_file",
+					"/tekton/run/0/out",
+					"-termination_path",
+					"/tekton/termination",
+					"-step_metadata_dir",
+					"/tekton/run/0/status",
+					"-entrypoint",
+					"cmd",
+					"--",
+				},
+				Env: []corev1.EnvVar{
+					{Name: "GOFLAGS", Value: "-mod=vendor"},
+					{Name: "HTTP_PROXY", Value: "proxy:3128"},
+					{Name: "LOG_LEVEL", Value: "debug"},
+				},
+				VolumeMounts: append([]corev1.VolumeMount{binROMount, runMount(0, false), downwardMount, {
+					Name:      "tekton-creds-init-home-0",
+					MountPath: "/tekton/creds",
+				}}, implicitVolumeMounts...),
+				TerminationMessagePath: "/tekton/termination",
+			}, {
+				Name:  "sidecar-sc-name",
+				Image: "sidecar-image",
+				Env: []corev1.EnvVar{
+					{Name: "HTTP_PROXY", Value: "proxy:3128"},
+					{Name: "LOG_LEVEL", Value: "debug"},
+				},
+			}},
+			Volumes: append(implicitVolumes, binVolume, runVolume(0), downwardVolume, corev1.Volume{
+				Name:         "tekton-creds-init-home-0",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}},
+			}),
+			Tolerations: []corev1.Toleration{{
+				Key:      "nvidia.com/gpu",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}, {
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "ci",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+			ActiveDeadlineSeconds: &defaultActiveDeadlineSeconds,
+		},
 	}, {
 		desc: "very long step name",
 		ts: v1beta1.TaskSpec{
@@ -627,6 +712,83 @@ _EOF_
 			}),
 			ActiveDeadlineSeconds: &defaultActiveDeadlineSeconds,
 		},
+	}, {
+		desc: "sidecar container with script and enable-params-as-env-in-scripts",
+		ts: v1beta1.TaskSpec{
+			Params: []v1beta1.ParamSpec{{
+				Name:    "greeting",
+				Type:    v1beta1.ParamTypeString,
+				Default: v1beta1.NewArrayOrString("hello"),
+			}},
+			Steps: []v1beta1.Step{{
+				Name:    "primary-name",
+				Image:   "primary-image",
+				Command: []string{"cmd"}, // avoid entrypoint lookup.
+			}},
+			Sidecars: []v1beta1.Sidecar{{
+				Name:   "sc-name",
+				Image:  "sidecar-image",
+				Script: "#!/bin/sh\necho hello from sidecar",
+			}},
+		},
+		featureFlags: map[string]string{
+			"enable-params-as-env-in-scripts": "true",
+		},
+		wantAnnotations: map[string]string{},
+		want: &corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			InitContainers: []corev1.Container{
+				entrypointInitContainer(images.EntrypointImage, []v1beta1.Step{{Name: "primary-name"}}),
+				{
+					Name:         "place-scripts",
+					Image:        "busybox",
+					Command:      []string{"sh"},
+					VolumeMounts: []corev1.VolumeMount{writeScriptsVolumeMount, binMount},
+					Args: []string{"-c", `scriptfile="/tekton/scripts/sidecar-script-0-9l9zj"
+touch ${scriptfile} && chmod +x ${scriptfile}
+cat > ${scriptfile} << '_EOF_'
+IyEvYmluL3NoCmVjaG8gaGVsbG8gZnJvbSBzaWRlY2Fy
+_EOF_
+/tekton/bin/entrypoint decode-script "${scriptfile}"
+`},
+				},
+			},
+			Containers: []corev1.Container{{
+				Name:    "step-primary-name",
+				Image:   "primary-image",
+				Command: []string{"/tekton/bin/entrypoint"},
+				Args: []string{
+					"-wait_file",
+					"/tekton/downward/ready",
+					"-wait_file_content",
+					"-post_file",
+					"/tekton/run/0/out",
+					"-termination_path",
+					"/tekton/termination",
+					"-step_metadata_dir",
+					"/tekton/run/0/status",
+					"-entrypoint",
+					"cmd",
+					"--",
+				},
+				VolumeMounts: append([]corev1.VolumeMount{binROMount, runMount(0, false), downwardMount, {
+					Name:      "tekton-creds-init-home-0",
+					MountPath: "/tekton/creds",
+				}}, implicitVolumeMounts...),
+				TerminationMessagePath: "/tekton/termination",
+			}, {
+				Name:         "sidecar-sc-name",
+				Image:        "sidecar-image",
+				Command:      []string{"/tekton/scripts/sidecar-script-0-9l9zj"},
+				Env:          []corev1.EnvVar{{Name: "PARAMS_GREETING", Value: "hello"}},
+				VolumeMounts: []corev1.VolumeMount{scriptsVolumeMount},
+			}},
+			Volumes: append(implicitVolumes, scriptsVolume, binVolume, runVolume(0), downwardVolume, corev1.Volume{
+				Name:         "tekton-creds-init-home-0",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}},
+			}),
+			ActiveDeadlineSeconds: &defaultActiveDeadlineSeconds,
+		},
 	}, {
 		desc: "sidecar container with enable-ready-annotation-on-pod-create",
 		ts: v1beta1.TaskSpec{
@@ -680,6 +842,92 @@ _EOF_
 			}),
 			ActiveDeadlineSeconds: &defaultActiveDeadlineSeconds,
 		},
+	}, {
+		desc: "sidecar container with enable-graceful-sidecar-stop",
+		ts: v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Name:    "primary-name",
+				Image:   "primary-image",
+				Command: []string{"cmd"}, // avoid entrypoint lookup.
+			}},
+			Sidecars: []v1beta1.Sidecar{{
+				Name:        "sc-name",
+				Image:       "sidecar-image",
+				Command:     []string{"proxy", "--port"}, // avoid entrypoint lookup.
+				Args:        []string{"8080"},
+				GracePeriod: &metav1.Duration{Duration: 10 * time.Second},
+			}},
+		},
+		featureFlags: map[string]string{
+			"enable-graceful-sidecar-stop": "true",
+		},
+		wantAnnotations: map[string]string{},
+		want: &corev1.PodSpec{
+			RestartPolicy:  corev1.RestartPolicyNever,
+			InitContainers: []corev1.Container{entrypointInitContainer(images.EntrypointImage, []v1beta1.Step{{Name: "primary-name"}})},
+			Containers: []corev1.Container{{
+				Name:    "step-primary-name",
+				Image:   "primary-image",
+				Command: []string{"/tekton/bin/entrypoint"},
+				Args: []string{
+					"-wait_file",
+					"/tekton/downward/ready",
+					"-wait_file_content",
+					"-post_file",
+					"/tekton/run/0/out",
+					"-termination_path",
+					"/tekton/termination",
+					"-step_metadata_dir",
+					"/tekton/run/0/status",
+					"-entrypoint",
+					"cmd",
+					"--",
+				},
+				VolumeMounts: append([]corev1.VolumeMount{binROMount, runMount(0, false), downwardMount, {
+					Name:      "tekton-creds-init-home-0",
+					MountPath: "/tekton/creds",
+				}}, implicitVolumeMounts...),
+				TerminationMessagePath: "/tekton/termination",
+			}, {
+				Name:    "sidecar-sc-name",
+				Image:   "sidecar-image",
+				Command: []string{"/tekton/bin/entrypoint"},
+				Args: []string{
+					"-stop_file",
+					"/tekton/downward/stop-sidecars",
+					"-grace_period",
+					"10s",
+					"-entrypoint",
+					"proxy",
+					"--",
+					"--port",
+					"8080",
+				},
+				VolumeMounts: []corev1.VolumeMount{binROMount, downwardMount, {
+					Name:      "tekton-internal-results",
+					MountPath: "/tekton/results",
+				}},
+				TerminationMessagePath: "/tekton/termination",
+			}},
+			Volumes: append(implicitVolumes, binVolume, runVolume(0), corev1.Volume{
+				Name: downwardVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					DownwardAPI: &corev1.DownwardAPIVolumeSource{
+						Items: []corev1.DownwardAPIVolumeFile{{
+							Path:     "ready",
+							FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations['tekton.dev/ready']"},
+						}, {
+							Path:     "stop-sidecars",
+							FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations['tekton.dev/stop-sidecars']"},
+						}},
+					},
+				},
+			}, corev1.Volume{
+				Name:         "tekton-creds-init-home-0",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}},
+			}),
+			ActiveDeadlineSeconds: &defaultActiveDeadlineSeconds,
+		},
 	}, {
 		desc: "resource request",
 		ts: v1beta1.TaskSpec{
@@ -1428,6 +1676,54 @@ _EOF_
 				}),
 				ActiveDeadlineSeconds: &defaultActiveDeadlineSeconds,
 			},
+		}, {
+			desc: "step-with-timeout-and-grace-period",
+			ts: v1beta1.TaskSpec{
+				Steps: []v1beta1.Step{{
+					Name:        "name",
+					Image:       "image",
+					Command:     []string{"cmd"}, // avoid entrypoint lookup.
+					Timeout:     &metav1.Duration{Duration: time.Minute},
+					GracePeriod: &metav1.Duration{Duration: 10 * time.Second},
+				}},
+			},
+			want: &corev1.PodSpec{
+				RestartPolicy:  corev1.RestartPolicyNever,
+				InitContainers: []corev1.Container{entrypointInitContainer(images.EntrypointImage, []v1beta1.Step{{Name: "name"}})},
+				Containers: []corev1.Container{{
+					Name:    "step-name",
+					Image:   "image",
+					Command: []string{"/tekton/bin/entrypoint"},
+					Args: []string{
+						"-wait_file",
+						"/tekton/downward/ready",
+						"-wait_file_content",
+						"-post_file",
+						"/tekton/run/0/out",
+						"-termination_path",
+						"/tekton/termination",
+						"-step_metadata_dir",
+						"/tekton/run/0/status",
+						"-timeout",
+						"1m0s",
+						"-grace_period",
+						"10s",
+						"-entrypoint",
+						"cmd",
+						"--",
+					},
+					VolumeMounts: append([]corev1.VolumeMount{binROMount, runMount(0, false), downwardMount, {
+						Name:      "tekton-creds-init-home-0",
+						MountPath: "/tekton/creds",
+					}}, implicitVolumeMounts...),
+					TerminationMessagePath: "/tekton/termination",
+				}},
+				Volumes: append(implicitVolumes, binVolume, runVolume(0), downwardVolume, corev1.Volume{
+					Name:         "tekton-creds-init-home-0",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}},
+				}),
+				ActiveDeadlineSeconds: &defaultActiveDeadlineSeconds,
+			},
 		}, {
 			desc: "step-with-no-timeout-equivalent-to-0-second-timeout",
 			ts: v1beta1.TaskSpec{
@@ -1732,6 +2028,14 @@ _EOF_
 					Data:       c.featureFlags,
 				},
 			)
+			if c.defaults != nil {
+				store.OnConfigChanged(
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: config.GetDefaultsConfigName(), Namespace: system.Namespace()},
+						Data:       c.defaults,
+					},
+				)
+			}
 			kubeclient := fakek8s.NewSimpleClientset(
 				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"}},
 				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "service-account", Namespace: "default"},
@@ -2039,6 +2343,11 @@ func TestShouldAddReadyAnnotationonPodCreate(t *testing.T) {
 	sd := v1beta1.Sidecar{
 		Name: "a-sidecar",
 	}
+	readinessGate := false
+	notGatingSd := v1beta1.Sidecar{
+		Name:          "a-not-gating-sidecar",
+		ReadinessGate: &readinessGate,
+	}
 	tcs := []struct {
 		description string
 		sidecars    []v1beta1.Sidecar
@@ -2100,6 +2409,26 @@ func TestShouldAddReadyAnnotationonPodCreate(t *testing.T) {
 			},
 		},
 		expected: true,
+	}, {
+		description: "Setting running-in-environment-with-injected-sidecars to false with only sidecars which are not a readiness gate results in true",
+		sidecars:    []v1beta1.Sidecar{notGatingSd},
+		configMap: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: config.GetFeatureFlagsConfigName(), Namespace: system.Namespace()},
+			Data: map[string]string{
+				featureInjectedSidecar: "false",
+			},
+		},
+		expected: true,
+	}, {
+		description: "Setting running-in-environment-with-injected-sidecars to false with a sidecar which is a readiness gate results in false",
+		sidecars:    []v1beta1.Sidecar{sd, notGatingSd},
+		configMap: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: config.GetFeatureFlagsConfigName(), Namespace: system.Namespace()},
+			Data: map[string]string{
+				featureInjectedSidecar: "false",
+			},
+		},
+		expected: false,
 	}}
 
 	for _, tc := range tcs {
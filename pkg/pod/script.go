@@ -18,10 +18,12 @@ package pod
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
 
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/names"
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +38,10 @@ const (
 	debugScriptsDir        = "/tekton/debug/scripts"
 	defaultScriptPreamble  = "#!/bin/sh\nset -e\n"
 	debugInfoDir           = "/tekton/debug/info"
+
+	paramEnvPrefix      = "PARAMS_"
+	resultPathEnvPrefix = "RESULTS_"
+	resultPathEnvSuffix = "_PATH"
 )
 
 var (
@@ -285,3 +291,60 @@ func extractWindowsScriptComponents(script string, fileName string) ([]string, [
 
 	return command, args, script, fileName
 }
+
+// scriptEnvVars returns the env vars exposing the params and the result paths
+// of the Task to scripts, so that scripts can read them from the environment
+// instead of having them substituted inline. The value of a param of type
+// array or object is encoded in JSON.
+func scriptEnvVars(taskSpec v1beta1.TaskSpec, taskRun *v1beta1.TaskRun) ([]corev1.EnvVar, error) {
+	values := map[string]v1beta1.ArrayOrString{}
+	for _, p := range taskSpec.Params {
+		if p.Default != nil {
+			values[p.Name] = *p.Default
+		}
+	}
+	for _, p := range taskRun.Spec.Params {
+		values[p.Name] = p.Value
+	}
+
+	var env []corev1.EnvVar
+	for _, p := range taskSpec.Params {
+		v, ok := values[p.Name]
+		if !ok {
+			continue
+		}
+		value := v.StringVal
+		if v.Type != v1beta1.ParamTypeString {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode param %q: %w", p.Name, err)
+			}
+			value = string(b)
+		}
+		env = append(env, corev1.EnvVar{Name: paramEnvName(p.Name), Value: value})
+	}
+	for _, r := range taskSpec.Results {
+		env = append(env, corev1.EnvVar{
+			Name:  resultPathEnvPrefix + v1beta1.ScriptEnvName(r.Name) + resultPathEnvSuffix,
+			Value: filepath.Join(pipeline.DefaultResultPath, r.Name),
+		})
+	}
+	return env, nil
+}
+
+// addScriptEnvVars prepends env to the env of the containers which run a script,
+// so that the env vars declared by the steps and sidecars take precedence.
+func addScriptEnvVars(containers []corev1.Container, scripts []string, env []corev1.EnvVar) []corev1.Container {
+	for i := range containers {
+		if i < len(scripts) && scripts[i] != "" {
+			containers[i].Env = append(append([]corev1.EnvVar{}, env...), containers[i].Env...)
+		}
+	}
+	return containers
+}
+
+// paramEnvName returns the name of the env var exposing the param to scripts,
+// e.g. "PARAMS_GIT_URL" for the param "git-url".
+func paramEnvName(name string) string {
+	return paramEnvPrefix + v1beta1.ScriptEnvName(name)
+}
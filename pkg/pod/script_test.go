@@ -651,3 +651,72 @@ sidecar-1
 	}
 
 }
+
+func TestScriptEnvVars(t *testing.T) {
+	taskSpec := v1beta1.TaskSpec{
+		Params: []v1beta1.ParamSpec{{
+			Name:    "git-url",
+			Type:    v1beta1.ParamTypeString,
+			Default: v1beta1.NewArrayOrString("https://github.com/tektoncd/pipeline"),
+		}, {
+			Name: "flags",
+			Type: v1beta1.ParamTypeArray,
+		}, {
+			Name: "not.provided",
+			Type: v1beta1.ParamTypeString,
+		}},
+		Results: []v1beta1.TaskResult{{
+			Name: "commit",
+		}},
+	}
+	taskRun := &v1beta1.TaskRun{
+		Spec: v1beta1.TaskRunSpec{
+			Params: []v1beta1.Param{{
+				Name:  "flags",
+				Value: *v1beta1.NewArrayOrString("-v", "--depth=1"),
+			}, {
+				Name:  "not-declared",
+				Value: *v1beta1.NewArrayOrString("ignored"),
+			}},
+		},
+	}
+
+	got, err := scriptEnvVars(taskSpec, taskRun)
+	if err != nil {
+		t.Fatalf("scriptEnvVars: %v", err)
+	}
+	want := []corev1.EnvVar{{
+		Name:  "PARAMS_GIT_URL",
+		Value: "https://github.com/tektoncd/pipeline",
+	}, {
+		Name:  "PARAMS_FLAGS",
+		Value: `["-v","--depth=1"]`,
+	}, {
+		Name:  "RESULTS_COMMIT_PATH",
+		Value: "/tekton/results/commit",
+	}}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Diff %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestAddScriptEnvVars(t *testing.T) {
+	env := []corev1.EnvVar{{Name: "PARAMS_URL", Value: "default"}}
+	containers := []corev1.Container{{
+		Name: "script",
+		Env:  []corev1.EnvVar{{Name: "PARAMS_URL", Value: "declared"}},
+	}, {
+		Name: "no-script",
+	}}
+
+	got := addScriptEnvVars(containers, []string{"echo $PARAMS_URL", ""}, env)
+	want := []corev1.Container{{
+		Name: "script",
+		Env:  []corev1.EnvVar{{Name: "PARAMS_URL", Value: "default"}, {Name: "PARAMS_URL", Value: "declared"}},
+	}, {
+		Name: "no-script",
+	}}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Diff %s", diff.PrintWantGot(d))
+	}
+}
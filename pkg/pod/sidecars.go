@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nativeSidecarsMinMinorVersion is the first minor version of Kubernetes 1
+// which enables native sidecar containers, i.e. init containers with the
+// Always restart policy, by default.
+const nativeSidecarsMinMinorVersion = 29
+
+// resultsMount lets sidecars run by the entrypoint write results.
+var resultsMount = corev1.VolumeMount{
+	Name:      "tekton-internal-results",
+	MountPath: pipeline.DefaultResultPath,
+}
+
+// runSidecarsWithEntrypoint overrides the command of the sidecars with the
+// entrypoint binary, which runs the sidecar until the stop sidecars annotation
+// is projected via the Downward API and then sends it a SIGTERM, followed by a
+// SIGKILL once the grace period of the sidecar is over.
+//
+// Containers must have Command specified, or their image's ENTRYPOINT
+// resolved with entrypoint_lookup.go, like steps.
+func runSidecarsWithEntrypoint(sidecars []v1beta1.Sidecar, containers []corev1.Container) []corev1.Container {
+	for i, c := range containers {
+		argsForEntrypoint := []string{"-stop_file", filepath.Join(downwardMountPoint, downwardMountStopSidecarsFile)}
+		if i < len(sidecars) && sidecars[i].GracePeriod != nil {
+			argsForEntrypoint = append(argsForEntrypoint, "-grace_period", sidecars[i].GracePeriod.Duration.String())
+		}
+
+		cmd, args := c.Command, c.Args
+		if len(cmd) > 0 {
+			argsForEntrypoint = append(argsForEntrypoint, "-entrypoint", cmd[0])
+		}
+		if len(cmd) > 1 {
+			args = append(cmd[1:], args...)
+		}
+		argsForEntrypoint = append(argsForEntrypoint, "--")
+		argsForEntrypoint = append(argsForEntrypoint, args...)
+
+		containers[i].Command = []string{entrypointBinary}
+		containers[i].Args = argsForEntrypoint
+		// The entrypoint reports a sidecar killed after its grace period in
+		// its termination message.
+		containers[i].TerminationMessagePath = terminationPath
+
+		// Mount the entrypoint binary, the Downward volume and the results
+		// directory, unless the sidecar mounts something else at their path.
+		requestedVolumeMounts := map[string]bool{}
+		for _, vm := range c.VolumeMounts {
+			requestedVolumeMounts[filepath.Clean(vm.MountPath)] = true
+		}
+		for _, vm := range []corev1.VolumeMount{binROMount, downwardMount, resultsMount} {
+			if !requestedVolumeMounts[filepath.Clean(vm.MountPath)] {
+				containers[i].VolumeMounts = append(containers[i].VolumeMounts, vm)
+			}
+		}
+	}
+	return containers
+}
+
+// isRunByEntrypoint returns true if the container is run by the entrypoint
+// binary.
+func isRunByEntrypoint(c corev1.Container) bool {
+	return len(c.Command) > 0 && c.Command[0] == entrypointBinary
+}
+
+// isReadinessGate returns true if the first step waits for the sidecar to be
+// ready, which is the default.
+func isReadinessGate(sidecar v1beta1.Sidecar) bool {
+	return sidecar.ReadinessGate == nil || *sidecar.ReadinessGate
+}
+
+// nativeSidecarsSupported returns true if the Kubernetes version of the
+// cluster supports native sidecar containers. Clusters whose version can't
+// be determined are assumed not to support them.
+func nativeSidecarsSupported(kubeclient kubernetes.Interface) bool {
+	if kubeclient == nil {
+		return false
+	}
+	info, err := kubeclient.Discovery().ServerVersion()
+	if err != nil {
+		return false
+	}
+	// Managed clusters may report minor versions like "29+".
+	minor, err := strconv.Atoi(strings.TrimSuffix(info.Minor, "+"))
+	if err != nil {
+		return false
+	}
+	return info.Major == "1" && minor >= nativeSidecarsMinMinorVersion
+}
+
+// hasNativeSidecars returns true if the sidecars of the pod are run as native
+// sidecar containers, i.e. as init containers.
+func hasNativeSidecars(pod *corev1.Pod) bool {
+	for _, c := range pod.Spec.InitContainers {
+		if isContainerSidecar(c.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatePod creates the pod of a TaskRun. The pod of a TaskRun whose sidecars
+// are run as native sidecar containers is created from its JSON, since the
+// Always restart policy of their init containers can't be set with the
+// version of the Kubernetes API used by Tekton.
+func CreatePod(ctx context.Context, kubeclient kubernetes.Interface, pod *corev1.Pod) (*corev1.Pod, error) {
+	if !hasNativeSidecars(pod) {
+		return kubeclient.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	}
+	body, err := nativeSidecarsPodJSON(pod)
+	if err != nil {
+		return nil, err
+	}
+	created := &corev1.Pod{}
+	err = kubeclient.CoreV1().RESTClient().Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Body(body).
+		Do(ctx).
+		Into(created)
+	return created, err
+}
+
+// nativeSidecarsPodJSON returns the JSON of the pod, with the Always restart
+// policy set on the init containers of its sidecars.
+func nativeSidecarsPodJSON(pod *corev1.Pod) ([]byte, error) {
+	b, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	spec, _ := obj["spec"].(map[string]interface{})
+	initContainers, _ := spec["initContainers"].([]interface{})
+	for _, ic := range initContainers {
+		c, ok := ic.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected init container %v in pod %s", ic, pod.Name)
+		}
+		if name, _ := c["name"].(string); isContainerSidecar(name) {
+			c["restartPolicy"] = string(corev1.RestartPolicyAlways)
+		}
+	}
+	return json.Marshal(obj)
+}
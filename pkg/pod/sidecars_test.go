@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/test/diff"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakek8s "k8s.io/client-go/kubernetes/fake"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/system"
+)
+
+func TestRunSidecarsWithEntrypoint(t *testing.T) {
+	sidecars := []v1beta1.Sidecar{{
+		Name:        "proxy",
+		GracePeriod: &metav1.Duration{Duration: time.Minute},
+	}, {
+		Name: "db",
+	}}
+	userResultsMount := corev1.VolumeMount{
+		Name:      "my-results",
+		MountPath: "/tekton/results/",
+	}
+	containers := []corev1.Container{{
+		Name:    "sidecar-proxy",
+		Command: []string{"proxy", "--port"},
+		Args:    []string{"8080"},
+	}, {
+		// The command of the image is resolved through TEKTON_PLATFORM_COMMANDS.
+		Name:         "sidecar-db",
+		VolumeMounts: []corev1.VolumeMount{userResultsMount},
+	}}
+
+	want := []corev1.Container{{
+		Name:                   "sidecar-proxy",
+		Command:                []string{entrypointBinary},
+		Args:                   []string{"-stop_file", "/tekton/downward/stop-sidecars", "-grace_period", "1m0s", "-entrypoint", "proxy", "--", "--port", "8080"},
+		VolumeMounts:           []corev1.VolumeMount{binROMount, downwardMount, resultsMount},
+		TerminationMessagePath: "/tekton/termination",
+	}, {
+		Name:                   "sidecar-db",
+		Command:                []string{entrypointBinary},
+		Args:                   []string{"-stop_file", "/tekton/downward/stop-sidecars", "--"},
+		VolumeMounts:           []corev1.VolumeMount{userResultsMount, binROMount, downwardMount},
+		TerminationMessagePath: "/tekton/termination",
+	}}
+	got := runSidecarsWithEntrypoint(sidecars, containers)
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Diff %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestBuildNativeSidecars(t *testing.T) {
+	for _, tc := range []struct {
+		desc               string
+		enabled            bool
+		serverVersion      *version.Info
+		wantInitContainers []string
+		wantContainers     []string
+	}{{
+		desc:               "native sidecars on a supported cluster",
+		enabled:            true,
+		serverVersion:      &version.Info{Major: "1", Minor: "29"},
+		wantInitContainers: []string{"prepare", "sidecar-proxy"},
+		wantContainers:     []string{"step-build"},
+	}, {
+		desc:               "native sidecars on a managed cluster",
+		enabled:            true,
+		serverVersion:      &version.Info{Major: "1", Minor: "30+"},
+		wantInitContainers: []string{"prepare", "sidecar-proxy"},
+		wantContainers:     []string{"step-build"},
+	}, {
+		desc:               "fallback on an older cluster",
+		enabled:            true,
+		serverVersion:      &version.Info{Major: "1", Minor: "28"},
+		wantInitContainers: []string{"prepare"},
+		wantContainers:     []string{"step-build", "sidecar-proxy"},
+	}, {
+		desc:               "fallback on a cluster with an unknown version",
+		enabled:            true,
+		serverVersion:      &version.Info{},
+		wantInitContainers: []string{"prepare"},
+		wantContainers:     []string{"step-build", "sidecar-proxy"},
+	}, {
+		desc:               "disabled",
+		serverVersion:      &version.Info{Major: "1", Minor: "29"},
+		wantInitContainers: []string{"prepare"},
+		wantContainers:     []string{"step-build", "sidecar-proxy"},
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			store := config.NewStore(logtesting.TestLogger(t))
+			store.OnConfigChanged(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: config.GetFeatureFlagsConfigName(), Namespace: system.Namespace()},
+				Data:       map[string]string{"enable-native-sidecars": strconv.FormatBool(tc.enabled)},
+			})
+			ctx := store.ToContext(context.Background())
+			kubeclient := fakek8s.NewSimpleClientset(
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"}},
+			)
+			kubeclient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = tc.serverVersion
+			builder := Builder{
+				Images:          images,
+				KubeClient:      kubeclient,
+				EntrypointCache: fakeCache{},
+			}
+			tr := &v1beta1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "taskrun", Namespace: "default"}}
+			got, err := builder.Build(ctx, tr, v1beta1.TaskSpec{
+				Steps: []v1beta1.Step{{
+					Name:    "build",
+					Image:   "builder",
+					Command: []string{"make"}, // avoid entrypoint lookup.
+				}},
+				Sidecars: []v1beta1.Sidecar{{
+					Name:    "proxy",
+					Image:   "proxy",
+					Command: []string{"proxy"},
+				}},
+			})
+			if err != nil {
+				t.Fatalf("builder.Build: %v", err)
+			}
+			if d := cmp.Diff(tc.wantInitContainers, containerNames(got.Spec.InitContainers)); d != "" {
+				t.Errorf("init containers %s", diff.PrintWantGot(d))
+			}
+			if d := cmp.Diff(tc.wantContainers, containerNames(got.Spec.Containers)); d != "" {
+				t.Errorf("containers %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func containerNames(containers []corev1.Container) []string {
+	var names []string
+	for _, c := range containers {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestCreatePodWithoutNativeSidecars(t *testing.T) {
+	kubeclient := fakek8s.NewSimpleClientset()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "step-build"}, {Name: "sidecar-proxy"}},
+		},
+	}
+	got, err := CreatePod(context.Background(), kubeclient, pod)
+	if err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if d := cmp.Diff(pod, got); d != "" {
+		t.Errorf("created pod %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestNativeSidecarsPodJSON(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "prepare"}, {Name: "sidecar-proxy"}},
+			Containers:     []corev1.Container{{Name: "step-build"}},
+		},
+	}
+	b, err := nativeSidecarsPodJSON(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Spec struct {
+			InitContainers []map[string]interface{} `json:"initContainers"`
+			Containers     []map[string]interface{} `json:"containers"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{nil, "Always"}
+	for i, c := range got.Spec.InitContainers {
+		if d := cmp.Diff(want[i], c["restartPolicy"]); d != "" {
+			t.Errorf("restartPolicy of init container %v %s", c["name"], diff.PrintWantGot(d))
+		}
+	}
+	if _, ok := got.Spec.Containers[0]["restartPolicy"]; ok {
+		t.Errorf("unexpected restartPolicy on container %v", got.Spec.Containers[0]["name"])
+	}
+}
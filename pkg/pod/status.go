@@ -68,19 +68,34 @@ const (
 	// ReasonExceededNodeResources or isPodHitConfigError
 	ReasonPending = "Pending"
 
+	// ReasonStepCancelled indicates that the step was terminated because the
+	// TaskRun was cancelled gracefully
+	ReasonStepCancelled = "Cancelled"
+
+	// ReasonSidecarGracePeriodExceeded indicates that the sidecar was killed
+	// because it did not exit within its grace period after it was stopped
+	ReasonSidecarGracePeriodExceeded = "GracePeriodExceeded"
+
 	// timeFormat is RFC3339 with millisecond
 	timeFormat = "2006-01-02T15:04:05.000Z07:00"
 )
 
 const oomKilled = "OOMKilled"
 
-// SidecarsReady returns true if all of the Pod's sidecars are Ready or
-// Terminated.
-func SidecarsReady(podStatus corev1.PodStatus) bool {
+// SidecarsReady returns true if all of the Pod's sidecars, including the ones
+// run as native sidecar containers, are Ready or Terminated, except the
+// sidecars of the Task which are not a readiness gate.
+func SidecarsReady(podStatus corev1.PodStatus, sidecars []v1beta1.Sidecar) bool {
 	if podStatus.Phase != corev1.PodRunning {
 		return false
 	}
-	for _, s := range podStatus.ContainerStatuses {
+	notGating := map[string]bool{}
+	for _, s := range sidecars {
+		if !isReadinessGate(s) {
+			notGating[s.Name] = true
+		}
+	}
+	for _, s := range append(podStatus.ContainerStatuses, NativeSidecarStatuses(podStatus)...) {
 		// If the step indicates that it's a step, skip it.
 		// An injected sidecar might not have the "sidecar-" prefix, so
 		// we can't just look for that prefix, we need to look at any
@@ -88,6 +103,9 @@ func SidecarsReady(podStatus corev1.PodStatus) bool {
 		if IsContainerStep(s.Name) {
 			continue
 		}
+		if isContainerSidecar(s.Name) && notGating[TrimSidecarPrefix(s.Name)] {
+			continue
+		}
 		if s.State.Running != nil && s.Ready {
 			continue
 		}
@@ -130,13 +148,14 @@ func MakeTaskRunStatus(logger *zap.SugaredLogger, tr v1beta1.TaskRun, pod *corev
 			sidecarStatuses = append(sidecarStatuses, s)
 		}
 	}
+	sidecarStatuses = append(sidecarStatuses, NativeSidecarStatuses(pod.Status)...)
 
 	var merr *multierror.Error
 	if err := setTaskRunStatusBasedOnStepStatus(logger, stepStatuses, &tr); err != nil {
 		merr = multierror.Append(merr, err)
 	}
 
-	setTaskRunStatusBasedOnSidecarStatus(sidecarStatuses, trs)
+	setTaskRunStatusBasedOnSidecarStatus(logger, sidecarStatuses, trs)
 
 	trs.TaskRunResults = removeDuplicateResults(trs.TaskRunResults)
 
@@ -184,6 +203,9 @@ func setTaskRunStatusBasedOnStepStatus(logger *zap.SugaredLogger, stepStatuses [
 				if exitCode != nil {
 					s.State.Terminated.ExitCode = *exitCode
 				}
+				if isStepCancelled(results) {
+					s.State.Terminated.Reason = ReasonStepCancelled
+				}
 			}
 		}
 		trs.Steps = append(trs.Steps, v1beta1.StepState{
@@ -198,10 +220,31 @@ func setTaskRunStatusBasedOnStepStatus(logger *zap.SugaredLogger, stepStatuses [
 
 }
 
-func setTaskRunStatusBasedOnSidecarStatus(sidecarStatuses []corev1.ContainerStatus, trs *v1beta1.TaskRunStatus) {
+// NativeSidecarStatuses returns the statuses of the sidecars of the pod run
+// as native sidecar containers, which are reported with the init containers.
+func NativeSidecarStatuses(podStatus corev1.PodStatus) []corev1.ContainerStatus {
+	var statuses []corev1.ContainerStatus
+	for _, s := range podStatus.InitContainerStatuses {
+		if isContainerSidecar(s.Name) {
+			statuses = append(statuses, s)
+		}
+	}
+	return statuses
+}
+
+func setTaskRunStatusBasedOnSidecarStatus(logger *zap.SugaredLogger, sidecarStatuses []corev1.ContainerStatus, trs *v1beta1.TaskRunStatus) {
 	for _, s := range sidecarStatuses {
+		state := s.State.DeepCopy()
+		// The entrypoint only writes the termination message of a sidecar
+		// killed after its grace period, other sidecars may write any message.
+		if state.Terminated != nil && len(state.Terminated.Message) != 0 {
+			if results, err := termination.ParseMessage(logger, state.Terminated.Message); err == nil && isSidecarGracePeriodExceeded(results) {
+				state.Terminated.Reason = ReasonSidecarGracePeriodExceeded
+				state.Terminated.Message = ""
+			}
+		}
 		trs.Sidecars = append(trs.Sidecars, v1beta1.SidecarState{
-			ContainerState: *s.State.DeepCopy(),
+			ContainerState: *state,
 			Name:           TrimSidecarPrefix(s.Name),
 			ContainerName:  s.Name,
 			ImageID:        s.ImageID,
@@ -304,6 +347,28 @@ func extractExitCodeFromResults(results []v1beta1.PipelineResourceResult) (*int3
 	return nil, nil
 }
 
+// isStepCancelled returns true if the entrypoint of the step reported that it
+// was cancelled.
+func isStepCancelled(results []v1beta1.PipelineResourceResult) bool {
+	for _, result := range results {
+		if result.ResultType == v1beta1.InternalTektonResultType && result.Key == "Reason" && result.Value == ReasonStepCancelled {
+			return true
+		}
+	}
+	return false
+}
+
+// isSidecarGracePeriodExceeded returns true if the entrypoint of the sidecar
+// reported that it was killed after its grace period.
+func isSidecarGracePeriodExceeded(results []v1beta1.PipelineResourceResult) bool {
+	for _, result := range results {
+		if result.ResultType == v1beta1.InternalTektonResultType && result.Key == "Reason" && result.Value == ReasonSidecarGracePeriodExceeded {
+			return true
+		}
+	}
+	return false
+}
+
 func updateCompletedTaskRunStatus(logger *zap.SugaredLogger, trs *v1beta1.TaskRunStatus, pod *corev1.Pod) {
 	if DidTaskRunFail(pod) {
 		msg := getFailureMessage(logger, pod)
@@ -227,6 +227,47 @@ func TestMakeTaskRunStatus(t *testing.T) {
 				Sidecars: []v1beta1.SidecarState{},
 			},
 		},
+	}, {
+		desc: "cancelled-step",
+		podStatus: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "step-cancelled-step",
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 143,
+						Message:  `[{"key":"Reason","value":"Cancelled","type":"InternalTektonResult"}]`,
+					},
+				},
+			}, {
+				Name: "step-next-step",
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{},
+				},
+			}},
+		},
+		want: v1beta1.TaskRunStatus{
+			Status: statusRunning(),
+			TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+				Steps: []v1beta1.StepState{{
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 143,
+							Reason:   ReasonStepCancelled,
+						},
+					},
+					Name:          "cancelled-step",
+					ContainerName: "step-cancelled-step",
+				}, {
+					ContainerState: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+					Name:          "next-step",
+					ContainerName: "step-next-step",
+				}},
+				Sidecars: []v1beta1.SidecarState{},
+			},
+		},
 	}, {
 		desc: "failure-terminated",
 		podStatus: corev1.PodStatus{
@@ -563,6 +604,94 @@ func TestMakeTaskRunStatus(t *testing.T) {
 				}},
 			},
 		},
+	}, {
+		desc: "with-native-sidecar-running",
+		podStatus: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name: "prepare",
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{},
+				},
+			}, {
+				Name:    "sidecar-proxy",
+				ImageID: "image-id",
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{},
+				},
+				Ready: true,
+			}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "step-running-step",
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{},
+				},
+			}},
+		},
+		want: v1beta1.TaskRunStatus{
+			Status: statusRunning(),
+			TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+				Steps: []v1beta1.StepState{{
+					ContainerState: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+					Name:          "running-step",
+					ContainerName: "step-running-step",
+				}},
+				Sidecars: []v1beta1.SidecarState{{
+					ContainerState: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+					Name:          "proxy",
+					ImageID:       "image-id",
+					ContainerName: "sidecar-proxy",
+				}},
+			},
+		},
+	}, {
+		desc: "with-sidecar-killed-after-grace-period",
+		podStatus: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "step-running-step",
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{},
+				},
+			}, {
+				Name:    "sidecar-proxy",
+				ImageID: "image-id",
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 1,
+						Reason:   "Error",
+						Message:  `[{"key":"Reason","value":"GracePeriodExceeded","type":3}]`,
+					},
+				},
+			}},
+		},
+		want: v1beta1.TaskRunStatus{
+			Status: statusRunning(),
+			TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+				Steps: []v1beta1.StepState{{
+					ContainerState: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+					Name:          "running-step",
+					ContainerName: "step-running-step",
+				}},
+				Sidecars: []v1beta1.SidecarState{{
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 1,
+							Reason:   "GracePeriodExceeded",
+						},
+					},
+					Name:          "proxy",
+					ImageID:       "image-id",
+					ContainerName: "sidecar-proxy",
+				}},
+			},
+		},
 	}, {
 		desc: "image resource updated",
 		podStatus: corev1.PodStatus{
@@ -1414,10 +1543,13 @@ func TestMakeRunStatusJSONError(t *testing.T) {
 }
 
 func TestSidecarsReady(t *testing.T) {
+	readinessGate := false
 	for _, c := range []struct {
-		desc     string
-		statuses []corev1.ContainerStatus
-		want     bool
+		desc         string
+		statuses     []corev1.ContainerStatus
+		initStatuses []corev1.ContainerStatus
+		sidecars     []v1beta1.Sidecar
+		want         bool
 	}{{
 		desc: "no sidecars",
 		statuses: []corev1.ContainerStatus{
@@ -1490,12 +1622,80 @@ func TestSidecarsReady(t *testing.T) {
 			{Name: "step-ignore-me"},
 		},
 		want: false,
+	}, {
+		desc: "sidecar running but not ready is not a readiness gate",
+		statuses: []corev1.ContainerStatus{
+			{Name: "step-ignore-me"},
+			{
+				Name:  "sidecar-ready",
+				Ready: true,
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{
+						StartedAt: metav1.NewTime(time.Now()),
+					},
+				},
+			},
+			{
+				Name:  "sidecar-running-not-ready",
+				Ready: false, // Not ready.
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{
+						StartedAt: metav1.NewTime(time.Now()),
+					},
+				},
+			},
+		},
+		sidecars: []v1beta1.Sidecar{{
+			Name: "ready",
+		}, {
+			Name:          "running-not-ready",
+			ReadinessGate: &readinessGate,
+		}},
+		want: true,
+	}, {
+		desc: "injected sidecar running but not ready",
+		statuses: []corev1.ContainerStatus{
+			{Name: "step-ignore-me"},
+			{
+				Name:  "running-not-ready",
+				Ready: false, // Not ready.
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{
+						StartedAt: metav1.NewTime(time.Now()),
+					},
+				},
+			},
+		},
+		sidecars: []v1beta1.Sidecar{{
+			Name:          "running-not-ready",
+			ReadinessGate: &readinessGate,
+		}},
+		want: false,
+	}, {
+		desc: "native sidecar running but not ready",
+		statuses: []corev1.ContainerStatus{
+			{Name: "step-ignore-me"},
+		},
+		initStatuses: []corev1.ContainerStatus{
+			{Name: "prepare-ignore-me"},
+			{
+				Name:  "sidecar-running-not-ready",
+				Ready: false, // Not ready.
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{
+						StartedAt: metav1.NewTime(time.Now()),
+					},
+				},
+			},
+		},
+		want: false,
 	}} {
 		t.Run(c.desc, func(t *testing.T) {
 			got := SidecarsReady(corev1.PodStatus{
-				Phase:             corev1.PodRunning,
-				ContainerStatuses: c.statuses,
-			})
+				Phase:                 corev1.PodRunning,
+				InitContainerStatuses: c.initStatuses,
+				ContainerStatuses:     c.statuses,
+			}, c.sidecars)
 			if got != c.want {
 				t.Errorf("SidecarsReady got %t, want %t", got, c.want)
 			}
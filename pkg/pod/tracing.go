@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// addTracingArgs configures the entrypoint of each step to export a span
+// around the step, as a child of the TaskRun span, when tracing is enabled
+// and the TaskRun has a span context.
+func addTracingArgs(ctx context.Context, taskRun *v1beta1.TaskRun, steps []corev1.Container) []corev1.Container {
+	cfg := config.FromContextOrDefaults(ctx).Tracing
+	spanContext := taskRun.Annotations[pipeline.TaskRunSpanContextAnnotationKey]
+	if cfg == nil || !cfg.Enabled || spanContext == "" {
+		return steps
+	}
+	for i, s := range steps {
+		// The entrypoint flags precede the "--" separating them from the
+		// step's own args, so the tracing flags can be prepended.
+		steps[i].Args = append([]string{
+			"-tracing_exporter", cfg.Exporter,
+			"-tracing_endpoint", cfg.Endpoint,
+			"-span_context", spanContext,
+			"-step_name", s.Name,
+		}, s.Args...)
+	}
+	return steps
+}
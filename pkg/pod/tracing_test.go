@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/test/diff"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAddTracingArgs(t *testing.T) {
+	spanContext := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	tracingEnabled := &config.Tracing{
+		Enabled:  true,
+		Exporter: config.TracingExporterOTLP,
+		Endpoint: "http://collector:4318",
+	}
+	annotated := &v1beta1.TaskRun{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{pipeline.TaskRunSpanContextAnnotationKey: spanContext},
+	}}
+	steps := func() []corev1.Container {
+		return []corev1.Container{{
+			Name: "step-foo",
+			Args: []string{"-post_file", "/tekton/run/0/out", "--", "arg"},
+		}}
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		tracing *config.Tracing
+		taskRun *v1beta1.TaskRun
+		want    []corev1.Container
+	}{{
+		desc:    "tracing disabled",
+		tracing: &config.Tracing{},
+		taskRun: annotated,
+		want:    steps(),
+	}, {
+		desc:    "no span context",
+		tracing: tracingEnabled,
+		taskRun: &v1beta1.TaskRun{},
+		want:    steps(),
+	}, {
+		desc:    "tracing enabled",
+		tracing: tracingEnabled,
+		taskRun: annotated,
+		want: []corev1.Container{{
+			Name: "step-foo",
+			Args: []string{
+				"-tracing_exporter", "otlp",
+				"-tracing_endpoint", "http://collector:4318",
+				"-span_context", spanContext,
+				"-step_name", "step-foo",
+				"-post_file", "/tekton/run/0/out", "--", "arg",
+			},
+		}},
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := config.ToContext(context.Background(), &config.Config{Tracing: tc.tracing})
+			got := addTracingArgs(ctx, tc.taskRun, steps())
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("Diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
@@ -24,11 +24,32 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // Struct to unmarshal the event data
 type eventData struct {
 	Run *v1alpha1.Run `json:"run,omitempty"`
+	// Subject is set in the data of CDEvents
+	Subject *eventSubject `json:"subject,omitempty"`
+	// Kind, Name, Namespace and UID are set in the summary payload
+	Kind      string    `json:"kind,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	UID       types.UID `json:"uid,omitempty"`
+}
+
+// Struct to unmarshal the subject of CDEvents
+type eventSubject struct {
+	ID      string              `json:"id"`
+	Source  string              `json:"source"`
+	Type    string              `json:"type"`
+	Content eventSubjectContent `json:"content"`
+}
+
+// Struct to unmarshal the content of the subject of CDEvents
+type eventSubjectContent struct {
+	UID types.UID `json:"uid,omitempty"`
 }
 
 // AddEventSentToCache adds the particular object to cache marking it as sent
@@ -63,16 +84,28 @@ func EventKey(event *cloudevents.Event) (string, error) {
 		data              eventData
 		resourceName      string
 		resourceNamespace string
+		resourceUID       types.UID
 	)
 	err := json.Unmarshal(event.Data(), &data)
 	if err != nil {
 		return "", err
 	}
-	if data.Run == nil {
+	// The UID is part of the key so that the events of an object which is
+	// recreated with the same name are not considered already sent.
+	eventType := event.Type()
+	switch {
+	case data.Run != nil:
+		resourceName = data.Run.Name
+		resourceNamespace = data.Run.Namespace
+		resourceUID = data.Run.UID
+	case data.Kind == "Run":
+		resourceName = data.Name
+		resourceNamespace = data.Namespace
+		resourceUID = data.UID
+	case data.Subject != nil:
+		return fmt.Sprintf("%s/%s/%s/%s/%s", eventType, data.Subject.Type, data.Subject.Source, data.Subject.ID, data.Subject.Content.UID), nil
+	default:
 		return "", fmt.Errorf("Invalid Run data in %v", event)
 	}
-	resourceName = data.Run.Name
-	resourceNamespace = data.Run.Namespace
-	eventType := event.Type()
-	return fmt.Sprintf("%s/run/%s/%s", eventType, resourceNamespace, resourceName), nil
+	return fmt.Sprintf("%s/run/%s/%s/%s", eventType, resourceNamespace, resourceName, resourceUID), nil
 }
@@ -30,14 +30,18 @@ import (
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/test/diff"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func strptr(s string) *string { return &s }
 
 func getEventData(run interface{}) map[string]interface{} {
 	cloudEventData := map[string]interface{}{}
-	if v, ok := run.(*v1alpha1.Run); ok {
+	switch v := run.(type) {
+	case *v1alpha1.Run:
 		cloudEventData["run"] = v
+	case map[string]interface{}:
+		cloudEventData = v
 	}
 	return cloudEventData
 }
@@ -58,7 +62,7 @@ func getEventToTest(eventtype string, run interface{}) *event.Event {
 	return &e
 }
 
-func getRunByMeta(name string, namespace string) *v1alpha1.Run {
+func getRunByMeta(name string, namespace string, uid types.UID) *v1alpha1.Run {
 	return &v1alpha1.Run{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Run",
@@ -67,6 +71,7 @@ func getRunByMeta(name string, namespace string) *v1alpha1.Run {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
+			UID:       uid,
 		},
 		Spec:   v1alpha1.RunSpec{},
 		Status: v1alpha1.RunStatus{},
@@ -84,9 +89,24 @@ func TestEventsKey(t *testing.T) {
 	}{{
 		name:      "run event",
 		eventtype: "my.test.run.event",
-		run:       getRunByMeta("myrun", "mynamespace"),
-		wantKey:   "my.test.run.event/run/mynamespace/myrun",
+		run:       getRunByMeta("myrun", "mynamespace", "myuid"),
+		wantKey:   "my.test.run.event/run/mynamespace/myrun/myuid",
 		wantErr:   false,
+	}, {
+		name:      "run summary event",
+		eventtype: "my.test.run.event",
+		run:       map[string]interface{}{"kind": "Run", "name": "myrun", "namespace": "mynamespace", "uid": "myuid"},
+		wantKey:   "my.test.run.event/run/mynamespace/myrun/myuid",
+		wantErr:   false,
+	}, {
+		name:      "cdevent",
+		eventtype: "dev.cdevents.taskrun.started.0.1.0",
+		run: map[string]interface{}{"subject": map[string]interface{}{
+			"id": "mytaskrun", "source": "mynamespace", "type": "taskRun",
+			"content": map[string]interface{}{"uid": "myuid"},
+		}},
+		wantKey: "dev.cdevents.taskrun.started.0.1.0/taskRun/mynamespace/mytaskrun/myuid",
+		wantErr: false,
 	}, {
 		name:      "run event missing data",
 		eventtype: "my.test.run.event",
@@ -112,8 +132,9 @@ func TestEventsKey(t *testing.T) {
 }
 
 func TestAddCheckEvent(t *testing.T) {
-	run := getRunByMeta("arun", "anamespace")
-	runb := getRunByMeta("arun", "bnamespace")
+	run := getRunByMeta("arun", "anamespace", "auid")
+	runb := getRunByMeta("arun", "bnamespace", "buid")
+	recreatedRun := getRunByMeta("arun", "anamespace", "cuid")
 	baseEvent := getEventToTest("some.event.type", run)
 
 	testcases := []struct {
@@ -136,6 +157,11 @@ func TestAddCheckEvent(t *testing.T) {
 		firstEvent:  baseEvent,
 		secondEvent: getEventToTest("some.event.type", runb),
 		wantFound:   false,
+	}, {
+		name:        "recreated run",
+		firstEvent:  baseEvent,
+		secondEvent: getEventToTest("some.event.type", recreatedRun),
+		wantFound:   false,
 	}, {
 		name:        "different event type",
 		firstEvent:  baseEvent,
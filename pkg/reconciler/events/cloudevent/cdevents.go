@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudevent
+
+import (
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+)
+
+// CDEventType holds the types of the CDEvents sent by Tekton, see
+// https://github.com/cdevents/spec
+type CDEventType string
+
+const (
+	// CDEventsVersion is the version of the CDEvents specification of the CDEvents sent by Tekton
+	CDEventsVersion = "0.1.0"
+
+	// PipelineRunQueuedCDEventV1 is sent for PipelineRuns which are pending or
+	// waiting for their Pipeline to be validated or resolved
+	PipelineRunQueuedCDEventV1 CDEventType = "dev.cdevents.pipelinerun.queued.0.1.0"
+	// PipelineRunStartedCDEventV1 is sent for PipelineRuns once they are running
+	PipelineRunStartedCDEventV1 CDEventType = "dev.cdevents.pipelinerun.started.0.1.0"
+	// PipelineRunFinishedCDEventV1 is sent for PipelineRuns once they are done
+	PipelineRunFinishedCDEventV1 CDEventType = "dev.cdevents.pipelinerun.finished.0.1.0"
+	// TaskRunStartedCDEventV1 is sent for TaskRuns and Runs once they are started
+	TaskRunStartedCDEventV1 CDEventType = "dev.cdevents.taskrun.started.0.1.0"
+	// TaskRunFinishedCDEventV1 is sent for TaskRuns and Runs once they are done
+	TaskRunFinishedCDEventV1 CDEventType = "dev.cdevents.taskrun.finished.0.1.0"
+
+	// CDEventOutcomeSuccess is the outcome of runs which succeeded
+	CDEventOutcomeSuccess = "success"
+	// CDEventOutcomeFailure is the outcome of runs which failed
+	CDEventOutcomeFailure = "failure"
+)
+
+func (t CDEventType) String() string {
+	return string(t)
+}
+
+// CDEventData type is used to marshal and unmarshal the payload of a CDEvent
+type CDEventData struct {
+	Context CDEventContext `json:"context"`
+	Subject CDEventSubject `json:"subject"`
+}
+
+// CDEventContext holds the context of a CDEvent, which repeats the
+// attributes of the CloudEvent carrying it
+type CDEventContext struct {
+	Version   string    `json:"version"`
+	ID        string    `json:"id"`
+	Source    string    `json:"source"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// CDEventSubject holds the subject of a CDEvent, i.e. the run it is about
+type CDEventSubject struct {
+	ID      string                `json:"id"`
+	Source  string                `json:"source"`
+	Type    string                `json:"type"`
+	Content CDEventSubjectContent `json:"content"`
+}
+
+// CDEventSubjectContent holds the fields of the subject of a CDEvent
+type CDEventSubjectContent struct {
+	PipelineName string              `json:"pipelineName,omitempty"`
+	TaskName     string              `json:"taskName,omitempty"`
+	URL          string              `json:"url,omitempty"`
+	PipelineRun  *CDEventSubjectLink `json:"pipelineRun,omitempty"`
+	Outcome      string              `json:"outcome,omitempty"`
+	Errors       string              `json:"errors,omitempty"`
+	// UID is the UID of the run, which tells apart runs recreated with the
+	// same name
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// CDEventSubjectLink references the subject of other CDEvents
+type CDEventSubjectLink struct {
+	ID     string `json:"id"`
+	Source string `json:"source,omitempty"`
+}
+
+// cdEventForObjectWithCondition creates a new CDEvent for the transition of
+// the condition of a objectWithCondition, or returns nil if there is no
+// CDEvent for it.
+func cdEventForObjectWithCondition(runObject objectWithCondition) (*cloudevents.Event, error) {
+	eventType := getCDEventType(runObject)
+	if eventType == nil {
+		return nil, nil
+	}
+	event, err := newEvent(runObject, *eventType, nil)
+	if err != nil {
+		return nil, err
+	}
+	event.SetTime(time.Now())
+
+	meta := runObject.GetObjectMeta()
+	subject := CDEventSubject{
+		ID:     meta.GetName(),
+		Source: meta.GetNamespace(),
+		Type:   "taskRun",
+		Content: CDEventSubjectContent{
+			URL: event.Source(),
+			UID: meta.GetUID(),
+		},
+	}
+	labels := meta.GetLabels()
+	switch v := runObject.(type) {
+	case *v1beta1.PipelineRun:
+		subject.Type = "pipelineRun"
+		subject.Content.PipelineName = labels[pipeline.PipelineLabelKey]
+		if subject.Content.PipelineName == "" && v.Spec.PipelineRef != nil {
+			subject.Content.PipelineName = v.Spec.PipelineRef.Name
+		}
+	case *v1beta1.TaskRun:
+		subject.Content.TaskName = labels[pipeline.TaskLabelKey]
+		if subject.Content.TaskName == "" && v.Spec.TaskRef != nil {
+			subject.Content.TaskName = v.Spec.TaskRef.Name
+		}
+	case *v1alpha1.Run:
+		if v.Spec.Ref != nil {
+			subject.Content.TaskName = v.Spec.Ref.Name
+		}
+	}
+	if pipelineRun, ok := labels[pipeline.PipelineRunLabelKey]; ok && subject.Type == "taskRun" {
+		subject.Content.PipelineRun = &CDEventSubjectLink{ID: pipelineRun, Source: meta.GetNamespace()}
+	}
+	if c := runObject.GetStatusCondition().GetCondition(apis.ConditionSucceeded); c != nil && !c.IsUnknown() {
+		subject.Content.Outcome = CDEventOutcomeSuccess
+		if c.IsFalse() {
+			subject.Content.Outcome = CDEventOutcomeFailure
+			subject.Content.Errors = c.Message
+		}
+	}
+
+	data := CDEventData{
+		Context: CDEventContext{
+			Version:   CDEventsVersion,
+			ID:        event.ID(),
+			Source:    event.Source(),
+			Type:      event.Type(),
+			Timestamp: event.Time(),
+		},
+		Subject: subject,
+	}
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// getCDEventType returns the type of the CDEvent for the condition of a
+// objectWithCondition, or nil if there is none.
+func getCDEventType(runObject objectWithCondition) *CDEventType {
+	var eventType CDEventType
+	c := runObject.GetStatusCondition().GetCondition(apis.ConditionSucceeded)
+	switch {
+	case c == nil:
+		// Runs may not have any condition until they are picked up by their
+		// reconciler, see getEventType
+		if _, isRun := runObject.(*v1alpha1.Run); !isRun {
+			return nil
+		}
+		eventType = TaskRunStartedCDEventV1
+	case c.IsUnknown():
+		switch runObject.(type) {
+		case *v1beta1.PipelineRun:
+			eventType = PipelineRunQueuedCDEventV1
+			if c.Reason == v1beta1.PipelineRunReasonRunning.String() {
+				eventType = PipelineRunStartedCDEventV1
+			}
+		default:
+			eventType = TaskRunStartedCDEventV1
+		}
+	default:
+		switch runObject.(type) {
+		case *v1beta1.PipelineRun:
+			eventType = PipelineRunFinishedCDEventV1
+		default:
+			eventType = TaskRunFinishedCDEventV1
+		}
+	}
+	return &eventType
+}
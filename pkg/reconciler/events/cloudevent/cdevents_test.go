@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudevent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/reconciler/events/cache"
+	"github.com/tektoncd/pipeline/test/diff"
+	eventstest "github.com/tektoncd/pipeline/test/events"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestCDEventForObjectWithCondition(t *testing.T) {
+	taskRun := getTaskRunByCondition(corev1.ConditionFalse, "Failed")
+	taskRun.Labels = map[string]string{
+		pipeline.TaskLabelKey:        "build",
+		pipeline.PipelineRunLabelKey: "release-run",
+	}
+	taskRun.Status.Conditions[0].Message = "step build failed"
+	taskRun.UID = "a-taskrun-uid"
+
+	for _, tc := range []struct {
+		desc          string
+		runObject     objectWithCondition
+		wantEventType CDEventType
+		wantSubject   CDEventSubject
+	}{{
+		desc:          "pipelinerun started",
+		runObject:     getPipelineRunByCondition(corev1.ConditionUnknown, v1beta1.PipelineRunReasonStarted.String()),
+		wantEventType: PipelineRunQueuedCDEventV1,
+		wantSubject: CDEventSubject{
+			ID:      pipelineRunName,
+			Source:  "marshmallow",
+			Type:    "pipelineRun",
+			Content: CDEventSubjectContent{URL: defaultEventSourceURI},
+		},
+	}, {
+		desc:          "pipelinerun running",
+		runObject:     getPipelineRunByCondition(corev1.ConditionUnknown, v1beta1.PipelineRunReasonRunning.String()),
+		wantEventType: PipelineRunStartedCDEventV1,
+		wantSubject: CDEventSubject{
+			ID:      pipelineRunName,
+			Source:  "marshmallow",
+			Type:    "pipelineRun",
+			Content: CDEventSubjectContent{URL: defaultEventSourceURI},
+		},
+	}, {
+		desc:          "pipelinerun successful",
+		runObject:     getPipelineRunByCondition(corev1.ConditionTrue, "Succeeded"),
+		wantEventType: PipelineRunFinishedCDEventV1,
+		wantSubject: CDEventSubject{
+			ID:      pipelineRunName,
+			Source:  "marshmallow",
+			Type:    "pipelineRun",
+			Content: CDEventSubjectContent{URL: defaultEventSourceURI, Outcome: CDEventOutcomeSuccess},
+		},
+	}, {
+		desc:          "taskrun running",
+		runObject:     getTaskRunByCondition(corev1.ConditionUnknown, v1beta1.TaskRunReasonRunning.String()),
+		wantEventType: TaskRunStartedCDEventV1,
+		wantSubject: CDEventSubject{
+			ID:      taskRunName,
+			Source:  "marshmallow",
+			Type:    "taskRun",
+			Content: CDEventSubjectContent{URL: defaultEventSourceURI},
+		},
+	}, {
+		desc:          "taskrun of a pipelinerun failed",
+		runObject:     taskRun,
+		wantEventType: TaskRunFinishedCDEventV1,
+		wantSubject: CDEventSubject{
+			ID:     taskRunName,
+			Source: "marshmallow",
+			Type:   "taskRun",
+			Content: CDEventSubjectContent{
+				TaskName:    "build",
+				URL:         defaultEventSourceURI,
+				PipelineRun: &CDEventSubjectLink{ID: "release-run", Source: "marshmallow"},
+				Outcome:     CDEventOutcomeFailure,
+				Errors:      "step build failed",
+				UID:         "a-taskrun-uid",
+			},
+		},
+	}, {
+		desc:          "run without condition",
+		runObject:     &v1alpha1.Run{},
+		wantEventType: TaskRunStartedCDEventV1,
+		wantSubject: CDEventSubject{
+			Type:    "taskRun",
+			Content: CDEventSubjectContent{URL: "/apis///namespaces///"},
+		},
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := cdEventForObjectWithCondition(tc.runObject)
+			if err != nil {
+				t.Fatalf("I did not expect an error but I got %s", err)
+			}
+			if d := cmp.Diff(tc.wantEventType.String(), got.Type()); d != "" {
+				t.Errorf("Wrong Event Type %s", diff.PrintWantGot(d))
+			}
+			gotData := CDEventData{}
+			if err := got.DataAs(&gotData); err != nil {
+				t.Fatalf("Unexpected error from DataAs; %s", err)
+			}
+			if d := cmp.Diff(tc.wantSubject, gotData.Subject); d != "" {
+				t.Errorf("Wrong Event subject %s", diff.PrintWantGot(d))
+			}
+			wantContext := CDEventContext{
+				Version:   CDEventsVersion,
+				ID:        got.ID(),
+				Source:    got.Source(),
+				Type:      got.Type(),
+				Timestamp: got.Time(),
+			}
+			if d := cmp.Diff(wantContext, gotData.Context); d != "" {
+				t.Errorf("Wrong Event context %s", diff.PrintWantGot(d))
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("Expected event to be valid; %s", err)
+			}
+		})
+	}
+}
+
+func TestCDEventForObjectWithConditionNoEvent(t *testing.T) {
+	taskRun := getTaskRunByCondition(corev1.ConditionUnknown, "")
+	taskRun.Status.Conditions = nil
+	got, err := cdEventForObjectWithCondition(taskRun)
+	if err != nil {
+		t.Fatalf("I did not expect an error but I got %s", err)
+	}
+	if got != nil {
+		t.Errorf("Expected no CDEvent, got %v", got)
+	}
+}
+
+func TestSendCloudEventWithRetriesCDEvents(t *testing.T) {
+	ctx := setupFakeContext(t, FakeClientBehaviour{SendSuccessfully: true}, true)
+	cacheClient, _ := lru.New(10)
+	ctx = cache.ToContext(ctx, cacheClient)
+	events, _ := config.NewEventsFromMap(map[string]string{"format": "cdevents"})
+	ctx = config.ToContext(ctx, &config.Config{Events: events})
+
+	taskRun := getTaskRunByCondition(corev1.ConditionUnknown, v1beta1.TaskRunReasonStarted.String())
+	if err := SendCloudEventWithRetries(ctx, taskRun); err != nil {
+		t.Fatalf("Unexpected error sending cloud events: %v", err)
+	}
+	if err := SendStepCloudEventWithRetries(ctx, taskRun, v1beta1.StepState{Name: "build"}); err != nil {
+		t.Fatalf("Unexpected error sending cloud events: %v", err)
+	}
+	ceClient := Get(ctx).(FakeClient)
+	wantCEvents := []string{`(?s)dev.cdevents.taskrun.started.0.1.0.*faketaskrunname`}
+	if err := eventstest.CheckEventsUnordered(t, ceClient.Events, "cdevents", wantCEvents); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Wait for the CDEvent to be added to the cache, after it was sent
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return cacheClient.Len() == 1, nil
+	}); err != nil {
+		t.Fatalf("The CDEvent was not added to the cache: %v", err)
+	}
+
+	// The TaskRun is still started when it is running, the CDEvent is only sent once
+	taskRun = getTaskRunByCondition(corev1.ConditionUnknown, v1beta1.TaskRunReasonRunning.String())
+	if err := SendCloudEventWithRetries(ctx, taskRun); err != nil {
+		t.Fatalf("Unexpected error sending cloud events: %v", err)
+	}
+	if err := eventstest.CheckEventsUnordered(t, ceClient.Events, "cdevents deduplicated", []string{}); err != nil {
+		t.Fatalf(err.Error())
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/hashicorp/go-multierror"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	resource "github.com/tektoncd/pipeline/pkg/apis/resource/v1alpha1"
@@ -37,6 +38,10 @@ import (
 	"knative.dev/pkg/logging"
 )
 
+// DeadLetterLoggerName is the name of the logger cloud events which could
+// not be delivered are logged to.
+const DeadLetterLoggerName = "deadletter"
+
 // InitializeCloudEvents initializes the CloudEvents part of the
 // TaskRunStatus from a slice of PipelineResources
 func InitializeCloudEvents(tr *v1beta1.TaskRun, prs map[string]*resource.PipelineResource) {
@@ -130,25 +135,103 @@ func SendCloudEventWithRetries(ctx context.Context, object runtime.Object) error
 	if o, ok = object.(objectWithCondition); !ok {
 		return errors.New("Input object does not satisfy objectWithCondition")
 	}
+	return sendCloudEventWithRetries(ctx, o, func() (*cloudevents.Event, error) {
+		if isCDEvents(ctx) {
+			return cdEventForObjectWithCondition(o)
+		}
+		return eventForObjectWithCondition(o)
+	})
+}
+
+// SendSkippedTaskCloudEventWithRetries sends a cloud event for a PipelineTask
+// skipped in the PipelineRun, the same way SendCloudEventWithRetries does.
+func SendSkippedTaskCloudEventWithRetries(ctx context.Context, pipelineRun *v1beta1.PipelineRun, skippedTask v1beta1.SkippedTask) error {
+	return sendCloudEventWithRetries(ctx, pipelineRun, func() (*cloudevents.Event, error) {
+		if isCDEvents(ctx) {
+			// There is no CDEvent for skipped PipelineTasks
+			return nil, nil
+		}
+		return eventForSkippedTask(pipelineRun, skippedTask)
+	})
+}
+
+// SendStepCloudEventWithRetries sends a cloud event for a terminated step of
+// the TaskRun, the same way SendCloudEventWithRetries does.
+func SendStepCloudEventWithRetries(ctx context.Context, taskRun *v1beta1.TaskRun, step v1beta1.StepState) error {
+	return sendCloudEventWithRetries(ctx, taskRun, func() (*cloudevents.Event, error) {
+		if isCDEvents(ctx) {
+			// There is no CDEvent for steps
+			return nil, nil
+		}
+		return eventForStep(taskRun, step)
+	})
+}
+
+// isCDEvents returns true if CDEvents should be sent instead of the Tekton
+// CloudEvents.
+func isCDEvents(ctx context.Context) bool {
+	cfg := config.FromContextOrDefaults(ctx).Events
+	return cfg != nil && cfg.Format == config.EventsFormatCDEvents
+}
+
+// sendCloudEventWithRetries sends the event about o created by makeEvent,
+// unless there is none or it is filtered out by the events configuration.
+// Events which could not be delivered after all retries are kept in the
+// outbox when the delivery is durable, or logged to the dead-letter logger.
+func sendCloudEventWithRetries(ctx context.Context, o objectWithCondition, makeEvent func() (*cloudevents.Event, error)) error {
 	logger := logging.FromContext(ctx)
 	ceClient := Get(ctx)
 	if ceClient == nil {
 		return errors.New("No cloud events client found in the context")
 	}
-	event, err := eventForObjectWithCondition(o)
-	if err != nil {
+	event, err := makeEvent()
+	if err != nil || event == nil {
 		return err
 	}
-	// Events for Runs require a cache of events that have been sent
+	cfg := config.FromContextOrDefaults(ctx).Events
+	if !cfg.Allows(event.Type(), o.GetObjectMeta().GetLabels()) {
+		logger.Debugf("Not sending cloudevent of type %q, filtered out", event.Type())
+		return nil
+	}
+	retries := config.DefaultEventsRetries
+	if cfg != nil {
+		retries = cfg.Retries
+		if cfg.Payload == config.EventsPayloadSummary && cfg.Format != config.EventsFormatCDEvents {
+			if err := summarize(event, o); err != nil {
+				return err
+			}
+		}
+	}
+	var target string
+	if u := cloudevents.TargetFromContext(ctx); u != nil {
+		target = u.String()
+	}
+	// Events for Runs and CDEvents require a cache of events that have been sent
 	cacheClient := cache.Get(ctx)
-	_, isRun := object.(*v1alpha1.Run)
+	_, isRun := o.(*v1alpha1.Run)
+	useCache := isRun || isCDEvents(ctx)
+
+	// With durable delivery, the event is recorded in the outbox until it is
+	// delivered, by this routine or by the outbox controller
+	var outboxName string
+	if cfg != nil && cfg.Delivery == config.EventsDeliveryDurable && target != "" {
+		if useCache {
+			if cloudEventSent, _ := cache.IsCloudEventSent(cacheClient, event); cloudEventSent {
+				logger.Infof("cloudevent %v already sent", event)
+				return nil
+			}
+		}
+		if outboxName, err = addToOutbox(ctx, target, event); err != nil {
+			logger.Warnf("Failed to record cloudevent in the outbox: %s", err)
+		}
+	}
 
 	wasIn := make(chan error)
 	go func() {
 		wasIn <- nil
 		logger.Debugf("Sending cloudevent of type %q", event.Type())
-		// In case of Run event, check cache if cloudevent is already sent
-		if isRun {
+		// In case of Run event or CDEvent, check cache if cloudevent is already sent
+		if useCache {
 			cloudEventSent, err := cache.IsCloudEventSent(cacheClient, event)
 			if err != nil {
 				logger.Errorf("error while checking cache: %s", err)
@@ -158,16 +241,26 @@ func SendCloudEventWithRetries(ctx context.Context, object runtime.Object) error
 				return
 			}
 		}
-		if result := ceClient.Send(cloudevents.ContextWithRetriesExponentialBackoff(ctx, 10*time.Millisecond, 10), *event); !cloudevents.IsACK(result) {
+		if result := ceClient.Send(cloudevents.ContextWithRetriesExponentialBackoff(ctx, 10*time.Millisecond, retries), *event); !cloudevents.IsACK(result) {
 			logger.Warnf("Failed to send cloudevent: %s", result.Error())
+			if outboxName == "" {
+				logger.Named(DeadLetterLoggerName).Errorw("Cloudevent dropped after retries",
+					zap.String("target", target),
+					zap.Int("retries", retries),
+					zap.String("event", event.String()))
+			}
 			recorder := controller.GetEventRecorder(ctx)
 			if recorder == nil {
 				logger.Warnf("No recorder in context, cannot emit error event")
 			}
-			recorder.Event(object, corev1.EventTypeWarning, "Cloud Event Failure", result.Error())
+			recorder.Event(o, corev1.EventTypeWarning, "Cloud Event Failure", result.Error())
+		} else if outboxName != "" {
+			if err := removeFromOutbox(ctx, outboxName); err != nil {
+				logger.Errorf("error while removing sent event from the outbox: %s", err)
+			}
 		}
-		// In case of Run event, add to the cache to avoid duplicate events
-		if isRun {
+		// In case of Run event or CDEvent, add to the cache to avoid duplicate events
+		if useCache {
 			if err := cache.AddEventSentToCache(cacheClient, event); err != nil {
 				logger.Errorf("error while adding sent event to cache: %s", err)
 			}
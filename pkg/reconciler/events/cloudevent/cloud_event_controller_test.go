@@ -21,21 +21,29 @@ import (
 	"testing"
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	resourcev1alpha1 "github.com/tektoncd/pipeline/pkg/apis/resource/v1alpha1"
 	"github.com/tektoncd/pipeline/test/diff"
 	eventstest "github.com/tektoncd/pipeline/test/events"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	"knative.dev/pkg/apis"
 	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	_ "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	rtesting "knative.dev/pkg/reconciler/testing"
+	_ "knative.dev/pkg/system/testing" // Setup system.Namespace()
 )
 
 var now = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
@@ -622,6 +630,133 @@ func TestSendCloudEventWithRetries(t *testing.T) {
 	}
 }
 
+func TestSendCloudEventWithRetriesEventsConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        map[string]string
+		wantCEvents []string
+	}{{
+		name:        "no filter",
+		data:        map[string]string{},
+		wantCEvents: []string{`(?s)dev.tekton.event.taskrun.successful.v1.*"taskRun"`},
+	}, {
+		name:        "filtered type",
+		data:        map[string]string{"filter.types": "dev.tekton.event.taskrun.failed.v1"},
+		wantCEvents: []string{},
+	}, {
+		name:        "filtered labels",
+		data:        map[string]string{"filter.labels": "app=bar"},
+		wantCEvents: []string{},
+	}, {
+		name: "matching filters",
+		data: map[string]string{
+			"filter.types":  "dev.tekton.event.taskrun.successful.v1",
+			"filter.labels": "app=foo",
+		},
+		wantCEvents: []string{`(?s)dev.tekton.event.taskrun.successful.v1.*"taskRun"`},
+	}, {
+		name:        "summary payload",
+		data:        map[string]string{"payload": "summary"},
+		wantCEvents: []string{`(?s)dev.tekton.event.taskrun.successful.v1.*"kind": "TaskRun"`},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupFakeContext(t, FakeClientBehaviour{SendSuccessfully: true}, true)
+			events, err := config.NewEventsFromMap(tc.data)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing the events config: %v", err)
+			}
+			ctx = config.ToContext(ctx, &config.Config{Events: events})
+			taskRun := getTaskRunByCondition(corev1.ConditionTrue, "Succeeded")
+			taskRun.Labels = map[string]string{"app": "foo"}
+			if err := SendCloudEventWithRetries(ctx, taskRun); err != nil {
+				t.Fatalf("Unexpected error sending cloud events: %v", err)
+			}
+			ceClient := Get(ctx).(FakeClient)
+			if err := eventstest.CheckEventsUnordered(t, ceClient.Events, tc.name, tc.wantCEvents); err != nil {
+				t.Fatalf(err.Error())
+			}
+		})
+	}
+}
+
+func TestSendCloudEventWithRetriesDeadLetter(t *testing.T) {
+	ctx := setupFakeContext(t, FakeClientBehaviour{SendSuccessfully: false}, true)
+	observer, logs := observer.New(zap.ErrorLevel)
+	ctx = logging.WithLogger(ctx, zap.New(observer).Sugar())
+	events, _ := config.NewEventsFromMap(map[string]string{"retries": "0"})
+	ctx = config.ToContext(ctx, &config.Config{Events: events})
+	if err := SendStepCloudEventWithRetries(ctx, getTaskRunByCondition(corev1.ConditionUnknown, "Running"), v1beta1.StepState{Name: "build"}); err != nil {
+		t.Fatalf("Unexpected error sending cloud events: %v", err)
+	}
+	recorder := controller.GetEventRecorder(ctx).(*record.FakeRecorder)
+	if err := eventstest.CheckEventsOrdered(t, recorder.Events, "dead letter", []string{"Warning Cloud Event Failure"}); err != nil {
+		t.Fatalf(err.Error())
+	}
+	deadLetters := logs.FilterField(zap.Int("retries", 0)).All()
+	if len(deadLetters) != 1 || deadLetters[0].LoggerName != DeadLetterLoggerName {
+		t.Fatalf("Expected one entry in the %s log, got %v", DeadLetterLoggerName, deadLetters)
+	}
+}
+
+func TestSendCloudEventWithRetriesDurable(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		sendSuccessfully bool
+		wantCEvents      []string
+		wantOutbox       int
+	}{{
+		name:             "delivered events are removed from the outbox",
+		sendSuccessfully: true,
+		wantCEvents:      []string{`(?s)dev.tekton.event.taskrun.successful.v1.*faketaskrunname`},
+		wantOutbox:       0,
+	}, {
+		name:             "failed events are kept in the outbox",
+		sendSuccessfully: false,
+		wantCEvents:      []string{},
+		wantOutbox:       1,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupFakeContext(t, FakeClientBehaviour{SendSuccessfully: tc.sendSuccessfully}, true)
+			observer, logs := observer.New(zap.ErrorLevel)
+			ctx = logging.WithLogger(ctx, zap.New(observer).Sugar())
+			ctx = cloudevents.ContextWithTarget(ctx, "http://sink")
+			events, _ := config.NewEventsFromMap(map[string]string{"retries": "0", "delivery": "durable"})
+			ctx = config.ToContext(ctx, &config.Config{Events: events})
+			now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			ctx = ClockToContext(ctx, clock.NewFakePassiveClock(now))
+			if err := SendCloudEventWithRetries(ctx, getTaskRunByCondition(corev1.ConditionTrue, "Succeeded")); err != nil {
+				t.Fatalf("Unexpected error sending cloud events: %v", err)
+			}
+			ceClient := Get(ctx).(FakeClient)
+			if err := eventstest.CheckEventsUnordered(t, ceClient.Events, tc.name, tc.wantCEvents); err != nil {
+				t.Fatalf(err.Error())
+			}
+			var outbox *corev1.ConfigMapList
+			if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+				var err error
+				outbox, err = kubeclient.Get(ctx).CoreV1().ConfigMaps(OutboxNamespace()).List(ctx, metav1.ListOptions{
+					LabelSelector: OutboxLabelKey + "=true",
+				})
+				return err == nil && len(outbox.Items) == tc.wantOutbox, err
+			}); err != nil {
+				t.Fatalf("Expected %d events in the outbox, got %v: %v", tc.wantOutbox, outbox, err)
+			}
+			for _, cm := range outbox.Items {
+				// The delivery by the outbox is scheduled with the clock of the reconciler.
+				if got, want := cm.Annotations[OutboxNextAttemptAnnotationKey], "2022-01-01T00:01:00Z"; got != want {
+					t.Errorf("Expected the next attempt of the outbox to be %s, got %s", want, got)
+				}
+			}
+			for _, entry := range logs.All() {
+				if entry.LoggerName == DeadLetterLoggerName {
+					t.Errorf("Expected no entry in the %s log with durable delivery, got %v", DeadLetterLoggerName, entry)
+				}
+			}
+		})
+	}
+}
+
 func TestSendCloudEventWithRetriesInvalid(t *testing.T) {
 
 	tests := []struct {
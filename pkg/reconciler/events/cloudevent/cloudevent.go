@@ -30,6 +30,9 @@ import (
 
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // TektonEventType holds the types of cloud events sent by Tekton
@@ -72,6 +75,10 @@ const (
 	RunSuccessfulEventV1 TektonEventType = "dev.tekton.event.run.successful.v1"
 	// RunFailedEventV1 is sent for Runs with "ConditionSucceeded" "False"
 	RunFailedEventV1 TektonEventType = "dev.tekton.event.run.failed.v1"
+	// PipelineRunTaskSkippedEventV1 is sent for each PipelineTask skipped in a PipelineRun
+	PipelineRunTaskSkippedEventV1 TektonEventType = "dev.tekton.event.pipelinerun.task.skipped.v1"
+	// TaskRunStepTerminatedEventV1 is sent for each step of a TaskRun once it terminates
+	TaskRunStepTerminatedEventV1 TektonEventType = "dev.tekton.event.taskrun.step.terminated.v1"
 )
 
 func (t TektonEventType) String() string {
@@ -82,11 +89,32 @@ func (t TektonEventType) String() string {
 type CEClient cloudevents.Client
 
 // TektonCloudEventData type is used to marshal and unmarshal the payload of
-// a Tekton cloud event. It can include a TaskRun or a PipelineRun
+// a Tekton cloud event. It can include a TaskRun or a PipelineRun, and the
+// skipped PipelineTask or the terminated step the event is about.
 type TektonCloudEventData struct {
 	TaskRun     *v1beta1.TaskRun     `json:"taskRun,omitempty"`
 	PipelineRun *v1beta1.PipelineRun `json:"pipelineRun,omitempty"`
 	Run         *v1alpha1.Run        `json:"run,omitempty"`
+	SkippedTask *v1beta1.SkippedTask `json:"skippedTask,omitempty"`
+	Step        *v1beta1.StepState   `json:"step,omitempty"`
+}
+
+// TektonCloudEventSummary type is used to marshal and unmarshal the compact
+// payload of a Tekton cloud event, sent instead of TektonCloudEventData when
+// the "summary" payload is configured.
+type TektonCloudEventSummary struct {
+	Kind           string                 `json:"kind"`
+	Name           string                 `json:"name"`
+	Namespace      string                 `json:"namespace"`
+	UID            types.UID              `json:"uid,omitempty"`
+	Labels         map[string]string      `json:"labels,omitempty"`
+	Status         corev1.ConditionStatus `json:"status,omitempty"`
+	Reason         string                 `json:"reason,omitempty"`
+	Message        string                 `json:"message,omitempty"`
+	StartTime      *metav1.Time           `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time           `json:"completionTime,omitempty"`
+	SkippedTask    *v1beta1.SkippedTask   `json:"skippedTask,omitempty"`
+	Step           *v1beta1.StepState     `json:"step,omitempty"`
 }
 
 // newTektonCloudEventData returns a new instance of TektonCloudEventData
@@ -103,9 +131,79 @@ func newTektonCloudEventData(runObject objectWithCondition) TektonCloudEventData
 	return tektonCloudEventData
 }
 
+// newTektonCloudEventSummary returns a new instance of TektonCloudEventSummary
+func newTektonCloudEventSummary(runObject objectWithCondition) TektonCloudEventSummary {
+	meta := runObject.GetObjectMeta()
+	summary := TektonCloudEventSummary{
+		Name:      meta.GetName(),
+		Namespace: meta.GetNamespace(),
+		UID:       meta.GetUID(),
+		Labels:    meta.GetLabels(),
+	}
+	if c := runObject.GetStatusCondition().GetCondition(apis.ConditionSucceeded); c != nil {
+		summary.Status = c.Status
+		summary.Reason = c.Reason
+		summary.Message = c.Message
+	}
+	switch v := runObject.(type) {
+	case *v1beta1.TaskRun:
+		summary.Kind = "TaskRun"
+		summary.StartTime = v.Status.StartTime
+		summary.CompletionTime = v.Status.CompletionTime
+	case *v1beta1.PipelineRun:
+		summary.Kind = "PipelineRun"
+		summary.StartTime = v.Status.StartTime
+		summary.CompletionTime = v.Status.CompletionTime
+	case *v1alpha1.Run:
+		summary.Kind = "Run"
+		summary.StartTime = v.Status.StartTime
+		summary.CompletionTime = v.Status.CompletionTime
+	}
+	return summary
+}
+
 // eventForObjectWithCondition creates a new event based for a objectWithCondition,
 // or return an error if not possible.
 func eventForObjectWithCondition(runObject objectWithCondition) (*cloudevents.Event, error) {
+	eventType, err := getEventType(runObject)
+	if err != nil {
+		return nil, err
+	}
+	if eventType == nil {
+		return nil, errors.New("No matching event type found")
+	}
+	return newEvent(runObject, *eventType, newTektonCloudEventData(runObject))
+}
+
+// eventForSkippedTask creates a new event for a PipelineTask skipped in a PipelineRun.
+func eventForSkippedTask(pipelineRun *v1beta1.PipelineRun, skippedTask v1beta1.SkippedTask) (*cloudevents.Event, error) {
+	data := newTektonCloudEventData(pipelineRun)
+	data.SkippedTask = &skippedTask
+	return newEvent(pipelineRun, PipelineRunTaskSkippedEventV1, data)
+}
+
+// eventForStep creates a new event for a terminated step of a TaskRun.
+func eventForStep(taskRun *v1beta1.TaskRun, step v1beta1.StepState) (*cloudevents.Event, error) {
+	data := newTektonCloudEventData(taskRun)
+	data.Step = &step
+	return newEvent(taskRun, TaskRunStepTerminatedEventV1, data)
+}
+
+// summarize replaces the data of an event created for runObject with a
+// TektonCloudEventSummary.
+func summarize(event *cloudevents.Event, runObject objectWithCondition) error {
+	data := TektonCloudEventData{}
+	if err := event.DataAs(&data); err != nil {
+		return err
+	}
+	summary := newTektonCloudEventSummary(runObject)
+	summary.SkippedTask = data.SkippedTask
+	summary.Step = data.Step
+	return event.SetData(cloudevents.ApplicationJSON, summary)
+}
+
+// newEvent creates a new event of the given type about runObject with the given data.
+func newEvent(runObject objectWithCondition, eventType fmt.Stringer, data interface{}) (*cloudevents.Event, error) {
 	event := cloudevents.NewEvent()
 	event.SetID(uuid.New().String())
 	event.SetSubject(runObject.GetObjectMeta().GetName())
@@ -121,16 +219,9 @@ func eventForObjectWithCondition(runObject objectWithCondition) (*cloudevents.Ev
 			runObject.GetObjectMeta().GetName())
 	}
 	event.SetSource(source)
-	eventType, err := getEventType(runObject)
-	if err != nil {
-		return nil, err
-	}
-	if eventType == nil {
-		return nil, errors.New("No matching event type found")
-	}
 	event.SetType(eventType.String())
 
-	if err := event.SetData(cloudevents.ApplicationJSON, newTektonCloudEventData(runObject)); err != nil {
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
 		return nil, err
 	}
 	return &event, nil
@@ -295,3 +295,80 @@ func TestEventForRun(t *testing.T) {
 		})
 	}
 }
+
+func TestEventForSkippedTaskAndStep(t *testing.T) {
+	pipelineRun := getPipelineRunByCondition(corev1.ConditionUnknown, v1beta1.PipelineRunReasonRunning.String())
+	skippedTask := v1beta1.SkippedTask{Name: "deploy", Reason: v1beta1.WhenExpressionsSkip}
+	got, err := eventForSkippedTask(pipelineRun, skippedTask)
+	if err != nil {
+		t.Fatalf("I did not expect an error but I got %s", err)
+	}
+	if d := cmp.Diff(string(PipelineRunTaskSkippedEventV1), got.Type()); d != "" {
+		t.Errorf("Wrong Event Type %s", diff.PrintWantGot(d))
+	}
+	wantData := newTektonCloudEventData(pipelineRun)
+	wantData.SkippedTask = &skippedTask
+	gotData := TektonCloudEventData{}
+	if err := got.DataAs(&gotData); err != nil {
+		t.Errorf("Unexpected error from DataAs; %s", err)
+	}
+	if d := cmp.Diff(wantData, gotData); d != "" {
+		t.Errorf("Wrong Event data %s", diff.PrintWantGot(d))
+	}
+
+	taskRun := getTaskRunByCondition(corev1.ConditionUnknown, v1beta1.TaskRunReasonRunning.String())
+	step := v1beta1.StepState{
+		Name: "build",
+		ContainerState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"},
+		},
+	}
+	got, err = eventForStep(taskRun, step)
+	if err != nil {
+		t.Fatalf("I did not expect an error but I got %s", err)
+	}
+	if d := cmp.Diff(string(TaskRunStepTerminatedEventV1), got.Type()); d != "" {
+		t.Errorf("Wrong Event Type %s", diff.PrintWantGot(d))
+	}
+	wantData = newTektonCloudEventData(taskRun)
+	wantData.Step = &step
+	gotData = TektonCloudEventData{}
+	if err := got.DataAs(&gotData); err != nil {
+		t.Errorf("Unexpected error from DataAs; %s", err)
+	}
+	if d := cmp.Diff(wantData, gotData); d != "" {
+		t.Errorf("Wrong Event data %s", diff.PrintWantGot(d))
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("Expected event to be valid; %s", err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	pipelineRun := getPipelineRunByCondition(corev1.ConditionFalse, "Failed")
+	pipelineRun.Labels = map[string]string{"app": "foo"}
+	skippedTask := v1beta1.SkippedTask{Name: "deploy", Reason: v1beta1.WhenExpressionsSkip}
+	event, err := eventForSkippedTask(pipelineRun, skippedTask)
+	if err != nil {
+		t.Fatalf("I did not expect an error but I got %s", err)
+	}
+	if err := summarize(event, pipelineRun); err != nil {
+		t.Fatalf("Unexpected error summarizing the event: %s", err)
+	}
+	want := TektonCloudEventSummary{
+		Kind:        "PipelineRun",
+		Name:        pipelineRunName,
+		Namespace:   pipelineRun.Namespace,
+		Labels:      map[string]string{"app": "foo"},
+		Status:      corev1.ConditionFalse,
+		Reason:      "Failed",
+		SkippedTask: &skippedTask,
+	}
+	got := TektonCloudEventSummary{}
+	if err := event.DataAs(&got); err != nil {
+		t.Errorf("Unexpected error from DataAs; %s", err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Wrong Event data %s", diff.PrintWantGot(d))
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudevent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// OutboxLabelKey is set on the ConfigMaps of the outbox, which hold the
+	// cloud events waiting to be delivered when the delivery is durable
+	OutboxLabelKey = pipeline.GroupName + "/cloudevent-outbox"
+
+	// OutboxAttemptsAnnotationKey is set on the ConfigMaps of the outbox to the
+	// number of attempts made to deliver their cloud event by the outbox
+	OutboxAttemptsAnnotationKey = pipeline.GroupName + "/cloudevent-attempts"
+
+	// OutboxNextAttemptAnnotationKey is set on the ConfigMaps of the outbox to
+	// the time of the next attempt to deliver their cloud event
+	OutboxNextAttemptAnnotationKey = pipeline.GroupName + "/cloudevent-next-attempt"
+
+	// OutboxNamespaceEnvKey is the environment variable setting the namespace
+	// of the ConfigMaps of the outbox
+	OutboxNamespaceEnvKey = "CLOUDEVENTS_OUTBOX_NAMESPACE"
+
+	outboxTargetKey = "target"
+	outboxEventKey  = "event"
+
+	// outboxGracePeriod is the time left to the reconcilers to deliver a cloud
+	// event before it is delivered by the outbox
+	outboxGracePeriod = time.Minute
+)
+
+// OutboxNamespace returns the namespace of the ConfigMaps of the outbox. The
+// outbox has a namespace of its own because RBAC cannot restrict the creation
+// of ConfigMaps by name or label, so that the controller is only granted write
+// access to the ConfigMaps of the outbox. It defaults to the system namespace
+// with the -outbox suffix.
+func OutboxNamespace() string {
+	if ns := os.Getenv(OutboxNamespaceEnvKey); ns != "" {
+		return ns
+	}
+	return system.Namespace() + "-outbox"
+}
+
+// clockKey is used to associate the clock of the reconciler inside the context.Context
+type clockKey struct{}
+
+// ClockToContext adds the clock of the reconciler sending cloud events to the context
+func ClockToContext(ctx context.Context, c clock.PassiveClock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// clockFromContext returns the clock of the reconciler sending cloud events, or
+// the real clock if there is none in the context.
+func clockFromContext(ctx context.Context) clock.PassiveClock {
+	if c, ok := ctx.Value(clockKey{}).(clock.PassiveClock); ok {
+		return c
+	}
+	return clock.RealClock{}
+}
+
+// addToOutbox records the event to be delivered to the target in a ConfigMap
+// of the outbox, and returns the name of the ConfigMap.
+func addToOutbox(ctx context.Context, target string, event *cloudevents.Event) (string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cloudevent-" + event.ID(),
+			Namespace: OutboxNamespace(),
+			Labels: map[string]string{
+				v1beta1.ManagedByLabelKey: "tekton-pipelines",
+				OutboxLabelKey:            "true",
+			},
+			Annotations: map[string]string{
+				OutboxAttemptsAnnotationKey:    "0",
+				OutboxNextAttemptAnnotationKey: clockFromContext(ctx).Now().Add(outboxGracePeriod).UTC().Format(time.RFC3339),
+			},
+		},
+		Data: map[string]string{
+			outboxTargetKey: target,
+			outboxEventKey:  string(data),
+		},
+	}
+	if _, err := kubeclient.Get(ctx).CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+		return "", err
+	}
+	return cm.Name, nil
+}
+
+// removeFromOutbox deletes the ConfigMap of the outbox with the given name,
+// once its cloud event is delivered.
+func removeFromOutbox(ctx context.Context, name string) error {
+	err := kubeclient.Get(ctx).CoreV1().ConfigMaps(OutboxNamespace()).Delete(ctx, name, metav1.DeleteOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// OutboxEntry is a cloud event waiting to be delivered in the outbox
+type OutboxEntry struct {
+	Target      string
+	Event       cloudevents.Event
+	Attempts    int
+	NextAttempt time.Time
+}
+
+// OutboxEntryFromConfigMap returns the cloud event held by a ConfigMap of the outbox.
+func OutboxEntryFromConfigMap(cm *corev1.ConfigMap) (*OutboxEntry, error) {
+	entry := &OutboxEntry{Target: cm.Data[outboxTargetKey]}
+	if entry.Target == "" {
+		return nil, fmt.Errorf("no target in outbox ConfigMap %s", cm.Name)
+	}
+	if err := json.Unmarshal([]byte(cm.Data[outboxEventKey]), &entry.Event); err != nil {
+		return nil, fmt.Errorf("invalid event in outbox ConfigMap %s: %w", cm.Name, err)
+	}
+	if attempts, ok := cm.Annotations[OutboxAttemptsAnnotationKey]; ok {
+		n, err := strconv.Atoi(attempts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation in outbox ConfigMap %s: %w", OutboxAttemptsAnnotationKey, cm.Name, err)
+		}
+		entry.Attempts = n
+	}
+	if next, ok := cm.Annotations[OutboxNextAttemptAnnotationKey]; ok {
+		t, err := time.Parse(time.RFC3339, next)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation in outbox ConfigMap %s: %w", OutboxNextAttemptAnnotationKey, cm.Name, err)
+		}
+		entry.NextAttempt = t
+	}
+	return entry, nil
+}
@@ -21,10 +21,15 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/reconciler/events/cloudevent"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/controller"
@@ -40,6 +45,21 @@ const (
 	EventReasonStarted = "Started"
 	// EventReasonError is the reason set for events related to TaskRuns / PipelineRuns reconcile errors
 	EventReasonError = "Error"
+	// EventReasonPipelineTaskSkipped is the reason set for events about PipelineTasks skipped in PipelineRuns
+	EventReasonPipelineTaskSkipped = "PipelineTaskSkipped"
+	// EventReasonPipelineTaskRetried is the reason set for events about PipelineTasks retried in PipelineRuns
+	EventReasonPipelineTaskRetried = "PipelineTaskRetried"
+	// EventReasonPipelineTaskTimedOut is the reason set for events about PipelineTasks timed out in PipelineRuns
+	EventReasonPipelineTaskTimedOut = "PipelineTaskTimedOut"
+	// EventReasonStepFailed is the reason set for events about steps of TaskRuns which failed
+	EventReasonStepFailed = "StepFailed"
+	// EventReasonStepOOMKilled is the reason set for events about steps of TaskRuns which ran out of memory
+	EventReasonStepOOMKilled = "StepOOMKilled"
+	// EventReasonSidecarsStopFailed is the reason set for events about sidecars of TaskRuns which failed to stop
+	EventReasonSidecarsStopFailed = "SidecarsStopFailed"
+
+	// oomKilled is the reason of the termination of containers which ran out of memory
+	oomKilled = "OOMKilled"
 )
 
 // Emit emits events for object
@@ -50,10 +70,10 @@ const (
 func Emit(ctx context.Context, beforeCondition *apis.Condition, afterCondition *apis.Condition, object runtime.Object) {
 	recorder := controller.GetEventRecorder(ctx)
 	logger := logging.FromContext(ctx)
-	configs := config.FromContextOrDefaults(ctx)
-	sendCloudEvents := (configs.Defaults.DefaultCloudEventsSink != "")
+	sink := cloudEventsSink(ctx, object)
+	sendCloudEvents := (sink != "")
 	if sendCloudEvents {
-		ctx = cloudevents.ContextWithTarget(ctx, configs.Defaults.DefaultCloudEventsSink)
+		ctx = cloudevents.ContextWithTarget(ctx, sink)
 	}
 
 	sendKubernetesEvents(recorder, beforeCondition, afterCondition, object)
@@ -72,10 +92,10 @@ func Emit(ctx context.Context, beforeCondition *apis.Condition, afterCondition *
 // EmitCloudEvents emits CloudEvents (only) for object
 func EmitCloudEvents(ctx context.Context, object runtime.Object) {
 	logger := logging.FromContext(ctx)
-	configs := config.FromContextOrDefaults(ctx)
-	sendCloudEvents := (configs.Defaults.DefaultCloudEventsSink != "")
+	sink := cloudEventsSink(ctx, object)
+	sendCloudEvents := (sink != "")
 	if sendCloudEvents {
-		ctx = cloudevents.ContextWithTarget(ctx, configs.Defaults.DefaultCloudEventsSink)
+		ctx = cloudevents.ContextWithTarget(ctx, sink)
 	}
 
 	if sendCloudEvents {
@@ -86,6 +106,149 @@ func EmitCloudEvents(ctx context.Context, object runtime.Object) {
 	}
 }
 
+// EmitSkippedTaskEvents emits events for each PipelineTask skipped in the
+// PipelineRun that was not in beforeSkippedTasks.
+//
+// k8s events are sent if detailed Kubernetes events are enabled
+// Cloud events are sent if enabled, i.e. if a sink is available
+func EmitSkippedTaskEvents(ctx context.Context, pr *v1beta1.PipelineRun, beforeSkippedTasks []v1beta1.SkippedTask) {
+	recorder := controller.GetEventRecorder(ctx)
+	logger := logging.FromContext(ctx)
+	sendKubernetesEvents := detailedKubernetesEvents(ctx)
+	sink := cloudEventsSink(ctx, pr)
+	sendCloudEvents := (sink != "")
+	if sendCloudEvents {
+		ctx = cloudevents.ContextWithTarget(ctx, sink)
+	}
+
+	skipped := sets.NewString()
+	for _, st := range beforeSkippedTasks {
+		skipped.Insert(st.Name)
+	}
+	for _, st := range pr.Status.SkippedTasks {
+		if skipped.Has(st.Name) {
+			continue
+		}
+		if sendKubernetesEvents {
+			recorder.Eventf(pr, corev1.EventTypeNormal, EventReasonPipelineTaskSkipped, "PipelineTask %q was skipped: %s", st.Name, st.Reason)
+		}
+		if sendCloudEvents {
+			if err := cloudevent.SendSkippedTaskCloudEventWithRetries(ctx, pr, st); err != nil {
+				logger.Warnf("Failed to emit cloud events %v", err.Error())
+			}
+		}
+	}
+}
+
+// EmitStepEvents emits events for each step of the TaskRun which terminated
+// since beforeSteps.
+//
+// k8s events are sent for the steps which failed if detailed Kubernetes
+// events are enabled
+// Cloud events are sent if enabled, i.e. if a sink is available
+func EmitStepEvents(ctx context.Context, tr *v1beta1.TaskRun, beforeSteps []v1beta1.StepState) {
+	recorder := controller.GetEventRecorder(ctx)
+	logger := logging.FromContext(ctx)
+	sendKubernetesEvents := detailedKubernetesEvents(ctx)
+	sink := cloudEventsSink(ctx, tr)
+	sendCloudEvents := (sink != "")
+	if sendCloudEvents {
+		ctx = cloudevents.ContextWithTarget(ctx, sink)
+	}
+
+	terminated := sets.NewString()
+	for _, s := range beforeSteps {
+		if s.Terminated != nil {
+			terminated.Insert(s.Name)
+		}
+	}
+	for _, s := range tr.Status.Steps {
+		if s.Terminated == nil || terminated.Has(s.Name) {
+			continue
+		}
+		if sendKubernetesEvents {
+			switch {
+			case s.Terminated.Reason == oomKilled:
+				recorder.Eventf(tr, corev1.EventTypeWarning, EventReasonStepOOMKilled, "Step %q ran out of memory", s.Name)
+			case s.Terminated.ExitCode != 0:
+				recorder.Eventf(tr, corev1.EventTypeWarning, EventReasonStepFailed, "Step %q failed with exit code %d", s.Name, s.Terminated.ExitCode)
+			}
+		}
+		if sendCloudEvents {
+			if err := cloudevent.SendStepCloudEventWithRetries(ctx, tr, s); err != nil {
+				logger.Warnf("Failed to emit cloud events %v", err.Error())
+			}
+		}
+	}
+}
+
+// EmitPipelineTaskRetried emits a k8s event on the PipelineRun when the
+// TaskRun of one of its PipelineTasks is retried, if detailed Kubernetes
+// events are enabled
+func EmitPipelineTaskRetried(ctx context.Context, pr *v1beta1.PipelineRun, pipelineTaskName string, retry, retries int) {
+	if !detailedKubernetesEvents(ctx) {
+		return
+	}
+	controller.GetEventRecorder(ctx).Eventf(pr, corev1.EventTypeWarning, EventReasonPipelineTaskRetried,
+		"PipelineTask %q failed and is retried (retry %d of %d)", pipelineTaskName, retry, retries)
+}
+
+// EmitPipelineTaskTimedOut emits a k8s event on the PipelineRun owning the
+// TaskRun when the TaskRun of one of its PipelineTasks times out, if detailed
+// Kubernetes events are enabled
+func EmitPipelineTaskTimedOut(ctx context.Context, tr *v1beta1.TaskRun, message string) {
+	if !detailedKubernetesEvents(ctx) {
+		return
+	}
+	pipelineTaskName, ok := tr.Labels[pipeline.PipelineTaskLabelKey]
+	if !ok {
+		return
+	}
+	for _, ref := range tr.OwnerReferences {
+		if ref.Kind != pipeline.PipelineRunControllerName {
+			continue
+		}
+		pr := &v1beta1.PipelineRun{
+			TypeMeta:   metav1.TypeMeta{APIVersion: ref.APIVersion, Kind: ref.Kind},
+			ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: tr.Namespace, UID: ref.UID},
+		}
+		controller.GetEventRecorder(ctx).Eventf(pr, corev1.EventTypeWarning, EventReasonPipelineTaskTimedOut,
+			"PipelineTask %q timed out: %s", pipelineTaskName, message)
+	}
+}
+
+// EmitSidecarsStopFailed emits a k8s event on the TaskRun when its sidecars
+// fail to stop, if detailed Kubernetes events are enabled
+func EmitSidecarsStopFailed(ctx context.Context, tr *v1beta1.TaskRun, err error) {
+	if !detailedKubernetesEvents(ctx) {
+		return
+	}
+	controller.GetEventRecorder(ctx).Eventf(tr, corev1.EventTypeWarning, EventReasonSidecarsStopFailed,
+		"Failed to stop the sidecars: %v", err)
+}
+
+// detailedKubernetesEvents returns true if k8s events should be sent for the
+// transitions of PipelineTasks, steps and sidecars
+func detailedKubernetesEvents(ctx context.Context) bool {
+	return config.FromContextOrDefaults(ctx).Events.KubernetesEvents == config.EventsKubernetesEventsDetailed
+}
+
+// cloudEventsSink returns the sink of the CloudEvents about object: the sink
+// selected with its annotation if allowed, the sink of its namespace, the
+// sink of the events configuration or the default sink, in that order.
+func cloudEventsSink(ctx context.Context, object runtime.Object) string {
+	configs := config.FromContextOrDefaults(ctx)
+	var namespace, annotation string
+	if m, err := meta.Accessor(object); err == nil {
+		namespace = m.GetNamespace()
+		annotation = m.GetAnnotations()[pipeline.CloudEventsSinkAnnotationKey]
+	}
+	if sink := configs.Events.SinkFor(namespace, annotation); sink != "" {
+		return sink
+	}
+	return configs.Defaults.DefaultCloudEventsSink
+}
+
 func sendKubernetesEvents(c record.EventRecorder, beforeCondition *apis.Condition, afterCondition *apis.Condition, object runtime.Object) {
 	// Events that are going to be sent
 	//
@@ -17,11 +17,13 @@ limitations under the License.
 package events
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/reconciler/events/cloudevent"
@@ -300,3 +302,215 @@ func TestEmitCloudEvents(t *testing.T) {
 		}
 	}
 }
+
+func TestCloudEventsSink(t *testing.T) {
+	object := &v1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test1",
+			Namespace:   "team-a",
+			Annotations: map[string]string{pipeline.CloudEventsSinkAnnotationKey: "http://annotation"},
+		},
+	}
+	testcases := []struct {
+		name     string
+		defaults map[string]string
+		events   map[string]string
+		want     string
+	}{{
+		name: "without sink",
+	}, {
+		name:     "default sink",
+		defaults: map[string]string{"default-cloud-events-sink": "http://default"},
+		want:     "http://default",
+	}, {
+		name:     "events sink",
+		defaults: map[string]string{"default-cloud-events-sink": "http://default"},
+		events:   map[string]string{"sink": "http://events"},
+		want:     "http://events",
+	}, {
+		name:   "namespace sink",
+		events: map[string]string{"sink": "http://events", "sink.namespace.team-a": "http://team-a"},
+		want:   "http://team-a",
+	}, {
+		name:   "annotation not allowed",
+		events: map[string]string{"sink.namespace.team-b": "http://team-b"},
+	}, {
+		name:   "annotation allowed",
+		events: map[string]string{"sink.namespace.team-a": "http://team-a", "allow-sink-annotation": "true"},
+		want:   "http://annotation",
+	}}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			defaults, _ := config.NewDefaultsFromMap(tc.defaults)
+			events, err := config.NewEventsFromMap(tc.events)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing the events config: %v", err)
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{Defaults: defaults, Events: events})
+			if got := cloudEventsSink(ctx, object); got != tc.want {
+				t.Errorf("cloudEventsSink() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEmitSkippedTaskEvents(t *testing.T) {
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1",
+			Namespace: "ns",
+		},
+		Status: v1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
+				SkippedTasks: []v1beta1.SkippedTask{{
+					Name:   "already-skipped",
+					Reason: v1beta1.WhenExpressionsSkip,
+				}, {
+					Name:   "newly-skipped",
+					Reason: v1beta1.ParentTasksSkip,
+				}},
+			},
+		},
+	}
+	ctx, _ := rtesting.SetupFakeContext(t)
+	ctx = cloudevent.WithClient(ctx, &cloudevent.FakeClientBehaviour{SendSuccessfully: true})
+	fakeClient := cloudevent.Get(ctx).(cloudevent.FakeClient)
+	defaults, _ := config.NewDefaultsFromMap(map[string]string{"default-cloud-events-sink": "http://mysink"})
+	events, _ := config.NewEventsFromMap(map[string]string{"kubernetes-events": "detailed"})
+	ctx = config.ToContext(ctx, &config.Config{Defaults: defaults, Events: events})
+
+	EmitSkippedTaskEvents(ctx, pr, pr.Status.SkippedTasks[:1])
+	recorder := controller.GetEventRecorder(ctx).(*record.FakeRecorder)
+	wantEvents := []string{`Normal PipelineTaskSkipped PipelineTask "newly-skipped" was skipped: Parent Tasks were skipped`}
+	if err := eventstest.CheckEventsOrdered(t, recorder.Events, "skipped tasks", wantEvents); err != nil {
+		t.Fatalf(err.Error())
+	}
+	wantCloudEvents := []string{`(?s)dev.tekton.event.pipelinerun.task.skipped.v1.*newly-skipped`}
+	if err := eventstest.CheckEventsUnordered(t, fakeClient.Events, "skipped tasks", wantCloudEvents); err != nil {
+		t.Fatalf(err.Error())
+	}
+}
+
+func TestEmitStepEvents(t *testing.T) {
+	terminated := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}
+	tr := &v1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1",
+			Namespace: "ns",
+		},
+		Status: v1beta1.TaskRunStatus{
+			TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+				Steps: []v1beta1.StepState{{
+					Name:           "already-terminated",
+					ContainerState: terminated,
+				}, {
+					Name:           "newly-terminated",
+					ContainerState: terminated,
+				}, {
+					Name: "failed",
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 2},
+					},
+				}, {
+					Name: "oom-killed",
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+					},
+				}, {
+					Name: "running",
+					ContainerState: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				}},
+			},
+		},
+	}
+	beforeSteps := []v1beta1.StepState{{
+		Name:           "already-terminated",
+		ContainerState: terminated,
+	}, {
+		Name: "newly-terminated",
+		ContainerState: corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{},
+		},
+	}}
+	ctx, _ := rtesting.SetupFakeContext(t)
+	ctx = cloudevent.WithClient(ctx, &cloudevent.FakeClientBehaviour{SendSuccessfully: true})
+	fakeClient := cloudevent.Get(ctx).(cloudevent.FakeClient)
+	defaults, _ := config.NewDefaultsFromMap(map[string]string{"default-cloud-events-sink": "http://mysink"})
+	events, _ := config.NewEventsFromMap(map[string]string{"kubernetes-events": "detailed"})
+	ctx = config.ToContext(ctx, &config.Config{Defaults: defaults, Events: events})
+
+	EmitStepEvents(ctx, tr, beforeSteps)
+	recorder := controller.GetEventRecorder(ctx).(*record.FakeRecorder)
+	wantEvents := []string{
+		`Warning StepFailed Step "failed" failed with exit code 2`,
+		`Warning StepOOMKilled Step "oom-killed" ran out of memory`,
+	}
+	if err := eventstest.CheckEventsOrdered(t, recorder.Events, "steps", wantEvents); err != nil {
+		t.Fatalf(err.Error())
+	}
+	wantCloudEvents := []string{
+		`(?s)dev.tekton.event.taskrun.step.terminated.v1.*newly-terminated`,
+		`(?s)dev.tekton.event.taskrun.step.terminated.v1.*"failed"`,
+		`(?s)dev.tekton.event.taskrun.step.terminated.v1.*oom-killed`,
+	}
+	if err := eventstest.CheckEventsUnordered(t, fakeClient.Events, "steps", wantCloudEvents); err != nil {
+		t.Fatalf(err.Error())
+	}
+}
+
+func TestEmitDetailedKubernetesEvents(t *testing.T) {
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pipelinerun",
+			Namespace: "ns",
+		},
+	}
+	tr := &v1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-taskrun",
+			Namespace: "ns",
+			Labels:    map[string]string{pipeline.PipelineTaskLabelKey: "build"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "tekton.dev/v1beta1",
+				Kind:       pipeline.PipelineRunControllerName,
+				Name:       "test-pipelinerun",
+			}},
+		},
+	}
+	emit := func(ctx context.Context) {
+		EmitPipelineTaskRetried(ctx, pr, "build", 1, 2)
+		EmitPipelineTaskTimedOut(ctx, tr, `TaskRun "test-taskrun" failed to finish within "1m0s"`)
+		EmitSidecarsStopFailed(ctx, tr, errors.New("pod not found"))
+	}
+	for _, tc := range []struct {
+		name       string
+		events     map[string]string
+		wantEvents []string
+	}{{
+		name:       "minimal",
+		events:     map[string]string{},
+		wantEvents: []string{},
+	}, {
+		name:   "detailed",
+		events: map[string]string{"kubernetes-events": "detailed"},
+		wantEvents: []string{
+			`Warning PipelineTaskRetried PipelineTask "build" failed and is retried \(retry 1 of 2\)`,
+			`Warning PipelineTaskTimedOut PipelineTask "build" timed out: TaskRun "test-taskrun" failed to finish within "1m0s"`,
+			`Warning SidecarsStopFailed Failed to stop the sidecars: pod not found`,
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			events, _ := config.NewEventsFromMap(tc.events)
+			ctx = config.ToContext(ctx, &config.Config{Events: events})
+			emit(ctx)
+			recorder := controller.GetEventRecorder(ctx).(*record.FakeRecorder)
+			if err := eventstest.CheckEventsOrdered(t, recorder.Events, tc.name, tc.wantEvents); err != nil {
+				t.Fatalf(err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"context"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cloudeventclient "github.com/tektoncd/pipeline/pkg/reconciler/events/cloudevent"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	k8scache "k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	filteredconfigmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/filtered"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+)
+
+// NewController instantiates a new controller.Impl delivering the cloud events
+// of the outbox.
+func NewController(clock clock.PassiveClock) func(context.Context, configmap.Watcher) *controller.Impl {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		logger := logging.FromContext(ctx)
+		configMapInformer := filteredconfigmapinformer.Get(ctx, v1beta1.ManagedByLabelKey)
+
+		if err := registerViews(); err != nil {
+			logger.Errorf("Failed to register outbox metrics: %v", err)
+		}
+
+		c := &Reconciler{
+			KubeClientSet:    kubeclient.Get(ctx),
+			Clock:            clock,
+			cloudEventClient: cloudeventclient.Get(ctx),
+			configMapLister:  configMapInformer.Lister(),
+		}
+		impl := controller.NewContext(ctx, c, controller.ControllerOptions{
+			WorkQueueName: pipeline.CloudEventOutboxControllerName,
+			Logger:        logger.Named(pipeline.CloudEventOutboxControllerName),
+		})
+		c.LeaderAwareFuncs = reconciler.LeaderAwareFuncs{
+			PromoteFunc: func(bkt reconciler.Bucket, enq func(reconciler.Bucket, types.NamespacedName)) error {
+				cms, err := c.configMapLister.ConfigMaps(cloudeventclient.OutboxNamespace()).List(outboxSelector())
+				if err != nil {
+					return err
+				}
+				for _, cm := range cms {
+					enq(bkt, types.NamespacedName{Namespace: cm.Namespace, Name: cm.Name})
+				}
+				return nil
+			},
+		}
+
+		configMapInformer.Informer().AddEventHandler(k8scache.FilteringResourceEventHandler{
+			FilterFunc: reconciler.ChainFilterFuncs(
+				reconciler.NamespaceFilterFunc(cloudeventclient.OutboxNamespace()),
+				reconciler.LabelFilterFunc(cloudeventclient.OutboxLabelKey, "true", false),
+			),
+			Handler: controller.HandleAll(impl.Enqueue),
+		})
+
+		return impl
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+var (
+	statusTag = tag.MustNewKey("status")
+
+	pendingCount = stats.Float64("cloudevent_outbox_pending_count",
+		"number of cloud events waiting to be delivered in the outbox",
+		stats.UnitDimensionless)
+	pendingCountView = &view.View{
+		Description: pendingCount.Description(),
+		Measure:     pendingCount,
+		Aggregation: view.LastValue(),
+	}
+
+	deliveryCount = stats.Float64("cloudevent_outbox_delivery_count",
+		"number of attempts to deliver the cloud events of the outbox",
+		stats.UnitDimensionless)
+	deliveryCountView = &view.View{
+		Description: deliveryCount.Description(),
+		Measure:     deliveryCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{statusTag},
+	}
+
+	once        sync.Once
+	registerErr error
+)
+
+// registerViews registers the views of the outbox metrics, once.
+func registerViews() error {
+	once.Do(func() {
+		registerErr = view.Register(pendingCountView, deliveryCountView)
+	})
+	return registerErr
+}
+
+// recordPending records the number of cloud events in the outbox.
+func recordPending(ctx context.Context, n int) {
+	metrics.Record(ctx, pendingCount.M(float64(n)))
+}
+
+// recordDelivery records an attempt to deliver a cloud event of the outbox,
+// with its status: "acknowledged" or "failed".
+func recordDelivery(ctx context.Context, status string) {
+	ctx, err := tag.New(ctx, tag.Insert(statusTag, status))
+	if err != nil {
+		return
+	}
+	metrics.Record(ctx, deliveryCount.M(1))
+}
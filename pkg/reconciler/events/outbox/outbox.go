@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/tektoncd/pipeline/pkg/reconciler/events/cloudevent"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+)
+
+const (
+	// minBackoff is the delay before the second attempt of the outbox to
+	// deliver a cloud event, it doubles with each attempt up to maxBackoff
+	minBackoff = 10 * time.Second
+	maxBackoff = 10 * time.Minute
+)
+
+// Reconciler delivers the cloud events of the outbox, retrying with an
+// exponential backoff until they are acknowledged by their sink.
+type Reconciler struct {
+	reconciler.LeaderAwareFuncs
+
+	KubeClientSet    kubernetes.Interface
+	Clock            clock.PassiveClock
+	cloudEventClient cloudevent.CEClient
+	configMapLister  corev1listers.ConfigMapLister
+}
+
+var _ controller.Reconciler = (*Reconciler)(nil)
+
+// Reconcile delivers the cloud event held by the ConfigMap of the outbox
+// with the given key, once its next attempt is due.
+func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorf("invalid resource key: %s", key)
+		return nil
+	}
+	if !r.IsLeaderFor(types.NamespacedName{Namespace: namespace, Name: name}) {
+		return controller.NewSkipKey(key)
+	}
+	defer r.recordPending(ctx)
+
+	cm, err := r.configMapLister.ConfigMaps(namespace).Get(name)
+	if k8serrors.IsNotFound(err) {
+		// The cloud event was delivered
+		return nil
+	} else if err != nil {
+		return err
+	}
+	entry, err := cloudevent.OutboxEntryFromConfigMap(cm)
+	if err != nil {
+		return controller.NewPermanentError(err)
+	}
+
+	now := r.Clock.Now()
+	if entry.NextAttempt.After(now) {
+		return controller.NewRequeueAfter(entry.NextAttempt.Sub(now))
+	}
+
+	// Schedule the next attempt before sending the cloud event, so that other
+	// reconciles of the ConfigMap, e.g. because of this update, do not send it.
+	backoff := backoffFor(entry.Attempts + 1)
+	cm = cm.DeepCopy()
+	cm.Annotations[cloudevent.OutboxAttemptsAnnotationKey] = fmt.Sprint(entry.Attempts + 1)
+	cm.Annotations[cloudevent.OutboxNextAttemptAnnotationKey] = now.Add(backoff).UTC().Format(time.RFC3339)
+	if _, err := r.KubeClientSet.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	if result := r.cloudEventClient.Send(cloudevents.ContextWithTarget(ctx, entry.Target), entry.Event); !cloudevents.IsACK(result) {
+		recordDelivery(ctx, "failed")
+		logger.Warnf("Failed to deliver cloudevent %s from the outbox, attempt %d: %s", entry.Event.ID(), entry.Attempts+1, result.Error())
+		return controller.NewRequeueAfter(backoff)
+	}
+	recordDelivery(ctx, "acknowledged")
+	logger.Infof("Delivered cloudevent %s from the outbox after %d attempts", entry.Event.ID(), entry.Attempts+1)
+	err = r.KubeClientSet.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// recordPending records the number of cloud events in the outbox.
+func (r *Reconciler) recordPending(ctx context.Context) {
+	cms, err := r.configMapLister.ConfigMaps(cloudevent.OutboxNamespace()).List(outboxSelector())
+	if err != nil {
+		return
+	}
+	recordPending(ctx, len(cms))
+}
+
+// backoffFor returns the delay between the given attempt and the next one.
+func backoffFor(attempt int) time.Duration {
+	backoff := minBackoff
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// outboxSelector selects the ConfigMaps of the outbox.
+func outboxSelector() labels.Selector {
+	return labels.SelectorFromSet(labels.Set{cloudevent.OutboxLabelKey: "true"})
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/reconciler/events/cloudevent"
+	"github.com/tektoncd/pipeline/test/diff"
+	eventstest "github.com/tektoncd/pipeline/test/events"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	fakek8s "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/reconciler"
+	rtesting "knative.dev/pkg/reconciler/testing"
+	_ "knative.dev/pkg/system/testing" // Setup cloudevent.OutboxNamespace()
+)
+
+var now = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func outboxConfigMap(t *testing.T, attempts string, nextAttempt time.Time) *corev1.ConfigMap {
+	t.Helper()
+	event := cloudevents.NewEvent()
+	event.SetID("1234")
+	event.SetSource("/apis/tekton.dev/v1beta1/namespaces/default/taskruns/test")
+	event.SetType("dev.tekton.event.taskrun.successful.v1")
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Failed to marshal the event: %v", err)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cloudevent-1234",
+			Namespace: cloudevent.OutboxNamespace(),
+			Labels:    map[string]string{cloudevent.OutboxLabelKey: "true"},
+			Annotations: map[string]string{
+				cloudevent.OutboxAttemptsAnnotationKey:    attempts,
+				cloudevent.OutboxNextAttemptAnnotationKey: nextAttempt.Format(time.RFC3339),
+			},
+		},
+		Data: map[string]string{
+			"target": "http://sink",
+			"event":  string(data),
+		},
+	}
+}
+
+func newReconciler(ctx context.Context, t *testing.T, sendSuccessfully bool, cms ...*corev1.ConfigMap) (*Reconciler, *fakek8s.Clientset, context.Context) {
+	t.Helper()
+	ctx = cloudevent.WithClient(ctx, &cloudevent.FakeClientBehaviour{SendSuccessfully: sendSuccessfully})
+	kubeClient := fakek8s.NewSimpleClientset()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, cm := range cms {
+		if _, err := kubeClient.CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create ConfigMap: %v", err)
+		}
+		if err := indexer.Add(cm); err != nil {
+			t.Fatalf("Failed to index ConfigMap: %v", err)
+		}
+	}
+	r := &Reconciler{
+		KubeClientSet:    kubeClient,
+		Clock:            clock.NewFakePassiveClock(now),
+		cloudEventClient: cloudevent.Get(ctx),
+		configMapLister:  corev1listers.NewConfigMapLister(indexer),
+	}
+	if err := r.Promote(reconciler.UniversalBucket(), func(reconciler.Bucket, types.NamespacedName) {}); err != nil {
+		t.Fatalf("Failed to promote the reconciler: %v", err)
+	}
+	return r, kubeClient, ctx
+}
+
+func TestReconcileDelivered(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	cm := outboxConfigMap(t, "0", now.Add(-time.Second))
+	r, kubeClient, ctx := newReconciler(ctx, t, true, cm)
+
+	if err := r.Reconcile(ctx, cloudevent.OutboxNamespace()+"/"+cm.Name); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fakeClient := r.cloudEventClient.(cloudevent.FakeClient)
+	if err := eventstest.CheckEventsUnordered(t, fakeClient.Events, "delivered", []string{`(?s)dev.tekton.event.taskrun.successful.v1.*1234`}); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if _, err := kubeClient.CoreV1().ConfigMaps(cm.Namespace).Get(ctx, cm.Name, metav1.GetOptions{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("Expected the ConfigMap of the delivered event to be deleted, got %v", err)
+	}
+}
+
+func TestReconcileFailed(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	cm := outboxConfigMap(t, "2", now.Add(-time.Second))
+	r, kubeClient, ctx := newReconciler(ctx, t, false, cm)
+
+	err := r.Reconcile(ctx, cloudevent.OutboxNamespace()+"/"+cm.Name)
+	if ok, delay := controller.IsRequeueKey(err); !ok || delay != 40*time.Second {
+		t.Fatalf("Expected the event to be requeued after 40s, got %v", err)
+	}
+	got, err := kubeClient.CoreV1().ConfigMaps(cm.Namespace).Get(ctx, cm.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected the ConfigMap of the failed event to be kept, got %v", err)
+	}
+	want := map[string]string{
+		cloudevent.OutboxAttemptsAnnotationKey:    "3",
+		cloudevent.OutboxNextAttemptAnnotationKey: now.Add(40 * time.Second).Format(time.RFC3339),
+	}
+	if d := cmp.Diff(want, got.Annotations); d != "" {
+		t.Errorf("Wrong annotations %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestReconcileNotDue(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	cm := outboxConfigMap(t, "0", now.Add(time.Minute))
+	r, _, ctx := newReconciler(ctx, t, true, cm)
+
+	err := r.Reconcile(ctx, cloudevent.OutboxNamespace()+"/"+cm.Name)
+	if ok, delay := controller.IsRequeueKey(err); !ok || delay != time.Minute {
+		t.Fatalf("Expected the event to be requeued after 1m, got %v", err)
+	}
+	fakeClient := r.cloudEventClient.(cloudevent.FakeClient)
+	if err := eventstest.CheckEventsUnordered(t, fakeClient.Events, "not due", []string{}); err != nil {
+		t.Fatalf(err.Error())
+	}
+}
+
+func TestReconcileInvalid(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	cm := outboxConfigMap(t, "0", now)
+	cm.Data["event"] = "not an event"
+	r, _, ctx := newReconciler(ctx, t, true, cm)
+
+	if err := r.Reconcile(ctx, cloudevent.OutboxNamespace()+"/"+cm.Name); !controller.IsPermanentError(err) {
+		t.Fatalf("Expected a permanent error, got %v", err)
+	}
+}
+
+func TestReconcileNotLeader(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	cm := outboxConfigMap(t, "0", now)
+	r, _, ctx := newReconciler(ctx, t, true, cm)
+	r.Demote(reconciler.UniversalBucket())
+
+	err := r.Reconcile(ctx, cloudevent.OutboxNamespace()+"/"+cm.Name)
+	if !controller.IsSkipKey(err) {
+		t.Fatalf("Expected the key to be skipped, got %v", err)
+	}
+}
+
+func TestBackoffFor(t *testing.T) {
+	for _, tc := range []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 10 * time.Second},
+		{attempt: 2, want: 20 * time.Second},
+		{attempt: 4, want: 80 * time.Second},
+		{attempt: 10, want: maxBackoff},
+		{attempt: 100, want: maxBackoff},
+	} {
+		if got := backoffFor(tc.attempt); got != tc.want {
+			t.Errorf("backoffFor(%d) = %v, want %v", tc.attempt, got, tc.want)
+		}
+	}
+}
@@ -32,6 +32,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
@@ -48,34 +49,54 @@ const (
 // createAffinityAssistants creates an Affinity Assistant StatefulSet for every workspace in the PipelineRun that
 // use a PersistentVolumeClaim volume. This is done to achieve Node Affinity for all TaskRuns that
 // share the workspace volume and make it possible for the tasks to execute parallel while sharing volume.
+// When the Affinity Assistant mode of the PipelineRun is per PipelineRun, a single Affinity Assistant
+// StatefulSet mounting all the PersistentVolumeClaim volumes is created instead, so that all the TaskRuns
+// of the PipelineRun achieve Node Affinity.
 func (c *Reconciler) createAffinityAssistants(ctx context.Context, wb []v1beta1.WorkspaceBinding, pr *v1beta1.PipelineRun, namespace string) error {
-	logger := logging.FromContext(ctx)
 	cfg := config.FromContextOrDefaults(ctx)
 
+	if getAffinityAssistantMode(pr, cfg.Defaults.DefaultAAPodTemplate).IsPerPipelineRun() {
+		var claimNames []string
+		for _, w := range wb {
+			if w.PersistentVolumeClaim != nil || w.VolumeClaimTemplate != nil {
+				claimNames = append(claimNames, getClaimName(w, *kmeta.NewControllerRef(pr)))
+			}
+		}
+		return c.createAffinityAssistant(ctx, getAffinityAssistantName("", pr.Name), pr, claimNames, namespace)
+	}
+
 	var errs []error
 	for _, w := range wb {
 		if w.PersistentVolumeClaim != nil || w.VolumeClaimTemplate != nil {
-			affinityAssistantName := getAffinityAssistantName(w.Name, pr.Name)
-			_, err := c.KubeClientSet.AppsV1().StatefulSets(namespace).Get(ctx, affinityAssistantName, metav1.GetOptions{})
 			claimName := getClaimName(w, *kmeta.NewControllerRef(pr))
-			switch {
-			case apierrors.IsNotFound(err):
-				affinityAssistantStatefulSet := affinityAssistantStatefulSet(affinityAssistantName, pr, claimName, c.Images.NopImage, cfg.Defaults.DefaultAAPodTemplate)
-				_, err := c.KubeClientSet.AppsV1().StatefulSets(namespace).Create(ctx, affinityAssistantStatefulSet, metav1.CreateOptions{})
-				if err != nil {
-					errs = append(errs, fmt.Errorf("failed to create StatefulSet %s: %s", affinityAssistantName, err))
-				}
-				if err == nil {
-					logger.Infof("Created StatefulSet %s in namespace %s", affinityAssistantName, namespace)
-				}
-			case err != nil:
-				errs = append(errs, fmt.Errorf("failed to retrieve StatefulSet %s: %s", affinityAssistantName, err))
+			if err := c.createAffinityAssistant(ctx, getAffinityAssistantName(w.Name, pr.Name), pr, []string{claimName}, namespace); err != nil {
+				errs = append(errs, err)
 			}
 		}
 	}
 	return errorutils.NewAggregate(errs)
 }
 
+// createAffinityAssistant creates the Affinity Assistant StatefulSet called affinityAssistantName
+// mounting the claims, unless it already exists.
+func (c *Reconciler) createAffinityAssistant(ctx context.Context, affinityAssistantName string, pr *v1beta1.PipelineRun, claimNames []string, namespace string) error {
+	logger := logging.FromContext(ctx)
+	cfg := config.FromContextOrDefaults(ctx)
+
+	_, err := c.KubeClientSet.AppsV1().StatefulSets(namespace).Get(ctx, affinityAssistantName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		affinityAssistantStatefulSet := affinityAssistantStatefulSet(affinityAssistantName, pr, claimNames, c.Images.NopImage, cfg.Defaults.DefaultAAPodTemplate)
+		if _, err := c.KubeClientSet.AppsV1().StatefulSets(namespace).Create(ctx, affinityAssistantStatefulSet, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create StatefulSet %s: %s", affinityAssistantName, err)
+		}
+		logger.Infof("Created StatefulSet %s in namespace %s", affinityAssistantName, namespace)
+	case err != nil:
+		return fmt.Errorf("failed to retrieve StatefulSet %s: %s", affinityAssistantName, err)
+	}
+	return nil
+}
+
 func getClaimName(w v1beta1.WorkspaceBinding, ownerReference metav1.OwnerReference) string {
 	if w.PersistentVolumeClaim != nil {
 		return w.PersistentVolumeClaim.ClaimName
@@ -86,6 +107,9 @@ func getClaimName(w v1beta1.WorkspaceBinding, ownerReference metav1.OwnerReferen
 	return ""
 }
 
+// cleanupAffinityAssistants deletes the Affinity Assistant StatefulSets of the PipelineRun. They are
+// selected by their labels rather than by the current Affinity Assistant mode, which may have changed
+// since they were created.
 func (c *Reconciler) cleanupAffinityAssistants(ctx context.Context, pr *v1beta1.PipelineRun) error {
 
 	// omit cleanup if the feature is disabled
@@ -93,18 +117,71 @@ func (c *Reconciler) cleanupAffinityAssistants(ctx context.Context, pr *v1beta1.
 		return nil
 	}
 
+	selector := labels.SelectorFromSet(labels.Set{
+		pipeline.PipelineRunLabelKey: pr.Name,
+		workspace.LabelComponent:     workspace.ComponentNameAffinityAssistant,
+	})
+	affinityAssistantStatefulSets, err := c.KubeClientSet.AppsV1().StatefulSets(pr.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return fmt.Errorf("failed to list StatefulSets of PipelineRun %s: %s", pr.Name, err)
+	}
+
 	var errs []error
-	for _, w := range pr.Spec.Workspaces {
-		if w.PersistentVolumeClaim != nil || w.VolumeClaimTemplate != nil {
-			affinityAssistantStsName := getAffinityAssistantName(w.Name, pr.Name)
-			if err := c.KubeClientSet.AppsV1().StatefulSets(pr.Namespace).Delete(ctx, affinityAssistantStsName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
-				errs = append(errs, fmt.Errorf("failed to delete StatefulSet %s: %s", affinityAssistantStsName, err))
-			}
+	for _, sts := range affinityAssistantStatefulSets.Items {
+		if err := c.KubeClientSet.AppsV1().StatefulSets(pr.Namespace).Delete(ctx, sts.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete StatefulSet %s: %s", sts.Name, err))
 		}
 	}
 	return errorutils.NewAggregate(errs)
 }
 
+// storeAffinityAssistantMode persists the Affinity Assistant mode of the PipelineRun in its annotations
+// when it starts, so that changes of the default Affinity Assistant pod template while it runs do not
+// change how its Affinity Assistants are created and how its TaskRuns are scheduled with them.
+func storeAffinityAssistantMode(ctx context.Context, pr *v1beta1.PipelineRun) {
+	if _, ok := pr.Annotations[workspace.AnnotationAffinityAssistantMode]; ok {
+		return
+	}
+	if pr.Annotations == nil {
+		pr.Annotations = map[string]string{}
+	}
+	mode := getAffinityAssistantMode(pr, config.FromContextOrDefaults(ctx).Defaults.DefaultAAPodTemplate)
+	pr.Annotations[workspace.AnnotationAffinityAssistantMode] = string(mode)
+}
+
+// getAffinityAssistantMode returns the Affinity Assistant mode persisted in the annotations of the
+// PipelineRun, falling back to the mode of its spec, to the mode of the default Affinity Assistant
+// pod template, and then to the per workspace mode.
+func getAffinityAssistantMode(pr *v1beta1.PipelineRun, defaultAATpl *pod.AffinityAssistantTemplate) pod.AffinityAssistantMode {
+	switch {
+	case pr.Annotations[workspace.AnnotationAffinityAssistantMode] != "":
+		return pod.AffinityAssistantMode(pr.Annotations[workspace.AnnotationAffinityAssistantMode])
+	case pr.Spec.AffinityAssistantMode != "":
+		return pr.Spec.AffinityAssistantMode
+	case defaultAATpl != nil && defaultAATpl.Mode != "":
+		return defaultAATpl.Mode
+	default:
+		return pod.AffinityAssistantPerWorkspace
+	}
+}
+
+// setAffinityAssistantAnnotations sets the annotations scheduling the pods of a TaskRun or Run
+// of the PipelineRun with its Affinity Assistant: the Affinity Assistant of the PipelineRun when
+// its mode is per PipelineRun, or else the one of the PersistentVolumeClaim workspace it uses.
+// The mode annotation propagated from the PipelineRun is only kept when its mode is per PipelineRun.
+func setAffinityAssistantAnnotations(ctx context.Context, annotations map[string]string, pr *v1beta1.PipelineRun, pipelinePVCWorkspaceName string) {
+	mode := getAffinityAssistantMode(pr, config.FromContextOrDefaults(ctx).Defaults.DefaultAAPodTemplate)
+	if mode.IsPerPipelineRun() {
+		annotations[workspace.AnnotationAffinityAssistantName] = getAffinityAssistantName("", pr.Name)
+		annotations[workspace.AnnotationAffinityAssistantMode] = string(mode)
+		return
+	}
+	delete(annotations, workspace.AnnotationAffinityAssistantMode)
+	if pipelinePVCWorkspaceName != "" {
+		annotations[workspace.AnnotationAffinityAssistantName] = getAffinityAssistantName(pipelinePVCWorkspaceName, pr.Name)
+	}
+}
+
 func getAffinityAssistantName(pipelineWorkspaceName string, pipelineRunName string) string {
 	hashBytes := sha256.Sum256([]byte(pipelineWorkspaceName + pipelineRunName))
 	hashString := fmt.Sprintf("%x", hashBytes)
@@ -126,7 +203,7 @@ func getStatefulSetLabels(pr *v1beta1.PipelineRun, affinityAssistantName string)
 	return labels
 }
 
-func affinityAssistantStatefulSet(name string, pr *v1beta1.PipelineRun, claimName string, affinityAssistantImage string, defaultAATpl *pod.AffinityAssistantTemplate) *appsv1.StatefulSet {
+func affinityAssistantStatefulSet(name string, pr *v1beta1.PipelineRun, claimNames []string, affinityAssistantImage string, defaultAATpl *pod.AffinityAssistantTemplate) *appsv1.StatefulSet {
 	// We want a singleton pod
 	replicas := int32(1)
 
@@ -157,16 +234,50 @@ func affinityAssistantStatefulSet(name string, pr *v1beta1.PipelineRun, claimNam
 	}}
 
 	// use podAntiAffinity to repel other affinity assistants
-	repelOtherAffinityAssistantsPodAffinityTerm := corev1.WeightedPodAffinityTerm{
-		Weight: 100,
-		PodAffinityTerm: corev1.PodAffinityTerm{
-			LabelSelector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					workspace.LabelComponent: workspace.ComponentNameAffinityAssistant,
-				},
+	repelOtherAffinityAssistantsPodAffinityTerm := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				workspace.LabelComponent: workspace.ComponentNameAffinityAssistant,
 			},
-			TopologyKey: "kubernetes.io/hostname",
 		},
+		TopologyKey: "kubernetes.io/hostname",
+	}
+	podAntiAffinity := &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight:          100,
+			PodAffinityTerm: repelOtherAffinityAssistantsPodAffinityTerm,
+		}},
+	}
+	// an isolated affinity assistant never shares its node with other affinity assistants,
+	// and so with the TaskRun pods of other PipelineRuns
+	if getAffinityAssistantMode(pr, defaultAATpl) == pod.AffinityAssistantIsolatePipelineRun {
+		podAntiAffinity = &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{repelOtherAffinityAssistantsPodAffinityTerm},
+		}
+	}
+
+	var volumes []corev1.Volume
+	for i, claimName := range claimNames {
+		volumeName := "workspace"
+		if len(claimNames) > 1 {
+			volumeName = fmt.Sprintf("workspace-%d", i)
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+
+					// A Pod mounting a PersistentVolumeClaim that has a StorageClass with
+					// volumeBindingMode: Immediate
+					// the PV is allocated on a Node first, and then the pod need to be
+					// scheduled to that node.
+					// To support those PVCs, the Affinity Assistant must also mount the
+					// same PersistentVolumeClaim - to be sure that the Affinity Assistant
+					// pod is scheduled to the same Availability Zone as the PV, when using
+					// a regional cluster. This is called VolumeScheduling.
+					ClaimName: claimName,
+				}},
+		})
 	}
 
 	return &appsv1.StatefulSet{
@@ -196,26 +307,9 @@ func affinityAssistantStatefulSet(name string, pr *v1beta1.PipelineRun, claimNam
 					ImagePullSecrets: tpl.ImagePullSecrets,
 
 					Affinity: &corev1.Affinity{
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{repelOtherAffinityAssistantsPodAffinityTerm},
-						},
+						PodAntiAffinity: podAntiAffinity,
 					},
-					Volumes: []corev1.Volume{{
-						Name: "workspace",
-						VolumeSource: corev1.VolumeSource{
-							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-
-								// A Pod mounting a PersistentVolumeClaim that has a StorageClass with
-								// volumeBindingMode: Immediate
-								// the PV is allocated on a Node first, and then the pod need to be
-								// scheduled to that node.
-								// To support those PVCs, the Affinity Assistant must also mount the
-								// same PersistentVolumeClaim - to be sure that the Affinity Assistant
-								// pod is scheduled to the same Availability Zone as the PV, when using
-								// a regional cluster. This is called VolumeScheduling.
-								ClaimName: claimName,
-							}},
-					}},
+					Volumes: volumes,
 				},
 			},
 		},
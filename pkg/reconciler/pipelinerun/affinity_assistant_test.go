@@ -20,10 +20,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/workspace"
+	"github.com/tektoncd/pipeline/test/diff"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -85,6 +88,285 @@ func TestCreateAndDeleteOfAffinityAssistant(t *testing.T) {
 	}
 }
 
+// TestCreateAndDeleteOfAffinityAssistantPerPipelineRun tests to create and delete the single Affinity
+// Assistant of a PipelineRun with several PVC workspaces
+func TestCreateAndDeleteOfAffinityAssistantPerPipelineRun(t *testing.T) {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := Reconciler{
+		KubeClientSet: fakek8s.NewSimpleClientset(),
+		Images:        pipeline.Images{},
+	}
+
+	testPipelineRun := &v1beta1.PipelineRun{
+		TypeMeta: metav1.TypeMeta{Kind: "PipelineRun"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pipelinerun-1",
+		},
+		Spec: v1beta1.PipelineRunSpec{
+			AffinityAssistantMode: pod.AffinityAssistantPerPipelineRun,
+			Workspaces: []v1beta1.WorkspaceBinding{{
+				Name: "ws1",
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: "myclaim1",
+				},
+			}, {
+				Name: "ws2",
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: "myclaim2",
+				},
+			}, {
+				Name:     "ws3",
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			}},
+		},
+	}
+
+	err := c.createAffinityAssistants(ctx, testPipelineRun.Spec.Workspaces, testPipelineRun, testPipelineRun.Namespace)
+	if err != nil {
+		t.Errorf("unexpected error from createAffinityAssistants: %v", err)
+	}
+
+	statefulSets, err := c.KubeClientSet.AppsV1().StatefulSets(testPipelineRun.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error when listing StatefulSets: %v", err)
+	}
+	if len(statefulSets.Items) != 1 {
+		t.Fatalf("expected one StatefulSet, got %d", len(statefulSets.Items))
+	}
+	sts := statefulSets.Items[0]
+	if expectedAffinityAssistantName := getAffinityAssistantName("", testPipelineRun.Name); sts.Name != expectedAffinityAssistantName {
+		t.Errorf("expected StatefulSet %s, got %s", expectedAffinityAssistantName, sts.Name)
+	}
+	var claimNames []string
+	for _, v := range sts.Spec.Template.Spec.Volumes {
+		claimNames = append(claimNames, v.PersistentVolumeClaim.ClaimName)
+	}
+	if d := cmp.Diff([]string{"myclaim1", "myclaim2"}, claimNames); d != "" {
+		t.Errorf("StatefulSet claims diff %s", diff.PrintWantGot(d))
+	}
+
+	err = c.cleanupAffinityAssistants(ctx, testPipelineRun)
+	if err != nil {
+		t.Errorf("unexpected error from cleanupAffinityAssistants: %v", err)
+	}
+
+	_, err = c.KubeClientSet.AppsV1().StatefulSets(testPipelineRun.Namespace).Get(ctx, sts.Name, metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected a NotFound response, got: %v", err)
+	}
+}
+
+// TestDeleteOfAffinityAssistantAfterModeChange tests that the Affinity Assistants of a PipelineRun
+// are deleted even though the Affinity Assistant mode changed since they were created, while the
+// ones of other PipelineRuns are kept
+func TestDeleteOfAffinityAssistantAfterModeChange(t *testing.T) {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := Reconciler{
+		KubeClientSet: fakek8s.NewSimpleClientset(),
+		Images:        pipeline.Images{},
+	}
+
+	workspaces := []v1beta1.WorkspaceBinding{{
+		Name: "testws",
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: "myclaim",
+		},
+	}}
+	testPipelineRun := &v1beta1.PipelineRun{
+		TypeMeta:   metav1.TypeMeta{Kind: "PipelineRun"},
+		ObjectMeta: metav1.ObjectMeta{Name: "pipelinerun-1"},
+		Spec:       v1beta1.PipelineRunSpec{Workspaces: workspaces},
+	}
+	otherPipelineRun := &v1beta1.PipelineRun{
+		TypeMeta:   metav1.TypeMeta{Kind: "PipelineRun"},
+		ObjectMeta: metav1.ObjectMeta{Name: "pipelinerun-2"},
+		Spec:       v1beta1.PipelineRunSpec{Workspaces: workspaces},
+	}
+	for _, pr := range []*v1beta1.PipelineRun{testPipelineRun, otherPipelineRun} {
+		if err := c.createAffinityAssistants(ctx, pr.Spec.Workspaces, pr, pr.Namespace); err != nil {
+			t.Errorf("unexpected error from createAffinityAssistants: %v", err)
+		}
+	}
+
+	testPipelineRun.Spec.AffinityAssistantMode = pod.AffinityAssistantPerPipelineRun
+	if err := c.cleanupAffinityAssistants(ctx, testPipelineRun); err != nil {
+		t.Errorf("unexpected error from cleanupAffinityAssistants: %v", err)
+	}
+
+	_, err := c.KubeClientSet.AppsV1().StatefulSets(testPipelineRun.Namespace).Get(ctx, getAffinityAssistantName("testws", testPipelineRun.Name), metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected a NotFound response, got: %v", err)
+	}
+	_, err = c.KubeClientSet.AppsV1().StatefulSets(otherPipelineRun.Namespace).Get(ctx, getAffinityAssistantName("testws", otherPipelineRun.Name), metav1.GetOptions{})
+	if err != nil {
+		t.Errorf("expected the Affinity Assistant of another PipelineRun to be kept, got: %v", err)
+	}
+}
+
+func TestIsolatedAffinityAssistantRepelsOtherAffinityAssistants(t *testing.T) {
+	pr := &v1beta1.PipelineRun{
+		TypeMeta: metav1.TypeMeta{Kind: "PipelineRun"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pipelinerun-isolated",
+		},
+	}
+	defaultTpl := &pod.AffinityAssistantTemplate{
+		Mode: pod.AffinityAssistantIsolatePipelineRun,
+	}
+
+	sts := affinityAssistantStatefulSet("test-assistant", pr, []string{"mypvc"}, "nginx", defaultTpl)
+
+	podAntiAffinity := sts.Spec.Template.Spec.Affinity.PodAntiAffinity
+	if len(podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 0 {
+		t.Errorf("unexpected preferred podAntiAffinity in the StatefulSet")
+	}
+	want := []corev1.PodAffinityTerm{{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				workspace.LabelComponent: workspace.ComponentNameAffinityAssistant,
+			},
+		},
+		TopologyKey: "kubernetes.io/hostname",
+	}}
+	if d := cmp.Diff(want, podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution); d != "" {
+		t.Errorf("required podAntiAffinity diff %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestSetAffinityAssistantAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		desc                     string
+		mode                     pod.AffinityAssistantMode
+		persistedMode            pod.AffinityAssistantMode
+		defaultTpl               *pod.AffinityAssistantTemplate
+		pipelinePVCWorkspaceName string
+		want                     map[string]string
+	}{{
+		desc:                     "per workspace by default",
+		pipelinePVCWorkspaceName: "ws",
+		want: map[string]string{
+			workspace.AnnotationAffinityAssistantName: getAffinityAssistantName("ws", "pr"),
+		},
+	}, {
+		desc: "per workspace without PVC workspace",
+		want: map[string]string{},
+	}, {
+		desc:                     "per PipelineRun",
+		mode:                     pod.AffinityAssistantPerPipelineRun,
+		pipelinePVCWorkspaceName: "ws",
+		want: map[string]string{
+			workspace.AnnotationAffinityAssistantName: getAffinityAssistantName("", "pr"),
+			workspace.AnnotationAffinityAssistantMode: "pipelineRun",
+		},
+	}, {
+		desc: "per PipelineRun without PVC workspace",
+		mode: pod.AffinityAssistantPerPipelineRun,
+		want: map[string]string{
+			workspace.AnnotationAffinityAssistantName: getAffinityAssistantName("", "pr"),
+			workspace.AnnotationAffinityAssistantMode: "pipelineRun",
+		},
+	}, {
+		desc:       "isolated PipelineRun from the default pod template",
+		defaultTpl: &pod.AffinityAssistantTemplate{Mode: pod.AffinityAssistantIsolatePipelineRun},
+		want: map[string]string{
+			workspace.AnnotationAffinityAssistantName: getAffinityAssistantName("", "pr"),
+			workspace.AnnotationAffinityAssistantMode: "isolatePipelineRun",
+		},
+	}, {
+		desc:                     "PipelineRun mode overrides the default pod template",
+		mode:                     pod.AffinityAssistantPerWorkspace,
+		defaultTpl:               &pod.AffinityAssistantTemplate{Mode: pod.AffinityAssistantIsolatePipelineRun},
+		pipelinePVCWorkspaceName: "ws",
+		want: map[string]string{
+			workspace.AnnotationAffinityAssistantName: getAffinityAssistantName("ws", "pr"),
+		},
+	}, {
+		desc:                     "persisted per workspace mode overrides a changed default pod template",
+		persistedMode:            pod.AffinityAssistantPerWorkspace,
+		defaultTpl:               &pod.AffinityAssistantTemplate{Mode: pod.AffinityAssistantIsolatePipelineRun},
+		pipelinePVCWorkspaceName: "ws",
+		want: map[string]string{
+			workspace.AnnotationAffinityAssistantName: getAffinityAssistantName("ws", "pr"),
+		},
+	}, {
+		desc:                     "persisted per PipelineRun mode overrides a changed default pod template",
+		persistedMode:            pod.AffinityAssistantPerPipelineRun,
+		defaultTpl:               &pod.AffinityAssistantTemplate{Mode: pod.AffinityAssistantPerWorkspace},
+		pipelinePVCWorkspaceName: "ws",
+		want: map[string]string{
+			workspace.AnnotationAffinityAssistantName: getAffinityAssistantName("", "pr"),
+			workspace.AnnotationAffinityAssistantMode: "pipelineRun",
+		},
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := config.ToContext(context.Background(), &config.Config{
+				Defaults: &config.Defaults{DefaultAAPodTemplate: tc.defaultTpl},
+			})
+			pr := &v1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "pr"},
+				Spec:       v1beta1.PipelineRunSpec{AffinityAssistantMode: tc.mode},
+			}
+			annotations := map[string]string{}
+			if tc.persistedMode != "" {
+				pr.Annotations = map[string]string{workspace.AnnotationAffinityAssistantMode: string(tc.persistedMode)}
+				// the annotations of the PipelineRun are propagated to its TaskRuns
+				annotations[workspace.AnnotationAffinityAssistantMode] = string(tc.persistedMode)
+			}
+			setAffinityAssistantAnnotations(ctx, annotations, pr, tc.pipelinePVCWorkspaceName)
+			if d := cmp.Diff(tc.want, annotations); d != "" {
+				t.Errorf("annotations diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestStoreAffinityAssistantMode(t *testing.T) {
+	for _, tc := range []struct {
+		desc        string
+		annotations map[string]string
+		mode        pod.AffinityAssistantMode
+		defaultTpl  *pod.AffinityAssistantTemplate
+		want        string
+	}{{
+		desc: "per workspace by default",
+		want: "workspace",
+	}, {
+		desc:       "mode of the default pod template",
+		defaultTpl: &pod.AffinityAssistantTemplate{Mode: pod.AffinityAssistantIsolatePipelineRun},
+		want:       "isolatePipelineRun",
+	}, {
+		desc:       "mode of the PipelineRun",
+		mode:       pod.AffinityAssistantPerPipelineRun,
+		defaultTpl: &pod.AffinityAssistantTemplate{Mode: pod.AffinityAssistantIsolatePipelineRun},
+		want:       "pipelineRun",
+	}, {
+		desc:        "already persisted mode is kept",
+		annotations: map[string]string{workspace.AnnotationAffinityAssistantMode: "pipelineRun"},
+		defaultTpl:  &pod.AffinityAssistantTemplate{Mode: pod.AffinityAssistantPerWorkspace},
+		want:        "pipelineRun",
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := config.ToContext(context.Background(), &config.Config{
+				Defaults: &config.Defaults{DefaultAAPodTemplate: tc.defaultTpl},
+			})
+			pr := &v1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "pr", Annotations: tc.annotations},
+				Spec:       v1beta1.PipelineRunSpec{AffinityAssistantMode: tc.mode},
+			}
+			storeAffinityAssistantMode(ctx, pr)
+			if d := cmp.Diff(tc.want, pr.Annotations[workspace.AnnotationAffinityAssistantMode]); d != "" {
+				t.Errorf("persisted mode diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
 func TestPipelineRunPodTemplatesArePropagatedToAffinityAssistant(t *testing.T) {
 	prWithCustomPodTemplate := &v1beta1.PipelineRun{
 		TypeMeta: metav1.TypeMeta{Kind: "PipelineRun"},
@@ -109,7 +391,7 @@ func TestPipelineRunPodTemplatesArePropagatedToAffinityAssistant(t *testing.T) {
 		},
 	}
 
-	stsWithTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithCustomPodTemplate, "mypvc", "nginx", nil)
+	stsWithTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithCustomPodTemplate, []string{"mypvc"}, "nginx", nil)
 
 	if len(stsWithTolerationsAndNodeSelector.Spec.Template.Spec.Tolerations) != 1 {
 		t.Errorf("expected Tolerations in the StatefulSet")
@@ -147,7 +429,7 @@ func TestDefaultPodTemplatesArePropagatedToAffinityAssistant(t *testing.T) {
 		}},
 	}
 
-	stsWithTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithCustomPodTemplate, "mypvc", "nginx", defaultTpl)
+	stsWithTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithCustomPodTemplate, []string{"mypvc"}, "nginx", defaultTpl)
 
 	if len(stsWithTolerationsAndNodeSelector.Spec.Template.Spec.Tolerations) != 1 {
 		t.Errorf("expected Tolerations in the StatefulSet")
@@ -193,7 +475,7 @@ func TestMergedPodTemplatesArePropagatedToAffinityAssistant(t *testing.T) {
 		}},
 	}
 
-	stsWithTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithCustomPodTemplate, "mypvc", "nginx", defaultTpl)
+	stsWithTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithCustomPodTemplate, []string{"mypvc"}, "nginx", defaultTpl)
 
 	if len(stsWithTolerationsAndNodeSelector.Spec.Template.Spec.Tolerations) != 1 {
 		t.Errorf("expected Tolerations from spec in the StatefulSet")
@@ -230,7 +512,7 @@ func TestOnlySelectPodTemplateFieldsArePropagatedToAffinityAssistant(t *testing.
 		},
 	}
 
-	stsWithTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithCustomPodTemplate, "mypvc", "nginx", nil)
+	stsWithTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithCustomPodTemplate, []string{"mypvc"}, "nginx", nil)
 
 	if len(stsWithTolerationsAndNodeSelector.Spec.Template.Spec.Tolerations) != 1 {
 		t.Errorf("expected Tolerations from spec in the StatefulSet")
@@ -250,7 +532,7 @@ func TestThatTheAffinityAssistantIsWithoutNodeSelectorAndTolerations(t *testing.
 		Spec: v1beta1.PipelineRunSpec{},
 	}
 
-	stsWithoutTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithoutCustomPodTemplate, "mypvc", "nginx", nil)
+	stsWithoutTolerationsAndNodeSelector := affinityAssistantStatefulSet("test-assistant", prWithoutCustomPodTemplate, []string{"mypvc"}, "nginx", nil)
 
 	if len(stsWithoutTolerationsAndNodeSelector.Spec.Template.Spec.Tolerations) != 0 {
 		t.Errorf("unexpected Tolerations in the StatefulSet")
@@ -0,0 +1,30 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelinerun
+
+import (
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/reconciler/pipelinerun/resources"
+)
+
+// applyCache enables caching on the TaskRun of the pipeline task by annotating it with the key
+// configured in the cache of the pipeline task. The TaskRun reconciler computes the cache key
+// of the TaskRun from it and from the inputs of the TaskRun.
+func applyCache(tr *v1beta1.TaskRun, rpt *resources.ResolvedPipelineTask) {
+	tr.Annotations[pipeline.CacheAnnotationKey] = rpt.PipelineTask.Cache.Key
+}
@@ -29,8 +29,10 @@ import (
 	pipelinerunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1beta1/pipelinerun"
 	resourceinformer "github.com/tektoncd/pipeline/pkg/client/resource/injection/informers/resource/v1alpha1/pipelineresource"
 	"github.com/tektoncd/pipeline/pkg/pipelinerunmetrics"
+	cacheclient "github.com/tektoncd/pipeline/pkg/reconciler/events/cache"
 	cloudeventclient "github.com/tektoncd/pipeline/pkg/reconciler/events/cloudevent"
 	"github.com/tektoncd/pipeline/pkg/reconciler/volumeclaim"
+	"github.com/tektoncd/pipeline/pkg/tracing"
 	resolutionclient "github.com/tektoncd/resolution/pkg/client/injection/client"
 	resolutioninformer "github.com/tektoncd/resolution/pkg/client/injection/informers/resolution/v1alpha1/resolutionrequest"
 	resolution "github.com/tektoncd/resolution/pkg/resource"
@@ -53,7 +55,8 @@ func NewController(opts *pipeline.Options, clock clock.PassiveClock) func(contex
 		pipelineRunInformer := pipelineruninformer.Get(ctx)
 		resourceInformer := resourceinformer.Get(ctx)
 		resolutionInformer := resolutioninformer.Get(ctx)
-		configStore := config.NewStore(logger.Named("config-store"), pipelinerunmetrics.MetricsOnStore(logger))
+		tracerProvider := tracing.New(TracerName)
+		configStore := config.NewStore(logger.Named("config-store"), pipelinerunmetrics.MetricsOnStore(logger), tracing.OnStore(logger, tracerProvider))
 		configStore.WatchConfigs(cmw)
 
 		c := &Reconciler{
@@ -66,9 +69,11 @@ func NewController(opts *pipeline.Options, clock clock.PassiveClock) func(contex
 			runLister:           runInformer.Lister(),
 			resourceLister:      resourceInformer.Lister(),
 			cloudEventClient:    cloudeventclient.Get(ctx),
+			cacheClient:         cacheclient.Get(ctx),
 			metrics:             pipelinerunmetrics.Get(ctx),
 			pvcHandler:          volumeclaim.NewPVCHandler(kubeclientset, logger),
 			resolutionRequester: resolution.NewCRDRequester(resolutionclient.Get(ctx), resolutionInformer.Lister()),
+			tracerProvider:      tracerProvider,
 		}
 		impl := pipelinerunreconciler.NewImpl(ctx, c, func(impl *controller.Impl) controller.Options {
 			return controller.Options{
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
 	apisconfig "github.com/tektoncd/pipeline/pkg/apis/config"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
@@ -43,6 +44,7 @@ import (
 	"github.com/tektoncd/pipeline/pkg/pipelinerunmetrics"
 	tknreconciler "github.com/tektoncd/pipeline/pkg/reconciler"
 	"github.com/tektoncd/pipeline/pkg/reconciler/events"
+	"github.com/tektoncd/pipeline/pkg/reconciler/events/cache"
 	"github.com/tektoncd/pipeline/pkg/reconciler/events/cloudevent"
 	"github.com/tektoncd/pipeline/pkg/reconciler/pipeline/dag"
 	"github.com/tektoncd/pipeline/pkg/reconciler/pipelinerun/resources"
@@ -50,8 +52,8 @@ import (
 	tresources "github.com/tektoncd/pipeline/pkg/reconciler/taskrun/resources"
 	"github.com/tektoncd/pipeline/pkg/reconciler/volumeclaim"
 	"github.com/tektoncd/pipeline/pkg/remote"
-	"github.com/tektoncd/pipeline/pkg/workspace"
 	resolution "github.com/tektoncd/resolution/pkg/resource"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -131,9 +133,11 @@ type Reconciler struct {
 	runLister           listersv1alpha1.RunLister
 	resourceLister      resourcelisters.PipelineResourceLister
 	cloudEventClient    cloudevent.CEClient
+	cacheClient         *lru.Cache
 	metrics             *pipelinerunmetrics.Recorder
 	pvcHandler          volumeclaim.PvcHandler
 	resolutionRequester resolution.Requester
+	tracerProvider      trace.TracerProvider
 }
 
 var (
@@ -147,9 +151,17 @@ var (
 func (c *Reconciler) ReconcileKind(ctx context.Context, pr *v1beta1.PipelineRun) pkgreconciler.Event {
 	logger := logging.FromContext(ctx)
 	ctx = cloudevent.ToContext(ctx, c.cloudEventClient)
+	ctx = cloudevent.ClockToContext(ctx, c.Clock)
+	ctx = cache.ToContext(ctx, c.cacheClient)
+	ctx, runSpan := initTracing(ctx, c.tracerProvider, pr)
+	defer runSpan.End()
+	ctx, span := c.tracerProvider.Tracer(TracerName).Start(ctx, "PipelineRun:ReconcileKind", trace.WithAttributes(pipelineRunAttributes(pr)...))
+	defer span.End()
 
 	// Read the initial condition
 	before := pr.Status.GetCondition(apis.ConditionSucceeded)
+	initial := before
+	beforeSkippedTasks := append([]v1beta1.SkippedTask(nil), pr.Status.SkippedTasks...)
 
 	if !pr.HasStarted() && !pr.IsPending() {
 		pr.Status.InitializeConditions(c.Clock)
@@ -158,6 +170,9 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, pr *v1beta1.PipelineRun)
 			logger.Warnf("PipelineRun %s createTimestamp %s is after the pipelineRun started %s", pr.GetNamespacedName().String(), pr.CreationTimestamp, pr.Status.StartTime)
 			pr.Status.StartTime = &pr.CreationTimestamp
 		}
+		if initial != nil && initial.Reason == ReasonPending {
+			c.latencyMetrics(ctx, pr, c.metrics.PendingDuration, pr.Status.StartTime.Sub(initial.LastTransitionTime.Inner.Time))
+		}
 
 		// Emit events. During the first reconcile the status of the PipelineRun may change twice
 		// from not Started to Started and then to Running, so we need to sent the event here
@@ -225,6 +240,10 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, pr *v1beta1.PipelineRun)
 	if err = c.reconcile(ctx, pr, getPipelineFunc); err != nil {
 		logger.Errorf("Reconcile error: %v", err.Error())
 	}
+	if isResolving(initial) && !isResolving(pr.Status.GetCondition(apis.ConditionSucceeded)) {
+		c.latencyMetrics(ctx, pr, c.metrics.ResolutionDuration, c.Clock.Since(initial.LastTransitionTime.Inner.Time))
+	}
+	events.EmitSkippedTaskEvents(ctx, pr, beforeSkippedTasks)
 
 	if err = c.finishReconcileUpdateEmitEvents(ctx, pr, before, err); err != nil {
 		return err
@@ -257,11 +276,32 @@ func (c *Reconciler) durationAndCountMetrics(ctx context.Context, pr *v1beta1.Pi
 	}
 }
 
+// latencyMetrics logs one of the latencies of the PipelineRun with record.
+func (c *Reconciler) latencyMetrics(ctx context.Context, pr *v1beta1.PipelineRun, record func(*v1beta1.PipelineRun, time.Duration) error, latency time.Duration) {
+	if err := record(pr, latency); err != nil {
+		logging.FromContext(ctx).Warnf("Failed to log the metrics : %v", err)
+	}
+}
+
+// isResolving returns true if the condition shows that the PipelineRun is
+// waiting for the remote resolution of its Pipeline or of one of its Tasks.
+func isResolving(cond *apis.Condition) bool {
+	return cond != nil && cond.Status == corev1.ConditionUnknown &&
+		(cond.Reason == ReasonResolvingPipelineRef || cond.Reason == v1beta1.TaskRunReasonResolvingTaskRef)
+}
+
 func (c *Reconciler) finishReconcileUpdateEmitEvents(ctx context.Context, pr *v1beta1.PipelineRun, beforeCondition *apis.Condition, previousError error) error {
 	logger := logging.FromContext(ctx)
 
 	afterCondition := pr.Status.GetCondition(apis.ConditionSucceeded)
 	events.Emit(ctx, beforeCondition, afterCondition, pr)
+	if pr.IsDone() && (beforeCondition == nil || beforeCondition.IsUnknown()) {
+		endTracing(ctx, c.tracerProvider, pr, c.Clock.Now())
+		if err := c.storeRunSummary(ctx, pr); err != nil {
+			logger.Warn("Failed to store the PipelineRun summary", zap.Error(err))
+			events.EmitError(controller.GetEventRecorder(ctx), err, pr)
+		}
+	}
 	_, err := c.updateLabelsAndAnnotations(ctx, pr)
 	if err != nil {
 		logger.Warn("Failed to update PipelineRun labels/annotations", zap.Error(err))
@@ -283,6 +323,8 @@ func (c *Reconciler) resolvePipelineState(
 	pipelineMeta *metav1.ObjectMeta,
 	pr *v1beta1.PipelineRun,
 	providedResources map[string]*v1alpha1.PipelineResource) (resources.PipelineRunState, error) {
+	ctx, span := c.tracerProvider.Tracer(TracerName).Start(ctx, "resolvePipelineState")
+	defer span.End()
 	pst := resources.PipelineRunState{}
 	// Resolve each task individually because they each could have a different reference context (remote or local).
 	for _, task := range tasks {
@@ -333,6 +375,8 @@ func (c *Reconciler) resolvePipelineState(
 }
 
 func (c *Reconciler) reconcile(ctx context.Context, pr *v1beta1.PipelineRun, getPipelineFunc resources.GetPipeline) error {
+	ctx, span := c.tracerProvider.Tracer(TracerName).Start(ctx, "reconcile")
+	defer span.End()
 	defer c.durationAndCountMetrics(ctx, pr)
 	logger := logging.FromContext(ctx)
 	cfg := config.FromContextOrDefaults(ctx)
@@ -540,6 +584,7 @@ func (c *Reconciler) reconcile(ctx context.Context, pr *v1beta1.PipelineRun, get
 		}
 
 		if !c.isAffinityAssistantDisabled(ctx) {
+			storeAffinityAssistantMode(ctx, pr)
 			// create Affinity Assistant (StatefulSet) so that taskRun pods that share workspace PVC achieve Node Affinity
 			if err = c.createAffinityAssistants(ctx, pr.Spec.Workspaces, pr, pr.Namespace); err != nil {
 				logger.Errorf("Failed to create affinity assistant StatefulSet for PipelineRun %s: %v", pr.Name, err)
@@ -553,6 +598,10 @@ func (c *Reconciler) reconcile(ctx context.Context, pr *v1beta1.PipelineRun, get
 
 	as, err := artifacts.InitializeArtifactStorage(ctx, c.Images, pr, pipelineSpec, c.KubeClientSet)
 	if err != nil {
+		if errors.Is(err, artifacts.ErrArtifactWorkspacesRequireBucket) {
+			pr.Status.MarkFailed(ReasonInvalidWorkspaceBinding,
+				"PipelineRun %s/%s can't bind artifact workspaces: %s", pr.Namespace, pr.Name, err)
+		}
 		logger.Infof("PipelineRun failed to initialize artifact storage %s", pr.Name)
 		return controller.NewPermanentError(err)
 	}
@@ -631,6 +680,8 @@ func (c *Reconciler) processRunTimeouts(ctx context.Context, pr *v1beta1.Pipelin
 // pipeline run state, and starts them
 // after all DAG tasks are done, it's responsible for scheduling final tasks and start executing them
 func (c *Reconciler) runNextSchedulableTask(ctx context.Context, pr *v1beta1.PipelineRun, pipelineRunFacts *resources.PipelineRunFacts, as artifacts.ArtifactStorageInterface) error {
+	ctx, span := c.tracerProvider.Tracer(TracerName).Start(ctx, "runNextSchedulableTask")
+	defer span.End()
 
 	logger := logging.FromContext(ctx)
 	recorder := controller.GetEventRecorder(ctx)
@@ -673,6 +724,14 @@ func (c *Reconciler) runNextSchedulableTask(ctx context.Context, pr *v1beta1.Pip
 		}
 	}
 
+	// Tasks without dependencies are ready as soon as the PipelineRun starts, or
+	// once the remote resolution it was waiting for completes.
+	readySince := c.Clock.Now()
+	if pr.Status.StartTime != nil && !isResolving(pr.Status.GetCondition(apis.ConditionSucceeded)) {
+		readySince = pr.Status.StartTime.Time
+	}
+	beforeFirstTask := pipelineRunFacts.State.IsBeforeFirstTaskRun()
+
 	for _, rpt := range nextRpts {
 		if rpt == nil || rpt.Skip(pipelineRunFacts).IsSkipped || rpt.IsFinallySkipped(pipelineRunFacts).IsSkipped {
 			continue
@@ -690,9 +749,9 @@ func (c *Reconciler) runNextSchedulableTask(ctx context.Context, pr *v1beta1.Pip
 			}
 		case rpt.IsMatrixed():
 			if rpt.IsFinalTask(pipelineRunFacts) {
-				rpt.TaskRuns, err = c.createTaskRuns(ctx, rpt, pr, as.StorageBasePath(pr), getFinallyTaskRunTimeout)
+				rpt.TaskRuns, err = c.createTaskRuns(ctx, rpt, pr, pipelineRunFacts, as.StorageBasePath(pr), getFinallyTaskRunTimeout)
 			} else {
-				rpt.TaskRuns, err = c.createTaskRuns(ctx, rpt, pr, as.StorageBasePath(pr), getTaskRunTimeout)
+				rpt.TaskRuns, err = c.createTaskRuns(ctx, rpt, pr, pipelineRunFacts, as.StorageBasePath(pr), getTaskRunTimeout)
 			}
 			if err != nil {
 				recorder.Eventf(pr, corev1.EventTypeWarning, "TaskRunsCreationFailed", "Failed to create TaskRuns %q: %v", rpt.TaskRunNames, err)
@@ -700,9 +759,9 @@ func (c *Reconciler) runNextSchedulableTask(ctx context.Context, pr *v1beta1.Pip
 			}
 		default:
 			if rpt.IsFinalTask(pipelineRunFacts) {
-				rpt.TaskRun, err = c.createTaskRun(ctx, rpt.TaskRunName, nil, rpt, pr, as.StorageBasePath(pr), getFinallyTaskRunTimeout)
+				rpt.TaskRun, err = c.createTaskRun(ctx, rpt.TaskRunName, nil, rpt, pr, pipelineRunFacts, as.StorageBasePath(pr), getFinallyTaskRunTimeout)
 			} else {
-				rpt.TaskRun, err = c.createTaskRun(ctx, rpt.TaskRunName, nil, rpt, pr, as.StorageBasePath(pr), getTaskRunTimeout)
+				rpt.TaskRun, err = c.createTaskRun(ctx, rpt.TaskRunName, nil, rpt, pr, pipelineRunFacts, as.StorageBasePath(pr), getTaskRunTimeout)
 			}
 			if err != nil {
 				recorder.Eventf(pr, corev1.EventTypeWarning, "TaskRunCreationFailed", "Failed to create TaskRun %q: %v", rpt.TaskRunName, err)
@@ -710,6 +769,11 @@ func (c *Reconciler) runNextSchedulableTask(ctx context.Context, pr *v1beta1.Pip
 			}
 
 		}
+		if beforeFirstTask {
+			c.latencyMetrics(ctx, pr, c.metrics.FirstTaskLatency, c.Clock.Since(pr.CreationTimestamp.Time))
+			beforeFirstTask = false
+		}
+		c.latencyMetrics(ctx, pr, c.metrics.SchedulingDelay, c.Clock.Since(pipelineRunFacts.ReadySince(rpt, readySince)))
 	}
 	return nil
 }
@@ -751,12 +815,12 @@ func (c *Reconciler) updateRunsStatusDirectly(pr *v1beta1.PipelineRun) error {
 
 type getTimeoutFunc func(ctx context.Context, pr *v1beta1.PipelineRun, rpt *resources.ResolvedPipelineTask, c clock.PassiveClock) *metav1.Duration
 
-func (c *Reconciler) createTaskRuns(ctx context.Context, rpt *resources.ResolvedPipelineTask, pr *v1beta1.PipelineRun, storageBasePath string, getTimeoutFunc getTimeoutFunc) ([]*v1beta1.TaskRun, error) {
+func (c *Reconciler) createTaskRuns(ctx context.Context, rpt *resources.ResolvedPipelineTask, pr *v1beta1.PipelineRun, facts *resources.PipelineRunFacts, storageBasePath string, getTimeoutFunc getTimeoutFunc) ([]*v1beta1.TaskRun, error) {
 	var taskRuns []*v1beta1.TaskRun
 	matrixCombinations := matrix.FanOut(rpt.PipelineTask.Matrix).ToMap()
 	for i, taskRunName := range rpt.TaskRunNames {
 		params := matrixCombinations[strconv.Itoa(i)]
-		taskRun, err := c.createTaskRun(ctx, taskRunName, params, rpt, pr, storageBasePath, getTimeoutFunc)
+		taskRun, err := c.createTaskRun(ctx, taskRunName, params, rpt, pr, facts, storageBasePath, getTimeoutFunc)
 		if err != nil {
 			return nil, err
 		}
@@ -765,7 +829,7 @@ func (c *Reconciler) createTaskRuns(ctx context.Context, rpt *resources.Resolved
 	return taskRuns, nil
 }
 
-func (c *Reconciler) createTaskRun(ctx context.Context, taskRunName string, params []v1beta1.Param, rpt *resources.ResolvedPipelineTask, pr *v1beta1.PipelineRun, storageBasePath string, getTimeoutFunc getTimeoutFunc) (*v1beta1.TaskRun, error) {
+func (c *Reconciler) createTaskRun(ctx context.Context, taskRunName string, params []v1beta1.Param, rpt *resources.ResolvedPipelineTask, pr *v1beta1.PipelineRun, facts *resources.PipelineRunFacts, storageBasePath string, getTimeoutFunc getTimeoutFunc) (*v1beta1.TaskRun, error) {
 	logger := logging.FromContext(ctx)
 
 	tr, _ := c.taskRunLister.TaskRuns(pr.Namespace).Get(taskRunName)
@@ -777,7 +841,11 @@ func (c *Reconciler) createTaskRun(ctx context.Context, taskRunName string, para
 		clearStatus(tr)
 		tr.Status.MarkResourceOngoing("", "")
 		logger.Infof("Updating taskrun %s with cleared status and retry history (length: %d).", tr.GetName(), len(tr.Status.RetriesStatus))
-		return c.PipelineClientSet.TektonV1beta1().TaskRuns(pr.Namespace).UpdateStatus(ctx, tr, metav1.UpdateOptions{})
+		updated, err := c.PipelineClientSet.TektonV1beta1().TaskRuns(pr.Namespace).UpdateStatus(ctx, tr, metav1.UpdateOptions{})
+		if err == nil {
+			events.EmitPipelineTaskRetried(ctx, pr, rpt.PipelineTask.Name, len(tr.Status.RetriesStatus), rpt.PipelineTask.Retries)
+		}
+		return updated, err
 	}
 
 	rpt.PipelineTask = resources.ApplyPipelineTaskContexts(rpt.PipelineTask)
@@ -797,11 +865,15 @@ func (c *Reconciler) createTaskRun(ctx context.Context, taskRunName string, para
 			Params:             params,
 			ServiceAccountName: taskRunSpec.TaskServiceAccountName,
 			Timeout:            getTimeoutFunc(ctx, pr, rpt, c.Clock),
-			PodTemplate:        taskRunSpec.TaskPodTemplate,
+			PodTemplate:        getPipelineTaskPodTemplate(ctx, pr, rpt.PipelineTask, taskRunSpec),
 			StepOverrides:      taskRunSpec.StepOverrides,
 			SidecarOverrides:   taskRunSpec.SidecarOverrides,
 		}}
 
+	if pr.Spec.Timeouts != nil {
+		tr.Spec.GracePeriod = pr.Spec.Timeouts.GracePeriod
+	}
+
 	if rpt.ResolvedTaskResources.TaskName != "" {
 		// We pass the entire, original task ref because it may contain additional references like a Bundle url.
 		tr.Spec.TaskRef = rpt.PipelineTask.TaskRef
@@ -816,11 +888,19 @@ func (c *Reconciler) createTaskRun(ctx context.Context, taskRunName string, para
 		return nil, err
 	}
 
-	if !c.isAffinityAssistantDisabled(ctx) && pipelinePVCWorkspaceName != "" {
-		tr.Annotations[workspace.AnnotationAffinityAssistantName] = getAffinityAssistantName(pipelinePVCWorkspaceName, pr.Name)
+	if !c.isAffinityAssistantDisabled(ctx) {
+		setAffinityAssistantAnnotations(ctx, tr.Annotations, pr, pipelinePVCWorkspaceName)
 	}
 
 	resources.WrapSteps(&tr.Spec, rpt.PipelineTask, rpt.ResolvedTaskResources.Inputs, rpt.ResolvedTaskResources.Outputs, storageBasePath)
+	if err := resources.ApplyWorkspaceArtifacts(tr, rpt, facts, storageBasePath); err != nil {
+		return nil, err
+	}
+
+	if rpt.PipelineTask.Cache != nil && config.FromContextOrDefaults(ctx).FeatureFlags.EnableAPIFields == config.AlphaAPIFields {
+		applyCache(tr, rpt)
+	}
+
 	logger.Infof("Creating a new TaskRun object %s for pipeline task %s", taskRunName, rpt.PipelineTask.Name)
 	return c.PipelineClientSet.TektonV1beta1().TaskRuns(pr.Namespace).Create(ctx, tr, metav1.CreateOptions{})
 }
@@ -842,7 +922,7 @@ func (c *Reconciler) createRun(ctx context.Context, rpt *resources.ResolvedPipel
 			Params:             rpt.PipelineTask.Params,
 			ServiceAccountName: taskRunSpec.TaskServiceAccountName,
 			Timeout:            getTimeoutFunc(ctx, pr, rpt, c.Clock),
-			PodTemplate:        taskRunSpec.TaskPodTemplate,
+			PodTemplate:        getPipelineTaskPodTemplate(ctx, pr, rpt.PipelineTask, taskRunSpec),
 		},
 	}
 
@@ -871,8 +951,8 @@ func (c *Reconciler) createRun(ctx context.Context, rpt *resources.ResolvedPipel
 
 	// Set the affinity assistant annotation in case the custom task creates TaskRuns or Pods
 	// that can take advantage of it.
-	if !c.isAffinityAssistantDisabled(ctx) && pipelinePVCWorkspaceName != "" {
-		r.Annotations[workspace.AnnotationAffinityAssistantName] = getAffinityAssistantName(pipelinePVCWorkspaceName, pr.Name)
+	if !c.isAffinityAssistantDisabled(ctx) {
+		setAffinityAssistantAnnotations(ctx, r.Annotations, pr, pipelinePVCWorkspaceName)
 	}
 
 	logger.Infof("Creating a new Run object %s", rpt.RunName)
@@ -896,10 +976,19 @@ func getTaskrunWorkspaces(pr *v1beta1.PipelineRun, rpt *resources.ResolvedPipeli
 		}
 
 		if b, hasBinding := pipelineRunWorkspaces[pipelineWorkspace]; hasBinding {
+			// Only the PersistentVolumeClaims shared by the TaskRuns of the PipelineRun need the
+			// Affinity Assistant: the claim of an Ephemeral volume is created for the pod of each
+			// TaskRun, and the other volume sources, some of which are read-only, are not persisted.
 			if b.PersistentVolumeClaim != nil || b.VolumeClaimTemplate != nil {
 				pipelinePVCWorkspaceName = pipelineWorkspace
 			}
-			workspaces = append(workspaces, taskWorkspaceByWorkspaceVolumeSource(b, taskWorkspaceName, pipelineTaskSubPath, *kmeta.NewControllerRef(pr)))
+			binding := taskWorkspaceByWorkspaceVolumeSource(b, taskWorkspaceName, pipelineTaskSubPath, *kmeta.NewControllerRef(pr))
+			// A PipelineTask which declares that it only reads the workspace is not
+			// allowed to write to it.
+			if ws.Access == v1beta1.WorkspaceAccessReadOnly {
+				binding.ReadOnly = true
+			}
+			workspaces = append(workspaces, binding)
 		} else {
 			workspaceIsOptional := false
 			if rpt.ResolvedTaskResources != nil && rpt.ResolvedTaskResources.TaskSpec != nil {
@@ -1020,6 +1109,22 @@ func getTaskrunLabels(pr *v1beta1.PipelineRun, pipelineTaskName string, includeP
 	return labels
 }
 
+// getPipelineTaskPodTemplate returns the pod template of the TaskRun or Run of the PipelineTask. The pod
+// template of the PipelineTask in the TaskRunSpecs of the PipelineRun is merged with the pod template of the
+// PipelineTask, which is merged with the pod template of the PipelineRun.
+func getPipelineTaskPodTemplate(ctx context.Context, pr *v1beta1.PipelineRun, pipelineTask *v1beta1.PipelineTask, taskRunSpec v1beta1.PipelineTaskRunSpec) *v1beta1.PodTemplate {
+	if pipelineTask.PodTemplate == nil || config.FromContextOrDefaults(ctx).FeatureFlags.EnableAPIFields != config.AlphaAPIFields {
+		return taskRunSpec.TaskPodTemplate
+	}
+	podTemplate := v1beta1.MergePodTemplateWithDefault(pipelineTask.PodTemplate.DeepCopy(), pr.Spec.PodTemplate)
+	for _, task := range pr.Spec.TaskRunSpecs {
+		if task.PipelineTaskName == pipelineTask.Name && task.TaskPodTemplate != nil {
+			podTemplate = v1beta1.MergePodTemplateWithDefault(task.TaskPodTemplate.DeepCopy(), podTemplate)
+		}
+	}
+	return podTemplate
+}
+
 func combineTaskRunAndTaskSpecLabels(pr *v1beta1.PipelineRun, pipelineTask *v1beta1.PipelineTask) map[string]string {
 	labels := make(map[string]string)
 
@@ -78,6 +78,7 @@ var (
 		KubeconfigWriterImage:    "override-with-kubeconfig-writer:latest",
 		ShellImage:               "busybox",
 		GsutilImage:              "gcr.io/google.com/cloudsdktool/cloud-sdk",
+		S3Image:                  "docker.io/amazon/aws-cli",
 		PRImage:                  "override-with-pr:latest",
 		ImageDigestExporterImage: "override-with-imagedigest-exporter-image:latest",
 	}
@@ -121,7 +122,7 @@ type PipelineRunTest struct {
 }
 
 func ensureConfigurationConfigMapsExist(d *test.Data) {
-	var defaultsExists, featureFlagsExists, artifactBucketExists, artifactPVCExists, metricsExists bool
+	var defaultsExists, featureFlagsExists, artifactBucketExists, artifactPVCExists, metricsExists, tracingExists, eventsExists bool
 	for _, cm := range d.ConfigMaps {
 		if cm.Name == config.GetDefaultsConfigName() {
 			defaultsExists = true
@@ -138,6 +139,12 @@ func ensureConfigurationConfigMapsExist(d *test.Data) {
 		if cm.Name == config.GetMetricsConfigName() {
 			metricsExists = true
 		}
+		if cm.Name == config.GetTracingConfigName() {
+			tracingExists = true
+		}
+		if cm.Name == config.GetEventsConfigName() {
+			eventsExists = true
+		}
 	}
 	if !defaultsExists {
 		d.ConfigMaps = append(d.ConfigMaps, &corev1.ConfigMap{
@@ -169,6 +176,18 @@ func ensureConfigurationConfigMapsExist(d *test.Data) {
 			Data:       map[string]string{},
 		})
 	}
+	if !tracingExists {
+		d.ConfigMaps = append(d.ConfigMaps, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: config.GetTracingConfigName(), Namespace: system.Namespace()},
+			Data:       map[string]string{},
+		})
+	}
+	if !eventsExists {
+		d.ConfigMaps = append(d.ConfigMaps, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: config.GetEventsConfigName(), Namespace: system.Namespace()},
+			Data:       map[string]string{},
+		})
+	}
 }
 
 // getPipelineRunController returns an instance of the PipelineRun controller/reconciler that has been seeded with
@@ -1256,6 +1275,37 @@ spec:
 			"Normal Started",
 			"Warning Failed PipelineRun foo's Pipeline DAG is invalid for finally clause",
 		},
+	}, {
+		name: "invalid-pipeline-with-artifact-workspace-and-pvc-storage",
+		pipelineRun: parse.MustParsePipelineRun(t, `
+metadata:
+  name: pipeline-artifact-workspace-pvc-storage
+  namespace: foo
+spec:
+  pipelineSpec:
+    workspaces:
+      - name: source
+    tasks:
+      - name: dag-task-1
+        taskSpec:
+          workspaces:
+            - name: source
+          steps:
+            - image: busybox
+        workspaces:
+          - name: source
+            workspace: source
+  workspaces:
+    - name: source
+      emptyDir: {}
+      artifact: {}
+`),
+		reason:         ReasonInvalidWorkspaceBinding,
+		permanentError: true,
+		wantEvents: []string{
+			"Normal Started",
+			"Warning Failed PipelineRun foo/pipeline-artifact-workspace-pvc-storage can't bind artifact workspaces: artifact workspaces require a bucket to be configured as artifact storage",
+		},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
 			d := test.Data{
@@ -2487,6 +2537,7 @@ spec:
   serviceAccountName: test-sa
   timeouts:
     pipeline: 12h0m0s
+    gracePeriod: 1m0s
 status:
   startTime: "2021-12-31T00:00:00Z"
 `)}
@@ -2521,6 +2572,11 @@ status:
 	if actual.Spec.Timeout.Duration > prs[0].Spec.Timeouts.Pipeline.Duration {
 		t.Errorf("TaskRun timeout %s should be less than or equal to PipelineRun timeout %s", actual.Spec.Timeout.Duration.String(), prs[0].Spec.Timeouts.Pipeline.Duration.String())
 	}
+
+	// The TaskRun should be given the grace period of the PipelineRun.
+	if d := cmp.Diff(prs[0].Spec.Timeouts.GracePeriod, actual.Spec.GracePeriod); d != "" {
+		t.Errorf("TaskRun grace period should be the PipelineRun grace period %s", diff.PrintWantGot(d))
+	}
 }
 
 func TestReconcileWithoutPVC(t *testing.T) {
@@ -3058,6 +3114,78 @@ status:
 	}
 }
 
+func TestReconcileWithRetryDetailedEvents(t *testing.T) {
+	ps := []*v1beta1.Pipeline{parse.MustParsePipeline(t, `
+metadata:
+  name: test-pipeline-retry
+  namespace: foo
+spec:
+  tasks:
+  - name: hello-world-1
+    retries: 2
+    taskRef:
+      name: hello-world
+`)}
+	prs := []*v1beta1.PipelineRun{parse.MustParsePipelineRun(t, `
+metadata:
+  name: test-pipeline-retry-run
+  namespace: foo
+spec:
+  pipelineRef:
+    name: test-pipeline-retry
+  serviceAccountName: test-sa
+  timeout: 48h0m0s
+status:
+  startTime: "2021-12-31T00:00:00Z"
+  conditions:
+  - status: Unknown
+    type: Succeeded
+    reason: Running
+`)}
+	trs := []*v1beta1.TaskRun{parse.MustParseTaskRun(t, `
+metadata:
+  name: test-pipeline-retry-run-hello-world-1
+  namespace: foo
+  labels:
+    tekton.dev/pipelineTask: hello-world-1
+status:
+  conditions:
+  - status: "False"
+    type: Succeeded
+  podName: my-pod-name
+  retriesStatus:
+  - conditions:
+    - status: "False"
+      type: Succeeded
+`)}
+	prs[0].Status.TaskRuns = map[string]*v1beta1.PipelineRunTaskRunStatus{
+		trs[0].Name: {PipelineTaskName: "hello-world-1", Status: &trs[0].Status},
+	}
+	cms := []*corev1.ConfigMap{{
+		ObjectMeta: metav1.ObjectMeta{Name: config.GetEventsConfigName(), Namespace: system.Namespace()},
+		Data:       map[string]string{config.EventsKubernetesEventsKey: config.EventsKubernetesEventsDetailed},
+	}}
+
+	d := test.Data{
+		PipelineRuns: prs,
+		Pipelines:    ps,
+		Tasks:        []*v1beta1.Task{simpleHelloWorldTask},
+		TaskRuns:     trs,
+		ConfigMaps:   cms,
+	}
+	prt := newPipelineRunTest(d, t)
+	defer prt.Cancel()
+
+	wantEvents := []string{
+		`Warning PipelineTaskRetried PipelineTask "hello-world-1" failed and is retried \(retry 2 of 2\)`,
+		"Normal Running Tasks Completed: 0",
+	}
+	reconciledRun, _ := prt.reconcileRun("foo", "test-pipeline-retry-run", wantEvents, false)
+	if got := len(reconciledRun.Status.TaskRuns[trs[0].Name].Status.RetriesStatus); got != 2 {
+		t.Fatalf("2 retries expected but got %d", got)
+	}
+}
+
 func TestGetTaskRunTimeout(t *testing.T) {
 	prName := "pipelinerun-timeouts"
 	ns := "foo"
@@ -7328,6 +7456,125 @@ spec:
 	}
 }
 
+func TestGetTaskrunWorkspaces_VolumeSources(t *testing.T) {
+	tests := []struct {
+		name                         string
+		binding                      string
+		access                       v1beta1.WorkspaceAccess
+		expectedBinding              v1beta1.WorkspaceBinding
+		expectedPipelinePVCWorkspace string
+	}{{
+		name: "persistentVolumeClaim",
+		binding: `
+      persistentVolumeClaim:
+        claimName: my-pvc`,
+		expectedBinding: v1beta1.WorkspaceBinding{
+			Name:                  "my-task-workspace",
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "my-pvc"},
+		},
+		expectedPipelinePVCWorkspace: "source",
+	}, {
+		name: "projected",
+		binding: `
+      projected:
+        sources:
+        - secret:
+            name: my-secret
+        - serviceAccountToken:
+            path: token`,
+		expectedBinding: v1beta1.WorkspaceBinding{
+			Name: "my-task-workspace",
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}},
+				}, {
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token"},
+				}},
+			},
+		},
+	}, {
+		name: "csi",
+		binding: `
+      csi:
+        driver: secrets-store.csi.k8s.io
+        readOnly: true`,
+		expectedBinding: v1beta1.WorkspaceBinding{
+			Name: "my-task-workspace",
+			CSI: &corev1.CSIVolumeSource{
+				Driver:   "secrets-store.csi.k8s.io",
+				ReadOnly: &[]bool{true}[0],
+			},
+		},
+	}, {
+		name: "ephemeral",
+		binding: `
+      ephemeral:
+        volumeClaimTemplate:
+          spec:
+            accessModes:
+            - ReadWriteOnce`,
+		expectedBinding: v1beta1.WorkspaceBinding{
+			Name: "my-task-workspace",
+			Ephemeral: &corev1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					},
+				},
+			},
+		},
+	}, {
+		name: "readOnly access",
+		binding: `
+      emptyDir: {}`,
+		access: v1beta1.WorkspaceAccessReadOnly,
+		expectedBinding: v1beta1.WorkspaceBinding{
+			Name:     "my-task-workspace",
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+			ReadOnly: true,
+		},
+	}, {
+		name: "readWrite access",
+		binding: `
+      emptyDir: {}`,
+		access: v1beta1.WorkspaceAccessReadWrite,
+		expectedBinding: v1beta1.WorkspaceBinding{
+			Name:     "my-task-workspace",
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := parse.MustParsePipelineRun(t, `
+metadata:
+  name: pipeline
+spec:
+  workspaces:
+    - name: source`+tt.binding)
+			rprt := &resources.ResolvedPipelineTask{
+				PipelineTask: &v1beta1.PipelineTask{
+					Name: "resolved-pipelinetask",
+					Workspaces: []v1beta1.WorkspacePipelineTaskBinding{{
+						Name:      "my-task-workspace",
+						Workspace: "source",
+						Access:    tt.access,
+					}},
+				},
+			}
+			workspaces, pipelinePVCWorkspaceName, err := getTaskrunWorkspaces(pr, rprt)
+			if err != nil {
+				t.Fatalf("Pipeline.getTaskrunWorkspaces() returned error for valid pipeline: %v", err)
+			}
+			if d := cmp.Diff([]v1beta1.WorkspaceBinding{tt.expectedBinding}, workspaces); d != "" {
+				t.Errorf("Pipeline.getTaskrunWorkspaces() bindings diff %s", diff.PrintWantGot(d))
+			}
+			if pipelinePVCWorkspaceName != tt.expectedPipelinePVCWorkspace {
+				t.Errorf("expected pipeline PVC workspace %q but got %q", tt.expectedPipelinePVCWorkspace, pipelinePVCWorkspaceName)
+			}
+		})
+	}
+}
+
 func TestReconcile_PropagatePipelineTaskRunSpecMetadata(t *testing.T) {
 	names.TestingSeed()
 	prName := "test-pipeline-run"
@@ -7652,7 +7899,8 @@ spec:
 metadata:
   name: pr
   namespace: foo
-  annotations: {}
+  annotations:
+    pipeline.tekton.dev/affinity-assistant-mode: workspace
   labels:
     tekton.dev/pipeline: p-dag
 spec:
@@ -7919,6 +8167,135 @@ spec:
 	}
 }
 
+func TestReconcile_PipelineTaskCache(t *testing.T) {
+	prYAML := `
+metadata:
+  name: pr
+  namespace: foo
+spec:
+  pipelineSpec:
+    params:
+    - name: revision
+    tasks:
+    - name: lint
+      cache:
+        key: $(params.revision)
+      params:
+      - name: revision
+        value: $(params.revision)
+      taskSpec:
+        params:
+        - name: revision
+        results:
+        - name: report
+        steps:
+        - image: foo:latest
+    - name: build
+      taskSpec:
+        steps:
+        - image: foo:latest
+  params:
+  - name: revision
+    value: abc123
+`
+	prt := newPipelineRunTest(test.Data{
+		PipelineRuns: []*v1beta1.PipelineRun{parse.MustParsePipelineRun(t, prYAML)},
+		ConfigMaps:   []*corev1.ConfigMap{withEnabledAlphaAPIFields(newFeatureFlagsConfigMap())},
+	}, t)
+	defer prt.Cancel()
+	_, clients := prt.reconcileRun("foo", "pr", []string{}, false)
+
+	// The TaskRun reconciler computes the cache key from the substituted key and the
+	// inputs of the TaskRun, so the TaskRuns are only annotated with the key.
+	got := map[string]string{}
+	for _, tr := range getTaskRunCreations(t, clients.Pipeline.Actions(), 2) {
+		if key, ok := tr.Annotations[pipeline.CacheAnnotationKey]; ok {
+			got[tr.Name] = key
+		}
+		if _, ok := tr.Labels[pipeline.CacheKeyLabelKey]; ok {
+			t.Errorf("expected TaskRun %s not to be labelled with a cache key", tr.Name)
+		}
+	}
+	if d := cmp.Diff(map[string]string{"pr-lint": "abc123"}, got); d != "" {
+		t.Errorf("unexpected cache annotations %s", diff.PrintWantGot(d))
+	}
+}
+
 func lessTaskResourceBindings(i, j v1beta1.TaskResourceBinding) bool {
 	return i.Name < j.Name
 }
+
+func TestGetPipelineTaskPodTemplate(t *testing.T) {
+	gpuToleration := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	ciToleration := corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "ci", Effect: corev1.TaintEffectNoSchedule}
+	prPodTemplate := &pod.Template{
+		NodeSelector: map[string]string{"pool": "general"},
+		Tolerations:  []corev1.Toleration{ciToleration},
+	}
+	pipelineTask := &v1beta1.PipelineTask{
+		Name: "build",
+		PodTemplate: &pod.Template{
+			NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+			Tolerations:  []corev1.Toleration{gpuToleration},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc         string
+		apiFields    string
+		pipelineTask *v1beta1.PipelineTask
+		taskRunSpecs []v1beta1.PipelineTaskRunSpec
+		want         *pod.Template
+	}{{
+		desc:         "no pod template in the pipeline task",
+		apiFields:    config.AlphaAPIFields,
+		pipelineTask: &v1beta1.PipelineTask{Name: "build"},
+		want:         prPodTemplate,
+	}, {
+		desc:         "pipeline task pod template merged with the pipeline run pod template",
+		apiFields:    config.AlphaAPIFields,
+		pipelineTask: pipelineTask,
+		want: &pod.Template{
+			NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+			Tolerations:  []corev1.Toleration{gpuToleration, ciToleration},
+		},
+	}, {
+		desc:         "task run specs pod template takes precedence",
+		apiFields:    config.AlphaAPIFields,
+		pipelineTask: pipelineTask,
+		taskRunSpecs: []v1beta1.PipelineTaskRunSpec{{
+			PipelineTaskName: "build",
+			TaskPodTemplate:  &pod.Template{NodeSelector: map[string]string{"kubernetes.io/arch": "amd64"}},
+		}},
+		want: &pod.Template{
+			NodeSelector: map[string]string{"kubernetes.io/arch": "amd64"},
+			Tolerations:  []corev1.Toleration{gpuToleration, ciToleration},
+		},
+	}, {
+		desc:         "pipeline task pod template ignored without alpha",
+		apiFields:    config.StableAPIFields,
+		pipelineTask: pipelineTask,
+		want:         prPodTemplate,
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			pr := &v1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "foo"},
+				Spec: v1beta1.PipelineRunSpec{
+					PodTemplate:  prPodTemplate,
+					TaskRunSpecs: tc.taskRunSpecs,
+				},
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{
+				FeatureFlags: &config.FeatureFlags{EnableAPIFields: tc.apiFields},
+			})
+			got := getPipelineTaskPodTemplate(ctx, pr, tc.pipelineTask, pr.GetTaskRunSpec(tc.pipelineTask.Name))
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("PodTemplate diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+
+	if d := cmp.Diff([]corev1.Toleration{gpuToleration}, pipelineTask.PodTemplate.Tolerations); d != "" {
+		t.Errorf("PipelineTask pod template was modified %s", diff.PrintWantGot(d))
+	}
+}
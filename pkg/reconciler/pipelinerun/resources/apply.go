@@ -87,6 +87,20 @@ func ApplyContexts(ctx context.Context, spec *v1beta1.PipelineSpec, pipelineName
 	return ApplyReplacements(ctx, spec, replacements, map[string][]string{})
 }
 
+// ApplyParametersToPipelineRef applies the params and context variables of a PipelineRun to the
+// bundle and resolver parameters of its PipelineRef, which are needed before the Pipeline is resolved.
+func ApplyParametersToPipelineRef(pr *v1beta1.PipelineRun) *v1beta1.PipelineRef {
+	ref := pr.Spec.PipelineRef.DeepCopy()
+	replacements := map[string]string{
+		"context.pipelineRun.name":      pr.Name,
+		"context.pipelineRun.namespace": pr.Namespace,
+		"context.pipelineRun.uid":       string(pr.ObjectMeta.UID),
+	}
+	v1beta1.AddRefParamReplacements(replacements, pr.Spec.Params)
+	v1beta1.ApplyPipelineRefReplacements(ref, replacements)
+	return ref
+}
+
 // ApplyPipelineTaskContexts applies the substitution from $(context.pipelineTask.*) with the specified values.
 // Uses "0" as a default if a value is not available.
 func ApplyPipelineTaskContexts(pt *v1beta1.PipelineTask) *v1beta1.PipelineTask {
@@ -107,6 +121,9 @@ func ApplyTaskResults(targets PipelineRunState, resolvedResultRefs ResolvedResul
 			pipelineTask := resolvedPipelineRunTask.PipelineTask.DeepCopy()
 			pipelineTask.Params = replaceParamValues(pipelineTask.Params, stringReplacements, nil)
 			pipelineTask.WhenExpressions = pipelineTask.WhenExpressions.ReplaceWhenExpressionsVariables(stringReplacements, nil)
+			if pipelineTask.Cache != nil {
+				pipelineTask.Cache.Key = substitution.ApplyReplacements(pipelineTask.Cache.Key, stringReplacements)
+			}
 			resolvedPipelineRunTask.PipelineTask = pipelineTask
 		}
 	}
@@ -151,6 +168,12 @@ func ApplyReplacements(ctx context.Context, p *v1beta1.PipelineSpec, replacement
 			p.Tasks[i].Workspaces[j].SubPath = substitution.ApplyReplacements(p.Tasks[i].Workspaces[j].SubPath, replacements)
 		}
 		p.Tasks[i].WhenExpressions = p.Tasks[i].WhenExpressions.ReplaceWhenExpressionsVariables(replacements, arrayReplacements)
+		if p.Tasks[i].TaskRef != nil {
+			v1beta1.ApplyTaskRefReplacements(p.Tasks[i].TaskRef, replacements)
+		}
+		if p.Tasks[i].Cache != nil {
+			p.Tasks[i].Cache.Key = substitution.ApplyReplacements(p.Tasks[i].Cache.Key, replacements)
+		}
 		p.Tasks[i], replacements, arrayReplacements = propagateParams(ctx, p.Tasks[i], replacements, arrayReplacements)
 	}
 
@@ -158,6 +181,12 @@ func ApplyReplacements(ctx context.Context, p *v1beta1.PipelineSpec, replacement
 		p.Finally[i].Params = replaceParamValues(p.Finally[i].Params, replacements, arrayReplacements)
 		p.Finally[i].Matrix = replaceParamValues(p.Finally[i].Matrix, replacements, arrayReplacements)
 		p.Finally[i].WhenExpressions = p.Finally[i].WhenExpressions.ReplaceWhenExpressionsVariables(replacements, arrayReplacements)
+		if p.Finally[i].TaskRef != nil {
+			v1beta1.ApplyTaskRefReplacements(p.Finally[i].TaskRef, replacements)
+		}
+		if p.Finally[i].Cache != nil {
+			p.Finally[i].Cache.Key = substitution.ApplyReplacements(p.Finally[i].Cache.Key, replacements)
+		}
 	}
 
 	return p
@@ -541,6 +541,55 @@ func TestApplyParameters(t *testing.T) {
 				},
 			}},
 		},
+	}, {
+		name: "parameters in task and finally task references",
+		original: v1beta1.PipelineSpec{
+			Params: []v1beta1.ParamSpec{
+				{Name: "revision", Type: v1beta1.ParamTypeString, Default: v1beta1.NewArrayOrString("main")},
+				{Name: "registry", Type: v1beta1.ParamTypeString},
+			},
+			Tasks: []v1beta1.PipelineTask{{
+				TaskRef: &v1beta1.TaskRef{
+					ResolverRef: v1beta1.ResolverRef{
+						Resolver: "git",
+						Resource: []v1beta1.ResolverParam{
+							{Name: "url", Value: "https://github.com/tektoncd/catalog.git"},
+							{Name: "revision", Value: "$(params.revision)"},
+						},
+					},
+				},
+			}},
+			Finally: []v1beta1.PipelineTask{{
+				TaskRef: &v1beta1.TaskRef{
+					Name:   "notify",
+					Bundle: "$(params.registry)/catalog:$(params['revision'])",
+				},
+			}},
+		},
+		params: []v1beta1.Param{{Name: "registry", Value: *v1beta1.NewArrayOrString("registry.io")}},
+		expected: v1beta1.PipelineSpec{
+			Params: []v1beta1.ParamSpec{
+				{Name: "revision", Type: v1beta1.ParamTypeString, Default: v1beta1.NewArrayOrString("main")},
+				{Name: "registry", Type: v1beta1.ParamTypeString},
+			},
+			Tasks: []v1beta1.PipelineTask{{
+				TaskRef: &v1beta1.TaskRef{
+					ResolverRef: v1beta1.ResolverRef{
+						Resolver: "git",
+						Resource: []v1beta1.ResolverParam{
+							{Name: "url", Value: "https://github.com/tektoncd/catalog.git"},
+							{Name: "revision", Value: "main"},
+						},
+					},
+				},
+			}},
+			Finally: []v1beta1.PipelineTask{{
+				TaskRef: &v1beta1.TaskRef{
+					Name:   "notify",
+					Bundle: "registry.io/catalog:main",
+				},
+			}},
+		},
 	},
 	} {
 		ctx := context.Background()
@@ -601,6 +650,30 @@ func TestApplyTaskResults_MinimalExpression(t *testing.T) {
 				}},
 			},
 		}},
+	}, {
+		name: "Test result substitution on minimal variable substitution expression - cache key",
+		resolvedResultRefs: ResolvedResultRefs{{
+			Value: *v1beta1.NewArrayOrString("sha256:abc"),
+			ResultReference: v1beta1.ResultRef{
+				PipelineTask: "aTask",
+				Result:       "digest",
+			},
+			FromTaskRun: "aTaskRun",
+		}},
+		targets: PipelineRunState{{
+			PipelineTask: &v1beta1.PipelineTask{
+				Name:    "bTask",
+				TaskRef: &v1beta1.TaskRef{Name: "bTask"},
+				Cache:   &v1beta1.PipelineTaskCache{Key: "$(tasks.aTask.results.digest)"},
+			},
+		}},
+		want: PipelineRunState{{
+			PipelineTask: &v1beta1.PipelineTask{
+				Name:    "bTask",
+				TaskRef: &v1beta1.TaskRef{Name: "bTask"},
+				Cache:   &v1beta1.PipelineTaskCache{Key: "sha256:abc"},
+			},
+		}},
 	}, {
 		name: "Test array indexing result substitution on minimal variable substitution expression - params",
 		resolvedResultRefs: ResolvedResultRefs{{
@@ -958,6 +1031,64 @@ func TestContext(t *testing.T) {
 	}
 }
 
+func TestContext_TaskRef(t *testing.T) {
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace"},
+	}
+	orig := &v1beta1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pipeline"},
+		Spec: v1beta1.PipelineSpec{
+			Tasks: []v1beta1.PipelineTask{{
+				TaskRef: &v1beta1.TaskRef{ResolverRef: v1beta1.ResolverRef{
+					Resolver: "cluster",
+					Resource: []v1beta1.ResolverParam{{Name: "namespace", Value: "$(context.pipelineRun.namespace)"}},
+				}},
+			}},
+		},
+	}
+	expected := &v1beta1.TaskRef{ResolverRef: v1beta1.ResolverRef{
+		Resolver: "cluster",
+		Resource: []v1beta1.ResolverParam{{Name: "namespace", Value: "namespace"}},
+	}}
+	got := ApplyContexts(context.Background(), &orig.Spec, orig.Name, pr)
+	if d := cmp.Diff(expected, got.Tasks[0].TaskRef); d != "" {
+		t.Errorf(diff.PrintWantGot(d))
+	}
+}
+
+func TestApplyParametersToPipelineRef(t *testing.T) {
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace"},
+		Spec: v1beta1.PipelineRunSpec{
+			PipelineRef: &v1beta1.PipelineRef{ResolverRef: v1beta1.ResolverRef{
+				Resolver: "git",
+				Resource: []v1beta1.ResolverParam{
+					{Name: "revision", Value: "$(params.branch)"},
+					{Name: "path", Value: "$(context.pipelineRun.namespace)/pipeline.yaml"},
+				},
+			}},
+			Params: []v1beta1.Param{
+				{Name: "branch", Value: *v1beta1.NewArrayOrString("release")},
+				{Name: "flags", Value: *v1beta1.NewArrayOrString("-v", "-x")},
+			},
+		},
+	}
+	expected := &v1beta1.PipelineRef{ResolverRef: v1beta1.ResolverRef{
+		Resolver: "git",
+		Resource: []v1beta1.ResolverParam{
+			{Name: "revision", Value: "release"},
+			{Name: "path", Value: "namespace/pipeline.yaml"},
+		},
+	}}
+	got := ApplyParametersToPipelineRef(pr)
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf(diff.PrintWantGot(d))
+	}
+	if pr.Spec.PipelineRef.Resource[0].Value != "$(params.branch)" {
+		t.Errorf("ApplyParametersToPipelineRef() modified the PipelineRun's PipelineRef")
+	}
+}
+
 func TestApplyPipelineTaskContexts(t *testing.T) {
 	for _, tc := range []struct {
 		description string
@@ -23,9 +23,11 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"github.com/tektoncd/pipeline/pkg/internal/crossnamespace"
 	"github.com/tektoncd/pipeline/pkg/remote"
 	"github.com/tektoncd/pipeline/pkg/remote/oci"
 	"github.com/tektoncd/pipeline/pkg/remote/resolution"
@@ -54,6 +56,10 @@ func GetPipelineFunc(ctx context.Context, k8s kubernetes.Interface, tekton clien
 			}, nil
 		}, nil
 	}
+	if pr != nil {
+		// The bundle and resolver parameters may reference the PipelineRun's params.
+		pr = ApplyParametersToPipelineRef(pipelineRun)
+	}
 	switch {
 	case cfg.FeatureFlags.EnableTektonOCIBundles && pr != nil && pr.Bundle != "":
 		// Return an inline function that implements GetTask by calling Resolver.Get with the specified task type and
@@ -79,6 +85,25 @@ func GetPipelineFunc(ctx context.Context, k8s kubernetes.Interface, tekton clien
 			resolver := resolution.NewResolver(requester, pipelineRun, string(pr.Resolver), "", "", params)
 			return resolvePipeline(ctx, resolver, name)
 		}, nil
+	case cfg.FeatureFlags.EnableAPIFields == config.AlphaAPIFields && pr != nil && pr.Namespace != "" && pr.Namespace != namespace:
+		// Return an inline function that checks the referenced namespace opted in to sharing
+		// and that the service account may read the pipeline before resolving it locally.
+		local := &LocalPipelineRefResolver{
+			Namespace:    pr.Namespace,
+			Tektonclient: tekton,
+		}
+		return func(ctx context.Context, name string) (v1beta1.PipelineObject, error) {
+			if err := crossnamespace.Authorize(ctx, k8s, crossnamespace.Reference{
+				Namespace:          namespace,
+				ServiceAccountName: pipelineRun.Spec.ServiceAccountName,
+				TargetNamespace:    pr.Namespace,
+				Resource:           pipeline.PipelineResource,
+				Name:               name,
+			}); err != nil {
+				return nil, err
+			}
+			return local.GetPipeline(ctx, name)
+		}, nil
 	default:
 		// Even if there is no task ref, we should try to return a local resolver.
 		local := &LocalPipelineRefResolver{
@@ -26,6 +26,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-containerregistry/pkg/registry"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
@@ -33,11 +34,13 @@ import (
 	"github.com/tektoncd/pipeline/test"
 	"github.com/tektoncd/pipeline/test/diff"
 	"github.com/tektoncd/pipeline/test/parse"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	fakek8s "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
 	logtesting "knative.dev/pkg/logging/testing"
 )
 
@@ -328,6 +331,72 @@ func TestGetPipelineFunc_RemoteResolution(t *testing.T) {
 	}
 }
 
+func TestGetPipelineFunc_CrossNamespace(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.FromContextOrDefaults(ctx)
+	cfg.FeatureFlags.EnableAPIFields = config.AlphaAPIFields
+	ctx = config.ToContext(ctx, cfg)
+	catalogPipeline := &v1beta1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "catalog"},
+	}
+	pipelineRef := &v1beta1.PipelineRef{Name: "release", Namespace: "catalog"}
+
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		allowed     bool
+		wantErr     bool
+	}{{
+		name:        "namespace opted in and service account allowed",
+		annotations: map[string]string{pipeline.AllowReferencesFromAnnotationKey: "*"},
+		allowed:     true,
+	}, {
+		name:    "namespace not opted in",
+		allowed: true,
+		wantErr: true,
+	}, {
+		name:        "service account not allowed",
+		annotations: map[string]string{pipeline.AllowReferencesFromAnnotationKey: "default"},
+		wantErr:     true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			tektonclient := fake.NewSimpleClientset(catalogPipeline)
+			kubeclient := fakek8s.NewSimpleClientset(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "catalog", Annotations: tc.annotations},
+			})
+			kubeclient.PrependReactor("create", "subjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+				sar := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				sar.Status.Allowed = tc.allowed
+				return true, sar, nil
+			})
+
+			fn, err := resources.GetPipelineFunc(ctx, kubeclient, tektonclient, nil, &v1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				Spec: v1beta1.PipelineRunSpec{
+					PipelineRef:        pipelineRef,
+					ServiceAccountName: "default",
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to get pipeline fn: %s", err.Error())
+			}
+			resolvedPipeline, err := fn(ctx, pipelineRef.Name)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error resolving %s/%s but saw none", pipelineRef.Namespace, pipelineRef.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to call pipelinefn: %s", err.Error())
+			}
+			if d := cmp.Diff(catalogPipeline, resolvedPipeline); d != "" {
+				t.Error(diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
 func TestGetPipelineFunc_RemoteResolutionInvalidData(t *testing.T) {
 	ctx := context.Background()
 	cfg := config.FromContextOrDefaults(ctx)
@@ -30,6 +30,7 @@ import (
 	"github.com/tektoncd/pipeline/pkg/reconciler/taskrun/resources"
 	"github.com/tektoncd/pipeline/pkg/remote"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmeta"
 )
@@ -75,6 +76,34 @@ func (t ResolvedPipelineTask) isDone(facts *PipelineRunFacts) bool {
 	return t.Skip(facts).IsSkipped || t.isSuccessful() || t.isFailure()
 }
 
+// completionTime returns the time the Run or the last of the TaskRuns of the task
+// completed, or nil if it has not completed.
+func (t ResolvedPipelineTask) completionTime() *metav1.Time {
+	switch {
+	case t.IsCustomTask():
+		if t.Run == nil {
+			return nil
+		}
+		return t.Run.Status.CompletionTime
+	case t.IsMatrixed():
+		var completion *metav1.Time
+		for _, taskRun := range t.TaskRuns {
+			if taskRun.Status.CompletionTime == nil {
+				return nil
+			}
+			if completion == nil || completion.Before(taskRun.Status.CompletionTime) {
+				completion = taskRun.Status.CompletionTime
+			}
+		}
+		return completion
+	default:
+		if t.TaskRun == nil {
+			return nil
+		}
+		return t.TaskRun.Status.CompletionTime
+	}
+}
+
 // isRunning returns true only if the task is neither succeeded, cancelled nor failed
 func (t ResolvedPipelineTask) isRunning() bool {
 	switch {
@@ -19,6 +19,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
@@ -413,6 +414,35 @@ func (facts *PipelineRunFacts) GetFinalTasks() PipelineRunState {
 	return tasks
 }
 
+// ReadySince returns the time the given pipeline task became ready to be scheduled:
+// the completion time of the last of the tasks it depends on or, for a final task, of
+// the last DAG task. start is returned when none of these tasks completed after start.
+func (facts *PipelineRunFacts) ReadySince(rpt *ResolvedPipelineTask, start time.Time) time.Time {
+	var deps []string
+	if facts.isFinalTask(rpt.PipelineTask.Name) {
+		for name := range facts.TasksGraph.Nodes {
+			deps = append(deps, name)
+		}
+	} else if node, ok := facts.TasksGraph.Nodes[rpt.PipelineTask.Name]; ok {
+		for _, prev := range node.Prev {
+			deps = append(deps, prev.Task.HashKey())
+		}
+	}
+
+	since := start
+	tasks := facts.State.ToMap()
+	for _, name := range deps {
+		t, ok := tasks[name]
+		if !ok {
+			continue
+		}
+		if completion := t.completionTime(); completion != nil && completion.Time.After(since) {
+			since = completion.Time
+		}
+	}
+	return since
+}
+
 // GetPipelineConditionStatus will return the Condition that the PipelineRun prName should be
 // updated with, based on the status of the TaskRuns in state.
 func (facts *PipelineRunFacts) GetPipelineConditionStatus(ctx context.Context, pr *v1beta1.PipelineRun, logger *zap.SugaredLogger, c clock.PassiveClock) *apis.Condition {
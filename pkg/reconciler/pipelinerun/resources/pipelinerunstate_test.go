@@ -1227,6 +1227,101 @@ func TestPipelineRunState_GetFinalTasks(t *testing.T) {
 	}
 }
 
+func TestPipelineRunFacts_ReadySince(t *testing.T) {
+	completedTaskRun := func(name string, completion time.Time) *v1beta1.TaskRun {
+		return &v1beta1.TaskRun{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1beta1.TaskRunStatus{
+				TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+					CompletionTime: &metav1.Time{Time: completion},
+				},
+			},
+		}
+	}
+	dagTasks := []v1beta1.PipelineTask{{
+		Name:    "build",
+		TaskRef: &v1beta1.TaskRef{Name: "task"},
+	}, {
+		Name:     "lint",
+		TaskRef:  &v1beta1.TaskRef{Name: "task"},
+		RunAfter: []string{"build"},
+	}, {
+		Name:     "test",
+		TaskRef:  &v1beta1.TaskRef{Name: "task"},
+		RunAfter: []string{"build", "lint"},
+	}}
+	finalTasks := []v1beta1.PipelineTask{{
+		Name:    "report",
+		TaskRef: &v1beta1.TaskRef{Name: "task"},
+	}}
+	state := PipelineRunState{{
+		PipelineTask: &dagTasks[0],
+		TaskRunName:  "pr-build",
+		TaskRun:      completedTaskRun("pr-build", now.Add(time.Minute)),
+	}, {
+		PipelineTask: &dagTasks[1],
+		TaskRunName:  "pr-lint",
+		TaskRun:      completedTaskRun("pr-lint", now.Add(3*time.Minute)),
+	}, {
+		PipelineTask: &dagTasks[2],
+		TaskRunName:  "pr-test",
+	}, {
+		PipelineTask: &finalTasks[0],
+		TaskRunName:  "pr-report",
+	}}
+	d, err := dag.Build(v1beta1.PipelineTaskList(dagTasks), v1beta1.PipelineTaskList(dagTasks).Deps())
+	if err != nil {
+		t.Fatalf("Unexpected error while building DAG for pipelineTasks %v: %v", dagTasks, err)
+	}
+	df, err := dag.Build(v1beta1.PipelineTaskList(finalTasks), map[string][]string{})
+	if err != nil {
+		t.Fatalf("Unexpected error while building DAG for final pipelineTasks %v: %v", finalTasks, err)
+	}
+	facts := PipelineRunFacts{
+		State:           state,
+		TasksGraph:      d,
+		FinalTasksGraph: df,
+	}
+
+	for _, tc := range []struct {
+		name  string
+		rpt   *ResolvedPipelineTask
+		start time.Time
+		want  time.Time
+	}{{
+		name:  "task without dependencies",
+		rpt:   state[0],
+		start: now,
+		want:  now,
+	}, {
+		name:  "task ready when its dependency completed",
+		rpt:   state[1],
+		start: now,
+		want:  now.Add(time.Minute),
+	}, {
+		name:  "task ready when the last of its dependencies completed",
+		rpt:   state[2],
+		start: now,
+		want:  now.Add(3 * time.Minute),
+	}, {
+		name:  "final task ready when the last DAG task completed",
+		rpt:   state[3],
+		start: now,
+		want:  now.Add(3 * time.Minute),
+	}, {
+		name:  "dependencies completed before start",
+		rpt:   state[2],
+		start: now.Add(5 * time.Minute),
+		want:  now.Add(5 * time.Minute),
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := facts.ReadySince(tc.rpt, tc.start); !got.Equal(tc.want) {
+				t.Errorf("ReadySince() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestGetPipelineConditionStatus(t *testing.T) {
 
 	var taskRetriedState = PipelineRunState{{
@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/reconciler/pipeline/dag"
+	"github.com/tektoncd/pipeline/pkg/reconciler/taskrun/resources"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ApplyWorkspaceArtifacts sets the paths of the artifact storage of the artifact workspaces of
+// the TaskRun tr, created for rpt, in its workspace artifacts annotation: each workspace is
+// uploaded to a path of its own, and populated from the paths uploaded by the successful
+// TaskRuns of the PipelineTasks which rpt runs after and which bind the same Pipeline workspace,
+// in topological order.
+func ApplyWorkspaceArtifacts(tr *v1beta1.TaskRun, rpt *ResolvedPipelineTask, facts *PipelineRunFacts, storageBasePath string) error {
+	paths := map[string]resources.WorkspaceArtifactPaths{}
+	for _, wb := range tr.Spec.Workspaces {
+		if wb.Artifact == nil {
+			continue
+		}
+		pipelineWorkspace := getPipelineWorkspaceName(rpt.PipelineTask, wb.Name)
+		paths[wb.Name] = resources.WorkspaceArtifactPaths{
+			From: getWorkspaceArtifactSources(rpt.PipelineTask, pipelineWorkspace, facts, storageBasePath),
+			To:   workspaceArtifactPath(storageBasePath, pipelineWorkspace, tr.Name),
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(paths)
+	if err != nil {
+		return err
+	}
+	if tr.Annotations == nil {
+		tr.Annotations = map[string]string{}
+	}
+	tr.Annotations[pipeline.WorkspaceArtifactsAnnotationKey] = string(b)
+	return nil
+}
+
+// getWorkspaceArtifactSources returns the paths uploaded by the successful TaskRuns of the
+// ancestors of pt which bind the Pipeline workspace, in topological order.
+func getWorkspaceArtifactSources(pt *v1beta1.PipelineTask, pipelineWorkspace string, facts *PipelineRunFacts, storageBasePath string) []string {
+	tasks := facts.State.ToMap()
+	var sources []string
+	for _, name := range getAncestors(pt, facts) {
+		rpt, ok := tasks[name]
+		if !ok || rpt.IsCustomTask() || !bindsPipelineWorkspace(rpt.PipelineTask, pipelineWorkspace) {
+			continue
+		}
+		candidates := rpt.TaskRuns
+		if !rpt.IsMatrixed() {
+			candidates = []*v1beta1.TaskRun{rpt.TaskRun}
+		}
+		for _, taskRun := range candidates {
+			if taskRun != nil && taskRun.IsSuccessful() {
+				sources = append(sources, workspaceArtifactPath(storageBasePath, pipelineWorkspace, taskRun.Name))
+			}
+		}
+	}
+	return sources
+}
+
+// getAncestors returns the names of the PipelineTasks which pt runs after, each one after its
+// own ancestors: all the DAG tasks for a final task, and the transitive dependencies of pt
+// otherwise.
+func getAncestors(pt *v1beta1.PipelineTask, facts *PipelineRunFacts) []string {
+	var roots []*dag.Node
+	if facts.isFinalTask(pt.Name) {
+		var names []string
+		for name := range facts.TasksGraph.Nodes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			roots = append(roots, facts.TasksGraph.Nodes[name])
+		}
+	} else if node, ok := facts.TasksGraph.Nodes[pt.HashKey()]; ok {
+		roots = node.Prev
+	}
+
+	var ancestors []string
+	visited := sets.NewString()
+	var visit func(n *dag.Node)
+	visit = func(n *dag.Node) {
+		name := n.Task.HashKey()
+		if visited.Has(name) {
+			return
+		}
+		visited.Insert(name)
+		for _, prev := range n.Prev {
+			visit(prev)
+		}
+		ancestors = append(ancestors, name)
+	}
+	for _, n := range roots {
+		visit(n)
+	}
+	return ancestors
+}
+
+// getPipelineWorkspaceName returns the name of the Pipeline workspace bound to the workspace
+// of the Task of pt called taskWorkspace.
+func getPipelineWorkspaceName(pt *v1beta1.PipelineTask, taskWorkspace string) string {
+	for _, ws := range pt.Workspaces {
+		if ws.Name == taskWorkspace && ws.Workspace != "" {
+			return ws.Workspace
+		}
+	}
+	return taskWorkspace
+}
+
+// bindsPipelineWorkspace returns true if pt binds the Pipeline workspace to any of its Task's workspaces.
+func bindsPipelineWorkspace(pt *v1beta1.PipelineTask, pipelineWorkspace string) bool {
+	for _, ws := range pt.Workspaces {
+		if getPipelineWorkspaceName(pt, ws.Name) == pipelineWorkspace {
+			return true
+		}
+	}
+	return false
+}
+
+func workspaceArtifactPath(storageBasePath, pipelineWorkspace, taskRunName string) string {
+	return filepath.Join(resources.WorkspaceArtifactsBasePath(storageBasePath), pipelineWorkspace, taskRunName)
+}
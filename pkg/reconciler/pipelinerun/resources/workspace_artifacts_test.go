@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/reconciler/pipeline/dag"
+	"github.com/tektoncd/pipeline/test/diff"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+func TestApplyWorkspaceArtifacts(t *testing.T) {
+	completedTaskRun := func(name string, status corev1.ConditionStatus) *v1beta1.TaskRun {
+		return &v1beta1.TaskRun{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1beta1.TaskRunStatus{
+				Status: duckv1beta1.Status{
+					Conditions: []apis.Condition{{Type: apis.ConditionSucceeded, Status: status}},
+				},
+			},
+		}
+	}
+	sourceBinding := []v1beta1.WorkspacePipelineTaskBinding{{Name: "output", Workspace: "source"}}
+	state := PipelineRunState{{
+		PipelineTask: &v1beta1.PipelineTask{Name: "fetch", Workspaces: []v1beta1.WorkspacePipelineTaskBinding{{Name: "source"}}},
+		TaskRunName:  "pr-fetch",
+		TaskRun:      completedTaskRun("pr-fetch", corev1.ConditionTrue),
+	}, {
+		PipelineTask: &v1beta1.PipelineTask{Name: "generate", Workspaces: sourceBinding, RunAfter: []string{"fetch"}},
+		TaskRunName:  "pr-generate",
+		TaskRun:      completedTaskRun("pr-generate", corev1.ConditionTrue),
+	}, {
+		PipelineTask: &v1beta1.PipelineTask{Name: "lint", Workspaces: sourceBinding, RunAfter: []string{"fetch"}},
+		TaskRunName:  "pr-lint",
+		TaskRun:      completedTaskRun("pr-lint", corev1.ConditionFalse),
+	}, {
+		PipelineTask: &v1beta1.PipelineTask{Name: "docs", Workspaces: []v1beta1.WorkspacePipelineTaskBinding{{Name: "output", Workspace: "docs"}}, RunAfter: []string{"fetch"}},
+		TaskRunName:  "pr-docs",
+		TaskRun:      completedTaskRun("pr-docs", corev1.ConditionTrue),
+	}, {
+		// unrelated runs in parallel with build, which must not read its content.
+		PipelineTask: &v1beta1.PipelineTask{Name: "unrelated", Workspaces: sourceBinding},
+		TaskRunName:  "pr-unrelated",
+		TaskRun:      completedTaskRun("pr-unrelated", corev1.ConditionTrue),
+	}, {
+		PipelineTask: &v1beta1.PipelineTask{Name: "build", Workspaces: []v1beta1.WorkspacePipelineTaskBinding{{Name: "src", Workspace: "source"}, {Name: "cache"}}, RunAfter: []string{"generate", "lint", "docs"}},
+		TaskRunName:  "pr-build",
+	}}
+	finalState := PipelineRunState{{
+		PipelineTask: &v1beta1.PipelineTask{Name: "report", Workspaces: []v1beta1.WorkspacePipelineTaskBinding{{Name: "src", Workspace: "source"}}},
+		TaskRunName:  "pr-report",
+	}}
+	d, err := dagFromState(state)
+	if err != nil {
+		t.Fatalf("Unexpected error while building DAG for state %v: %v", state, err)
+	}
+	dfinally, err := dagFromState(finalState)
+	if err != nil {
+		t.Fatalf("Unexpected error while building DAG for final state %v: %v", finalState, err)
+	}
+	facts := &PipelineRunFacts{
+		State:           append(state, finalState...),
+		TasksGraph:      d,
+		FinalTasksGraph: dfinally,
+	}
+
+	for _, tc := range []struct {
+		name           string
+		rpt            *ResolvedPipelineTask
+		wantAnnotation string
+	}{{
+		name:           "dag task",
+		rpt:            state[5],
+		wantAnnotation: `{"src":{"from":["pr-foo-bucket/workspaces/source/pr-fetch","pr-foo-bucket/workspaces/source/pr-generate"],"to":"pr-foo-bucket/workspaces/source/pr-build"}}`,
+	}, {
+		name:           "final task",
+		rpt:            finalState[0],
+		wantAnnotation: `{"src":{"from":["pr-foo-bucket/workspaces/source/pr-fetch","pr-foo-bucket/workspaces/source/pr-generate","pr-foo-bucket/workspaces/source/pr-unrelated"],"to":"pr-foo-bucket/workspaces/source/pr-report"}}`,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := &v1beta1.TaskRun{
+				ObjectMeta: metav1.ObjectMeta{Name: tc.rpt.TaskRunName},
+				Spec: v1beta1.TaskRunSpec{
+					Workspaces: []v1beta1.WorkspaceBinding{{
+						Name:     "src",
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+						Artifact: &v1beta1.WorkspaceArtifact{},
+					}, {
+						Name:     "cache",
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					}},
+				},
+			}
+			if err := ApplyWorkspaceArtifacts(tr, tc.rpt, facts, "pr-foo-bucket"); err != nil {
+				t.Fatalf("ApplyWorkspaceArtifacts() = %v", err)
+			}
+			want := map[string]string{pipeline.WorkspaceArtifactsAnnotationKey: tc.wantAnnotation}
+			if d := cmp.Diff(want, tr.Annotations); d != "" {
+				t.Errorf("Annotations diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestApplyWorkspaceArtifacts_NoArtifactWorkspace(t *testing.T) {
+	tr := &v1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "pr-build"},
+		Spec: v1beta1.TaskRunSpec{
+			Workspaces: []v1beta1.WorkspaceBinding{{
+				Name:     "src",
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			}},
+		},
+	}
+	rpt := &ResolvedPipelineTask{
+		PipelineTask: &v1beta1.PipelineTask{Name: "build", Workspaces: []v1beta1.WorkspacePipelineTaskBinding{{Name: "src", Workspace: "source"}}},
+	}
+	facts := &PipelineRunFacts{
+		State:           PipelineRunState{rpt},
+		TasksGraph:      &dag.Graph{},
+		FinalTasksGraph: &dag.Graph{},
+	}
+	if err := ApplyWorkspaceArtifacts(tr, rpt, facts, "pr-foo-bucket"); err != nil {
+		t.Fatalf("ApplyWorkspaceArtifacts() = %v", err)
+	}
+	if tr.Annotations != nil {
+		t.Errorf("expected no annotations but got %v", tr.Annotations)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelinerun
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/reconciler/pipelinerun/summary"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	// RunSummaryJSONKey is the key of the JSON summary in the run summary ConfigMap
+	RunSummaryJSONKey = "summary.json"
+	// RunSummaryJUnitKey is the key of the JUnit XML report in the run summary ConfigMap
+	RunSummaryJUnitKey = "junit.xml"
+)
+
+// GetRunSummaryConfigMapName returns the name of the ConfigMap holding the
+// summary of the PipelineRun pr
+func GetRunSummaryConfigMapName(pr *v1beta1.PipelineRun) string {
+	return kmeta.ChildName(pr.Name, "-summary")
+}
+
+// storeRunSummary stores the summary of the finished PipelineRun pr, as JSON
+// and as a JUnit XML report, in a ConfigMap owned by pr, if enabled.
+func (c *Reconciler) storeRunSummary(ctx context.Context, pr *v1beta1.PipelineRun) error {
+	if !config.FromContextOrDefaults(ctx).FeatureFlags.EnableRunSummary {
+		return nil
+	}
+	pipelineRunLabels := getTaskrunLabels(pr, "", false)
+	taskRuns, err := c.taskRunLister.TaskRuns(pr.Namespace).List(k8slabels.SelectorFromSet(pipelineRunLabels))
+	if err != nil {
+		return err
+	}
+	runs, err := c.runLister.Runs(pr.Namespace).List(k8slabels.SelectorFromSet(pipelineRunLabels))
+	if err != nil {
+		return err
+	}
+
+	s := summary.New(pr, taskRuns, runs)
+	summaryJSON, err := s.JSON()
+	if err != nil {
+		return err
+	}
+	junit, err := s.JUnit()
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            GetRunSummaryConfigMapName(pr),
+			Namespace:       pr.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(pr)},
+			Labels:          map[string]string{pipeline.PipelineRunLabelKey: pr.Name},
+		},
+		Data: map[string]string{
+			RunSummaryJSONKey:  string(summaryJSON),
+			RunSummaryJUnitKey: string(junit),
+		},
+	}
+	// The summary is only written once, when the PipelineRun finishes, so a
+	// summary which already exists and is owned by the PipelineRun was stored
+	// by a previous reconcile. Any other ConfigMap with the same name is left
+	// untouched.
+	_, err = c.KubeClientSet.CoreV1().ConfigMaps(pr.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := c.KubeClientSet.CoreV1().ConfigMaps(pr.Namespace).Get(ctx, cm.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(existing, pr) {
+		return fmt.Errorf("failed to store the summary of PipelineRun %s: ConfigMap %s already exists and is not owned by it", pr.Name, cm.Name)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelinerun
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/test"
+	"github.com/tektoncd/pipeline/test/parse"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+)
+
+func TestReconcileStoresRunSummary(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		enabled         bool
+		existingSummary bool
+		existingOwned   bool
+		wantSummary     bool
+		wantEvents      []string
+	}{{
+		name:        "run summary enabled",
+		enabled:     true,
+		wantSummary: true,
+		wantEvents:  []string{"Normal Succeeded"},
+	}, {
+		name:        "run summary disabled",
+		enabled:     false,
+		wantSummary: false,
+		wantEvents:  []string{"Normal Succeeded"},
+	}, {
+		name:            "run summary already stored",
+		enabled:         true,
+		existingSummary: true,
+		existingOwned:   true,
+		wantSummary:     true,
+		wantEvents:      []string{"Normal Succeeded"},
+	}, {
+		name:            "configmap of another owner",
+		enabled:         true,
+		existingSummary: true,
+		wantSummary:     true,
+		wantEvents: []string{
+			"Normal Succeeded",
+			"Warning Error failed to store the summary of PipelineRun test-pipeline-run-summary: ConfigMap test-pipeline-run-summary-summary already exists and is not owned by it",
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			prs := []*v1beta1.PipelineRun{parse.MustParsePipelineRun(t, `
+metadata:
+  name: test-pipeline-run-summary
+  namespace: foo
+spec:
+  pipelineRef:
+    name: test-pipeline
+  serviceAccountName: test-sa
+  timeout: 48h0m0s
+status:
+  startTime: "2021-12-31T23:59:00Z"
+  conditions:
+  - status: Unknown
+    type: Succeeded
+    reason: Running
+`)}
+			trs := []*v1beta1.TaskRun{createHelloWorldTaskRunWithStatusTaskLabel(t, "test-pipeline-run-summary-hello-world-1", "foo",
+				"test-pipeline-run-summary", "test-pipeline", "my-pod", "hello-world-1",
+				apis.Condition{
+					Type:   apis.ConditionSucceeded,
+					Status: corev1.ConditionTrue,
+					Reason: v1beta1.TaskRunReasonSuccessful.String(),
+				})}
+			cm := newFeatureFlagsConfigMap()
+			if tc.enabled {
+				cm.Data["enable-run-summary"] = "true"
+			}
+			cms := []*corev1.ConfigMap{cm}
+			if tc.existingSummary {
+				existing := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: GetRunSummaryConfigMapName(prs[0]), Namespace: "foo"},
+					Data:       map[string]string{RunSummaryJSONKey: "{}"},
+				}
+				if tc.existingOwned {
+					existing.OwnerReferences = []metav1.OwnerReference{*kmeta.NewControllerRef(prs[0])}
+				}
+				cms = append(cms, existing)
+			}
+			d := test.Data{
+				PipelineRuns: prs,
+				Pipelines:    []*v1beta1.Pipeline{simpleHelloWorldPipeline},
+				Tasks:        []*v1beta1.Task{simpleHelloWorldTask},
+				TaskRuns:     trs,
+				ConfigMaps:   cms,
+			}
+			prt := newPipelineRunTest(d, t)
+			defer prt.Cancel()
+
+			reconciledRun, clients := prt.reconcileRun("foo", "test-pipeline-run-summary", tc.wantEvents, false)
+			if !reconciledRun.IsDone() {
+				t.Fatalf("Expected the PipelineRun to be done, got %v", reconciledRun.Status.GetCondition(apis.ConditionSucceeded))
+			}
+
+			summary, err := clients.Kube.CoreV1().ConfigMaps("foo").Get(prt.TestAssets.Ctx, GetRunSummaryConfigMapName(reconciledRun), metav1.GetOptions{})
+			if !tc.wantSummary {
+				if err == nil {
+					t.Fatalf("Expected no run summary, got %v", summary)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected a run summary: %v", err)
+			}
+			if tc.existingSummary {
+				if summary.Data[RunSummaryJSONKey] != "{}" {
+					t.Errorf("Expected the existing run summary to be kept, got %v", summary.Data)
+				}
+				return
+			}
+			if len(summary.OwnerReferences) != 1 || summary.OwnerReferences[0].Name != reconciledRun.Name {
+				t.Errorf("Expected the run summary to be owned by the PipelineRun, got %v", summary.OwnerReferences)
+			}
+			for key, want := range map[string]string{
+				RunSummaryJSONKey:  `"tasks":[{"name":"hello-world-1","runName":"test-pipeline-run-summary-hello-world-1","kind":"TaskRun","status":"Succeeded"`,
+				RunSummaryJUnitKey: `<testcase name="hello-world-1 (test-pipeline-run-summary-hello-world-1)" classname="test-pipeline"`,
+			} {
+				if !strings.Contains(summary.Data[key], want) {
+					t.Errorf("Expected %s to contain %s, got %s", key, want, summary.Data[key])
+				}
+			}
+		})
+	}
+}
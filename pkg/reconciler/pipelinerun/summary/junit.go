@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// JUnit returns the Summary rendered as a JUnit XML report, with a test suite
+// for the PipelineRun and a test case for each of its tasks.
+func (s *Summary) JUnit() ([]byte, error) {
+	suite := junitTestSuite{
+		Name: s.Name,
+		Time: seconds(s.DurationSeconds),
+	}
+	if s.StartTime != nil {
+		suite.Timestamp = s.StartTime.UTC().Format("2006-01-02T15:04:05")
+	}
+	className := s.Pipeline
+	if className == "" {
+		className = s.Name
+	}
+	for _, t := range s.Tasks {
+		name := t.Name
+		if t.RunName != "" {
+			name = fmt.Sprintf("%s (%s)", t.Name, t.RunName)
+		}
+		tc := junitTestCase{
+			Name:      name,
+			ClassName: className,
+			Time:      seconds(t.DurationSeconds),
+		}
+		switch t.Status {
+		case StatusFailed:
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: t.Reason, Type: t.Kind, Text: t.Message}
+		case StatusSkipped:
+			suite.Skipped++
+			tc.Skipped = &junitSkipped{Message: string(t.SkipReason)}
+		case StatusNotRun:
+			suite.Skipped++
+			tc.Skipped = &junitSkipped{Message: "not run"}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	suite.Tests = len(suite.TestCases)
+
+	b, err := xml.MarshalIndent(junitTestSuites{
+		Name:     s.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+func seconds(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
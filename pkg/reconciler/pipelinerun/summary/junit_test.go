@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+)
+
+func TestJUnit(t *testing.T) {
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="pr" tests="5" failures="1" skipped="2" time="60.000">
+  <testsuite name="pr" tests="5" failures="1" skipped="2" time="60.000" timestamp="2022-01-01T00:00:00">
+    <testcase name="build (pr-build)" classname="pipeline" time="20.000"></testcase>
+    <testcase name="test (pr-test)" classname="pipeline" time="20.000">
+      <failure message="Failed" type="TaskRun">step unit exited with code 1</failure>
+    </testcase>
+    <testcase name="approve (pr-approve)" classname="pipeline" time="5.000"></testcase>
+    <testcase name="deploy" classname="pipeline" time="0.000">
+      <skipped message="When Expressions evaluated to false"></skipped>
+    </testcase>
+    <testcase name="notify" classname="pipeline" time="0.000">
+      <skipped message="not run"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+	got, err := New(testPipelineRun(t), testTaskRuns(t), testRuns(t)).JUnit()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff(want, string(got)); d != "" {
+		t.Errorf("Wrong JUnit report %s", diff.PrintWantGot(d))
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package summary builds a compact report of a finished PipelineRun from its
+// status and the status of its TaskRuns and Runs.
+package summary
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// StatusSucceeded is the status of runs and tasks which succeeded
+	StatusSucceeded = "Succeeded"
+	// StatusFailed is the status of runs and tasks which failed
+	StatusFailed = "Failed"
+	// StatusRunning is the status of runs and tasks which are still running
+	StatusRunning = "Running"
+	// StatusSkipped is the status of tasks which were skipped
+	StatusSkipped = "Skipped"
+	// StatusNotRun is the status of tasks which were neither run nor skipped
+	StatusNotRun = "NotRun"
+)
+
+// Summary is a compact report of a PipelineRun
+type Summary struct {
+	Name            string                      `json:"name"`
+	Namespace       string                      `json:"namespace"`
+	Pipeline        string                      `json:"pipeline,omitempty"`
+	Status          string                      `json:"status"`
+	Reason          string                      `json:"reason,omitempty"`
+	Message         string                      `json:"message,omitempty"`
+	StartTime       *metav1.Time                `json:"startTime,omitempty"`
+	CompletionTime  *metav1.Time                `json:"completionTime,omitempty"`
+	DurationSeconds float64                     `json:"durationSeconds,omitempty"`
+	Tasks           []Task                      `json:"tasks"`
+	Results         []v1beta1.PipelineRunResult `json:"results,omitempty"`
+}
+
+// Task is the report of the run of a PipelineTask. Matrixed PipelineTasks
+// have one Task per TaskRun or Run.
+type Task struct {
+	Name            string                           `json:"name"`
+	RunName         string                           `json:"runName,omitempty"`
+	Kind            string                           `json:"kind,omitempty"`
+	Finally         bool                             `json:"finally,omitempty"`
+	RunAfter        []string                         `json:"runAfter,omitempty"`
+	Status          string                           `json:"status"`
+	Reason          string                           `json:"reason,omitempty"`
+	Message         string                           `json:"message,omitempty"`
+	StartTime       *metav1.Time                     `json:"startTime,omitempty"`
+	CompletionTime  *metav1.Time                     `json:"completionTime,omitempty"`
+	DurationSeconds float64                          `json:"durationSeconds,omitempty"`
+	Retries         int                              `json:"retries,omitempty"`
+	SkipReason      v1beta1.SkippingReason           `json:"skipReason,omitempty"`
+	Results         map[string]v1beta1.ArrayOrString `json:"results,omitempty"`
+	Steps           []Step                           `json:"steps,omitempty"`
+}
+
+// Step is the report of a terminated step of a TaskRun
+type Step struct {
+	Name            string  `json:"name"`
+	ExitCode        int32   `json:"exitCode"`
+	Reason          string  `json:"reason,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+}
+
+// New returns the Summary of the PipelineRun pr, given the TaskRuns and Runs
+// it created. The task graph is read from the PipelineSpec in the status of
+// the PipelineRun.
+func New(pr *v1beta1.PipelineRun, taskRuns []*v1beta1.TaskRun, runs []*v1alpha1.Run) *Summary {
+	s := &Summary{
+		Name:           pr.Name,
+		Namespace:      pr.Namespace,
+		Pipeline:       pr.Labels[pipeline.PipelineLabelKey],
+		StartTime:      pr.Status.StartTime,
+		CompletionTime: pr.Status.CompletionTime,
+		Results:        pr.Status.PipelineResults,
+		Tasks:          []Task{},
+	}
+	if s.Pipeline == "" && pr.Spec.PipelineRef != nil {
+		s.Pipeline = pr.Spec.PipelineRef.Name
+	}
+	s.Status, s.Reason, s.Message = status(pr.Status.GetCondition(apis.ConditionSucceeded))
+	s.DurationSeconds = durationSeconds(s.StartTime, s.CompletionTime)
+
+	if pr.Status.PipelineSpec == nil {
+		return s
+	}
+	taskRunsByTask := map[string][]*v1beta1.TaskRun{}
+	for _, tr := range taskRuns {
+		name := tr.Labels[pipeline.PipelineTaskLabelKey]
+		taskRunsByTask[name] = append(taskRunsByTask[name], tr)
+	}
+	runsByTask := map[string][]*v1alpha1.Run{}
+	for _, r := range runs {
+		name := r.Labels[pipeline.PipelineTaskLabelKey]
+		runsByTask[name] = append(runsByTask[name], r)
+	}
+	skipped := map[string]v1beta1.SkippingReason{}
+	for _, st := range pr.Status.SkippedTasks {
+		skipped[st.Name] = st.Reason
+	}
+
+	addTasks := func(pts []v1beta1.PipelineTask, finally bool) {
+		for _, pt := range pts {
+			base := Task{
+				Name:    pt.Name,
+				Finally: finally,
+			}
+			if deps := pt.Deps(); len(deps) > 0 {
+				base.RunAfter = deps
+			}
+			trs, rs := taskRunsByTask[pt.Name], runsByTask[pt.Name]
+			sort.Slice(trs, func(i, j int) bool { return trs[i].Name < trs[j].Name })
+			sort.Slice(rs, func(i, j int) bool { return rs[i].Name < rs[j].Name })
+			for _, tr := range trs {
+				s.Tasks = append(s.Tasks, taskFromTaskRun(base, tr))
+			}
+			for _, r := range rs {
+				s.Tasks = append(s.Tasks, taskFromRun(base, r))
+			}
+			if len(trs) > 0 || len(rs) > 0 {
+				continue
+			}
+			if reason, ok := skipped[pt.Name]; ok {
+				base.Status = StatusSkipped
+				base.SkipReason = reason
+			} else {
+				base.Status = StatusNotRun
+			}
+			s.Tasks = append(s.Tasks, base)
+		}
+	}
+	addTasks(pr.Status.PipelineSpec.Tasks, false)
+	addTasks(pr.Status.PipelineSpec.Finally, true)
+	return s
+}
+
+// JSON returns the Summary encoded as JSON
+func (s *Summary) JSON() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func taskFromTaskRun(t Task, tr *v1beta1.TaskRun) Task {
+	t.RunName = tr.Name
+	t.Kind = pipeline.TaskRunControllerName
+	t.Status, t.Reason, t.Message = status(tr.Status.GetCondition(apis.ConditionSucceeded))
+	t.StartTime = tr.Status.StartTime
+	t.CompletionTime = tr.Status.CompletionTime
+	t.DurationSeconds = durationSeconds(t.StartTime, t.CompletionTime)
+	t.Retries = len(tr.Status.RetriesStatus)
+	for _, result := range tr.Status.TaskRunResults {
+		if t.Results == nil {
+			t.Results = map[string]v1beta1.ArrayOrString{}
+		}
+		t.Results[result.Name] = result.Value
+	}
+	for _, step := range tr.Status.Steps {
+		if step.Terminated == nil {
+			continue
+		}
+		t.Steps = append(t.Steps, Step{
+			Name:            step.Name,
+			ExitCode:        step.Terminated.ExitCode,
+			Reason:          step.Terminated.Reason,
+			DurationSeconds: durationSeconds(&step.Terminated.StartedAt, &step.Terminated.FinishedAt),
+		})
+	}
+	return t
+}
+
+func taskFromRun(t Task, r *v1alpha1.Run) Task {
+	t.RunName = r.Name
+	t.Kind = pipeline.RunControllerName
+	t.Status, t.Reason, t.Message = status(r.Status.GetCondition(apis.ConditionSucceeded))
+	t.StartTime = r.Status.StartTime
+	t.CompletionTime = r.Status.CompletionTime
+	t.DurationSeconds = durationSeconds(t.StartTime, t.CompletionTime)
+	t.Retries = len(r.Status.RetriesStatus)
+	for _, result := range r.Status.Results {
+		if t.Results == nil {
+			t.Results = map[string]v1beta1.ArrayOrString{}
+		}
+		t.Results[result.Name] = *v1beta1.NewArrayOrString(result.Value)
+	}
+	return t
+}
+
+// status returns the status, reason and message of the Succeeded condition c
+func status(c *apis.Condition) (string, string, string) {
+	if c == nil {
+		return StatusRunning, "", ""
+	}
+	switch c.Status {
+	case corev1.ConditionTrue:
+		return StatusSucceeded, c.Reason, c.Message
+	case corev1.ConditionFalse:
+		return StatusFailed, c.Reason, c.Message
+	default:
+		return StatusRunning, c.Reason, c.Message
+	}
+}
+
+func durationSeconds(start, end *metav1.Time) float64 {
+	if start == nil || end == nil || start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start.Time).Seconds()
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/test/diff"
+	"github.com/tektoncd/pipeline/test/parse"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var now = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func at(seconds int) *metav1.Time {
+	t := metav1.NewTime(now.Add(time.Duration(seconds) * time.Second))
+	return &t
+}
+
+func testPipelineRun(t *testing.T) *v1beta1.PipelineRun {
+	t.Helper()
+	return parse.MustParsePipelineRun(t, `
+metadata:
+  name: pr
+  namespace: foo
+  labels:
+    tekton.dev/pipeline: pipeline
+spec:
+  pipelineRef:
+    name: pipeline
+status:
+  conditions:
+  - type: Succeeded
+    status: "False"
+    reason: Failed
+    message: "Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 1"
+  startTime: "2022-01-01T00:00:00Z"
+  completionTime: "2022-01-01T00:01:00Z"
+  pipelineResults:
+  - name: digest
+    value: sha256:abc
+  skippedTasks:
+  - name: deploy
+    reason: When Expressions evaluated to false
+  pipelineSpec:
+    tasks:
+    - name: build
+      taskRef:
+        name: build
+    - name: test
+      runAfter: ["build"]
+      retries: 1
+      taskRef:
+        name: test
+    - name: approve
+      runAfter: ["build"]
+      taskRef:
+        apiVersion: example.dev/v0
+        kind: Approval
+    - name: deploy
+      runAfter: ["test"]
+      taskRef:
+        name: deploy
+    finally:
+    - name: notify
+      taskRef:
+        name: notify
+`)
+}
+
+func testTaskRuns(t *testing.T) []*v1beta1.TaskRun {
+	t.Helper()
+	return []*v1beta1.TaskRun{parse.MustParseTaskRun(t, `
+metadata:
+  name: pr-build
+  namespace: foo
+  labels:
+    tekton.dev/pipelineTask: build
+status:
+  conditions:
+  - type: Succeeded
+    status: "True"
+    reason: Succeeded
+  startTime: "2022-01-01T00:00:00Z"
+  completionTime: "2022-01-01T00:00:20Z"
+  taskResults:
+  - name: image
+    value: registry/image
+  steps:
+  - name: compile
+    terminated:
+      exitCode: 0
+      reason: Completed
+      startedAt: "2022-01-01T00:00:05Z"
+      finishedAt: "2022-01-01T00:00:15Z"
+`), parse.MustParseTaskRun(t, `
+metadata:
+  name: pr-test
+  namespace: foo
+  labels:
+    tekton.dev/pipelineTask: test
+status:
+  conditions:
+  - type: Succeeded
+    status: "False"
+    reason: Failed
+    message: "step unit exited with code 1"
+  startTime: "2022-01-01T00:00:30Z"
+  completionTime: "2022-01-01T00:00:50Z"
+  retriesStatus:
+  - conditions:
+    - type: Succeeded
+      status: "False"
+  steps:
+  - name: unit
+    terminated:
+      exitCode: 1
+      reason: Error
+      startedAt: "2022-01-01T00:00:31Z"
+      finishedAt: "2022-01-01T00:00:49Z"
+`)}
+}
+
+func testRuns(t *testing.T) []*v1alpha1.Run {
+	t.Helper()
+	return []*v1alpha1.Run{parse.MustParseRun(t, `
+metadata:
+  name: pr-approve
+  namespace: foo
+  labels:
+    tekton.dev/pipelineTask: approve
+status:
+  conditions:
+  - type: Succeeded
+    status: "True"
+    reason: Approved
+  startTime: "2022-01-01T00:00:20Z"
+  completionTime: "2022-01-01T00:00:25Z"
+  results:
+  - name: approver
+    value: alice
+`)}
+}
+
+func TestNew(t *testing.T) {
+	want := &Summary{
+		Name:            "pr",
+		Namespace:       "foo",
+		Pipeline:        "pipeline",
+		Status:          StatusFailed,
+		Reason:          "Failed",
+		Message:         "Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 1",
+		StartTime:       at(0),
+		CompletionTime:  at(60),
+		DurationSeconds: 60,
+		Results:         []v1beta1.PipelineRunResult{{Name: "digest", Value: "sha256:abc"}},
+		Tasks: []Task{{
+			Name:            "build",
+			RunName:         "pr-build",
+			Kind:            "TaskRun",
+			Status:          StatusSucceeded,
+			Reason:          "Succeeded",
+			StartTime:       at(0),
+			CompletionTime:  at(20),
+			DurationSeconds: 20,
+			Results:         map[string]v1beta1.ArrayOrString{"image": *v1beta1.NewArrayOrString("registry/image")},
+			Steps:           []Step{{Name: "compile", ExitCode: 0, Reason: "Completed", DurationSeconds: 10}},
+		}, {
+			Name:            "test",
+			RunName:         "pr-test",
+			Kind:            "TaskRun",
+			RunAfter:        []string{"build"},
+			Status:          StatusFailed,
+			Reason:          "Failed",
+			Message:         "step unit exited with code 1",
+			StartTime:       at(30),
+			CompletionTime:  at(50),
+			DurationSeconds: 20,
+			Retries:         1,
+			Steps:           []Step{{Name: "unit", ExitCode: 1, Reason: "Error", DurationSeconds: 18}},
+		}, {
+			Name:            "approve",
+			RunName:         "pr-approve",
+			Kind:            "Run",
+			RunAfter:        []string{"build"},
+			Status:          StatusSucceeded,
+			Reason:          "Approved",
+			StartTime:       at(20),
+			CompletionTime:  at(25),
+			DurationSeconds: 5,
+			Results:         map[string]v1beta1.ArrayOrString{"approver": *v1beta1.NewArrayOrString("alice")},
+		}, {
+			Name:       "deploy",
+			RunAfter:   []string{"test"},
+			Status:     StatusSkipped,
+			SkipReason: v1beta1.WhenExpressionsSkip,
+		}, {
+			Name:    "notify",
+			Finally: true,
+			Status:  StatusNotRun,
+		}},
+	}
+
+	got := New(testPipelineRun(t), testTaskRuns(t), testRuns(t))
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Wrong summary %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestNewWithoutPipelineSpec(t *testing.T) {
+	pr := parse.MustParsePipelineRun(t, `
+metadata:
+  name: pr
+  namespace: foo
+spec:
+  pipelineRef:
+    name: missing
+status:
+  conditions:
+  - type: Succeeded
+    status: "False"
+    reason: CouldntGetPipeline
+    message: pipeline not found
+`)
+	want := &Summary{
+		Name:      "pr",
+		Namespace: "foo",
+		Pipeline:  "missing",
+		Status:    StatusFailed,
+		Reason:    "CouldntGetPipeline",
+		Message:   "pipeline not found",
+		Tasks:     []Task{},
+	}
+	if d := cmp.Diff(want, New(pr, nil, nil)); d != "" {
+		t.Errorf("Wrong summary %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestJSON(t *testing.T) {
+	s := &Summary{
+		Name:      "pr",
+		Namespace: "foo",
+		Status:    StatusSucceeded,
+		Tasks: []Task{{
+			Name:    "build",
+			RunName: "pr-build",
+			Kind:    "TaskRun",
+			Status:  StatusSucceeded,
+			Results: map[string]v1beta1.ArrayOrString{"image": *v1beta1.NewArrayOrString("registry/image")},
+		}},
+	}
+	want := `{"name":"pr","namespace":"foo","status":"Succeeded","tasks":[{"name":"build","runName":"pr-build","kind":"TaskRun","status":"Succeeded","results":{"image":"registry/image"}}]}`
+	got, err := s.JSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff(want, string(got)); d != "" {
+		t.Errorf("Wrong JSON %s", diff.PrintWantGot(d))
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelinerun
+
+import (
+	"context"
+	"time"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer used by the PipelineRun reconciler,
+// and the service name its spans are reported under.
+const TracerName = "PipelineRunReconciler"
+
+// initTracing returns a context carrying the span context of the PipelineRun.
+// The first time a PipelineRun is reconciled with tracing enabled, a span is
+// started for it and its context is recorded in the PipelineRun annotations,
+// from where it is propagated to the TaskRuns it creates. The returned span
+// must be ended once the reconcile is done; it is a non-recording span when
+// the PipelineRun already has a span context.
+func initTracing(ctx context.Context, tp trace.TracerProvider, pr *v1beta1.PipelineRun) (context.Context, trace.Span) {
+	if _, ok := pr.Annotations[pipeline.PipelineRunSpanContextAnnotationKey]; ok {
+		ctx = tracing.ContextFromAnnotation(ctx, pr.Annotations, pipeline.PipelineRunSpanContextAnnotationKey)
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	ctx, span := tp.Tracer(TracerName).Start(ctx, "PipelineRun:"+pr.Name, trace.WithAttributes(pipelineRunAttributes(pr)...))
+	pr.Annotations = tracing.AnnotateSpanContext(ctx, pr.Annotations, pipeline.PipelineRunSpanContextAnnotationKey)
+	return ctx, span
+}
+
+// endTracing records a span covering the execution of a done PipelineRun, from
+// its start to its completion time, as a child of the span of the PipelineRun.
+func endTracing(ctx context.Context, tp trace.TracerProvider, pr *v1beta1.PipelineRun, now time.Time) {
+	if pr.Status.StartTime == nil {
+		return
+	}
+	end := now
+	if pr.Status.CompletionTime != nil {
+		end = pr.Status.CompletionTime.Time
+	}
+	ctx = tracing.ContextFromAnnotation(ctx, pr.Annotations, pipeline.PipelineRunSpanContextAnnotationKey)
+	tracing.RecordSpan(ctx, tp.Tracer(TracerName), "PipelineRun:Execution", pr.Status.StartTime.Time, end,
+		trace.WithAttributes(pipelineRunAttributes(pr)...))
+}
+
+func pipelineRunAttributes(pr *v1beta1.PipelineRun) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("pipelinerun", pr.Name),
+		attribute.String("namespace", pr.Namespace),
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"knative.dev/pkg/configmap"
+)
+
+type cfgKey struct{}
+
+// store is a typed wrapper around configmap.UntypedStore to handle the config-pruner ConfigMap.
+type store struct {
+	*configmap.UntypedStore
+}
+
+// newStore creates a new store of the pruner configuration.
+func newStore(logger configmap.Logger) *store {
+	return &store{
+		UntypedStore: configmap.NewUntypedStore(
+			"pruner",
+			logger,
+			configmap.Constructors{
+				config.GetPrunerConfigName(): config.NewPrunerFromConfigMap,
+			},
+		),
+	}
+}
+
+// ToContext attaches the current pruner configuration to the provided context.
+func (s *store) ToContext(ctx context.Context) context.Context {
+	cfg, _ := s.UntypedLoad(config.GetPrunerConfigName()).(*config.Pruner)
+	if cfg == nil {
+		cfg = &config.Pruner{}
+	}
+	return context.WithValue(ctx, cfgKey{}, cfg.DeepCopy())
+}
+
+// fromContext extracts the pruner configuration from the provided context, or returns
+// a configuration that keeps every run if there is none.
+func fromContext(ctx context.Context) *config.Pruner {
+	if cfg, ok := ctx.Value(cfgKey{}).(*config.Pruner); ok {
+		return cfg
+	}
+	return &config.Pruner{}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	pipelineclient "github.com/tektoncd/pipeline/pkg/client/injection/client"
+	clustertaskinformer "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1beta1/clustertask"
+	pipelineinformer "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1beta1/pipeline"
+	pipelineruninformer "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1beta1/pipelinerun"
+	taskinformer "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1beta1/task"
+	taskruninformer "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1beta1/taskrun"
+	pipelinerunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1beta1/pipelinerun"
+	taskrunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1beta1/taskrun"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+// NewPipelineRunController instantiates a new controller.Impl pruning completed PipelineRuns.
+// This controller doesn't modify the PipelineRuns, hence the SkipStatusUpdates set to true
+func NewPipelineRunController(clock clock.PassiveClock) func(context.Context, configmap.Watcher) *controller.Impl {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		logger := logging.FromContext(ctx)
+		pipelineRunInformer := pipelineruninformer.Get(ctx)
+
+		configStore := newStore(logger.Named("config-store"))
+		configStore.WatchConfigs(cmw)
+
+		if err := registerViews(); err != nil {
+			logger.Errorf("Failed to register pruner metrics: %v", err)
+		}
+
+		c := &PipelineRunReconciler{
+			PipelineClientSet: pipelineclient.Get(ctx),
+			Clock:             clock,
+			pipelineRunLister: pipelineRunInformer.Lister(),
+			pipelineLister:    pipelineinformer.Get(ctx).Lister(),
+		}
+		impl := pipelinerunreconciler.NewImpl(ctx, c, func(impl *controller.Impl) controller.Options {
+			return controller.Options{
+				AgentName:         pipeline.PipelineRunPrunerControllerName,
+				ConfigStore:       configStore,
+				SkipStatusUpdates: true,
+			}
+		})
+
+		pipelineRunInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+		return impl
+	}
+}
+
+// NewTaskRunController instantiates a new controller.Impl pruning completed TaskRuns.
+// This controller doesn't modify the TaskRuns, hence the SkipStatusUpdates set to true
+func NewTaskRunController(clock clock.PassiveClock) func(context.Context, configmap.Watcher) *controller.Impl {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		logger := logging.FromContext(ctx)
+		taskRunInformer := taskruninformer.Get(ctx)
+
+		configStore := newStore(logger.Named("config-store"))
+		configStore.WatchConfigs(cmw)
+
+		if err := registerViews(); err != nil {
+			logger.Errorf("Failed to register pruner metrics: %v", err)
+		}
+
+		c := &TaskRunReconciler{
+			PipelineClientSet: pipelineclient.Get(ctx),
+			Clock:             clock,
+			taskRunLister:     taskRunInformer.Lister(),
+			taskLister:        taskinformer.Get(ctx).Lister(),
+			clusterTaskLister: clustertaskinformer.Get(ctx).Lister(),
+		}
+		impl := taskrunreconciler.NewImpl(ctx, c, func(impl *controller.Impl) controller.Options {
+			return controller.Options{
+				AgentName:         pipeline.TaskRunPrunerControllerName,
+				ConfigStore:       configStore,
+				SkipStatusUpdates: true,
+			}
+		})
+
+		taskRunInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+		return impl
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"sync"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+var (
+	kindTag      = tag.MustNewKey("kind")
+	namespaceTag = tag.MustNewKey("namespace")
+	reasonTag    = tag.MustNewKey("reason")
+
+	prunedCount = stats.Float64("pruned_runs_count",
+		"number of completed runs deleted by the pruner",
+		stats.UnitDimensionless)
+	prunedCountView = &view.View{
+		Description: prunedCount.Description(),
+		Measure:     prunedCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{kindTag, namespaceTag, reasonTag},
+	}
+
+	once        sync.Once
+	registerErr error
+)
+
+// registerViews registers the views of the pruner metrics, once.
+func registerViews() error {
+	once.Do(func() {
+		registerErr = view.Register(prunedCountView)
+	})
+	return registerErr
+}
+
+// recordPruned records the deletion of a run of the given kind.
+func recordPruned(ctx context.Context, kind, namespace, reason string) {
+	ctx, err := tag.New(ctx,
+		tag.Insert(kindTag, kind),
+		tag.Insert(namespaceTag, namespace),
+		tag.Insert(reasonTag, reason))
+	if err != nil {
+		return
+	}
+	metrics.Record(ctx, prunedCount.M(1))
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	pipelinerunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1beta1/pipelinerun"
+	listers "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// PipelineRunReconciler deletes completed PipelineRuns according to the pruner configuration.
+type PipelineRunReconciler struct {
+	PipelineClientSet clientset.Interface
+	Clock             clock.PassiveClock
+
+	pipelineRunLister listers.PipelineRunLister
+	pipelineLister    listers.PipelineLister
+}
+
+// Check that our Reconciler implements pipelinerunreconciler.Interface
+var _ pipelinerunreconciler.Interface = (*PipelineRunReconciler)(nil)
+
+// ReconcileKind prunes the completed PipelineRuns of the same Pipeline as the given PipelineRun.
+// If the PipelineRun itself expires later, it is requeued until then.
+func (c *PipelineRunReconciler) ReconcileKind(ctx context.Context, pr *v1beta1.PipelineRun) pkgreconciler.Event {
+	logger := logging.FromContext(ctx)
+	if !pr.IsDone() || pr.Status.CompletionTime == nil {
+		return nil
+	}
+
+	annotations, err := c.pipelineAnnotations(pr)
+	if err != nil {
+		return err
+	}
+	cfg, err := policyFor(fromContext(ctx), annotations)
+	if err != nil {
+		logger.Errorf("Invalid pruner annotations on the Pipeline of PipelineRun %s/%s: %v", pr.Namespace, pr.Name, err)
+		return controller.NewPermanentError(err)
+	}
+	if !cfg.IsEnabled() {
+		return nil
+	}
+
+	prs := []*v1beta1.PipelineRun{pr}
+	if pipelineName := pr.Labels[pipeline.PipelineLabelKey]; pipelineName != "" {
+		prs, err = c.pipelineRunLister.PipelineRuns(pr.Namespace).List(labels.SelectorFromSet(labels.Set{pipeline.PipelineLabelKey: pipelineName}))
+		if err != nil {
+			return fmt.Errorf("failed to list PipelineRuns of Pipeline %s: %w", pipelineName, err)
+		}
+	}
+	var runs []completedRun
+	for _, p := range prs {
+		if p.IsDone() && p.Status.CompletionTime != nil {
+			runs = append(runs, completedRun{
+				name:           p.Name,
+				successful:     p.Status.GetCondition(apis.ConditionSucceeded).IsTrue(),
+				completionTime: p.Status.CompletionTime.Time,
+			})
+		}
+	}
+
+	now := c.Clock.Now()
+	if err := deleteRuns(ctx, "PipelineRun", pr.Namespace, selectRuns(cfg, runs, now), c.PipelineClientSet.TektonV1beta1().PipelineRuns(pr.Namespace).Delete); err != nil {
+		return err
+	}
+	if d := requeueAfter(cfg, pr.Status.CompletionTime.Time, now); d > 0 {
+		return controller.NewRequeueAfter(d)
+	}
+	return nil
+}
+
+// pipelineAnnotations returns the annotations of the Pipeline the PipelineRun references, which
+// may override the pruner configuration of all the runs of the Pipeline. The annotations of the
+// PipelineRun itself are ignored so that a single run cannot change how the others are pruned.
+// It returns nil when the Pipeline is not in the namespace of the PipelineRun.
+func (c *PipelineRunReconciler) pipelineAnnotations(pr *v1beta1.PipelineRun) (map[string]string, error) {
+	ref := pr.Spec.PipelineRef
+	if ref == nil || ref.Name == "" || ref.Bundle != "" || ref.Resolver != "" || (ref.Namespace != "" && ref.Namespace != pr.Namespace) {
+		return nil, nil
+	}
+	p, err := c.pipelineLister.Pipelines(pr.Namespace).Get(ref.Name)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get Pipeline %s: %w", ref.Name, err)
+	}
+	return p.Annotations, nil
+}
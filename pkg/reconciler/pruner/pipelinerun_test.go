@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	ttesting "github.com/tektoncd/pipeline/pkg/reconciler/testing"
+	"github.com/tektoncd/pipeline/test"
+	"github.com/tektoncd/pipeline/test/diff"
+	"github.com/tektoncd/pipeline/test/parse"
+	"k8s.io/apimachinery/pkg/util/clock"
+	ktesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/controller"
+)
+
+func completedPipelineRun(t *testing.T, name, pipelineName, status string, completed time.Duration, annotations string) *v1beta1.PipelineRun {
+	t.Helper()
+	return parse.MustParsePipelineRun(t, fmt.Sprintf(`
+metadata:
+  name: %s
+  namespace: foo
+  labels:
+    tekton.dev/pipeline: %s
+  annotations: {%s}
+spec:
+  pipelineRef:
+    name: %s
+status:
+  conditions:
+  - type: Succeeded
+    status: %q
+  completionTime: %q
+`, name, pipelineName, annotations, pipelineName, status, now.Add(-completed).Format(time.RFC3339)))
+}
+
+// getDeletions returns the names of the resources deleted through the given actions.
+func getDeletions(actions []ktesting.Action) []string {
+	var deleted []string
+	for _, a := range actions {
+		if action, ok := a.(ktesting.DeleteAction); ok {
+			deleted = append(deleted, action.GetName())
+		}
+	}
+	sort.Strings(deleted)
+	return deleted
+}
+
+func TestReconcilePipelineRun(t *testing.T) {
+	prs := []*v1beta1.PipelineRun{
+		completedPipelineRun(t, "p-success-1", "p", "True", time.Minute, ""),
+		completedPipelineRun(t, "p-success-2", "p", "True", time.Hour, ""),
+		completedPipelineRun(t, "p-success-3", "p", "True", 2*time.Hour, ""),
+		completedPipelineRun(t, "p-failure-1", "p", "False", 3*time.Hour, ""),
+		completedPipelineRun(t, "other-success-1", "other", "True", 2*time.Hour, ""),
+		completedPipelineRun(t, "other-success-2", "other", "True", time.Hour, `"pruner.tekton.dev/successful-history-limit": "5"`),
+		completedPipelineRun(t, "annotated-success-1", "annotated", "True", time.Minute, ""),
+		parse.MustParsePipelineRun(t, `
+metadata:
+  name: p-running
+  namespace: foo
+  labels:
+    tekton.dev/pipeline: p
+spec:
+  pipelineRef:
+    name: p
+status:
+  conditions:
+  - type: Succeeded
+    status: Unknown
+`),
+	}
+	pipelines := []*v1beta1.Pipeline{parse.MustParsePipeline(t, `
+metadata:
+  name: annotated
+  namespace: foo
+  annotations:
+    pruner.tekton.dev/ttl-after-finished: 1h
+`)}
+	cfg := &config.Pruner{SuccessfulHistoryLimit: intPtr(1)}
+
+	for _, tc := range []struct {
+		name        string
+		pipelineRun string
+		wantDeleted []string
+		wantRequeue time.Duration
+	}{{
+		name:        "history limit",
+		pipelineRun: "p-success-1",
+		wantDeleted: []string{"p-success-2", "p-success-3"},
+	}, {
+		name:        "running PipelineRun",
+		pipelineRun: "p-running",
+	}, {
+		name:        "ttl from annotation of the Pipeline",
+		pipelineRun: "annotated-success-1",
+		wantRequeue: 59 * time.Minute,
+	}, {
+		name:        "annotations of the PipelineRun are ignored",
+		pipelineRun: "other-success-2",
+		wantDeleted: []string{"other-success-1"},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := ttesting.SetupFakeContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			clients, informers := test.SeedTestData(t, ctx, test.Data{PipelineRuns: prs, Pipelines: pipelines})
+			c := &PipelineRunReconciler{
+				PipelineClientSet: clients.Pipeline,
+				Clock:             clock.NewFakePassiveClock(now),
+				pipelineRunLister: informers.PipelineRun.Lister(),
+				pipelineLister:    informers.Pipeline.Lister(),
+			}
+			pr, err := informers.PipelineRun.Lister().PipelineRuns("foo").Get(tc.pipelineRun)
+			if err != nil {
+				t.Fatalf("failed to get PipelineRun %s: %v", tc.pipelineRun, err)
+			}
+			clients.Pipeline.ClearActions()
+
+			err = c.ReconcileKind(context.WithValue(ctx, cfgKey{}, cfg), pr)
+			var gotRequeue time.Duration
+			if ok, d := controller.IsRequeueKey(err); ok {
+				gotRequeue = d
+			} else if err != nil {
+				t.Fatalf("ReconcileKind() = %v", err)
+			}
+			if gotRequeue != tc.wantRequeue {
+				t.Errorf("expected requeue after %v, got %v", tc.wantRequeue, gotRequeue)
+			}
+			if d := cmp.Diff(tc.wantDeleted, getDeletions(clients.Pipeline.Actions())); d != "" {
+				t.Errorf("unexpected deletions %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
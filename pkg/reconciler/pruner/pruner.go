@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// reasonTTL is the reason recorded when a run is deleted because its TTL expired
+	reasonTTL = "TTLExpired"
+	// reasonHistoryLimit is the reason recorded when a run is deleted because it exceeds the history limit
+	reasonHistoryLimit = "HistoryLimitExceeded"
+)
+
+// completedRun is a completed TaskRun or PipelineRun that may be pruned
+type completedRun struct {
+	name           string
+	successful     bool
+	completionTime time.Time
+}
+
+// prunedRun is a completed run to delete, along with the reason why it is deleted
+type prunedRun struct {
+	name   string
+	reason string
+}
+
+// deleteFunc deletes the run with the given name
+type deleteFunc func(ctx context.Context, name string, opts metav1.DeleteOptions) error
+
+// policyFor returns the pruner configuration that applies to a run: the configuration
+// from the config-pruner ConfigMap, overridden by the pruner annotations of the run.
+func policyFor(cfg *config.Pruner, annotations map[string]string) (*config.Pruner, error) {
+	overrides := map[string]string{}
+	for key, value := range annotations {
+		if strings.HasPrefix(key, pipeline.PrunerAnnotationPrefix) {
+			overrides[strings.TrimPrefix(key, pipeline.PrunerAnnotationPrefix)] = value
+		}
+	}
+	return cfg.WithOverrides(overrides)
+}
+
+// selectRuns returns the runs to delete according to the pruner configuration. Runs whose TTL
+// expired are deleted first, then the oldest runs exceeding the history limits.
+func selectRuns(cfg *config.Pruner, runs []completedRun, now time.Time) []prunedRun {
+	sorted := append([]completedRun{}, runs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].completionTime.After(sorted[j].completionTime)
+	})
+
+	var pruned []prunedRun
+	var successful, failed int
+	for _, r := range sorted {
+		if cfg.TTLAfterFinished != nil && now.Sub(r.completionTime) >= *cfg.TTLAfterFinished {
+			pruned = append(pruned, prunedRun{name: r.name, reason: reasonTTL})
+			continue
+		}
+		if r.successful {
+			successful++
+			if cfg.SuccessfulHistoryLimit != nil && successful > *cfg.SuccessfulHistoryLimit {
+				pruned = append(pruned, prunedRun{name: r.name, reason: reasonHistoryLimit})
+			}
+		} else {
+			failed++
+			if cfg.FailedHistoryLimit != nil && failed > *cfg.FailedHistoryLimit {
+				pruned = append(pruned, prunedRun{name: r.name, reason: reasonHistoryLimit})
+			}
+		}
+	}
+	return pruned
+}
+
+// requeueAfter returns how long to wait before a run completed at the given time expires,
+// or zero if it never expires or already expired.
+func requeueAfter(cfg *config.Pruner, completionTime time.Time, now time.Time) time.Duration {
+	if cfg.TTLAfterFinished == nil {
+		return 0
+	}
+	if remaining := completionTime.Add(*cfg.TTLAfterFinished).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// deleteRuns deletes the given runs of the given kind. Dependents such as the TaskRuns, Pods
+// and PersistentVolumeClaims owned by a run are deleted in the background by the garbage collector.
+func deleteRuns(ctx context.Context, kind, namespace string, runs []prunedRun, deleteRun deleteFunc) error {
+	logger := logging.FromContext(ctx)
+	propagation := metav1.DeletePropagationBackground
+	for _, r := range runs {
+		logger.Infof("Deleting %s %s/%s: %s", kind, namespace, r.name, r.reason)
+		if err := deleteRun(ctx, r.name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		recordPruned(ctx, kind, namespace, r.reason)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/test/diff"
+)
+
+var now = time.Date(2022, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func intPtr(i int) *int { return &i }
+
+func durationPtr(d time.Duration) *time.Duration { return &d }
+
+func TestSelectRuns(t *testing.T) {
+	runs := []completedRun{
+		{name: "success-old", successful: true, completionTime: now.Add(-3 * time.Hour)},
+		{name: "success-new", successful: true, completionTime: now.Add(-time.Minute)},
+		{name: "failure-old", completionTime: now.Add(-2 * time.Hour)},
+		{name: "failure-new", completionTime: now.Add(-2 * time.Minute)},
+		{name: "success-mid", successful: true, completionTime: now.Add(-time.Hour)},
+	}
+	for _, tc := range []struct {
+		name string
+		cfg  *config.Pruner
+		want []prunedRun
+	}{{
+		name: "no limits",
+		cfg:  &config.Pruner{},
+	}, {
+		name: "successful history limit",
+		cfg:  &config.Pruner{SuccessfulHistoryLimit: intPtr(1)},
+		want: []prunedRun{
+			{name: "success-mid", reason: reasonHistoryLimit},
+			{name: "success-old", reason: reasonHistoryLimit},
+		},
+	}, {
+		name: "failed history limit",
+		cfg:  &config.Pruner{FailedHistoryLimit: intPtr(0)},
+		want: []prunedRun{
+			{name: "failure-new", reason: reasonHistoryLimit},
+			{name: "failure-old", reason: reasonHistoryLimit},
+		},
+	}, {
+		name: "ttl",
+		cfg:  &config.Pruner{TTLAfterFinished: durationPtr(time.Hour)},
+		want: []prunedRun{
+			{name: "success-mid", reason: reasonTTL},
+			{name: "failure-old", reason: reasonTTL},
+			{name: "success-old", reason: reasonTTL},
+		},
+	}, {
+		name: "expired runs don't count towards the history limit",
+		cfg:  &config.Pruner{TTLAfterFinished: durationPtr(90 * time.Minute), SuccessfulHistoryLimit: intPtr(1)},
+		want: []prunedRun{
+			{name: "success-mid", reason: reasonHistoryLimit},
+			{name: "failure-old", reason: reasonTTL},
+			{name: "success-old", reason: reasonTTL},
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := selectRuns(tc.cfg, runs, now)
+			if d := cmp.Diff(tc.want, got, cmp.AllowUnexported(prunedRun{})); d != "" {
+				t.Errorf("selectRuns() %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	cfg := &config.Pruner{SuccessfulHistoryLimit: intPtr(5), FailedHistoryLimit: intPtr(5)}
+	got, err := policyFor(cfg, map[string]string{
+		"pruner.tekton.dev/failed-history-limit": "1",
+		"pruner.tekton.dev/ttl-after-finished":   "1h",
+		"failed-history-limit":                   "2",
+	})
+	if err != nil {
+		t.Fatalf("policyFor() = %v", err)
+	}
+	want := &config.Pruner{SuccessfulHistoryLimit: intPtr(5), FailedHistoryLimit: intPtr(1), TTLAfterFinished: durationPtr(time.Hour)}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("policyFor() %s", diff.PrintWantGot(d))
+	}
+
+	if _, err := policyFor(cfg, map[string]string{"pruner.tekton.dev/ttl-after-finished": "tomorrow"}); err == nil {
+		t.Error("expected an error for an invalid annotation, got nil")
+	}
+}
+
+func TestRequeueAfter(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		cfg            *config.Pruner
+		completionTime time.Time
+		want           time.Duration
+	}{{
+		name:           "no ttl",
+		cfg:            &config.Pruner{},
+		completionTime: now,
+	}, {
+		name:           "not expired",
+		cfg:            &config.Pruner{TTLAfterFinished: durationPtr(time.Hour)},
+		completionTime: now.Add(-10 * time.Minute),
+		want:           50 * time.Minute,
+	}, {
+		name:           "expired",
+		cfg:            &config.Pruner{TTLAfterFinished: durationPtr(time.Hour)},
+		completionTime: now.Add(-2 * time.Hour),
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := requeueAfter(tc.cfg, tc.completionTime, now); got != tc.want {
+				t.Errorf("requeueAfter() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	taskrunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1beta1/taskrun"
+	listers "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// TaskRunReconciler deletes completed TaskRuns according to the pruner configuration.
+// TaskRuns owned by another resource, such as a PipelineRun, are left to their owner.
+type TaskRunReconciler struct {
+	PipelineClientSet clientset.Interface
+	Clock             clock.PassiveClock
+
+	taskRunLister     listers.TaskRunLister
+	taskLister        listers.TaskLister
+	clusterTaskLister listers.ClusterTaskLister
+}
+
+// Check that our Reconciler implements taskrunreconciler.Interface
+var _ taskrunreconciler.Interface = (*TaskRunReconciler)(nil)
+
+// ReconcileKind prunes the completed TaskRuns of the same Task as the given TaskRun.
+// If the TaskRun itself expires later, it is requeued until then.
+func (c *TaskRunReconciler) ReconcileKind(ctx context.Context, tr *v1beta1.TaskRun) pkgreconciler.Event {
+	logger := logging.FromContext(ctx)
+	if !tr.IsDone() || tr.Status.CompletionTime == nil || len(tr.OwnerReferences) > 0 {
+		return nil
+	}
+
+	annotations, err := c.taskAnnotations(tr)
+	if err != nil {
+		return err
+	}
+	cfg, err := policyFor(fromContext(ctx), annotations)
+	if err != nil {
+		logger.Errorf("Invalid pruner annotations on the Task of TaskRun %s/%s: %v", tr.Namespace, tr.Name, err)
+		return controller.NewPermanentError(err)
+	}
+	if !cfg.IsEnabled() {
+		return nil
+	}
+
+	trs := []*v1beta1.TaskRun{tr}
+	if selector := taskSelector(tr); selector != nil {
+		trs, err = c.taskRunLister.TaskRuns(tr.Namespace).List(selector)
+		if err != nil {
+			return fmt.Errorf("failed to list TaskRuns matching %s: %w", selector, err)
+		}
+	}
+	var runs []completedRun
+	for _, t := range trs {
+		if t.IsDone() && t.Status.CompletionTime != nil && len(t.OwnerReferences) == 0 {
+			runs = append(runs, completedRun{
+				name:           t.Name,
+				successful:     t.Status.GetCondition(apis.ConditionSucceeded).IsTrue(),
+				completionTime: t.Status.CompletionTime.Time,
+			})
+		}
+	}
+
+	now := c.Clock.Now()
+	if err := deleteRuns(ctx, "TaskRun", tr.Namespace, selectRuns(cfg, runs, now), c.PipelineClientSet.TektonV1beta1().TaskRuns(tr.Namespace).Delete); err != nil {
+		return err
+	}
+	if d := requeueAfter(cfg, tr.Status.CompletionTime.Time, now); d > 0 {
+		return controller.NewRequeueAfter(d)
+	}
+	return nil
+}
+
+// taskSelector returns the selector of the TaskRuns of the same Task or ClusterTask as the
+// given TaskRun, or nil if the TaskRun doesn't reference one.
+func taskSelector(tr *v1beta1.TaskRun) labels.Selector {
+	if name := tr.Labels[pipeline.ClusterTaskLabelKey]; name != "" {
+		return labels.SelectorFromSet(labels.Set{pipeline.ClusterTaskLabelKey: name})
+	}
+	if name := tr.Labels[pipeline.TaskLabelKey]; name != "" {
+		return labels.SelectorFromSet(labels.Set{pipeline.TaskLabelKey: name})
+	}
+	return nil
+}
+
+// taskAnnotations returns the annotations of the Task or ClusterTask the TaskRun references, which
+// may override the pruner configuration of all the runs of the Task. The annotations of the TaskRun
+// itself are ignored so that a single run cannot change how the others are pruned. It returns nil
+// when the Task is not in the cluster, e.g. when it is resolved from a bundle.
+func (c *TaskRunReconciler) taskAnnotations(tr *v1beta1.TaskRun) (map[string]string, error) {
+	ref := tr.Spec.TaskRef
+	if ref == nil || ref.Name == "" || ref.Bundle != "" || ref.Resolver != "" {
+		return nil, nil
+	}
+	var (
+		obj metav1.Object
+		err error
+	)
+	if ref.Kind == v1beta1.ClusterTaskKind {
+		obj, err = c.clusterTaskLister.Get(ref.Name)
+	} else {
+		if ref.Namespace != "" && ref.Namespace != tr.Namespace {
+			return nil, nil
+		}
+		obj, err = c.taskLister.Tasks(tr.Namespace).Get(ref.Name)
+	}
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", ref.Kind, ref.Name, err)
+	}
+	return obj.GetAnnotations(), nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	ttesting "github.com/tektoncd/pipeline/pkg/reconciler/testing"
+	"github.com/tektoncd/pipeline/test"
+	"github.com/tektoncd/pipeline/test/diff"
+	"github.com/tektoncd/pipeline/test/parse"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"knative.dev/pkg/controller"
+)
+
+func completedTaskRun(t *testing.T, name, taskName, status string, completed time.Duration, ownerReferences string) *v1beta1.TaskRun {
+	t.Helper()
+	return parse.MustParseTaskRun(t, fmt.Sprintf(`
+metadata:
+  name: %s
+  namespace: foo
+  labels:
+    tekton.dev/task: %s
+  annotations:
+    pruner.tekton.dev/failed-history-limit: "5"
+  ownerReferences: [%s]
+spec:
+  taskRef:
+    name: %s
+status:
+  conditions:
+  - type: Succeeded
+    status: %q
+  completionTime: %q
+`, name, taskName, ownerReferences, taskName, status, now.Add(-completed).Format(time.RFC3339)))
+}
+
+func TestReconcileTaskRun(t *testing.T) {
+	owner := `{apiVersion: tekton.dev/v1beta1, kind: PipelineRun, name: pr, uid: "1"}`
+	trs := []*v1beta1.TaskRun{
+		completedTaskRun(t, "t-success-1", "t", "True", time.Minute, ""),
+		completedTaskRun(t, "t-failure-1", "t", "False", 30*time.Minute, ""),
+		completedTaskRun(t, "t-failure-2", "t", "False", 2*time.Hour, ""),
+		completedTaskRun(t, "t-owned-1", "t", "False", 3*time.Hour, owner),
+		completedTaskRun(t, "annotated-failure-1", "annotated", "False", time.Minute, ""),
+		completedTaskRun(t, "annotated-failure-2", "annotated", "False", 30*time.Minute, ""),
+		completedTaskRun(t, "annotated-failure-3", "annotated", "False", 40*time.Minute, ""),
+	}
+	tasks := []*v1beta1.Task{parse.MustParseTask(t, `
+metadata:
+  name: annotated
+  namespace: foo
+  annotations:
+    pruner.tekton.dev/failed-history-limit: "1"
+`)}
+	cfg := &config.Pruner{TTLAfterFinished: durationPtr(time.Hour)}
+
+	for _, tc := range []struct {
+		name        string
+		taskRun     string
+		wantDeleted []string
+		wantRequeue time.Duration
+	}{{
+		name:        "ttl",
+		taskRun:     "t-success-1",
+		wantDeleted: []string{"t-failure-2"},
+		wantRequeue: 59 * time.Minute,
+	}, {
+		name:    "owned TaskRun",
+		taskRun: "t-owned-1",
+	}, {
+		name:        "history limit from annotation of the Task",
+		taskRun:     "annotated-failure-1",
+		wantDeleted: []string{"annotated-failure-2", "annotated-failure-3"},
+		wantRequeue: 59 * time.Minute,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := ttesting.SetupFakeContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			clients, informers := test.SeedTestData(t, ctx, test.Data{TaskRuns: trs, Tasks: tasks})
+			c := &TaskRunReconciler{
+				PipelineClientSet: clients.Pipeline,
+				Clock:             clock.NewFakePassiveClock(now),
+				taskRunLister:     informers.TaskRun.Lister(),
+				taskLister:        informers.Task.Lister(),
+				clusterTaskLister: informers.ClusterTask.Lister(),
+			}
+			tr, err := informers.TaskRun.Lister().TaskRuns("foo").Get(tc.taskRun)
+			if err != nil {
+				t.Fatalf("failed to get TaskRun %s: %v", tc.taskRun, err)
+			}
+			clients.Pipeline.ClearActions()
+
+			err = c.ReconcileKind(context.WithValue(ctx, cfgKey{}, cfg), tr)
+			var gotRequeue time.Duration
+			if ok, d := controller.IsRequeueKey(err); ok {
+				gotRequeue = d
+			} else if err != nil {
+				t.Fatalf("ReconcileKind() = %v", err)
+			}
+			if gotRequeue != tc.wantRequeue {
+				t.Errorf("expected requeue after %v, got %v", tc.wantRequeue, gotRequeue)
+			}
+			if d := cmp.Diff(tc.wantDeleted, getDeletions(clients.Pipeline.Actions())); d != "" {
+				t.Errorf("unexpected deletions %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
@@ -49,7 +49,7 @@ import (
 )
 
 func ensureConfigurationConfigMapsExist(d *test.Data) {
-	var defaultsExists, featureFlagsExists, artifactBucketExists, artifactPVCExists, metricsExists bool
+	var defaultsExists, featureFlagsExists, artifactBucketExists, artifactPVCExists, metricsExists, tracingExists, eventsExists bool
 	for _, cm := range d.ConfigMaps {
 		if cm.Name == config.GetDefaultsConfigName() {
 			defaultsExists = true
@@ -66,6 +66,12 @@ func ensureConfigurationConfigMapsExist(d *test.Data) {
 		if cm.Name == config.GetMetricsConfigName() {
 			metricsExists = true
 		}
+		if cm.Name == config.GetTracingConfigName() {
+			tracingExists = true
+		}
+		if cm.Name == config.GetEventsConfigName() {
+			eventsExists = true
+		}
 	}
 	if !defaultsExists {
 		d.ConfigMaps = append(d.ConfigMaps, &corev1.ConfigMap{
@@ -97,6 +103,18 @@ func ensureConfigurationConfigMapsExist(d *test.Data) {
 			Data:       map[string]string{},
 		})
 	}
+	if !tracingExists {
+		d.ConfigMaps = append(d.ConfigMaps, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: config.GetTracingConfigName(), Namespace: system.Namespace()},
+			Data:       map[string]string{},
+		})
+	}
+	if !eventsExists {
+		d.ConfigMaps = append(d.ConfigMaps, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: config.GetEventsConfigName(), Namespace: system.Namespace()},
+			Data:       map[string]string{},
+		})
+	}
 }
 
 func initializeRunControllerAssets(t *testing.T, d test.Data) (test.Assets, func()) {
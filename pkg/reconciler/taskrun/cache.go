@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskrun
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+)
+
+// cacheKey returns the key under which the results of the TaskRun are cached. It is a digest of
+// the TaskSpec stored in the status of the TaskRun, of the inputs in its spec, including its
+// params and workspace bindings, and of the key configured in the cache of its pipeline task.
+// It is only computed from what the controller resolved and the TaskRun runs with, so that the
+// cache key of any TaskRun can be recomputed to verify it.
+func cacheKey(tr *v1beta1.TaskRun) (string, error) {
+	spec := tr.Spec.DeepCopy()
+	// The reference to the Task is replaced by the resolved TaskSpec, and the fields
+	// which do not change what the Task computes are not part of the key.
+	spec.TaskRef = nil
+	spec.TaskSpec = nil
+	spec.Status = ""
+	spec.Timeout = nil
+	spec.GracePeriod = nil
+	sort.Slice(spec.Params, func(i, j int) bool { return spec.Params[i].Name < spec.Params[j].Name })
+	sort.Slice(spec.Workspaces, func(i, j int) bool { return spec.Workspaces[i].Name < spec.Workspaces[j].Name })
+	b, err := json.Marshal(struct {
+		TaskSpec *v1beta1.TaskSpec    `json:"taskSpec,omitempty"`
+		Spec     *v1beta1.TaskRunSpec `json:"spec"`
+		Key      string               `json:"key,omitempty"`
+	}{
+		TaskSpec: tr.Status.TaskSpec,
+		Spec:     spec,
+		Key:      tr.Annotations[pipeline.CacheAnnotationKey],
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute cache key for TaskRun %q: %w", tr.Name, err)
+	}
+	// A SHA-224 digest is used as it fits within the 63 characters allowed in a label value.
+	return fmt.Sprintf("%x", sha256.Sum224(b)), nil
+}
+
+// reuseCachedResults labels a TaskRun with caching enabled with its cache key and, when a previous
+// TaskRun with the same cache key succeeded, marks the TaskRun as successful with its results
+// without running its Task. The cache key of the previous TaskRuns is recomputed rather than
+// read from their labels, so that only the results of TaskRuns with the same inputs are reused.
+// It returns false when no results are reused, in which case the Task is run as usual.
+func (c *Reconciler) reuseCachedResults(ctx context.Context, tr *v1beta1.TaskRun) bool {
+	logger := logging.FromContext(ctx)
+
+	if _, ok := tr.Annotations[pipeline.CacheAnnotationKey]; !ok || tr.Status.PodName != "" || tr.Status.TaskSpec == nil ||
+		config.FromContextOrDefaults(ctx).FeatureFlags.EnableAPIFields != config.AlphaAPIFields {
+		return false
+	}
+	key, err := cacheKey(tr)
+	if err != nil {
+		logger.Warnf("Not reusing cached results for TaskRun %s: %v", tr.Name, err)
+		return false
+	}
+	if tr.Labels == nil {
+		tr.Labels = map[string]string{}
+	}
+	tr.Labels[pipeline.CacheKeyLabelKey] = key
+
+	// The labels of the TaskRuns only narrow down the candidates.
+	taskRuns, err := c.taskRunLister.TaskRuns(tr.Namespace).List(k8slabels.SelectorFromSet(k8slabels.Set{pipeline.CacheKeyLabelKey: key}))
+	if err != nil {
+		logger.Warnf("Not reusing cached results for TaskRun %s: failed to list TaskRuns with cache key %q: %v", tr.Name, key, err)
+		return false
+	}
+	var cached *v1beta1.TaskRun
+	for _, candidate := range taskRuns {
+		if candidate.Name == tr.Name || !candidate.IsSuccessful() || candidate.Status.CompletionTime == nil || candidate.Status.TaskSpec == nil {
+			continue
+		}
+		if candidate.Status.GetCondition(apis.ConditionSucceeded).Reason == v1beta1.TaskRunReasonCacheHit.String() {
+			// Results are only reused from TaskRuns which ran their Task.
+			continue
+		}
+		if candidateKey, err := cacheKey(candidate); err != nil || candidateKey != key {
+			continue
+		}
+		if cached == nil || candidate.Status.CompletionTime.After(cached.Status.CompletionTime.Time) {
+			cached = candidate
+		}
+	}
+	if cached == nil {
+		return false
+	}
+
+	logger.Infof("Reusing the results of TaskRun %s for TaskRun %s", cached.Name, tr.Name)
+	if tr.Annotations == nil {
+		tr.Annotations = map[string]string{}
+	}
+	tr.Annotations[pipeline.CachedFromAnnotationKey] = cached.Name
+	tr.Status.TaskRunResults = nil
+	for _, result := range cached.Status.TaskRunResults {
+		tr.Status.TaskRunResults = append(tr.Status.TaskRunResults, *result.DeepCopy())
+	}
+	tr.Status.CompletionTime = &metav1.Time{Time: c.Clock.Now()}
+	tr.Status.SetCondition(&apis.Condition{
+		Type:    apis.ConditionSucceeded,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.TaskRunReasonCacheHit.String(),
+		Message: fmt.Sprintf("Results reused from TaskRun %q", cached.Name),
+	})
+	return true
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskrun
+
+import (
+	"testing"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCacheKey(t *testing.T) {
+	params := []v1beta1.Param{{
+		Name: "a", Value: *v1beta1.NewArrayOrString("1"),
+	}, {
+		Name: "b", Value: *v1beta1.NewArrayOrString("2"),
+	}}
+	workspaces := []v1beta1.WorkspaceBinding{{
+		Name: "source", PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "pvc"},
+	}, {
+		Name: "config", EmptyDir: &corev1.EmptyDirVolumeSource{},
+	}}
+	newTaskRun := func(name, key, image string, params []v1beta1.Param, workspaces []v1beta1.WorkspaceBinding) *v1beta1.TaskRun {
+		return &v1beta1.TaskRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{pipeline.CacheAnnotationKey: key},
+			},
+			Spec: v1beta1.TaskRunSpec{
+				TaskRef:    &v1beta1.TaskRef{Name: "lint"},
+				Params:     params,
+				Workspaces: workspaces,
+			},
+			Status: v1beta1.TaskRunStatus{
+				TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+					TaskSpec: &v1beta1.TaskSpec{Steps: []v1beta1.Step{{Image: image}}},
+				},
+			},
+		}
+	}
+
+	want, err := cacheKey(newTaskRun("lint", "abc", "foo", params, workspaces))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(want) > 63 {
+		t.Errorf("cache key %q is not a valid label value", want)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		tr       *v1beta1.TaskRun
+		wantSame bool
+	}{{
+		name:     "same inputs",
+		tr:       newTaskRun("lint-2", "abc", "foo", params, workspaces),
+		wantSame: true,
+	}, {
+		name:     "params and workspaces in a different order",
+		tr:       newTaskRun("lint-2", "abc", "foo", []v1beta1.Param{params[1], params[0]}, []v1beta1.WorkspaceBinding{workspaces[1], workspaces[0]}),
+		wantSame: true,
+	}, {
+		name: "different param value",
+		tr:   newTaskRun("lint-2", "abc", "foo", []v1beta1.Param{params[0], {Name: "b", Value: *v1beta1.NewArrayOrString("3")}}, workspaces),
+	}, {
+		name: "different workspace binding",
+		tr: newTaskRun("lint-2", "abc", "foo", params, []v1beta1.WorkspaceBinding{{
+			Name: "source", PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "other-pvc"},
+		}, workspaces[1]}),
+	}, {
+		name: "different cache key",
+		tr:   newTaskRun("lint-2", "def", "foo", params, workspaces),
+	}, {
+		name: "different task spec",
+		tr:   newTaskRun("lint-2", "abc", "bar", params, workspaces),
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := cacheKey(tc.tr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (got == want) != tc.wantSame {
+				t.Errorf("expected key %q to match %q: %t", got, want, tc.wantSame)
+			}
+		})
+	}
+}
@@ -122,6 +122,23 @@ func ApplyContexts(spec *v1beta1.TaskSpec, taskName string, tr *v1beta1.TaskRun)
 	return ApplyReplacements(spec, replacements, map[string][]string{})
 }
 
+// ApplyParametersToTaskRef applies the params and context variables of a TaskRun to the
+// bundle and resolver parameters of its TaskRef, which are needed before the Task is resolved.
+func ApplyParametersToTaskRef(tr *v1beta1.TaskRun) *v1beta1.TaskRef {
+	if tr.Spec.TaskRef == nil {
+		return nil
+	}
+	ref := tr.Spec.TaskRef.DeepCopy()
+	replacements := map[string]string{
+		"context.taskRun.name":      tr.Name,
+		"context.taskRun.namespace": tr.Namespace,
+		"context.taskRun.uid":       string(tr.ObjectMeta.UID),
+	}
+	v1beta1.AddRefParamReplacements(replacements, tr.Spec.Params)
+	v1beta1.ApplyTaskRefReplacements(ref, replacements)
+	return ref
+}
+
 // ApplyWorkspaces applies the substitution from paths that the workspaces in declarations mounted to, the
 // volumes that bindings are realized with in the task spec and the PersistentVolumeClaim names for the
 // workspaces.
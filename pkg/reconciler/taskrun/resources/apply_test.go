@@ -44,6 +44,7 @@ var (
 		KubeconfigWriterImage:    "override-with-kubeconfig-writer-image:latest",
 		ShellImage:               "busybox",
 		GsutilImage:              "gcr.io/google.com/cloudsdktool/cloud-sdk",
+		S3Image:                  "docker.io/amazon/aws-cli",
 		PRImage:                  "override-with-pr:latest",
 		ImageDigestExporterImage: "override-with-imagedigest-exporter-image:latest",
 	}
@@ -1084,6 +1085,46 @@ func TestContext(t *testing.T) {
 	}
 }
 
+func TestApplyParametersToTaskRef(t *testing.T) {
+	tr := &v1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace"},
+		Spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{
+				Bundle: "registry.io/catalog:$(params['tag'])",
+				ResolverRef: v1beta1.ResolverRef{
+					Resolver: "git",
+					Resource: []v1beta1.ResolverParam{
+						{Name: "revision", Value: "$(params.branch)"},
+						{Name: "path", Value: "$(context.taskRun.namespace)/task.yaml"},
+					},
+				},
+			},
+			Params: []v1beta1.Param{
+				{Name: "branch", Value: *v1beta1.NewArrayOrString("release")},
+				{Name: "tag", Value: *v1beta1.NewArrayOrString("v1")},
+				{Name: "flags", Value: *v1beta1.NewArrayOrString("-v", "-x")},
+			},
+		},
+	}
+	expected := &v1beta1.TaskRef{
+		Bundle: "registry.io/catalog:v1",
+		ResolverRef: v1beta1.ResolverRef{
+			Resolver: "git",
+			Resource: []v1beta1.ResolverParam{
+				{Name: "revision", Value: "release"},
+				{Name: "path", Value: "namespace/task.yaml"},
+			},
+		},
+	}
+	got := resources.ApplyParametersToTaskRef(tr)
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf(diff.PrintWantGot(d))
+	}
+	if tr.Spec.TaskRef.Resource[0].Value != "$(params.branch)" {
+		t.Errorf("ApplyParametersToTaskRef() modified the TaskRun's TaskRef")
+	}
+}
+
 func TestTaskResults(t *testing.T) {
 	names.TestingSeed()
 	ts := &v1beta1.TaskSpec{
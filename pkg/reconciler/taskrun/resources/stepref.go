@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	remoteresource "github.com/tektoncd/resolution/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetStepTask is a function used to retrieve the Task referenced by a Step.
+type GetStepTask func(context.Context, *v1beta1.TaskRef) (v1beta1.TaskObject, error)
+
+// GetStepTaskFuncFromTaskRun returns a GetStepTask function which fetches the
+// Tasks referenced by the Steps of the TaskRun from the cluster, a bundle or a
+// remote resolver, like the Task of the TaskRun itself.
+func GetStepTaskFuncFromTaskRun(k8s kubernetes.Interface, tekton clientset.Interface, requester remoteresource.Requester, taskrun *v1beta1.TaskRun) GetStepTask {
+	return func(ctx context.Context, ref *v1beta1.TaskRef) (v1beta1.TaskObject, error) {
+		getTask, err := GetTaskFunc(ctx, k8s, tekton, requester, taskrun, ref, taskrun.Name, taskrun.Namespace, taskrun.Spec.ServiceAccountName)
+		if err != nil {
+			return nil, err
+		}
+		return getTask(ctx, ref.Name)
+	}
+}
+
+// ResolveStepRefs replaces the Steps of the TaskSpec which reference a Step of
+// another Task with the referenced Step, merged with the stepTemplate of that
+// Task. The name of the referencing Step is kept if set. The TaskSpec is
+// returned unchanged if none of its Steps have a ref.
+func ResolveStepRefs(ctx context.Context, taskSpec *v1beta1.TaskSpec, getTask GetStepTask) (*v1beta1.TaskSpec, error) {
+	if !HasStepRefs(taskSpec) {
+		return taskSpec, nil
+	}
+
+	resolved := taskSpec.DeepCopy()
+	for i, s := range resolved.Steps {
+		if s.Ref == nil {
+			continue
+		}
+		task, err := getTask(ctx, s.Ref.TaskRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Task %q referenced by step %d: %w", s.Ref.TaskRef.Name, i, err)
+		}
+		step, err := findStep(task.TaskSpec(), s.Ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("step %d references %w in Task %q", i, err, s.Ref.TaskRef.Name)
+		}
+		merged, err := v1beta1.MergeStepsWithStepTemplate(task.TaskSpec().StepTemplate, []v1beta1.Step{step})
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge step %d with the stepTemplate of Task %q: %w", i, s.Ref.TaskRef.Name, err)
+		}
+		step = merged[0]
+		if s.Name != "" {
+			step.Name = s.Name
+		}
+		resolved.Steps[i] = step
+	}
+	return resolved, nil
+}
+
+// HasStepRefs returns whether any of the Steps of the TaskSpec references a
+// Step of another Task.
+func HasStepRefs(taskSpec *v1beta1.TaskSpec) bool {
+	if taskSpec == nil {
+		return false
+	}
+	for _, s := range taskSpec.Steps {
+		if s.Ref != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// findStep returns a copy of the Step with the given name. Steps referencing
+// another Step are not followed, to avoid chains and cycles of references.
+func findStep(taskSpec v1beta1.TaskSpec, name string) (v1beta1.Step, error) {
+	for _, s := range taskSpec.Steps {
+		if s.Name != name {
+			continue
+		}
+		if s.Ref != nil {
+			return v1beta1.Step{}, fmt.Errorf("step %q which itself has a ref", name)
+		}
+		return *s.DeepCopy(), nil
+	}
+	return v1beta1.Step{}, fmt.Errorf("step %q which does not exist", name)
+}
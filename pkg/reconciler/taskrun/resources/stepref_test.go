@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/remote"
+	"github.com/tektoncd/pipeline/test/diff"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var stepRefCatalogTask = &v1beta1.Task{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "catalog",
+	},
+	Spec: v1beta1.TaskSpec{
+		StepTemplate: &v1beta1.StepTemplate{
+			Env: []corev1.EnvVar{{Name: "GIT_TERMINAL_PROMPT", Value: "0"}},
+		},
+		Steps: []v1beta1.Step{{
+			Name:   "git-clone",
+			Image:  "git",
+			Script: "git clone $(params.url)",
+		}, {
+			Name: "chained",
+			Ref: &v1beta1.StepRef{
+				Name:    "git-clone",
+				TaskRef: &v1beta1.TaskRef{Name: "catalog"},
+			},
+		}},
+	},
+}
+
+func getStepRefCatalogTask(_ context.Context, ref *v1beta1.TaskRef) (v1beta1.TaskObject, error) {
+	if ref.Name != stepRefCatalogTask.Name {
+		return nil, errors.New("not found")
+	}
+	return stepRefCatalogTask, nil
+}
+
+func TestResolveStepRefs(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		taskSpec *v1beta1.TaskSpec
+		want     *v1beta1.TaskSpec
+	}{{
+		name: "no step refs",
+		taskSpec: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{Name: "build", Image: "golang"}},
+		},
+		want: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{Name: "build", Image: "golang"}},
+		},
+	}, {
+		name: "step ref keeps the name of the referenced step",
+		taskSpec: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Ref: &v1beta1.StepRef{
+					Name:    "git-clone",
+					TaskRef: &v1beta1.TaskRef{Name: "catalog"},
+				},
+			}, {
+				Name:  "build",
+				Image: "golang",
+			}},
+		},
+		want: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Name:   "git-clone",
+				Image:  "git",
+				Script: "git clone $(params.url)",
+				Env:    []corev1.EnvVar{{Name: "GIT_TERMINAL_PROMPT", Value: "0"}},
+			}, {
+				Name:  "build",
+				Image: "golang",
+			}},
+		},
+	}, {
+		name: "step ref with a name",
+		taskSpec: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Name: "clone",
+				Ref: &v1beta1.StepRef{
+					Name:    "git-clone",
+					TaskRef: &v1beta1.TaskRef{Name: "catalog"},
+				},
+			}},
+		},
+		want: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Name:   "clone",
+				Image:  "git",
+				Script: "git clone $(params.url)",
+				Env:    []corev1.EnvVar{{Name: "GIT_TERMINAL_PROMPT", Value: "0"}},
+			}},
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ResolveStepRefs(context.Background(), tc.taskSpec, getStepRefCatalogTask)
+			if err != nil {
+				t.Fatalf("Unexpected error resolving step refs: %v", err)
+			}
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("Diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestResolveStepRefs_DoesNotModifyTaskSpec(t *testing.T) {
+	taskSpec := &v1beta1.TaskSpec{
+		Steps: []v1beta1.Step{{
+			Ref: &v1beta1.StepRef{
+				Name:    "git-clone",
+				TaskRef: &v1beta1.TaskRef{Name: "catalog"},
+			},
+		}},
+	}
+	want := taskSpec.DeepCopy()
+	if _, err := ResolveStepRefs(context.Background(), taskSpec, getStepRefCatalogTask); err != nil {
+		t.Fatalf("Unexpected error resolving step refs: %v", err)
+	}
+	if d := cmp.Diff(want, taskSpec); d != "" {
+		t.Errorf("TaskSpec was modified %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestResolveStepRefs_Error(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		ref     *v1beta1.StepRef
+		getTask GetStepTask
+	}{{
+		name: "task not found",
+		ref: &v1beta1.StepRef{
+			Name:    "git-clone",
+			TaskRef: &v1beta1.TaskRef{Name: "missing"},
+		},
+		getTask: getStepRefCatalogTask,
+	}, {
+		name: "step not found",
+		ref: &v1beta1.StepRef{
+			Name:    "missing",
+			TaskRef: &v1beta1.TaskRef{Name: "catalog"},
+		},
+		getTask: getStepRefCatalogTask,
+	}, {
+		name: "referenced step has a ref",
+		ref: &v1beta1.StepRef{
+			Name:    "chained",
+			TaskRef: &v1beta1.TaskRef{Name: "catalog"},
+		},
+		getTask: getStepRefCatalogTask,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			taskSpec := &v1beta1.TaskSpec{
+				Steps: []v1beta1.Step{{Ref: tc.ref}},
+			}
+			if _, err := ResolveStepRefs(context.Background(), taskSpec, tc.getTask); err == nil {
+				t.Error("Expected error resolving step refs but got none")
+			}
+		})
+	}
+}
+
+func TestResolveStepRefs_RequestInProgress(t *testing.T) {
+	taskSpec := &v1beta1.TaskSpec{
+		Steps: []v1beta1.Step{{
+			Ref: &v1beta1.StepRef{
+				Name:    "git-clone",
+				TaskRef: &v1beta1.TaskRef{Name: "catalog"},
+			},
+		}},
+	}
+	getTask := func(context.Context, *v1beta1.TaskRef) (v1beta1.TaskObject, error) {
+		return nil, remote.ErrorRequestInProgress
+	}
+	_, err := ResolveStepRefs(context.Background(), taskSpec, getTask)
+	if !errors.Is(err, remote.ErrorRequestInProgress) {
+		t.Errorf("Expected %v but got %v", remote.ErrorRequestInProgress, err)
+	}
+}
@@ -24,9 +24,11 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"github.com/tektoncd/pipeline/pkg/internal/crossnamespace"
 	"github.com/tektoncd/pipeline/pkg/remote"
 	"github.com/tektoncd/pipeline/pkg/remote/oci"
 	"github.com/tektoncd/pipeline/pkg/remote/resolution"
@@ -69,7 +71,9 @@ func GetTaskFuncFromTaskRun(ctx context.Context, k8s kubernetes.Interface, tekto
 			}, nil
 		}, nil
 	}
-	return GetTaskFunc(ctx, k8s, tekton, requester, taskrun, taskrun.Spec.TaskRef, taskrun.Name, taskrun.Namespace, taskrun.Spec.ServiceAccountName)
+	// The bundle and resolver parameters may reference the TaskRun's params.
+	ref := ApplyParametersToTaskRef(taskrun)
+	return GetTaskFunc(ctx, k8s, tekton, requester, taskrun, ref, taskrun.Name, taskrun.Namespace, taskrun.Spec.ServiceAccountName)
 }
 
 // GetTaskFunc is a factory function that will use the given TaskRef as context to return a valid GetTask function. It
@@ -114,6 +118,26 @@ func GetTaskFunc(ctx context.Context, k8s kubernetes.Interface, tekton clientset
 			return resolveTask(ctx, resolver, name, kind)
 		}, nil
 
+	case cfg.FeatureFlags.EnableAPIFields == config.AlphaAPIFields && tr != nil && tr.Namespace != "" && tr.Namespace != namespace:
+		// Return an inline function that checks the referenced namespace opted in to sharing
+		// and that the service account may read the task before resolving it locally.
+		local := &LocalTaskRefResolver{
+			Namespace:    tr.Namespace,
+			Kind:         kind,
+			Tektonclient: tekton,
+		}
+		return func(ctx context.Context, name string) (v1beta1.TaskObject, error) {
+			if err := crossnamespace.Authorize(ctx, k8s, crossnamespace.Reference{
+				Namespace:          namespace,
+				ServiceAccountName: saName,
+				TargetNamespace:    tr.Namespace,
+				Resource:           pipeline.TaskResource,
+				Name:               name,
+			}); err != nil {
+				return nil, err
+			}
+			return local.GetTask(ctx, name)
+		}, nil
 	default:
 		// Even if there is no task ref, we should try to return a local resolver.
 		local := &LocalTaskRefResolver{
@@ -26,6 +26,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-containerregistry/pkg/registry"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
@@ -33,11 +34,13 @@ import (
 	"github.com/tektoncd/pipeline/test"
 	"github.com/tektoncd/pipeline/test/diff"
 	"github.com/tektoncd/pipeline/test/parse"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	fakek8s "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
 	logtesting "knative.dev/pkg/logging/testing"
 )
 
@@ -535,6 +538,69 @@ func TestGetTaskFunc_RemoteResolution(t *testing.T) {
 	}
 }
 
+func TestGetTaskFunc_CrossNamespace(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.FromContextOrDefaults(ctx)
+	cfg.FeatureFlags.EnableAPIFields = config.AlphaAPIFields
+	ctx = config.ToContext(ctx, cfg)
+	catalogTask := &v1beta1.Task{
+		ObjectMeta: metav1.ObjectMeta{Name: "simple", Namespace: "catalog"},
+		Spec: v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{Image: "something"}},
+		},
+	}
+	taskRef := &v1beta1.TaskRef{Name: "simple", Namespace: "catalog"}
+
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		allowed     bool
+		wantErr     bool
+	}{{
+		name:        "namespace opted in and service account allowed",
+		annotations: map[string]string{pipeline.AllowReferencesFromAnnotationKey: "default"},
+		allowed:     true,
+	}, {
+		name:    "namespace not opted in",
+		allowed: true,
+		wantErr: true,
+	}, {
+		name:        "service account not allowed",
+		annotations: map[string]string{pipeline.AllowReferencesFromAnnotationKey: "*"},
+		wantErr:     true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			tektonclient := fake.NewSimpleClientset(catalogTask)
+			kubeclient := fakek8s.NewSimpleClientset(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "catalog", Annotations: tc.annotations},
+			})
+			kubeclient.PrependReactor("create", "subjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+				sar := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				sar.Status.Allowed = tc.allowed
+				return true, sar, nil
+			})
+
+			fn, err := resources.GetTaskFunc(ctx, kubeclient, tektonclient, nil, &v1beta1.TaskRun{}, taskRef, "", "default", "default")
+			if err != nil {
+				t.Fatalf("failed to get task fn: %s", err.Error())
+			}
+			task, err := fn(ctx, taskRef.Name)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error resolving %s/%s but saw none", taskRef.Namespace, taskRef.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to call taskfn: %s", err.Error())
+			}
+			if d := cmp.Diff(catalogTask, task); d != "" {
+				t.Error(diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
 func TestGetPipelineFunc_RemoteResolutionInvalidData(t *testing.T) {
 	ctx := context.Background()
 	cfg := config.FromContextOrDefaults(ctx)
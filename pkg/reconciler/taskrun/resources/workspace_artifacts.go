@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/artifacts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// artifactWorkspacesDir is where the copy steps mount the volumes of the artifact workspaces.
+const artifactWorkspacesDir = "/tekton/artifact-workspaces"
+
+// WorkspaceArtifactPaths are the paths of the artifact storage an artifact workspace of a
+// TaskRun is copied from and to. The PipelineRun controller sets them, by workspace name, in
+// the workspace artifacts annotation of the TaskRuns it creates.
+type WorkspaceArtifactPaths struct {
+	// From is the list of paths in the artifact storage which are copied, in order, into
+	// the workspace before the steps of the TaskRun run.
+	From []string `json:"from,omitempty"`
+	// To is the path in the artifact storage which the content of the workspace is copied
+	// to once the steps of the TaskRun ran.
+	To string `json:"to,omitempty"`
+}
+
+// AddWorkspaceArtifacts adds the steps copying the artifact workspaces of the TaskRun from the
+// artifact storage before the steps of the Task, and to the artifact storage after them.
+// The copy steps mount the subPath of the workspace, if any, so only the content the Task sees
+// is copied. The paths are read from the workspace artifacts annotation of the TaskRun, and must
+// be under the artifact storage of the PipelineRun which owns it.
+func AddWorkspaceArtifacts(
+	ctx context.Context,
+	kubeclient kubernetes.Interface,
+	images pipeline.Images,
+	taskSpec *v1beta1.TaskSpec,
+	taskRun *v1beta1.TaskRun,
+	workspaceVolumes map[string]corev1.Volume,
+) (*v1beta1.TaskSpec, error) {
+	var bindings []v1beta1.WorkspaceBinding
+	for _, wb := range taskRun.Spec.Workspaces {
+		if wb.Artifact != nil {
+			bindings = append(bindings, wb)
+		}
+	}
+	if len(bindings) == 0 {
+		return taskSpec, nil
+	}
+
+	owner := metav1.GetControllerOf(taskRun)
+	if owner == nil || owner.Kind != pipeline.PipelineRunControllerName {
+		return nil, fmt.Errorf("TaskRun validation failed. Artifact workspaces can only be bound by the TaskRuns of a PipelineRun")
+	}
+	as := artifacts.GetArtifactStorage(ctx, images, taskRun.GetPipelineRunPVCName(), kubeclient)
+	if as.GetType() == pipeline.ArtifactStoragePVCType {
+		return nil, fmt.Errorf("TaskRun validation failed. Artifact workspaces require a bucket to be configured as artifact storage")
+	}
+	pr := &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: owner.Name, Namespace: taskRun.Namespace}}
+	paths, err := getWorkspaceArtifactPaths(taskRun, WorkspaceArtifactsBasePath(as.StorageBasePath(pr)))
+	if err != nil {
+		return nil, fmt.Errorf("TaskRun validation failed. %w", err)
+	}
+
+	taskSpec = taskSpec.DeepCopy()
+	var downloadSteps, uploadSteps []v1beta1.Step
+	for _, wb := range bindings {
+		volumeMount := corev1.VolumeMount{
+			Name:      workspaceVolumes[wb.Name].Name,
+			MountPath: filepath.Join(artifactWorkspacesDir, wb.Name),
+			SubPath:   wb.SubPath,
+		}
+		p := paths[wb.Name]
+		var steps []v1beta1.Step
+		for _, from := range p.From {
+			steps = append(steps, as.GetCopyFromStorageToSteps(wb.Name, from, volumeMount.MountPath)...)
+		}
+		downloadSteps = append(downloadSteps, addArtifactVolumeMount(steps, volumeMount)...)
+		if p.To != "" {
+			steps = as.GetCopyToStorageFromSteps(wb.Name, volumeMount.MountPath, p.To)
+			uploadSteps = append(uploadSteps, addArtifactVolumeMount(steps, volumeMount)...)
+		}
+	}
+	taskSpec.Steps = append(append(downloadSteps, taskSpec.Steps...), uploadSteps...)
+	taskSpec.Volumes = appendNewSecretsVolumes(taskSpec.Volumes, as.GetSecretsVolumes()...)
+	return taskSpec, nil
+}
+
+// WorkspaceArtifactsBasePath returns the path of the artifact storage the artifact workspaces of
+// the TaskRuns of a PipelineRun are copied to, given its storage base path.
+func WorkspaceArtifactsBasePath(storageBasePath string) string {
+	return filepath.Join(storageBasePath, "workspaces")
+}
+
+// getWorkspaceArtifactPaths returns the paths set in the workspace artifacts annotation of the
+// TaskRun, after checking that they are under basePath.
+func getWorkspaceArtifactPaths(taskRun *v1beta1.TaskRun, basePath string) (map[string]WorkspaceArtifactPaths, error) {
+	paths := map[string]WorkspaceArtifactPaths{}
+	annotation, ok := taskRun.Annotations[pipeline.WorkspaceArtifactsAnnotationKey]
+	if !ok {
+		return paths, nil
+	}
+	if err := json.Unmarshal([]byte(annotation), &paths); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", pipeline.WorkspaceArtifactsAnnotationKey, err)
+	}
+	for name, p := range paths {
+		for _, path := range p.From {
+			if err := validateWorkspaceArtifactPath(name, path, basePath); err != nil {
+				return nil, err
+			}
+		}
+		if p.To != "" {
+			if err := validateWorkspaceArtifactPath(name, p.To, basePath); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return paths, nil
+}
+
+// validateWorkspaceArtifactPath checks that path is a clean path under basePath, so that
+// it cannot escape it with "..".
+func validateWorkspaceArtifactPath(workspace, path, basePath string) error {
+	if filepath.Clean(path) != path || !strings.HasPrefix(path, basePath+"/") {
+		return fmt.Errorf("path %q of artifact workspace %q is not under %s", path, workspace, basePath)
+	}
+	return nil
+}
+
+// addArtifactVolumeMount mounts the volume of the artifact workspace into the copy steps.
+func addArtifactVolumeMount(steps []v1beta1.Step, volumeMount corev1.VolumeMount) []v1beta1.Step {
+	for i := range steps {
+		steps[i].VolumeMounts = append(steps[i].VolumeMounts, volumeMount)
+	}
+	return steps
+}
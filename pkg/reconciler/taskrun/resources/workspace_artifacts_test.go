@@ -0,0 +1,284 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/test/diff"
+	"github.com/tektoncd/pipeline/test/names"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakek8s "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/ptr"
+)
+
+func TestAddWorkspaceArtifacts(t *testing.T) {
+	taskSpec := &v1beta1.TaskSpec{
+		Workspaces: []v1beta1.WorkspaceDeclaration{{Name: "source"}, {Name: "cache"}},
+		Steps: []v1beta1.Step{{
+			Name:  "build",
+			Image: "busybox",
+		}},
+	}
+	makeTaskRun := func(subPath string) *v1beta1.TaskRun {
+		return &v1beta1.TaskRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pr-build",
+				Namespace: "foo",
+				OwnerReferences: []metav1.OwnerReference{{
+					Kind:       "PipelineRun",
+					Name:       "pr",
+					Controller: ptr.Bool(true),
+				}},
+				Annotations: map[string]string{
+					pipeline.WorkspaceArtifactsAnnotationKey: `{"source":{"from":["pr-foo-bucket/workspaces/source/pr-clone"],"to":"pr-foo-bucket/workspaces/source/pr-build"}}`,
+				},
+			},
+			Spec: v1beta1.TaskRunSpec{
+				Workspaces: []v1beta1.WorkspaceBinding{{
+					Name:     "source",
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+					SubPath:  subPath,
+					Artifact: &v1beta1.WorkspaceArtifact{},
+				}, {
+					Name:     "cache",
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				}},
+			},
+		}
+	}
+	workspaceVolumes := map[string]corev1.Volume{
+		"source": {Name: "ws-source", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		"cache":  {Name: "ws-cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+	s3EnvVars := []corev1.EnvVar{{
+		Name:  "AWS_DEFAULT_REGION",
+		Value: "us-east-1",
+	}, {
+		Name: "AWS_ACCESS_KEY_ID",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "minio-credentials"},
+			Key:                  "AWS_ACCESS_KEY_ID",
+		}},
+	}, {
+		Name: "AWS_SECRET_ACCESS_KEY",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "minio-credentials"},
+			Key:                  "AWS_SECRET_ACCESS_KEY",
+		}},
+	}}
+	workspaceMount := corev1.VolumeMount{Name: "ws-source", MountPath: "/tekton/artifact-workspaces/source"}
+	subPathMount := corev1.VolumeMount{Name: "ws-source", MountPath: "/tekton/artifact-workspaces/source", SubPath: "src"}
+	bucketConfig := &config.Config{
+		ArtifactBucket: &config.ArtifactBucket{Location: "gs://fake-bucket"},
+	}
+
+	for _, tc := range []struct {
+		desc      string
+		config    *config.Config
+		taskRun   *v1beta1.TaskRun
+		wantSteps []v1beta1.Step
+	}{{
+		desc:    "bucket storage",
+		config:  bucketConfig,
+		taskRun: makeTaskRun(""),
+		wantSteps: []v1beta1.Step{{
+			Name:         "artifact-dest-mkdir-source-9l9zj",
+			Image:        "busybox",
+			Command:      []string{"mkdir", "-p", "/tekton/artifact-workspaces/source"},
+			VolumeMounts: []corev1.VolumeMount{workspaceMount},
+		}, {
+			Name:         "artifact-copy-from-source-mz4c7",
+			Image:        "gcr.io/google.com/cloudsdktool/cloud-sdk",
+			Command:      []string{"gsutil"},
+			Args:         []string{"cp", "-P", "-r", "gs://fake-bucket/pr-foo-bucket/workspaces/source/pr-clone/*", "/tekton/artifact-workspaces/source"},
+			VolumeMounts: []corev1.VolumeMount{workspaceMount},
+		}, {
+			Name:  "build",
+			Image: "busybox",
+		}, {
+			Name:         "artifact-copy-to-source-mssqb",
+			Image:        "gcr.io/google.com/cloudsdktool/cloud-sdk",
+			Command:      []string{"gsutil"},
+			Args:         []string{"cp", "-P", "-r", "/tekton/artifact-workspaces/source", "gs://fake-bucket/pr-foo-bucket/workspaces/source/pr-build"},
+			VolumeMounts: []corev1.VolumeMount{workspaceMount},
+		}},
+	}, {
+		desc:    "bucket storage with subPath",
+		config:  bucketConfig,
+		taskRun: makeTaskRun("src"),
+		wantSteps: []v1beta1.Step{{
+			Name:         "artifact-dest-mkdir-source-9l9zj",
+			Image:        "busybox",
+			Command:      []string{"mkdir", "-p", "/tekton/artifact-workspaces/source"},
+			VolumeMounts: []corev1.VolumeMount{subPathMount},
+		}, {
+			Name:         "artifact-copy-from-source-mz4c7",
+			Image:        "gcr.io/google.com/cloudsdktool/cloud-sdk",
+			Command:      []string{"gsutil"},
+			Args:         []string{"cp", "-P", "-r", "gs://fake-bucket/pr-foo-bucket/workspaces/source/pr-clone/*", "/tekton/artifact-workspaces/source"},
+			VolumeMounts: []corev1.VolumeMount{subPathMount},
+		}, {
+			Name:  "build",
+			Image: "busybox",
+		}, {
+			Name:         "artifact-copy-to-source-mssqb",
+			Image:        "gcr.io/google.com/cloudsdktool/cloud-sdk",
+			Command:      []string{"gsutil"},
+			Args:         []string{"cp", "-P", "-r", "/tekton/artifact-workspaces/source", "gs://fake-bucket/pr-foo-bucket/workspaces/source/pr-build"},
+			VolumeMounts: []corev1.VolumeMount{subPathMount},
+		}},
+	}, {
+		desc: "s3 bucket storage",
+		config: &config.Config{
+			ArtifactBucket: &config.ArtifactBucket{
+				Location:                "s3://fake-bucket",
+				S3Endpoint:              "http://minio.minio.svc:9000",
+				S3Region:                "us-east-1",
+				S3CredentialsSecretName: "minio-credentials",
+			},
+		},
+		taskRun: makeTaskRun(""),
+		wantSteps: []v1beta1.Step{{
+			Name:         "artifact-dest-mkdir-source-9l9zj",
+			Image:        "busybox",
+			Command:      []string{"mkdir", "-p", "/tekton/artifact-workspaces/source"},
+			VolumeMounts: []corev1.VolumeMount{workspaceMount},
+		}, {
+			Name:         "artifact-copy-from-source-mz4c7",
+			Image:        "docker.io/amazon/aws-cli",
+			Command:      []string{"aws"},
+			Args:         []string{"s3", "cp", "--recursive", "--only-show-errors", "--endpoint-url", "http://minio.minio.svc:9000", "s3://fake-bucket/pr-foo-bucket/workspaces/source/pr-clone", "/tekton/artifact-workspaces/source"},
+			Env:          s3EnvVars,
+			VolumeMounts: []corev1.VolumeMount{workspaceMount},
+		}, {
+			Name:  "build",
+			Image: "busybox",
+		}, {
+			Name:         "artifact-copy-to-source-mssqb",
+			Image:        "docker.io/amazon/aws-cli",
+			Command:      []string{"aws"},
+			Args:         []string{"s3", "cp", "--recursive", "--only-show-errors", "--endpoint-url", "http://minio.minio.svc:9000", "/tekton/artifact-workspaces/source", "s3://fake-bucket/pr-foo-bucket/workspaces/source/pr-build"},
+			Env:          s3EnvVars,
+			VolumeMounts: []corev1.VolumeMount{workspaceMount},
+		}},
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			names.TestingSeed()
+			ctx := config.ToContext(context.Background(), tc.config)
+			got, err := AddWorkspaceArtifacts(ctx, fakek8s.NewSimpleClientset(), images, taskSpec, tc.taskRun, workspaceVolumes)
+			if err != nil {
+				t.Fatalf("AddWorkspaceArtifacts() = %v", err)
+			}
+			if d := cmp.Diff(tc.wantSteps, got.Steps); d != "" {
+				t.Errorf("Steps diff %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestAddWorkspaceArtifacts_Invalid(t *testing.T) {
+	taskSpec := &v1beta1.TaskSpec{
+		Workspaces: []v1beta1.WorkspaceDeclaration{{Name: "source"}},
+		Steps:      []v1beta1.Step{{Name: "build", Image: "busybox"}},
+	}
+	makeTaskRun := func(owner *metav1.OwnerReference, annotation string) *v1beta1.TaskRun {
+		tr := &v1beta1.TaskRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pr-build",
+				Namespace:   "foo",
+				Annotations: map[string]string{pipeline.WorkspaceArtifactsAnnotationKey: annotation},
+			},
+			Spec: v1beta1.TaskRunSpec{
+				Workspaces: []v1beta1.WorkspaceBinding{{
+					Name:     "source",
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+					Artifact: &v1beta1.WorkspaceArtifact{},
+				}},
+			},
+		}
+		if owner != nil {
+			tr.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		return tr
+	}
+	owner := &metav1.OwnerReference{Kind: "PipelineRun", Name: "pr", Controller: ptr.Bool(true)}
+	bucketConfig := &config.Config{
+		ArtifactBucket: &config.ArtifactBucket{Location: "gs://fake-bucket"},
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		config  *config.Config
+		taskRun *v1beta1.TaskRun
+	}{{
+		desc:    "pvc storage",
+		config:  &config.Config{},
+		taskRun: makeTaskRun(owner, `{"source":{"to":"/pvc/workspaces/source/pr-build"}}`),
+	}, {
+		desc:    "not created by a PipelineRun",
+		config:  bucketConfig,
+		taskRun: makeTaskRun(nil, `{"source":{"to":"pr-foo-bucket/workspaces/source/pr-build"}}`),
+	}, {
+		desc:    "path of another PipelineRun",
+		config:  bucketConfig,
+		taskRun: makeTaskRun(owner, `{"source":{"from":["other-foo-bucket/workspaces/source/other-clone"]}}`),
+	}, {
+		desc:    "path escaping the PipelineRun",
+		config:  bucketConfig,
+		taskRun: makeTaskRun(owner, `{"source":{"to":"pr-foo-bucket/workspaces/../../other-foo-bucket"}}`),
+	}, {
+		desc:    "invalid annotation",
+		config:  bucketConfig,
+		taskRun: makeTaskRun(owner, `source`),
+	}} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := config.ToContext(context.Background(), tc.config)
+			if _, err := AddWorkspaceArtifacts(ctx, fakek8s.NewSimpleClientset(), images, taskSpec, tc.taskRun, nil); err == nil {
+				t.Errorf("expected AddWorkspaceArtifacts() to fail")
+			}
+		})
+	}
+}
+
+func TestAddWorkspaceArtifacts_NoArtifactWorkspace(t *testing.T) {
+	taskSpec := &v1beta1.TaskSpec{
+		Workspaces: []v1beta1.WorkspaceDeclaration{{Name: "source"}},
+		Steps:      []v1beta1.Step{{Name: "build", Image: "busybox"}},
+	}
+	taskRun := &v1beta1.TaskRun{
+		Spec: v1beta1.TaskRunSpec{
+			Workspaces: []v1beta1.WorkspaceBinding{{
+				Name:     "source",
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			}},
+		},
+	}
+	got, err := AddWorkspaceArtifacts(context.Background(), fakek8s.NewSimpleClientset(), images, taskSpec, taskRun, nil)
+	if err != nil {
+		t.Fatalf("AddWorkspaceArtifacts() = %v", err)
+	}
+	if d := cmp.Diff(taskSpec, got); d != "" {
+		t.Errorf("TaskSpec diff %s", diff.PrintWantGot(d))
+	}
+}
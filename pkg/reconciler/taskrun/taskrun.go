@@ -20,11 +20,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
@@ -40,6 +42,7 @@ import (
 	podconvert "github.com/tektoncd/pipeline/pkg/pod"
 	tknreconciler "github.com/tektoncd/pipeline/pkg/reconciler"
 	"github.com/tektoncd/pipeline/pkg/reconciler/events"
+	"github.com/tektoncd/pipeline/pkg/reconciler/events/cache"
 	"github.com/tektoncd/pipeline/pkg/reconciler/events/cloudevent"
 	"github.com/tektoncd/pipeline/pkg/reconciler/taskrun/resources"
 	"github.com/tektoncd/pipeline/pkg/reconciler/volumeclaim"
@@ -48,6 +51,7 @@ import (
 	_ "github.com/tektoncd/pipeline/pkg/taskrunmetrics/fake" // Make sure the taskrunmetrics are setup
 	"github.com/tektoncd/pipeline/pkg/workspace"
 	resolution "github.com/tektoncd/resolution/pkg/resource"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -78,10 +82,12 @@ type Reconciler struct {
 	limitrangeLister    corev1Listers.LimitRangeLister
 	podLister           corev1Listers.PodLister
 	cloudEventClient    cloudevent.CEClient
+	cacheClient         *lru.Cache
 	entrypointCache     podconvert.EntrypointCache
 	metrics             *taskrunmetrics.Recorder
 	pvcHandler          volumeclaim.PvcHandler
 	resolutionRequester resolution.Requester
+	tracerProvider      trace.TracerProvider
 }
 
 // Check that our Reconciler implements taskrunreconciler.Interface
@@ -95,9 +101,16 @@ var (
 func (c *Reconciler) ReconcileKind(ctx context.Context, tr *v1beta1.TaskRun) pkgreconciler.Event {
 	logger := logging.FromContext(ctx)
 	ctx = cloudevent.ToContext(ctx, c.cloudEventClient)
+	ctx = cloudevent.ClockToContext(ctx, c.Clock)
+	ctx = cache.ToContext(ctx, c.cacheClient)
+	ctx, runSpan := initTracing(ctx, c.tracerProvider, tr)
+	defer runSpan.End()
+	ctx, span := c.tracerProvider.Tracer(TracerName).Start(ctx, "TaskRun:ReconcileKind", trace.WithAttributes(taskRunAttributes(tr)...))
+	defer span.End()
 
 	// Read the initial condition
 	before := tr.Status.GetCondition(apis.ConditionSucceeded)
+	beforeSteps := append([]v1beta1.StepState(nil), tr.Status.Steps...)
 
 	// If the TaskRun is just starting, this will also set the starttime,
 	// from which the timeout will immediately begin counting down.
@@ -135,7 +148,31 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, tr *v1beta1.TaskRun) pkg
 			return cloudEventErr
 		}
 
-		if err := c.stopSidecars(ctx, tr); err != nil {
+		// The sidecars of a TaskRun cancelled gracefully are stopped once its
+		// steps terminated. Its pod is deleted if they did not terminate
+		// within the grace period.
+		stepsTerminated, err := c.syncGracefullyCancelledSteps(ctx, tr)
+		if err != nil {
+			return err
+		}
+		if !stepsTerminated {
+			waited := c.Clock.Since(tr.Status.CompletionTime.Time)
+			gracePeriod := podconvert.CancelGracePeriod(tr.Status.TaskSpec)
+			if waited < gracePeriod {
+				if err := c.finishReconcileUpdateEmitEvents(ctx, tr, before, nil); err != nil {
+					return err
+				}
+				// Check again once the grace period is over.
+				return controller.NewRequeueAfter(gracePeriod - waited)
+			}
+			logger.Warnf("Deleting pod %q of cancelled TaskRun %q, whose steps did not terminate within %s", tr.Status.PodName, tr.Name, gracePeriod)
+			err := c.KubeClientSet.CoreV1().Pods(tr.Namespace).Delete(ctx, tr.Status.PodName, metav1.DeleteOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				logger.Infof("Failed to terminate pod: %v", err)
+				return err
+			}
+			markStepsTerminated(tr, v1beta1.TaskRunReasonCancelled, *tr.Status.CompletionTime)
+		} else if err := c.stopSidecars(ctx, tr); err != nil {
 			return err
 		}
 
@@ -154,6 +191,9 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, tr *v1beta1.TaskRun) pkg
 	if tr.HasTimedOut(ctx, c.Clock) {
 		message := fmt.Sprintf("TaskRun %q failed to finish within %q", tr.Name, tr.GetTimeout(ctx))
 		err := c.failTaskRun(ctx, tr, v1beta1.TaskRunReasonTimedOut, message)
+		if err == nil {
+			events.EmitPipelineTaskTimedOut(ctx, tr, message)
+		}
 		return c.finishReconcileUpdateEmitEvents(ctx, tr, before, err)
 	}
 
@@ -176,11 +216,18 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, tr *v1beta1.TaskRun) pkg
 	// Store the condition before reconcile
 	before = tr.Status.GetCondition(apis.ConditionSucceeded)
 
+	// Reuse the results of a previous TaskRun with the same cache key, when
+	// caching is enabled and there is one, instead of running the Task.
+	if c.reuseCachedResults(ctx, tr) {
+		return c.finishReconcileUpdateEmitEvents(ctx, tr, before, nil)
+	}
+
 	// Reconcile this copy of the task run and then write back any status
 	// updates regardless of whether the reconciliation errored out.
 	if err = c.reconcile(ctx, tr, rtr); err != nil {
 		logger.Errorf("Reconcile: %v", err.Error())
 	}
+	events.EmitStepEvents(ctx, tr, beforeSteps)
 
 	// Emit events (only when ConditionSucceeded was changed)
 	if err = c.finishReconcileUpdateEmitEvents(ctx, tr, before, err); err != nil {
@@ -245,11 +292,12 @@ func (c *Reconciler) stopSidecars(ctx context.Context, tr *v1beta1.TaskRun) erro
 		return nil
 	}
 
-	// do not continue if the TaskRun was canceled or timed out as this caused the pod to be deleted in failTaskRun
+	// do not continue if the TaskRun was canceled or timed out as this caused the pod to be deleted in failTaskRun,
+	// unless it was cancelled gracefully
 	condition := tr.Status.GetCondition(apis.ConditionSucceeded)
 	if condition != nil {
 		reason := v1beta1.TaskRunReason(condition.Reason)
-		if reason == v1beta1.TaskRunReasonCancelled || reason == v1beta1.TaskRunReasonTimedOut {
+		if (reason == v1beta1.TaskRunReasonCancelled && !isGracefulCancellationEnabled(ctx)) || reason == v1beta1.TaskRunReasonTimedOut {
 			return nil
 		}
 	}
@@ -273,11 +321,51 @@ func (c *Reconciler) stopSidecars(ctx context.Context, tr *v1beta1.TaskRun) erro
 		return controller.NewPermanentError(err)
 	} else if err != nil {
 		logger.Errorf("Error stopping sidecars for TaskRun %q: %v", tr.Name, err)
+		events.EmitSidecarsStopFailed(ctx, tr, err)
 		tr.Status.MarkResourceFailed(podconvert.ReasonFailedResolution, err)
 	}
 	return nil
 }
 
+// syncGracefullyCancelledSteps updates the step and sidecar states of a
+// TaskRun cancelled gracefully from its pod, whose steps keep running until
+// they are terminated by their entrypoint. It returns whether the steps of
+// the TaskRun terminated, which is always the case for TaskRuns which were
+// not cancelled gracefully.
+func (c *Reconciler) syncGracefullyCancelledSteps(ctx context.Context, tr *v1beta1.TaskRun) (bool, error) {
+	logger := logging.FromContext(ctx)
+	if !tr.IsCancelled() || !isGracefulCancellationEnabled(ctx) || tr.Status.PodName == "" {
+		return true, nil
+	}
+
+	pod, err := c.podLister.Pods(tr.Namespace).Get(tr.Status.PodName)
+	if k8serrors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		logger.Errorf("Error getting pod %q to update the steps of cancelled TaskRun %q: %v", tr.Status.PodName, tr.Name, err)
+		return false, err
+	}
+
+	status, err := podconvert.MakeTaskRunStatus(logger, *tr.DeepCopy(), pod)
+	if err != nil {
+		logger.Errorf("Error reading the steps of cancelled TaskRun %q from pod %q: %v", tr.Name, pod.Name, err)
+	}
+	for _, step := range status.Steps {
+		if step.Terminated == nil {
+			return false, nil
+		}
+	}
+	tr.Status.Steps = status.Steps
+	tr.Status.Sidecars = status.Sidecars
+	return true, nil
+}
+
+// isGracefulCancellationEnabled returns whether cancelled TaskRuns signal
+// their steps to terminate instead of deleting their pod.
+func isGracefulCancellationEnabled(ctx context.Context) bool {
+	return config.FromContextOrDefaults(ctx).FeatureFlags.EnableGracefulCancellation
+}
+
 func (c *Reconciler) finishReconcileUpdateEmitEvents(ctx context.Context, tr *v1beta1.TaskRun, beforeCondition *apis.Condition, previousError error) error {
 	logger := logging.FromContext(ctx)
 
@@ -285,6 +373,9 @@ func (c *Reconciler) finishReconcileUpdateEmitEvents(ctx context.Context, tr *v1
 
 	// Send k8s events and cloud events (when configured)
 	events.Emit(ctx, beforeCondition, afterCondition, tr)
+	if tr.IsDone() && (beforeCondition == nil || beforeCondition.IsUnknown()) {
+		endTracing(ctx, c.tracerProvider, tr, c.Clock.Now())
+	}
 
 	_, err := c.updateLabelsAndAnnotations(ctx, tr)
 	if err != nil {
@@ -308,6 +399,8 @@ func (c *Reconciler) finishReconcileUpdateEmitEvents(ctx context.Context, tr *v1
 // them in the TaskRun.Status so we don't need to re-run `prepare` at every
 // reconcile (see https://github.com/tektoncd/pipeline/issues/2473).
 func (c *Reconciler) prepare(ctx context.Context, tr *v1beta1.TaskRun) (*v1beta1.TaskSpec, *resources.ResolvedTaskResources, error) {
+	ctx, span := c.tracerProvider.Tracer(TracerName).Start(ctx, "prepare")
+	defer span.End()
 	logger := logging.FromContext(ctx)
 	tr.SetDefaults(ctx)
 
@@ -319,6 +412,13 @@ func (c *Reconciler) prepare(ctx context.Context, tr *v1beta1.TaskRun) (*v1beta1
 	}
 
 	taskMeta, taskSpec, err := resources.GetTaskData(ctx, tr, getTaskfunc)
+	hasStepRefs := err == nil && resources.HasStepRefs(taskSpec)
+	if hasStepRefs {
+		// Inline the Steps referenced from other Tasks so that they are
+		// stored along with the rest of the TaskSpec.
+		getStepTask := resources.GetStepTaskFuncFromTaskRun(c.KubeClientSet, c.PipelineClientSet, c.resolutionRequester, tr)
+		taskSpec, err = resources.ResolveStepRefs(ctx, taskSpec, getStepTask)
+	}
 	switch {
 	case errors.Is(err, remote.ErrorRequestInProgress):
 		message := fmt.Sprintf("TaskRun %s/%s awaiting remote resource", tr.Namespace, tr.Name)
@@ -338,6 +438,16 @@ func (c *Reconciler) prepare(ctx context.Context, tr *v1beta1.TaskRun) (*v1beta1
 		}
 	}
 
+	// The variables used by the Steps referenced from other Tasks can only be
+	// validated once they are inlined.
+	if hasStepRefs {
+		if err := v1beta1.ValidateResolvedStepRefs(ctx, taskSpec); err != nil {
+			logger.Errorf("TaskRun %q referenced steps are invalid: %v", tr.Name, err)
+			tr.Status.MarkResourceFailed(podconvert.ReasonFailedValidation, err)
+			return nil, nil, controller.NewPermanentError(err)
+		}
+	}
+
 	inputs := []v1beta1.TaskResourceBinding{}
 	outputs := []v1beta1.TaskResourceBinding{}
 	if tr.Spec.Resources != nil {
@@ -378,13 +488,17 @@ func (c *Reconciler) prepare(ctx context.Context, tr *v1beta1.TaskRun) (*v1beta1
 		return nil, nil, controller.NewPermanentError(err)
 	}
 
-	if err := workspace.ValidateBindings(taskSpec.Workspaces, tr.Spec.Workspaces); err != nil {
+	if err := workspace.ValidateBindings(ctx, taskSpec.Workspaces, tr.Spec.Workspaces); err != nil {
 		logger.Errorf("TaskRun %q workspaces are invalid: %v", tr.Name, err)
 		tr.Status.MarkResourceFailed(podconvert.ReasonFailedValidation, err)
 		return nil, nil, controller.NewPermanentError(err)
 	}
 
-	if _, usesAssistant := tr.Annotations[workspace.AnnotationAffinityAssistantName]; usesAssistant {
+	// An Affinity Assistant per PipelineRun mounts all the PersistentVolumeClaims of the PipelineRun,
+	// so the TaskRun can use several of them without its pod being unschedulable.
+	_, usesAssistant := tr.Annotations[workspace.AnnotationAffinityAssistantName]
+	_, usesAssistantPerPipelineRun := tr.Annotations[workspace.AnnotationAffinityAssistantMode]
+	if usesAssistant && !usesAssistantPerPipelineRun {
 		if err := workspace.ValidateOnlyOnePVCIsUsed(tr.Spec.Workspaces); err != nil {
 			logger.Errorf("TaskRun %q workspaces incompatible with Affinity Assistant: %v", tr.Name, err)
 			tr.Status.MarkResourceFailed(podconvert.ReasonFailedValidation, err)
@@ -481,7 +595,7 @@ func (c *Reconciler) reconcile(ctx context.Context, tr *v1beta1.TaskRun, rtr *re
 		recorder.Eventf(tr, corev1.EventTypeWarning, podconvert.ReasonExceededNodeResources, "Insufficient resources to schedule pod %q", pod.Name)
 	}
 
-	if podconvert.SidecarsReady(pod.Status) {
+	if podconvert.SidecarsReady(pod.Status, ts.Sidecars) {
 		if err := podconvert.UpdateReady(ctx, c.KubeClientSet, *pod); err != nil {
 			return err
 		}
@@ -606,16 +720,53 @@ func (c *Reconciler) failTaskRun(ctx context.Context, tr *v1beta1.TaskRun, reaso
 		return nil
 	}
 
+	// A TaskRun cancelled gracefully signals its steps to terminate, which
+	// lets the running step clean up, instead of deleting its pod. A pod which
+	// did not start yet is deleted, since none of its steps can clean up.
+	if reason == v1beta1.TaskRunReasonCancelled && isGracefulCancellationEnabled(ctx) {
+		pod, err := c.podLister.Pods(tr.Namespace).Get(tr.Status.PodName)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			logger.Errorf("Error getting pod %q of cancelled TaskRun %q: %v", tr.Status.PodName, tr.Name, err)
+			return err
+		}
+		if err == nil && pod.Status.Phase != corev1.PodPending {
+			err := podconvert.CancelPod(ctx, c.KubeClientSet, tr.Namespace, tr.Status.PodName)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				logger.Infof("Failed to cancel pod: %v", err)
+				return err
+			}
+			markStepsTerminated(tr, reason, completionTime)
+			return nil
+		}
+	}
+
 	// tr.Status.PodName will be empty if the pod was never successfully created. This condition
 	// can be reached, for example, by the pod never being schedulable due to limits imposed by
 	// a namespace's ResourceQuota.
-	err := c.KubeClientSet.CoreV1().Pods(tr.Namespace).Delete(ctx, tr.Status.PodName, metav1.DeleteOptions{})
+	// A TaskRun which timed out with a grace period gives its pod that long to
+	// terminate, so that steps can clean up when they are sent a SIGTERM. The
+	// grace period is rounded up to whole seconds: a zero grace period would
+	// force delete the pod.
+	deleteOptions := metav1.DeleteOptions{}
+	if reason == v1beta1.TaskRunReasonTimedOut && tr.Spec.GracePeriod != nil {
+		gracePeriodSeconds := int64(math.Ceil(tr.Spec.GracePeriod.Seconds()))
+		deleteOptions.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	err := c.KubeClientSet.CoreV1().Pods(tr.Namespace).Delete(ctx, tr.Status.PodName, deleteOptions)
 	if err != nil && !k8serrors.IsNotFound(err) {
 		logger.Infof("Failed to terminate pod: %v", err)
 		return err
 	}
 
 	// Update step states for TaskRun on TaskRun object since pod has been deleted for cancel or timeout
+	markStepsTerminated(tr, reason, completionTime)
+
+	return nil
+}
+
+// markStepsTerminated marks the steps of a TaskRun which did not terminate yet
+// as terminated with the provided Reason.
+func markStepsTerminated(tr *v1beta1.TaskRun, reason v1beta1.TaskRunReason, completionTime metav1.Time) {
 	for i, step := range tr.Status.Steps {
 		// If running, include StartedAt for when step began running
 		if step.Running != nil {
@@ -639,13 +790,13 @@ func (c *Reconciler) failTaskRun(ctx context.Context, tr *v1beta1.TaskRun, reaso
 			tr.Status.Steps[i] = step
 		}
 	}
-
-	return nil
 }
 
 // createPod creates a Pod based on the Task's configuration, with pvcName as a volumeMount
 // TODO(dibyom): Refactor resource setup/substitution logic to its own function in the resources package
 func (c *Reconciler) createPod(ctx context.Context, ts *v1beta1.TaskSpec, tr *v1beta1.TaskRun, rtr *resources.ResolvedTaskResources) (*corev1.Pod, error) {
+	ctx, span := c.tracerProvider.Tracer(TracerName).Start(ctx, "createPod")
+	defer span.End()
 	logger := logging.FromContext(ctx)
 	inputResources, err := resourceImplBinding(rtr.Inputs, c.Images)
 	if err != nil {
@@ -698,6 +849,13 @@ func (c *Reconciler) createPod(ctx context.Context, ts *v1beta1.TaskSpec, tr *v1
 		return nil, err
 	}
 
+	// Copy the artifact workspaces from and to the artifact storage
+	ts, err = resources.AddWorkspaceArtifacts(ctx, c.KubeClientSet, c.Images, ts, tr, workspaceVolumes)
+	if err != nil {
+		logger.Errorf("Failed to create a pod for taskrun: %s due to workspace artifacts error %v", tr.Name, err)
+		return nil, err
+	}
+
 	// Apply path substitutions for the legacy credentials helper (aka "creds-init")
 	ts = resources.ApplyCredentialsPath(ts, pipeline.CredsDir)
 
@@ -714,7 +872,7 @@ func (c *Reconciler) createPod(ctx context.Context, ts *v1beta1.TaskSpec, tr *v1
 		return nil, fmt.Errorf("translating TaskSpec to Pod: %w", err)
 	}
 
-	pod, err = c.KubeClientSet.CoreV1().Pods(tr.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	pod, err = podconvert.CreatePod(ctx, c.KubeClientSet, pod)
 	if err == nil && willOverwritePodSetAffinity(tr) {
 		if recorder := controller.GetEventRecorder(ctx); recorder != nil {
 			recorder.Eventf(tr, corev1.EventTypeWarning, "PodAffinityOverwrite", "Pod template affinity is overwritten by affinity assistant for pod %q", pod.Name)
@@ -769,7 +927,8 @@ func resourceImplBinding(resources map[string]*resourcev1alpha1.PipelineResource
 // terminated by nop image
 func updateStoppedSidecarStatus(pod *corev1.Pod, tr *v1beta1.TaskRun) error {
 	tr.Status.Sidecars = []v1beta1.SidecarState{}
-	for _, s := range pod.Status.ContainerStatuses {
+	// Native sidecar containers are not stopped with the nop image, but by the kubelet.
+	for _, s := range append(pod.Status.ContainerStatuses, podconvert.NativeSidecarStatuses(pod.Status)...) {
 		if !podconvert.IsContainerStep(s.Name) {
 			var sidecarState corev1.ContainerState
 			if s.LastTerminationState.Terminated != nil {
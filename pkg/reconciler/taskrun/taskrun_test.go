@@ -49,6 +49,7 @@ import (
 	"github.com/tektoncd/pipeline/test/names"
 	"github.com/tektoncd/pipeline/test/parse"
 	resolutioncommon "github.com/tektoncd/resolution/pkg/common"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -89,6 +90,7 @@ var (
 		KubeconfigWriterImage:    "override-with-kubeconfig-writer:latest",
 		ShellImage:               "busybox",
 		GsutilImage:              "gcr.io/google.com/cloudsdktool/cloud-sdk",
+		S3Image:                  "docker.io/amazon/aws-cli",
 		PRImage:                  "override-with-pr:latest",
 		ImageDigestExporterImage: "override-with-imagedigest-exporter-image:latest",
 	}
@@ -454,7 +456,7 @@ func getRunName(tr *v1beta1.TaskRun) string {
 }
 
 func ensureConfigurationConfigMapsExist(d *test.Data) {
-	var defaultsExists, featureFlagsExists, artifactBucketExists, artifactPVCExists, metricsExists bool
+	var defaultsExists, featureFlagsExists, artifactBucketExists, artifactPVCExists, metricsExists, tracingExists, eventsExists bool
 	for _, cm := range d.ConfigMaps {
 		if cm.Name == config.GetDefaultsConfigName() {
 			defaultsExists = true
@@ -471,6 +473,12 @@ func ensureConfigurationConfigMapsExist(d *test.Data) {
 		if cm.Name == config.GetMetricsConfigName() {
 			metricsExists = true
 		}
+		if cm.Name == config.GetTracingConfigName() {
+			tracingExists = true
+		}
+		if cm.Name == config.GetEventsConfigName() {
+			eventsExists = true
+		}
 	}
 	if !defaultsExists {
 		d.ConfigMaps = append(d.ConfigMaps, &corev1.ConfigMap{
@@ -502,6 +510,18 @@ func ensureConfigurationConfigMapsExist(d *test.Data) {
 			Data:       map[string]string{},
 		})
 	}
+	if !tracingExists {
+		d.ConfigMaps = append(d.ConfigMaps, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: config.GetTracingConfigName(), Namespace: system.Namespace()},
+			Data:       map[string]string{},
+		})
+	}
+	if !eventsExists {
+		d.ConfigMaps = append(d.ConfigMaps, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: config.GetEventsConfigName(), Namespace: system.Namespace()},
+			Data:       map[string]string{},
+		})
+	}
 }
 
 // getTaskRunController returns an instance of the TaskRun controller/reconciler that has been seeded with
@@ -1347,6 +1367,205 @@ spec:
 	}
 }
 
+// TestReconcileWithStepRef checks that a Step referencing a Step of another
+// Task is inlined in the TaskSpec stored in the TaskRun status.
+func TestReconcileWithStepRef(t *testing.T) {
+	catalogTask := parse.MustParseTask(t, `
+metadata:
+  name: catalog
+  namespace: foo
+spec:
+  steps:
+  - name: git-clone
+    image: alpine/git
+    script: git clone https://github.com/tektoncd/pipeline
+`)
+	tr := parse.MustParseTaskRun(t, `
+metadata:
+  name: test-taskrun-step-ref
+  namespace: foo
+spec:
+  taskSpec:
+    steps:
+    - name: clone
+      ref:
+        name: git-clone
+        taskRef:
+          name: catalog
+    - name: build
+      image: golang
+      script: go build ./...
+`)
+
+	d := test.Data{
+		ConfigMaps: []*corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace(), Name: config.GetFeatureFlagsConfigName()},
+			Data: map[string]string{
+				"enable-api-fields": config.AlphaAPIFields,
+			},
+		}},
+		Tasks:    []*v1beta1.Task{catalogTask},
+		TaskRuns: []*v1beta1.TaskRun{tr},
+		ServiceAccounts: []*corev1.ServiceAccount{{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "foo"},
+		}},
+	}
+
+	testAssets, cancel := getTaskRunController(t, d)
+	defer cancel()
+	c := testAssets.Controller
+	clients := testAssets.Clients
+	if err := c.Reconciler.Reconcile(testAssets.Ctx, getRunName(tr)); err != nil {
+		if ok, _ := controller.IsRequeueKey(err); !ok {
+			t.Errorf("expected no error. Got error %v", err)
+		}
+	}
+
+	updatedTR, err := clients.Pipeline.TektonV1beta1().TaskRuns(tr.Namespace).Get(testAssets.Ctx, tr.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting updated taskrun: %v", err)
+	}
+	condition := updatedTR.Status.GetCondition(apis.ConditionSucceeded)
+	if condition == nil || condition.Reason != v1beta1.TaskRunReasonRunning.String() {
+		t.Errorf("Expected TaskRun to be running, but had %v", condition)
+	}
+	wantSteps := []v1beta1.Step{{
+		Name:   "clone",
+		Image:  "alpine/git",
+		Script: "git clone https://github.com/tektoncd/pipeline",
+	}, {
+		Name:   "build",
+		Image:  "golang",
+		Script: "go build ./...",
+	}}
+	if updatedTR.Status.TaskSpec == nil {
+		t.Fatal("Expected the TaskSpec to be stored in the TaskRun status")
+	}
+	if d := cmp.Diff(wantSteps, updatedTR.Status.TaskSpec.Steps); d != "" {
+		t.Errorf("Steps stored in the TaskRun status %s", diff.PrintWantGot(d))
+	}
+}
+
+// TestReconcileWithInvalidStepRef checks that a TaskRun fails validation when
+// a Step referenced from another Task uses a param its Task does not declare.
+func TestReconcileWithInvalidStepRef(t *testing.T) {
+	catalogTask := parse.MustParseTask(t, `
+metadata:
+  name: catalog
+  namespace: foo
+spec:
+  params:
+  - name: url
+  steps:
+  - name: git-clone
+    image: alpine/git
+    args: ["clone", "$(params.url)"]
+`)
+	tr := parse.MustParseTaskRun(t, `
+metadata:
+  name: test-taskrun-invalid-step-ref
+  namespace: foo
+spec:
+  taskSpec:
+    steps:
+    - name: clone
+      ref:
+        name: git-clone
+        taskRef:
+          name: catalog
+`)
+
+	d := test.Data{
+		ConfigMaps: []*corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace(), Name: config.GetFeatureFlagsConfigName()},
+			Data: map[string]string{
+				"enable-api-fields": config.AlphaAPIFields,
+			},
+		}},
+		Tasks:    []*v1beta1.Task{catalogTask},
+		TaskRuns: []*v1beta1.TaskRun{tr},
+	}
+
+	testAssets, cancel := getTaskRunController(t, d)
+	defer cancel()
+	c := testAssets.Controller
+	clients := testAssets.Clients
+	err := c.Reconciler.Reconcile(testAssets.Ctx, getRunName(tr))
+	if err == nil || !controller.IsPermanentError(err) {
+		t.Errorf("expected a permanent error, got %v", err)
+	}
+
+	updatedTR, err := clients.Pipeline.TektonV1beta1().TaskRuns(tr.Namespace).Get(testAssets.Ctx, tr.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting updated taskrun: %v", err)
+	}
+	condition := updatedTR.Status.GetCondition(apis.ConditionSucceeded)
+	if condition == nil || condition.Status != corev1.ConditionFalse || condition.Reason != podconvert.ReasonFailedValidation {
+		t.Errorf("Expected TaskRun to fail validation, but had %v", condition)
+	}
+}
+
+// TestReconcileWithResolverParams checks that the params of a TaskRun are
+// substituted in the resolver parameters of its TaskRef before the
+// ResolutionRequest is created.
+func TestReconcileWithResolverParams(t *testing.T) {
+	tr := parse.MustParseTaskRun(t, `
+metadata:
+  name: tr
+  namespace: default
+spec:
+  params:
+  - name: branch
+    value: release
+  taskRef:
+    resolver: git
+    resource:
+    - name: revision
+      value: $(params.branch)
+    - name: path
+      value: $(context.taskRun.namespace)/task.yaml
+  serviceAccountName: default
+`)
+
+	d := test.Data{
+		ConfigMaps: []*corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace(), Name: config.GetFeatureFlagsConfigName()},
+			Data: map[string]string{
+				"enable-api-fields": config.AlphaAPIFields,
+			},
+		}},
+		TaskRuns: []*v1beta1.TaskRun{tr},
+		ServiceAccounts: []*corev1.ServiceAccount{{
+			ObjectMeta: metav1.ObjectMeta{Name: tr.Spec.ServiceAccountName, Namespace: tr.Namespace},
+		}},
+	}
+
+	testAssets, cancel := getTaskRunController(t, d)
+	defer cancel()
+	c := testAssets.Controller
+	if err := c.Reconciler.Reconcile(testAssets.Ctx, getRunName(tr)); err == nil {
+		t.Error("Wanted a resource request in progress error, but got nil.")
+	} else if controller.IsPermanentError(err) {
+		t.Errorf("expected no error. Got error %v", err)
+	}
+
+	client := testAssets.Clients.ResolutionRequests.ResolutionV1alpha1().ResolutionRequests("default")
+	resolutionrequests, err := client.List(testAssets.Ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing resource requests: %v", err)
+	}
+	if len(resolutionrequests.Items) != 1 {
+		t.Fatalf("expected exactly 1 resource request but found %d", len(resolutionrequests.Items))
+	}
+	want := map[string]string{
+		"revision": "release",
+		"path":     "default/task.yaml",
+	}
+	if d := cmp.Diff(want, resolutionrequests.Items[0].Spec.Parameters); d != "" {
+		t.Errorf("Resolution request parameters %s", diff.PrintWantGot(d))
+	}
+}
+
 // TestReconcileWithFailingResolver checks that a TaskRun with a failing Resolver
 // field creates a ResolutionRequest object for that Resolver's type, and
 // that when the request fails, the TaskRun fails.
@@ -2031,6 +2250,155 @@ status:
 	}
 }
 
+func TestReconcileCachedTaskRun(t *testing.T) {
+	newTaskRun := func(name, cacheKey, value, status string) *v1beta1.TaskRun {
+		tr := parse.MustParseTaskRun(t, fmt.Sprintf(`
+metadata:
+  name: %s
+  namespace: foo
+  annotations:
+    tekton.dev/cache: %s
+spec:
+  serviceAccountName: default
+  params:
+  - name: revision
+    value: %s
+  taskSpec:
+    params:
+    - name: revision
+      type: string
+    steps:
+    - image: foo
+      command: ["/mycmd"]
+`, name, cacheKey, value))
+		if status != "" {
+			tr.Status.TaskSpec = tr.Spec.TaskSpec.DeepCopy()
+			tr.Status.CompletionTime = &metav1.Time{Time: now.Add(-time.Minute)}
+			tr.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionStatus(status)})
+			tr.Status.TaskRunResults = []v1beta1.TaskRunResult{{Name: "report", Value: *v1beta1.NewArrayOrString(name)}}
+		}
+		return tr
+	}
+	cached := newTaskRun("cached-taskrun", "abc", "main", "True")
+	key, err := cacheKey(cached)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failed := newTaskRun("failed-taskrun", "abc", "main", "False")
+	// A TaskRun labelled with the cache key of different inputs
+	forged := newTaskRun("forged-taskrun", "abc", "other", "True")
+	for _, tr := range []*v1beta1.TaskRun{cached, failed, forged} {
+		tr.Labels = map[string]string{pipeline.CacheKeyLabelKey: key}
+	}
+	cms := []*corev1.ConfigMap{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace(), Name: config.GetFeatureFlagsConfigName()},
+		Data: map[string]string{
+			"enable-api-fields": config.AlphaAPIFields,
+		},
+	}}
+	running := &apis.Condition{
+		Type:    apis.ConditionSucceeded,
+		Status:  corev1.ConditionUnknown,
+		Reason:  v1beta1.TaskRunReasonRunning.String(),
+		Message: "Not all Steps in the Task have finished executing",
+	}
+
+	for _, tc := range []struct {
+		name           string
+		tr             *v1beta1.TaskRun
+		taskRuns       []*v1beta1.TaskRun
+		wantCondition  *apis.Condition
+		wantResults    []v1beta1.TaskRunResult
+		wantCacheKey   string
+		wantCachedFrom string
+		wantPod        bool
+	}{{
+		name:     "successful TaskRun with the same inputs",
+		tr:       newTaskRun("test-taskrun", "abc", "main", ""),
+		taskRuns: []*v1beta1.TaskRun{cached, failed, forged},
+		wantCondition: &apis.Condition{
+			Type:    apis.ConditionSucceeded,
+			Status:  corev1.ConditionTrue,
+			Reason:  v1beta1.TaskRunReasonCacheHit.String(),
+			Message: `Results reused from TaskRun "cached-taskrun"`,
+		},
+		wantResults: []v1beta1.TaskRunResult{{
+			Name:  "report",
+			Value: *v1beta1.NewArrayOrString("cached-taskrun"),
+		}},
+		wantCacheKey:   key,
+		wantCachedFrom: "cached-taskrun",
+	}, {
+		name:          "failed TaskRun with the same inputs",
+		tr:            newTaskRun("test-taskrun", "abc", "main", ""),
+		taskRuns:      []*v1beta1.TaskRun{failed},
+		wantCondition: running,
+		wantCacheKey:  key,
+		wantPod:       true,
+	}, {
+		name: "cachedFrom annotation and cache key label of a TaskRun with different inputs",
+		tr: func() *v1beta1.TaskRun {
+			tr := newTaskRun("test-taskrun", "abc", "main", "")
+			tr.Annotations[pipeline.CachedFromAnnotationKey] = forged.Name
+			return tr
+		}(),
+		taskRuns:       []*v1beta1.TaskRun{forged},
+		wantCondition:  running,
+		wantCacheKey:   key,
+		wantCachedFrom: forged.Name,
+		wantPod:        true,
+	}, {
+		name: "caching not enabled",
+		tr: func() *v1beta1.TaskRun {
+			tr := newTaskRun("test-taskrun", "abc", "main", "")
+			delete(tr.Annotations, pipeline.CacheAnnotationKey)
+			return tr
+		}(),
+		taskRuns:      []*v1beta1.TaskRun{cached},
+		wantCondition: running,
+		wantPod:       true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := test.Data{
+				TaskRuns:   append([]*v1beta1.TaskRun{tc.tr}, tc.taskRuns...),
+				ConfigMaps: cms,
+				ServiceAccounts: []*corev1.ServiceAccount{{
+					ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "foo"},
+				}},
+			}
+			testAssets, cancel := getTaskRunController(t, d)
+			defer cancel()
+			c := testAssets.Controller
+			clients := testAssets.Clients
+
+			if err := c.Reconciler.Reconcile(testAssets.Ctx, getRunName(tc.tr)); err != nil {
+				if ok, _ := controller.IsRequeueKey(err); !ok {
+					t.Errorf("expected no error. Got error %v", err)
+				}
+			}
+			newTr, err := clients.Pipeline.TektonV1beta1().TaskRuns(tc.tr.Namespace).Get(testAssets.Ctx, tc.tr.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Expected TaskRun %s to exist but instead got error when getting it: %v", tc.tr.Name, err)
+			}
+			if d := cmp.Diff(tc.wantCondition, newTr.Status.GetCondition(apis.ConditionSucceeded), ignoreLastTransitionTime); d != "" {
+				t.Errorf("Did not get expected condition %s", diff.PrintWantGot(d))
+			}
+			if d := cmp.Diff(tc.wantResults, newTr.Status.TaskRunResults); d != "" {
+				t.Errorf("Did not get expected results %s", diff.PrintWantGot(d))
+			}
+			if got := newTr.Labels[pipeline.CacheKeyLabelKey]; got != tc.wantCacheKey {
+				t.Errorf("expected cache key label %q, got %q", tc.wantCacheKey, got)
+			}
+			if got := newTr.Annotations[pipeline.CachedFromAnnotationKey]; got != tc.wantCachedFrom {
+				t.Errorf("expected cachedFrom annotation %q, got %q", tc.wantCachedFrom, got)
+			}
+			if gotPod := newTr.Status.PodName != ""; gotPod != tc.wantPod {
+				t.Errorf("expected a pod to be created: %t, got pod %q", tc.wantPod, newTr.Status.PodName)
+			}
+		})
+	}
+}
+
 func TestReconcilePodFailuresSidecarImagePullFailed(t *testing.T) {
 	taskRun := parse.MustParseTaskRun(t, `
 metadata:
@@ -2106,6 +2474,7 @@ func TestReconcileTimeouts(t *testing.T) {
 	type testCase struct {
 		name           string
 		taskRun        *v1beta1.TaskRun
+		configMaps     []*corev1.ConfigMap
 		expectedStatus *apis.Condition
 		wantEvents     []string
 	}
@@ -2187,13 +2556,51 @@ status:
 			wantEvents: []string{
 				"Warning Failed ",
 			},
+		}, {
+			name: "pipeline task timeout with detailed kubernetes events",
+			taskRun: parse.MustParseTaskRun(t, `
+metadata:
+  name: test-pipelinerun-build
+  namespace: foo
+  labels:
+    tekton.dev/pipelineTask: build
+  ownerReferences:
+  - apiVersion: tekton.dev/v1beta1
+    kind: PipelineRun
+    name: test-pipelinerun
+    uid: "1234"
+spec:
+  taskRef:
+    name: test-task
+  timeout: 10s
+status:
+  conditions:
+  - status: Unknown
+    type: Succeeded
+  startTime: "2021-12-31T23:59:45Z"
+`),
+			configMaps: []*corev1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{Name: config.GetEventsConfigName(), Namespace: system.Namespace()},
+				Data:       map[string]string{config.EventsKubernetesEventsKey: config.EventsKubernetesEventsDetailed},
+			}},
+			expectedStatus: &apis.Condition{
+				Type:    apis.ConditionSucceeded,
+				Status:  corev1.ConditionFalse,
+				Reason:  "TaskRunTimeout",
+				Message: `TaskRun "test-pipelinerun-build" failed to finish within "10s"`,
+			},
+			wantEvents: []string{
+				`Warning PipelineTaskTimedOut PipelineTask "build" timed out: TaskRun "test-pipelinerun-build" failed to finish within "10s"`,
+				"Warning Failed ",
+			},
 		}}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			d := test.Data{
-				TaskRuns: []*v1beta1.TaskRun{tc.taskRun},
-				Tasks:    []*v1beta1.Task{simpleTask},
+				TaskRuns:   []*v1beta1.TaskRun{tc.taskRun},
+				Tasks:      []*v1beta1.Task{simpleTask},
+				ConfigMaps: tc.configMaps,
 			}
 			testAssets, cancel := getTaskRunController(t, d)
 			defer cancel()
@@ -2290,6 +2697,7 @@ spec:
 		metrics:           nil, // Not used
 		entrypointCache:   nil, // Not used
 		pvcHandler:        volumeclaim.NewPVCHandler(testAssets.Clients.Kube, testAssets.Logger),
+		tracerProvider:    trace.NewNoopTracerProvider(),
 	}
 
 	rtr := &resources.ResolvedTaskResources{
@@ -2393,6 +2801,7 @@ spec:
 		metrics:           nil, // Not used
 		entrypointCache:   nil, // Not used
 		pvcHandler:        volumeclaim.NewPVCHandler(testAssets.Clients.Kube, testAssets.Logger),
+		tracerProvider:    trace.NewNoopTracerProvider(),
 	}
 
 	rtr := &resources.ResolvedTaskResources{
@@ -2441,6 +2850,7 @@ status:
 		metrics:           nil, // Not used
 		entrypointCache:   nil, // Not used
 		pvcHandler:        volumeclaim.NewPVCHandler(testAssets.Clients.Kube, testAssets.Logger),
+		tracerProvider:    trace.NewNoopTracerProvider(),
 	}
 
 	testcases := []struct {
@@ -3368,6 +3778,71 @@ spec:
 	}
 }
 
+// TestReconcileWithWorkspacesAndAffinityAssistantPerPipelineRun tests that a TaskRun using more than one
+// PVC-backed workspace passes validation when its Affinity Assistant is the one of its PipelineRun,
+// which mounts all the PVCs of the PipelineRun.
+func TestReconcileWithWorkspacesAndAffinityAssistantPerPipelineRun(t *testing.T) {
+	taskWithTwoWorkspaces := parse.MustParseTask(t, `
+metadata:
+  name: test-task-two-workspaces
+  namespace: foo
+spec:
+  steps:
+  - command:
+    - /mycmd
+    image: foo
+    name: simple-step
+  workspaces:
+  - description: task workspace
+    name: ws1
+    readOnly: true
+  - description: another workspace
+    name: ws2
+`)
+	taskRun := parse.MustParseTaskRun(t, `
+metadata:
+  annotations:
+    pipeline.tekton.dev/affinity-assistant: dummy-affinity-assistant
+    pipeline.tekton.dev/affinity-assistant-mode: pipelineRun
+  name: taskrun-with-two-workspaces
+  namespace: foo
+spec:
+  taskRef:
+    name: test-task-two-workspaces
+  workspaces:
+  - name: ws1
+    persistentVolumeClaim:
+      claimName: pvc1
+  - name: ws2
+    persistentVolumeClaim:
+      claimName: pvc2
+`)
+
+	d := test.Data{
+		Tasks:    []*v1beta1.Task{taskWithTwoWorkspaces},
+		TaskRuns: []*v1beta1.TaskRun{taskRun},
+	}
+	testAssets, cancel := getTaskRunController(t, d)
+	defer cancel()
+	clients := testAssets.Clients
+	createServiceAccount(t, testAssets, "default", "foo")
+	_ = testAssets.Controller.Reconciler.Reconcile(testAssets.Ctx, getRunName(taskRun))
+
+	tr, err := clients.Pipeline.TektonV1beta1().TaskRuns(taskRun.Namespace).Get(testAssets.Ctx, taskRun.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected TaskRun %s to exist but instead got error when getting it: %v", taskRun.Name, err)
+	}
+
+	for _, cond := range tr.Status.Conditions {
+		if cond.Reason == podconvert.ReasonFailedValidation {
+			t.Errorf("unexpected validation failure of the TaskRun: %s", cond.Message)
+		}
+	}
+	if tr.Status.PodName == "" {
+		t.Errorf("expected a pod to be created for the TaskRun")
+	}
+}
+
 // TestReconcileWorkspaceWithVolumeClaimTemplate tests a reconcile of a TaskRun that has
 // a Workspace with VolumeClaimTemplate and check that it is translated to a created PersistentVolumeClaim.
 func TestReconcileWorkspaceWithVolumeClaimTemplate(t *testing.T) {
@@ -3440,6 +3915,8 @@ spec:
 }
 
 func TestFailTaskRun(t *testing.T) {
+	// The grace period of the TaskRun is rounded up to whole seconds.
+	gracePeriodSeconds := int64(60)
 	testCases := []struct {
 		name               string
 		taskRun            *v1beta1.TaskRun
@@ -3448,6 +3925,8 @@ func TestFailTaskRun(t *testing.T) {
 		message            string
 		expectedStatus     apis.Condition
 		expectedStepStates []v1beta1.StepState
+		// expectedGracePeriodSeconds is the grace period the pod is deleted with
+		expectedGracePeriodSeconds *int64
 	}{{
 		name: "no-pod-scheduled",
 		taskRun: parse.MustParseTaskRun(t, `
@@ -3706,6 +4185,39 @@ status:
 				},
 			},
 		},
+	}, {
+		name: "timeout-with-grace-period",
+		taskRun: parse.MustParseTaskRun(t, `
+metadata:
+  name: test-taskrun-run-timeout-grace-period
+  namespace: foo
+spec:
+  gracePeriod: 59.5s
+  taskRef:
+    name: test-task
+  timeout: 10s
+status:
+  conditions:
+  - status: Unknown
+    type: Succeeded
+  podName: foo-is-bar
+  steps:
+  - running:
+      startedAt: "2022-01-01T00:00:00Z"
+`),
+		pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+			Name:      "foo-is-bar",
+		}},
+		reason:  v1beta1.TaskRunReasonTimedOut,
+		message: "TaskRun test-taskrun-run-timeout-grace-period failed to finish within 10s",
+		expectedStatus: apis.Condition{
+			Type:    apis.ConditionSucceeded,
+			Status:  corev1.ConditionFalse,
+			Reason:  v1beta1.TaskRunReasonTimedOut.String(),
+			Message: "TaskRun test-taskrun-run-timeout-grace-period failed to finish within 10s",
+		},
+		expectedGracePeriodSeconds: &gracePeriodSeconds,
 	}, {
 		name: "step-status-update-with-multiple-steps-and-some-continue-on-error",
 		taskRun: parse.MustParseTaskRun(t, `
@@ -3795,6 +4307,17 @@ status:
 				t.Fatalf(diff.PrintWantGot(d))
 			}
 
+			if tc.expectedGracePeriodSeconds != nil {
+				var deleteOptions metav1.DeleteOptions
+				for _, action := range testAssets.Clients.Kube.Actions() {
+					if deleteAction, ok := action.(ktesting.DeleteActionImpl); ok && action.GetResource().Resource == "pods" {
+						deleteOptions = deleteAction.DeleteOptions
+					}
+				}
+				if d := cmp.Diff(tc.expectedGracePeriodSeconds, deleteOptions.GracePeriodSeconds); d != "" {
+					t.Errorf("unexpected pod deletion grace period %s", diff.PrintWantGot(d))
+				}
+			}
 			if tc.expectedStepStates != nil {
 				ignoreTerminatedFields := cmpopts.IgnoreFields(corev1.ContainerStateTerminated{}, "StartedAt", "FinishedAt")
 				if c := cmp.Diff(tc.expectedStepStates, tc.taskRun.Status.Steps, ignoreTerminatedFields); c != "" {
@@ -3805,6 +4328,265 @@ status:
 	}
 }
 
+func TestFailTaskRunCancelledGracefully(t *testing.T) {
+	expectedStepStates := []v1beta1.StepState{{
+		ContainerState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 1,
+				Reason:   v1beta1.TaskRunReasonCancelled.String(),
+			},
+		},
+	}}
+	for _, tc := range []struct {
+		name     string
+		phase    corev1.PodPhase
+		wantVerb string
+	}{{
+		name:     "running pod is signalled",
+		phase:    corev1.PodRunning,
+		wantVerb: "patch",
+	}, {
+		name:     "pending pod is deleted",
+		phase:    corev1.PodPending,
+		wantVerb: "delete",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := parse.MustParseTaskRun(t, `
+metadata:
+  name: test-taskrun-run-cancelled
+  namespace: foo
+spec:
+  status: TaskRunCancelled
+  taskRef:
+    name: test-task
+status:
+  conditions:
+  - status: Unknown
+    type: Succeeded
+  podName: foo-is-bar
+  steps:
+  - running:
+      startedAt: "2022-01-01T00:00:00Z"
+`)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "foo",
+					Name:        "foo-is-bar",
+					Annotations: map[string]string{"pipeline.tekton.dev/release": "devel"},
+				},
+				Status: corev1.PodStatus{Phase: tc.phase},
+			}
+			d := test.Data{
+				TaskRuns: []*v1beta1.TaskRun{tr},
+				Pods:     []*corev1.Pod{pod},
+			}
+			testAssets, cancel := getTaskRunController(t, d)
+			defer cancel()
+
+			c := &Reconciler{
+				KubeClientSet:     testAssets.Clients.Kube,
+				PipelineClientSet: testAssets.Clients.Pipeline,
+				Clock:             testClock,
+				taskRunLister:     testAssets.Informers.TaskRun.Lister(),
+				podLister:         testAssets.Informers.Pod.Lister(),
+			}
+			ctx := config.ToContext(testAssets.Ctx, &config.Config{FeatureFlags: &config.FeatureFlags{EnableGracefulCancellation: true}})
+
+			if err := c.failTaskRun(ctx, tr, v1beta1.TaskRunReasonCancelled, "TaskRun test-taskrun-run-cancelled was cancelled"); err != nil {
+				t.Fatal(err)
+			}
+
+			var verbs []string
+			for _, action := range testAssets.Clients.Kube.Actions() {
+				if action.GetResource().Resource != "pods" {
+					continue
+				}
+				if verb := action.GetVerb(); verb == "delete" || verb == "patch" {
+					verbs = append(verbs, verb)
+				}
+			}
+			if d := cmp.Diff([]string{tc.wantVerb}, verbs); d != "" {
+				t.Errorf("unexpected pod actions %s", diff.PrintWantGot(d))
+			}
+
+			ignoreTerminatedFields := cmpopts.IgnoreFields(corev1.ContainerStateTerminated{}, "StartedAt", "FinishedAt")
+			if d := cmp.Diff(expectedStepStates, tr.Status.Steps, ignoreTerminatedFields); d != "" {
+				t.Errorf("unexpected step states %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestReconcileGracefullyCancelledTaskRunGracePeriod(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		completionTime time.Time
+		wantRequeue    bool
+		wantDeleted    bool
+	}{{
+		name:           "within grace period",
+		completionTime: now.Add(-10 * time.Second),
+		wantRequeue:    true,
+	}, {
+		name:           "after grace period",
+		completionTime: now.Add(-2 * time.Minute),
+		wantDeleted:    true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := parse.MustParseTaskRun(t, `
+metadata:
+  name: test-taskrun-run-cancelled
+  namespace: foo
+spec:
+  status: TaskRunCancelled
+  taskRef:
+    name: test-task
+status:
+  conditions:
+  - status: "False"
+    type: Succeeded
+    reason: TaskRunCancelled
+  podName: foo-is-bar
+  startTime: "2021-12-31T23:00:00Z"
+  steps:
+  - name: foo
+    container: step-foo
+    terminated:
+      exitCode: 1
+      reason: TaskRunCancelled
+`)
+			tr.Status.CompletionTime = &metav1.Time{Time: tc.completionTime}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "foo-is-bar",
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  "step-foo",
+						State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+					}},
+				},
+			}
+			d := test.Data{
+				ConfigMaps: []*corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace(), Name: config.GetFeatureFlagsConfigName()},
+					Data: map[string]string{
+						"enable-graceful-cancellation": "true",
+					},
+				}},
+				TaskRuns: []*v1beta1.TaskRun{tr},
+				Pods:     []*corev1.Pod{pod},
+			}
+			testAssets, cancel := getTaskRunController(t, d)
+			defer cancel()
+
+			err := testAssets.Controller.Reconciler.Reconcile(testAssets.Ctx, getRunName(tr))
+			if ok, _ := controller.IsRequeueKey(err); ok != tc.wantRequeue {
+				t.Errorf("expected requeue to be %t, got error %v", tc.wantRequeue, err)
+			} else if !ok && err != nil {
+				t.Fatalf("Unexpected error when reconciling cancelled TaskRun: %v", err)
+			}
+
+			_, err = testAssets.Clients.Kube.CoreV1().Pods("foo").Get(testAssets.Ctx, "foo-is-bar", metav1.GetOptions{})
+			if deleted := k8sapierrors.IsNotFound(err); deleted != tc.wantDeleted {
+				t.Errorf("expected pod deleted to be %t, got error %v", tc.wantDeleted, err)
+			}
+		})
+	}
+}
+
+func TestSyncGracefullyCancelledSteps(t *testing.T) {
+	for _, tc := range []struct {
+		name               string
+		containerStatuses  []corev1.ContainerStatus
+		expectedTerminated bool
+		expectedStepStates []v1beta1.StepState
+	}{{
+		name: "step still running",
+		containerStatuses: []corev1.ContainerStatus{{
+			Name:  "step-foo",
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		}},
+		expectedTerminated: false,
+		expectedStepStates: []v1beta1.StepState{{
+			ContainerState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: v1beta1.TaskRunReasonCancelled.String()},
+			},
+		}},
+	}, {
+		name: "step terminated",
+		containerStatuses: []corev1.ContainerStatus{{
+			Name:  "step-foo",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 143, Reason: "Error"}},
+		}},
+		expectedTerminated: true,
+		expectedStepStates: []v1beta1.StepState{{
+			ContainerState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 143, Reason: "Error"},
+			},
+			Name:          "foo",
+			ContainerName: "step-foo",
+		}},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := parse.MustParseTaskRun(t, `
+metadata:
+  name: test-taskrun-run-cancelled
+  namespace: foo
+spec:
+  status: TaskRunCancelled
+  taskRef:
+    name: test-task
+status:
+  conditions:
+  - reason: TaskRunCancelled
+    status: "False"
+    type: Succeeded
+  podName: foo-is-bar
+  steps:
+  - terminated:
+      exitCode: 1
+      reason: TaskRunCancelled
+`)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "foo-is-bar",
+				},
+				Status: corev1.PodStatus{
+					Phase:             corev1.PodRunning,
+					ContainerStatuses: tc.containerStatuses,
+				},
+			}
+			d := test.Data{
+				TaskRuns: []*v1beta1.TaskRun{tr},
+				Pods:     []*corev1.Pod{pod},
+			}
+			testAssets, cancel := getTaskRunController(t, d)
+			defer cancel()
+
+			c := &Reconciler{
+				KubeClientSet: testAssets.Clients.Kube,
+				podLister:     testAssets.Informers.Pod.Lister(),
+			}
+			ctx := config.ToContext(testAssets.Ctx, &config.Config{FeatureFlags: &config.FeatureFlags{EnableGracefulCancellation: true}})
+
+			terminated, err := c.syncGracefullyCancelledSteps(ctx, tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if terminated != tc.expectedTerminated {
+				t.Errorf("expected steps terminated to be %t but was %t", tc.expectedTerminated, terminated)
+			}
+			if d := cmp.Diff(tc.expectedStepStates, tr.Status.Steps); d != "" {
+				t.Errorf("unexpected step states %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
 func Test_storeTaskSpec(t *testing.T) {
 	tr := parse.MustParseTaskRun(t, `
 metadata:
@@ -4069,6 +4851,71 @@ status:
 	}
 }
 
+func TestStopSidecars_SidecarsRunByEntrypointAreSignalled(t *testing.T) {
+	tr := parse.MustParseTaskRun(t, `
+metadata:
+  name: test-taskrun
+  namespace: foo
+status:
+  conditions:
+  - status: "True"
+    type: Succeeded
+  podName: test-taskrun-pod
+  sidecars:
+  - running:
+      startedAt: "2000-01-01T01:01:01Z"
+    name: proxy
+    container: sidecar-proxy
+  startTime: "2000-01-01T01:01:01Z"
+`)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-taskrun-pod",
+			Namespace: "foo",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    "sidecar-proxy",
+				Image:   "proxy-image",
+				Command: []string{"/tekton/bin/entrypoint"},
+				Args:    []string{"-stop_file", "/tekton/downward/stop-sidecars", "-entrypoint", "proxy", "--"},
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "sidecar-proxy",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	}
+
+	d := test.Data{
+		Pods:     []*corev1.Pod{pod},
+		TaskRuns: []*v1beta1.TaskRun{tr},
+	}
+
+	testAssets, cancel := getTaskRunController(t, d)
+	defer cancel()
+	c := testAssets.Controller
+	clients := testAssets.Clients
+	if err := c.Reconciler.Reconcile(testAssets.Ctx, getRunName(tr)); err != nil {
+		t.Errorf("Expected no error to be returned by reconciler: %v", err)
+	}
+
+	got, err := clients.Kube.CoreV1().Pods(pod.Namespace).Get(testAssets.Ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Getting pod %q: %v", pod.Name, err)
+	}
+	if got.Annotations["tekton.dev/stop-sidecars"] != "STOP" {
+		t.Errorf("expected the sidecars to be signalled to stop, got annotations %v", got.Annotations)
+	}
+	if got.Spec.Containers[0].Image != "proxy-image" {
+		t.Errorf("expected the image of the sidecar to be kept, got %q", got.Spec.Containers[0].Image)
+	}
+}
+
 func TestStopSidecars_NoClientGetPodForTaskSpecWithoutRunningSidecars(t *testing.T) {
 	for _, tc := range []struct {
 		desc string
@@ -4232,6 +5079,22 @@ func Test_validateTaskSpecRequestResources_ValidResources(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "task request less or equal than task limit",
+		taskSpec: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Image:   "image",
+				Command: []string{"cmd"},
+			}},
+			ComputeResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+		},
 	}}
 
 	for _, tc := range tcs {
@@ -4287,6 +5150,22 @@ func Test_validateTaskSpecRequestResources_InvalidResources(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "task request larger than task limit",
+		taskSpec: &v1beta1.TaskSpec{
+			Steps: []v1beta1.Step{{
+				Image:   "image",
+				Command: []string{"cmd"},
+			}},
+			ComputeResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+		},
 	}}
 
 	for _, tc := range tcs {
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskrun
+
+import (
+	"context"
+	"time"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer used by the TaskRun reconciler, and
+// the service name its spans are reported under.
+const TracerName = "TaskRunReconciler"
+
+// initTracing returns a context carrying the span context of the TaskRun.
+// The first time a TaskRun is reconciled with tracing enabled, a span is
+// started for it, as a child of the PipelineRun span when it was created by
+// a PipelineRun, and its context is recorded in the TaskRun annotations,
+// from where it is propagated to the Pod and its steps. The returned span
+// must be ended once the reconcile is done; it is a non-recording span when
+// the TaskRun already has a span context.
+func initTracing(ctx context.Context, tp trace.TracerProvider, tr *v1beta1.TaskRun) (context.Context, trace.Span) {
+	if _, ok := tr.Annotations[pipeline.TaskRunSpanContextAnnotationKey]; ok {
+		ctx = tracing.ContextFromAnnotation(ctx, tr.Annotations, pipeline.TaskRunSpanContextAnnotationKey)
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	ctx = tracing.ContextFromAnnotation(ctx, tr.Annotations, pipeline.PipelineRunSpanContextAnnotationKey)
+	ctx, span := tp.Tracer(TracerName).Start(ctx, "TaskRun:"+tr.Name, trace.WithAttributes(taskRunAttributes(tr)...))
+	tr.Annotations = tracing.AnnotateSpanContext(ctx, tr.Annotations, pipeline.TaskRunSpanContextAnnotationKey)
+	return ctx, span
+}
+
+// endTracing records a span covering the execution of a done TaskRun, from
+// its start to its completion time, as a child of the span of the TaskRun.
+func endTracing(ctx context.Context, tp trace.TracerProvider, tr *v1beta1.TaskRun, now time.Time) {
+	if tr.Status.StartTime == nil {
+		return
+	}
+	end := now
+	if tr.Status.CompletionTime != nil {
+		end = tr.Status.CompletionTime.Time
+	}
+	ctx = tracing.ContextFromAnnotation(ctx, tr.Annotations, pipeline.TaskRunSpanContextAnnotationKey)
+	tracing.RecordSpan(ctx, tp.Tracer(TracerName), "TaskRun:Execution", tr.Status.StartTime.Time, end,
+		trace.WithAttributes(taskRunAttributes(tr)...))
+}
+
+func taskRunAttributes(tr *v1beta1.TaskRun) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("taskrun", tr.Name),
+		attribute.String("namespace", tr.Namespace),
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskrun
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTracingSpanCoversTaskRun(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	parentCtx, parent := tp.Tracer("test").Start(context.Background(), "PipelineRun:pr")
+	parent.End()
+	tr := &v1beta1.TaskRun{ObjectMeta: metav1.ObjectMeta{
+		Name:        "tr",
+		Namespace:   "foo",
+		Annotations: tracing.AnnotateSpanContext(parentCtx, nil, pipeline.PipelineRunSpanContextAnnotationKey),
+	}}
+
+	ctx, span := initTracing(context.Background(), tp, tr)
+	span.End()
+	want := trace.SpanContextFromContext(ctx)
+	annotation := tr.Annotations[pipeline.TaskRunSpanContextAnnotationKey]
+	if annotation == "" {
+		t.Fatal("expected the span context to be stored in the TaskRun annotations")
+	}
+
+	// Later reconciles reuse the span context stored in the annotations.
+	_, span = initTracing(context.Background(), tp, tr)
+	span.End()
+	if tr.Annotations[pipeline.TaskRunSpanContextAnnotationKey] != annotation {
+		t.Errorf("expected the span context annotation to stay %q, got %q", annotation, tr.Annotations[pipeline.TaskRunSpanContextAnnotationKey])
+	}
+	if ended := recorder.Ended(); len(ended) != 2 {
+		t.Fatalf("expected only the PipelineRun and TaskRun spans to be recorded, got %d", len(ended))
+	}
+
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	tr.Status.StartTime = &metav1.Time{Time: start}
+	tr.Status.CompletionTime = &metav1.Time{Time: start.Add(time.Minute)}
+	endTracing(context.Background(), tp, tr, time.Now())
+
+	ended := recorder.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("expected 3 spans to be recorded, got %d", len(ended))
+	}
+	if got := ended[1]; got.SpanContext().SpanID() != want.SpanID() || got.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected the TaskRun span %v to be a child of the PipelineRun span %v, got %v with parent %v", want, parent.SpanContext(), got.SpanContext(), got.Parent())
+	}
+	execution := ended[2]
+	if execution.SpanContext().TraceID() != want.TraceID() || execution.Parent().SpanID() != want.SpanID() {
+		t.Errorf("expected the execution span to be a child of %v, got parent %v", want, execution.Parent())
+	}
+	if d := execution.EndTime().Sub(execution.StartTime()); d != time.Minute {
+		t.Errorf("expected the execution span to last 1m, got %v", d)
+	}
+}
@@ -224,6 +224,13 @@ func ValidateResolvedTaskResources(ctx context.Context, params []v1beta1.Param,
 }
 
 func validateTaskSpecRequestResources(taskSpec *v1beta1.TaskSpec) error {
+	if taskSpec != nil && taskSpec.ComputeResources != nil {
+		for k, request := range taskSpec.ComputeResources.Requests {
+			if limit, ok := taskSpec.ComputeResources.Limits[k]; ok && (&limit).Cmp(request) == -1 {
+				return fmt.Errorf("Invalid request resource value: %v must be less or equal to limit %v", request.String(), limit.String())
+			}
+		}
+	}
 	if taskSpec != nil {
 		for _, step := range taskSpec.Steps {
 			for k, request := range step.Resources.Requests {
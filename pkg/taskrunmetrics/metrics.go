@@ -24,6 +24,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	listers "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1beta1"
 	"go.opencensus.io/stats"
@@ -40,16 +41,21 @@ import (
 )
 
 var (
-	pipelinerunTag = tag.MustNewKey("pipelinerun")
-	pipelineTag    = tag.MustNewKey("pipeline")
-	taskrunTag     = tag.MustNewKey("taskrun")
-	taskTag        = tag.MustNewKey("task")
-	namespaceTag   = tag.MustNewKey("namespace")
-	statusTag      = tag.MustNewKey("status")
-	podTag         = tag.MustNewKey("pod")
+	pipelinerunTag  = tag.MustNewKey("pipelinerun")
+	pipelineTag     = tag.MustNewKey("pipeline")
+	taskrunTag      = tag.MustNewKey("taskrun")
+	taskTag         = tag.MustNewKey("task")
+	namespaceTag    = tag.MustNewKey("namespace")
+	statusTag       = tag.MustNewKey("status")
+	reasonTag       = tag.MustNewKey("reason")
+	podTag          = tag.MustNewKey("pod")
+	stepTag         = tag.MustNewKey("step")
+	pipelineTaskTag = tag.MustNewKey("pipelinetask")
 
 	trDurationView      *view.View
 	prTRDurationView    *view.View
+	stepDurationView    *view.View
+	ptDurationView      *view.View
 	trCountView         *view.View
 	runningTRsCountView *view.View
 	podLatencyView      *view.View
@@ -65,6 +71,16 @@ var (
 		"The pipelinerun's taskrun execution time in seconds",
 		stats.UnitDimensionless)
 
+	stepDuration = stats.Float64(
+		"taskrun_step_duration_seconds",
+		"The taskrun's step execution time in seconds",
+		stats.UnitDimensionless)
+
+	ptDuration = stats.Float64(
+		"pipelinerun_pipelinetask_duration_seconds",
+		"The pipelinerun's pipeline task execution time in seconds, including retries",
+		stats.UnitDimensionless)
+
 	trCount = stats.Float64("taskrun_count",
 		"number of taskruns",
 		stats.UnitDimensionless)
@@ -94,6 +110,12 @@ type Recorder struct {
 
 	insertPipelineTag func(pipeline,
 		pipelinerun string) []tag.Mutator
+
+	insertReasonTag func(reason string) []tag.Mutator
+
+	insertStepTag func(step string) []tag.Mutator
+
+	insertPipelineTaskTag func(pipelineTask string) []tag.Mutator
 }
 
 // We cannot register the view multiple times, so NewRecorder lazily
@@ -135,6 +157,14 @@ func viewRegister(cfg *config.Metrics) error {
 
 	prunTag := []tag.Key{}
 	trunTag := []tag.Key{}
+	// The reason, step and pipeline task tags are only added when metrics
+	// are not aggregated at namespace level, to keep the cardinality low.
+	reasonTags := []tag.Key{reasonTag}
+	stepTags := []tag.Key{stepTag}
+	pipelineTaskTags := []tag.Key{pipelineTaskTag}
+	r.insertReasonTag = reasonInsertTag
+	r.insertStepTag = stepInsertTag
+	r.insertPipelineTaskTag = pipelineTaskInsertTag
 	switch cfg.PipelinerunLevel {
 	case config.PipelinerunLevelAtPipelinerun:
 		prunTag = []tag.Key{pipelineTag, pipelinerunTag}
@@ -145,6 +175,8 @@ func viewRegister(cfg *config.Metrics) error {
 	case config.PipelinerunLevelAtNS:
 		prunTag = []tag.Key{}
 		r.insertPipelineTag = nilInsertTag
+		pipelineTaskTags = []tag.Key{}
+		r.insertPipelineTaskTag = nilSingleInsertTag
 	default:
 		return errors.New("invalid config for PipelinerunLevel: " + cfg.PipelinerunLevel)
 	}
@@ -159,6 +191,10 @@ func viewRegister(cfg *config.Metrics) error {
 	case config.PipelinerunLevelAtNS:
 		trunTag = []tag.Key{}
 		r.insertTaskTag = nilInsertTag
+		reasonTags = []tag.Key{}
+		r.insertReasonTag = nilSingleInsertTag
+		stepTags = []tag.Key{}
+		r.insertStepTag = nilSingleInsertTag
 	default:
 		return errors.New("invalid config for TaskrunLevel: " + cfg.TaskrunLevel)
 	}
@@ -178,23 +214,37 @@ func viewRegister(cfg *config.Metrics) error {
 		}
 	}
 
+	statusTags := append([]tag.Key{statusTag}, reasonTags...)
+
 	trDurationView = &view.View{
 		Description: trDuration.Description(),
 		Measure:     trDuration,
 		Aggregation: distribution,
-		TagKeys:     append([]tag.Key{statusTag, namespaceTag}, trunTag...),
+		TagKeys:     append([]tag.Key{namespaceTag}, append(statusTags, trunTag...)...),
 	}
 	prTRDurationView = &view.View{
 		Description: prTRDuration.Description(),
 		Measure:     prTRDuration,
 		Aggregation: distribution,
-		TagKeys:     append([]tag.Key{statusTag, namespaceTag}, append(trunTag, prunTag...)...),
+		TagKeys:     append([]tag.Key{namespaceTag}, append(statusTags, append(trunTag, prunTag...)...)...),
+	}
+	stepDurationView = &view.View{
+		Description: stepDuration.Description(),
+		Measure:     stepDuration,
+		Aggregation: distribution,
+		TagKeys:     append([]tag.Key{namespaceTag}, append(statusTags, append(stepTags, trunTag...)...)...),
+	}
+	ptDurationView = &view.View{
+		Description: ptDuration.Description(),
+		Measure:     ptDuration,
+		Aggregation: distribution,
+		TagKeys:     append([]tag.Key{namespaceTag}, append(statusTags, append(pipelineTaskTags, prunTag...)...)...),
 	}
 	trCountView = &view.View{
 		Description: trCount.Description(),
 		Measure:     trCount,
 		Aggregation: view.Count(),
-		TagKeys:     []tag.Key{statusTag},
+		TagKeys:     statusTags,
 	}
 	runningTRsCountView = &view.View{
 		Description: runningTRsCount.Description(),
@@ -216,6 +266,8 @@ func viewRegister(cfg *config.Metrics) error {
 	return view.Register(
 		trDurationView,
 		prTRDurationView,
+		stepDurationView,
+		ptDurationView,
 		trCountView,
 		runningTRsCountView,
 		podLatencyView,
@@ -227,6 +279,8 @@ func viewUnregister() {
 	view.Unregister(
 		trDurationView,
 		prTRDurationView,
+		stepDurationView,
+		ptDurationView,
 		trCountView,
 		runningTRsCountView,
 		podLatencyView,
@@ -276,8 +330,25 @@ func nilInsertTag(task, taskrun string) []tag.Mutator {
 	return []tag.Mutator{}
 }
 
-// DurationAndCount logs the duration of TaskRun execution and
-// count for number of TaskRuns succeed or failed
+func reasonInsertTag(reason string) []tag.Mutator {
+	return []tag.Mutator{tag.Insert(reasonTag, reason)}
+}
+
+func stepInsertTag(step string) []tag.Mutator {
+	return []tag.Mutator{tag.Insert(stepTag, step)}
+}
+
+func pipelineTaskInsertTag(pipelineTask string) []tag.Mutator {
+	return []tag.Mutator{tag.Insert(pipelineTaskTag, pipelineTask)}
+}
+
+func nilSingleInsertTag(string) []tag.Mutator {
+	return []tag.Mutator{}
+}
+
+// DurationAndCount logs the duration of TaskRun execution, of its steps and,
+// for a TaskRun of a PipelineRun, of its pipeline task, and count for number
+// of TaskRuns succeed or failed
 // returns an error if its failed to log the metrics
 func (r *Recorder) DurationAndCount(ctx context.Context, tr *v1beta1.TaskRun, beforeCondition *apis.Condition) error {
 
@@ -307,12 +378,19 @@ func (r *Recorder) DurationAndCount(ctx context.Context, tr *v1beta1.TaskRun, be
 	if cond := tr.Status.GetCondition(apis.ConditionSucceeded); cond.Status == corev1.ConditionFalse {
 		status = "failed"
 	}
+	statusTags := append([]tag.Mutator{tag.Insert(namespaceTag, tr.Namespace),
+		tag.Insert(statusTag, status)},
+		r.insertReasonTag(afterCondition.GetReason())...)
+
+	if err := r.recordStepDurations(ctx, tr, taskName); err != nil {
+		return err
+	}
 
+	pipelineTask, hasPipelineTask := tr.Labels[pipeline.PipelineTaskLabelKey]
 	if ok, pipeline, pipelinerun := tr.IsPartOfPipeline(); ok {
 		ctx, err := tag.New(
 			ctx,
-			append([]tag.Mutator{tag.Insert(namespaceTag, tr.Namespace),
-				tag.Insert(statusTag, status)},
+			append(statusTags,
 				append(r.insertPipelineTag(pipeline, pipelinerun),
 					r.insertTaskTag(taskName, tr.Name)...)...)...)
 
@@ -322,13 +400,24 @@ func (r *Recorder) DurationAndCount(ctx context.Context, tr *v1beta1.TaskRun, be
 
 		metrics.Record(ctx, prTRDuration.M(float64(duration/time.Second)))
 		metrics.Record(ctx, trCount.M(1))
+
+		if hasPipelineTask {
+			ctx, err := tag.New(
+				ctx,
+				append(statusTags,
+					append(r.insertPipelineTag(pipeline, pipelinerun),
+						r.insertPipelineTaskTag(pipelineTask)...)...)...)
+			if err != nil {
+				return err
+			}
+			metrics.Record(ctx, ptDuration.M(float64(pipelineTaskDuration(tr)/time.Second)))
+		}
 		return nil
 	}
 
 	ctx, err := tag.New(
 		ctx,
-		append([]tag.Mutator{tag.Insert(namespaceTag, tr.Namespace),
-			tag.Insert(statusTag, status)},
+		append(statusTags,
 			r.insertTaskTag(taskName, tr.Name)...)...)
 	if err != nil {
 		return err
@@ -340,6 +429,52 @@ func (r *Recorder) DurationAndCount(ctx context.Context, tr *v1beta1.TaskRun, be
 	return nil
 }
 
+// recordStepDurations logs the duration of each terminated step of the
+// TaskRun, tagged with the reason its container terminated.
+func (r *Recorder) recordStepDurations(ctx context.Context, tr *v1beta1.TaskRun, taskName string) error {
+	for _, step := range tr.Status.Steps {
+		terminated := step.Terminated
+		if terminated == nil || terminated.StartedAt.IsZero() || terminated.FinishedAt.IsZero() {
+			continue
+		}
+		status := "success"
+		if terminated.ExitCode != 0 {
+			status = "failed"
+		}
+		ctx, err := tag.New(
+			ctx,
+			append([]tag.Mutator{tag.Insert(namespaceTag, tr.Namespace),
+				tag.Insert(statusTag, status)},
+				append(r.insertReasonTag(terminated.Reason),
+					append(r.insertStepTag(step.Name),
+						r.insertTaskTag(taskName, tr.Name)...)...)...)...)
+		if err != nil {
+			return err
+		}
+		duration := terminated.FinishedAt.Sub(terminated.StartedAt.Time)
+		metrics.Record(ctx, stepDuration.M(float64(duration/time.Second)))
+	}
+	return nil
+}
+
+// pipelineTaskDuration returns the duration of the pipeline task the TaskRun
+// was created for, from the start of its first attempt to its completion.
+func pipelineTaskDuration(tr *v1beta1.TaskRun) time.Duration {
+	start := tr.Status.StartTime
+	for _, retry := range tr.Status.RetriesStatus {
+		if retry.StartTime != nil && (start == nil || retry.StartTime.Before(start)) {
+			start = retry.StartTime
+		}
+	}
+	if start == nil {
+		return 0
+	}
+	if tr.Status.CompletionTime != nil {
+		return tr.Status.CompletionTime.Sub(start.Time)
+	}
+	return time.Since(start.Time)
+}
+
 // RunningTaskRuns logs the number of TaskRuns running right now
 // returns an error if its failed to log the metrics
 func (r *Recorder) RunningTaskRuns(ctx context.Context, lister listers.TaskRunLister) error {
@@ -152,6 +152,7 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionTrue,
+						Reason: "Succeeded",
 					}},
 				},
 				TaskRunStatusFields: v1beta1.TaskRunStatusFields{
@@ -166,9 +167,11 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 			"taskrun":   "taskrun-1",
 			"namespace": "ns",
 			"status":    "success",
+			"reason":    "Succeeded",
 		},
 		expectedCountTags: map[string]string{
 			"status": "success",
+			"reason": "Succeeded",
 		},
 		expectedDuration: 60,
 		expectedCount:    1,
@@ -185,6 +188,7 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionTrue,
+						Reason: "Succeeded",
 					}},
 				},
 				TaskRunStatusFields: v1beta1.TaskRunStatusFields{
@@ -199,9 +203,11 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 			"taskrun":   "taskrun-1",
 			"namespace": "ns",
 			"status":    "success",
+			"reason":    "Succeeded",
 		},
 		expectedCountTags: map[string]string{
 			"status": "success",
+			"reason": "Succeeded",
 		},
 		expectedDuration: 60,
 		expectedCount:    1,
@@ -221,6 +227,7 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionTrue,
+						Reason: "Succeeded",
 					}},
 				},
 				TaskRunStatusFields: v1beta1.TaskRunStatusFields{
@@ -237,6 +244,7 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 		beforeCondition: &apis.Condition{
 			Type:   apis.ConditionSucceeded,
 			Status: corev1.ConditionTrue,
+			Reason: "Succeeded",
 		},
 	}, {
 		name: "for failed taskrun",
@@ -250,6 +258,7 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionFalse,
+						Reason: "TaskRunImagePullFailed",
 					}},
 				},
 				TaskRunStatusFields: v1beta1.TaskRunStatusFields{
@@ -264,9 +273,11 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 			"taskrun":   "taskrun-1",
 			"namespace": "ns",
 			"status":    "failed",
+			"reason":    "TaskRunImagePullFailed",
 		},
 		expectedCountTags: map[string]string{
 			"status": "failed",
+			"reason": "TaskRunImagePullFailed",
 		},
 		expectedDuration: 60,
 		expectedCount:    1,
@@ -289,6 +300,7 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionTrue,
+						Reason: "Succeeded",
 					}},
 				},
 				TaskRunStatusFields: v1beta1.TaskRunStatusFields{
@@ -305,9 +317,11 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 			"taskrun":     "taskrun-1",
 			"namespace":   "ns",
 			"status":      "success",
+			"reason":      "Succeeded",
 		},
 		expectedCountTags: map[string]string{
 			"status": "success",
+			"reason": "Succeeded",
 		},
 		expectedDuration: 60,
 		expectedCount:    1,
@@ -330,6 +344,7 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 					Conditions: duckv1beta1.Conditions{{
 						Type:   apis.ConditionSucceeded,
 						Status: corev1.ConditionFalse,
+						Reason: "TaskRunImagePullFailed",
 					}},
 				},
 				TaskRunStatusFields: v1beta1.TaskRunStatusFields{
@@ -346,9 +361,11 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 			"taskrun":     "taskrun-1",
 			"namespace":   "ns",
 			"status":      "failed",
+			"reason":      "TaskRunImagePullFailed",
 		},
 		expectedCountTags: map[string]string{
 			"status": "failed",
+			"reason": "TaskRunImagePullFailed",
 		},
 		expectedDuration: 60,
 		expectedCount:    1,
@@ -381,6 +398,150 @@ func TestRecordTaskRunDurationCount(t *testing.T) {
 	}
 }
 
+func TestRecordStepAndPipelineTaskDurations(t *testing.T) {
+	firstAttemptStart := metav1.NewTime(startTime.Time.Add(-time.Minute))
+	stepStart := metav1.NewTime(startTime.Time.Add(10 * time.Second))
+	stepEnd := metav1.NewTime(startTime.Time.Add(40 * time.Second))
+	taskRun := &v1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "taskrun-1", Namespace: "ns",
+			Labels: map[string]string{
+				pipeline.PipelineLabelKey:     "pipeline-1",
+				pipeline.PipelineRunLabelKey:  "pipelinerun-1",
+				pipeline.PipelineTaskLabelKey: "build",
+			},
+		},
+		Spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{Name: "task-1"},
+		},
+		Status: v1beta1.TaskRunStatus{
+			Status: duckv1beta1.Status{
+				Conditions: duckv1beta1.Conditions{{
+					Type:   apis.ConditionSucceeded,
+					Status: corev1.ConditionFalse,
+					Reason: "Failed",
+				}},
+			},
+			TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+				StartTime:      &startTime,
+				CompletionTime: &completionTime,
+				Steps: []v1beta1.StepState{{
+					Name: "compile",
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode:   1,
+							Reason:     "Error",
+							StartedAt:  stepStart,
+							FinishedAt: stepEnd,
+						},
+					},
+				}, {
+					Name: "skipped",
+					ContainerState: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{},
+					},
+				}},
+				RetriesStatus: []v1beta1.TaskRunStatus{{
+					TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+						StartTime:      &firstAttemptStart,
+						CompletionTime: &startTime,
+					},
+				}},
+			},
+		},
+	}
+
+	unregisterMetrics()
+	ctx := getConfigContext()
+	metrics, err := NewRecorder(ctx)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	if err := metrics.DurationAndCount(ctx, taskRun, nil); err != nil {
+		t.Errorf("DurationAndCount: %v", err)
+	}
+	metricstest.CheckLastValueData(t, "taskrun_step_duration_seconds", map[string]string{
+		"namespace": "ns",
+		"status":    "failed",
+		"reason":    "Error",
+		"step":      "compile",
+		"task":      "task-1",
+		"taskrun":   "taskrun-1",
+	}, 30)
+	metricstest.CheckLastValueData(t, "pipelinerun_pipelinetask_duration_seconds", map[string]string{
+		"namespace":    "ns",
+		"status":       "failed",
+		"reason":       "Failed",
+		"pipelinetask": "build",
+		"pipeline":     "pipeline-1",
+		"pipelinerun":  "pipelinerun-1",
+	}, 120)
+}
+
+func TestRecordTaskRunDurationCountAtNamespaceLevel(t *testing.T) {
+	taskRun := &v1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "taskrun-1", Namespace: "ns"},
+		Spec: v1beta1.TaskRunSpec{
+			TaskRef: &v1beta1.TaskRef{Name: "task-1"},
+		},
+		Status: v1beta1.TaskRunStatus{
+			Status: duckv1beta1.Status{
+				Conditions: duckv1beta1.Conditions{{
+					Type:   apis.ConditionSucceeded,
+					Status: corev1.ConditionFalse,
+					Reason: "TaskRunTimeout",
+				}},
+			},
+			TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+				StartTime:      &startTime,
+				CompletionTime: &completionTime,
+				Steps: []v1beta1.StepState{{
+					Name: "compile",
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode:   1,
+							Reason:     "TaskRunTimeout",
+							StartedAt:  startTime,
+							FinishedAt: completionTime,
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	unregisterMetrics()
+	ctx := config.ToContext(context.Background(), &config.Config{
+		Metrics: &config.Metrics{
+			TaskrunLevel:            config.TaskrunLevelAtNS,
+			PipelinerunLevel:        config.PipelinerunLevelAtNS,
+			DurationTaskrunType:     config.DurationTaskrunTypeLastValue,
+			DurationPipelinerunType: config.DurationPipelinerunTypeLastValue,
+		},
+	})
+	metrics, err := NewRecorder(ctx)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	if err := metrics.DurationAndCount(ctx, taskRun, nil); err != nil {
+		t.Errorf("DurationAndCount: %v", err)
+	}
+	// The reason and step tags are dropped at namespace level.
+	metricstest.CheckLastValueData(t, "taskrun_duration_seconds", map[string]string{
+		"namespace": "ns",
+		"status":    "failed",
+	}, 60)
+	metricstest.CheckCountData(t, "taskrun_count", map[string]string{
+		"status": "failed",
+	}, 1)
+	metricstest.CheckLastValueData(t, "taskrun_step_duration_seconds", map[string]string{
+		"namespace": "ns",
+		"status":    "failed",
+	}, 60)
+}
+
 func TestRecordRunningTaskRunsCount(t *testing.T) {
 	unregisterMetrics()
 	newTaskRun := func(status corev1.ConditionStatus) *v1beta1.TaskRun {
@@ -646,7 +807,7 @@ func TestRecordCloudEvents(t *testing.T) {
 }
 
 func unregisterMetrics() {
-	metricstest.Unregister("taskrun_duration_seconds", "pipelinerun_taskrun_duration_seconds", "taskrun_count", "running_taskruns_count", "taskruns_pod_latency", "cloudevent_count")
+	metricstest.Unregister("taskrun_duration_seconds", "pipelinerun_taskrun_duration_seconds", "taskrun_step_duration_seconds", "pipelinerun_pipelinetask_duration_seconds", "taskrun_count", "running_taskruns_count", "taskruns_pod_latency", "cloudevent_count")
 
 	// Allow the recorder singleton to be recreated.
 	once = sync.Once{}
@@ -0,0 +1,221 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// TracerProvider is a trace.TracerProvider that is reconfigured when the
+// tracing ConfigMap changes. It hands out no-op tracers while tracing is
+// disabled.
+type TracerProvider struct {
+	service string
+
+	mu       sync.RWMutex
+	cfg      *config.Tracing
+	provider trace.TracerProvider
+	shutdown func(context.Context) error
+}
+
+var _ trace.TracerProvider = (*TracerProvider)(nil)
+
+// New returns a TracerProvider for the given service, with tracing disabled
+// until it is configured.
+func New(service string) *TracerProvider {
+	return &TracerProvider{
+		service:  service,
+		provider: trace.NewNoopTracerProvider(),
+	}
+}
+
+// Tracer returns a tracer from the currently configured provider.
+func (t *TracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.provider.Tracer(name, opts...)
+}
+
+// Reconfigure replaces the underlying provider with one built from cfg,
+// flushing and shutting down the previous one.
+func (t *TracerProvider) Reconfigure(ctx context.Context, cfg *config.Tracing) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cfg.Equals(cfg) {
+		return nil
+	}
+
+	var provider trace.TracerProvider = trace.NewNoopTracerProvider()
+	var shutdown func(context.Context) error
+	if cfg != nil && cfg.Enabled {
+		tp, err := NewSDKTracerProvider(ctx, t.service, cfg, os.Stdout)
+		if err != nil {
+			return err
+		}
+		provider, shutdown = tp, tp.Shutdown
+	}
+
+	if t.shutdown != nil {
+		if err := t.shutdown(ctx); err != nil {
+			return fmt.Errorf("shutting down previous tracer provider: %w", err)
+		}
+	}
+	t.cfg, t.provider, t.shutdown = cfg.DeepCopy(), provider, shutdown
+	return nil
+}
+
+// Shutdown flushes and stops the underlying provider, if any.
+func (t *TracerProvider) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.shutdown == nil {
+		return nil
+	}
+	err := t.shutdown(ctx)
+	t.cfg, t.provider, t.shutdown = nil, trace.NewNoopTracerProvider(), nil
+	return err
+}
+
+// OnStore returns a function that reconfigures tp when the tracing
+// ConfigMap of a config.Store changes.
+func OnStore(logger *zap.SugaredLogger, tp *TracerProvider) func(name string, value interface{}) {
+	return func(name string, value interface{}) {
+		if name != config.GetTracingConfigName() {
+			return
+		}
+		cfg, ok := value.(*config.Tracing)
+		if !ok {
+			logger.Error("Failed to do type insertion for extracting tracing config")
+			return
+		}
+		if err := tp.Reconfigure(context.Background(), cfg); err != nil {
+			logger.Errorf("Failed to configure tracing: %v", err)
+		}
+	}
+}
+
+// NewSDKTracerProvider returns a provider exporting the spans of service
+// with the exporter configured in cfg. The stdout exporter prints the spans
+// to out.
+func NewSDKTracerProvider(ctx context.Context, service string, cfg *config.Tracing, out io.Writer) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case config.TracingExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(out))
+	case config.TracingExporterOTLP, "":
+		exporter, err = otlptracehttp.New(ctx, otlpOptions(cfg.Endpoint)...)
+	default:
+		err = fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating %s exporter: %w", cfg.Exporter, err)
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+	), nil
+}
+
+// otlpOptions translates an endpoint URL such as http://collector:4318 into
+// OTLP HTTP exporter options. The exporter defaults are used when it is empty.
+func otlpOptions(endpoint string) []otlptracehttp.Option {
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if u.Path != "" && u.Path != "/" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+	return opts
+}
+
+// traceparentHeader is the W3C Trace Context header holding a span context,
+// which is the value of the span context annotations.
+const traceparentHeader = "traceparent"
+
+// ContextFromAnnotation returns ctx with the remote span context stored
+// under key in annotations as its parent. ctx is returned unchanged if
+// there is no valid span context.
+func ContextFromAnnotation(ctx context.Context, annotations map[string]string, key string) context.Context {
+	carrier, err := ParseSpanContext(annotations[key])
+	if err != nil || carrier == nil {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, carrier)
+}
+
+// AnnotateSpanContext stores the span context of ctx under key in
+// annotations, as a W3C traceparent, returning the updated annotations.
+// Annotations are left untouched when ctx carries no valid span, e.g. when
+// tracing is disabled.
+func AnnotateSpanContext(ctx context.Context, annotations map[string]string, key string) map[string]string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return annotations
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = carrier.Get(traceparentHeader)
+	return annotations
+}
+
+// ParseSpanContext parses a span context as stored by AnnotateSpanContext,
+// returning a carrier from which it can be extracted.
+func ParseSpanContext(value string) (propagation.MapCarrier, error) {
+	if value == "" {
+		return nil, nil
+	}
+	carrier := propagation.MapCarrier{traceparentHeader: value}
+	if !trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier)).IsValid() {
+		return nil, fmt.Errorf("invalid span context %q", value)
+	}
+	return carrier, nil
+}
+
+// RecordSpan records a span named name, which ran from start to end, as a
+// child of the span of ctx. It is used for the spans covering the execution of
+// PipelineRuns and TaskRuns, which are only known once they are done.
+func RecordSpan(ctx context.Context, tracer trace.Tracer, name string, start, end time.Time, opts ...trace.SpanStartOption) {
+	_, span := tracer.Start(ctx, name, append(opts, trace.WithTimestamp(start))...)
+	span.End(trace.WithTimestamp(end))
+}
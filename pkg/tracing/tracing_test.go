@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/test/diff"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestAnnotateSpanContextRoundTrip(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	annotations := AnnotateSpanContext(ctx, nil, "key")
+	want := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"
+	if annotations["key"] != want {
+		t.Fatalf("expected traceparent %q in the annotations, got %v", want, annotations)
+	}
+
+	got := trace.SpanContextFromContext(ContextFromAnnotation(context.Background(), annotations, "key"))
+	if !got.IsRemote() {
+		t.Errorf("expected remote span context")
+	}
+	if got.TraceID() != span.SpanContext().TraceID() || got.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("expected span context %v, got %v", span.SpanContext(), got)
+	}
+}
+
+func TestAnnotateSpanContextNoSpan(t *testing.T) {
+	// Spans of a no-op provider are invalid and must not be recorded.
+	ctx, span := trace.NewNoopTracerProvider().Tracer("test").Start(context.Background(), "noop")
+	defer span.End()
+
+	if annotations := AnnotateSpanContext(ctx, nil, "key"); annotations != nil {
+		t.Errorf("expected no annotations, got %v", annotations)
+	}
+	existing := map[string]string{"foo": "bar"}
+	if d := cmp.Diff(map[string]string{"foo": "bar"}, AnnotateSpanContext(ctx, existing, "key")); d != "" {
+		t.Errorf("annotations changed %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestContextFromAnnotationInvalid(t *testing.T) {
+	for _, annotations := range []map[string]string{
+		nil,
+		{"key": ""},
+		{"key": "garbage"},
+		{"key": `{"traceparent":"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}`},
+	} {
+		ctx := ContextFromAnnotation(context.Background(), annotations, "key")
+		if trace.SpanContextFromContext(ctx).IsValid() {
+			t.Errorf("expected no span context for annotations %v", annotations)
+		}
+	}
+}
+
+func TestParseSpanContext(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		value   string
+		want    propagation.MapCarrier
+		wantErr bool
+	}{{
+		name: "empty",
+	}, {
+		name:  "valid",
+		value: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		want:  propagation.MapCarrier{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+	}, {
+		name:    "invalid",
+		value:   "00-garbage-01",
+		wantErr: true,
+	}, {
+		name:    "all zero trace id",
+		value:   "00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		wantErr: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseSpanContext(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseSpanContext() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("ParseSpanContext() %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestTracerProviderReconfigure(t *testing.T) {
+	ctx := context.Background()
+	tp := New("test")
+	defer tp.Shutdown(ctx)
+
+	if _, span := tp.Tracer("test").Start(ctx, "disabled"); span.SpanContext().IsValid() {
+		t.Errorf("expected no-op span before tracing is configured")
+	}
+
+	onStore := OnStore(logtesting.TestLogger(t), tp)
+	onStore(config.GetTracingConfigName(), &config.Tracing{Enabled: true, Exporter: config.TracingExporterStdout})
+	_, span := tp.Tracer("test").Start(ctx, "enabled")
+	if !span.SpanContext().IsValid() {
+		t.Errorf("expected recording span once tracing is enabled")
+	}
+	span.End()
+
+	// Other ConfigMaps and values of the wrong type are ignored.
+	onStore(config.GetMetricsConfigName(), &config.Tracing{})
+	onStore(config.GetTracingConfigName(), &config.Metrics{})
+	if _, span := tp.Tracer("test").Start(ctx, "still enabled"); !span.SpanContext().IsValid() {
+		t.Errorf("expected tracing to stay enabled")
+	}
+
+	onStore(config.GetTracingConfigName(), &config.Tracing{Enabled: false})
+	if _, span := tp.Tracer("test").Start(ctx, "disabled again"); span.SpanContext().IsValid() {
+		t.Errorf("expected no-op span once tracing is disabled")
+	}
+}
+
+func TestOTLPOptions(t *testing.T) {
+	for _, tc := range []struct {
+		endpoint string
+		want     int
+	}{
+		{endpoint: "", want: 0},
+		{endpoint: "collector:4318", want: 1},
+		{endpoint: "https://collector:4318", want: 1},
+		{endpoint: "http://collector:4318", want: 2},
+		{endpoint: "http://collector:4318/custom/v1/traces", want: 3},
+	} {
+		if got := otlpOptions(tc.endpoint); len(got) != tc.want {
+			t.Errorf("otlpOptions(%q) returned %d options, want %d", tc.endpoint, len(got), tc.want)
+		}
+	}
+}
+
+func TestRecordSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := tp.Tracer("test")
+
+	// The span context of the parent is only known from its annotation, as
+	// when the parent was started by another reconcile.
+	parentCtx, parent := tracer.Start(context.Background(), "parent")
+	annotations := AnnotateSpanContext(parentCtx, nil, "parent")
+	parent.End()
+
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+	RecordSpan(ContextFromAnnotation(context.Background(), annotations, "parent"), tracer, "child", start, end)
+
+	ended := recorder.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected 2 spans to be recorded, got %d", len(ended))
+	}
+	span := ended[1]
+	if span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("expected span %s in trace %v, got %v", span.Name(), parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+	if span.SpanContext().SpanID() == parent.SpanContext().SpanID() {
+		t.Errorf("expected span %s to have its own span ID", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected span %s to have parent %v, got %v", span.Name(), parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+	if !span.StartTime().Equal(start) || !span.EndTime().Equal(end) {
+		t.Errorf("expected span %s to run from %v to %v, got %v to %v", span.Name(), start, end, span.StartTime(), span.EndTime())
+	}
+}
@@ -27,4 +27,7 @@ const (
 
 	// AnnotationAffinityAssistantName is used to pass the instance name of an Affinity Assistant to TaskRun pods
 	AnnotationAffinityAssistantName = "pipeline.tekton.dev/affinity-assistant"
+	// AnnotationAffinityAssistantMode is used to persist the Affinity Assistant mode of a PipelineRun
+	// when it starts, and to pass it to its TaskRuns when it is not the default per workspace mode
+	AnnotationAffinityAssistantMode = "pipeline.tekton.dev/affinity-assistant-mode"
 )
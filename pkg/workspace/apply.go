@@ -70,6 +70,15 @@ func CreateVolumes(wb []v1beta1.WorkspaceBinding) map[string]corev1.Volume {
 		case w.Secret != nil:
 			s := *w.Secret
 			v.setVolumeSource(w.Name, name, corev1.VolumeSource{Secret: &s})
+		case w.Projected != nil:
+			p := *w.Projected
+			v.setVolumeSource(w.Name, name, corev1.VolumeSource{Projected: &p})
+		case w.CSI != nil:
+			csi := *w.CSI
+			v.setVolumeSource(w.Name, name, corev1.VolumeSource{CSI: &csi})
+		case w.Ephemeral != nil:
+			e := *w.Ephemeral
+			v.setVolumeSource(w.Name, name, corev1.VolumeSource{Ephemeral: &e})
 		}
 	}
 	return v
@@ -130,7 +139,9 @@ func Apply(ctx context.Context, ts v1beta1.TaskSpec, wb []v1beta1.WorkspaceBindi
 			Name:      vv.Name,
 			MountPath: w.GetMountPath(),
 			SubPath:   wb[i].SubPath,
-			ReadOnly:  w.ReadOnly,
+			// A workspace bound read-only, or populated by a read-only volume
+			// source, is mounted read-only even if it is not declared as such.
+			ReadOnly: w.ReadOnly || wb[i].ReadOnly || IsReadOnlySource(wb[i]),
 		}
 
 		if alphaAPIEnabled {
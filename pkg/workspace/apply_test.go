@@ -185,6 +185,82 @@ func TestCreateVolumes(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "binding a single workspace with projected",
+		workspaces: []v1beta1.WorkspaceBinding{{
+			Name: "custom",
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					Secret: &corev1.SecretProjection{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "foobarsecret",
+						},
+					},
+				}},
+			},
+		}},
+		expectedVolumes: map[string]corev1.Volume{
+			"custom": {
+				Name: "ws-twkr2",
+				VolumeSource: corev1.VolumeSource{
+					Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{{
+							Secret: &corev1.SecretProjection{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "foobarsecret",
+								},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}, {
+		name: "binding a single workspace with csi",
+		workspaces: []v1beta1.WorkspaceBinding{{
+			Name: "custom",
+			CSI: &corev1.CSIVolumeSource{
+				Driver:           "secrets-store.csi.k8s.io",
+				VolumeAttributes: map[string]string{"secretProviderClass": "vault-database"},
+			},
+		}},
+		expectedVolumes: map[string]corev1.Volume{
+			"custom": {
+				Name: "ws-mnq6l",
+				VolumeSource: corev1.VolumeSource{
+					CSI: &corev1.CSIVolumeSource{
+						Driver:           "secrets-store.csi.k8s.io",
+						VolumeAttributes: map[string]string{"secretProviderClass": "vault-database"},
+					},
+				},
+			},
+		},
+	}, {
+		name: "binding a single workspace with ephemeral",
+		workspaces: []v1beta1.WorkspaceBinding{{
+			Name: "custom",
+			Ephemeral: &corev1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					},
+				},
+			},
+		}},
+		expectedVolumes: map[string]corev1.Volume{
+			"custom": {
+				Name: "ws-hvpvf",
+				VolumeSource: corev1.VolumeSource{
+					Ephemeral: &corev1.EphemeralVolumeSource{
+						VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+							Spec: corev1.PersistentVolumeClaimSpec{
+								AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+							},
+						},
+					},
+				},
+			},
+		},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
 			v := workspace.CreateVolumes(tc.workspaces)
@@ -509,6 +585,77 @@ func TestApply(t *testing.T) {
 				ReadOnly:  true,
 			}},
 		},
+	}, {
+		name: "read-only volume source marks volume mount readOnly",
+		ts: v1beta1.TaskSpec{
+			Workspaces: []v1beta1.WorkspaceDeclaration{{
+				Name: "custom",
+			}},
+		},
+		workspaces: []v1beta1.WorkspaceBinding{{
+			Name: "custom",
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Path: "token",
+					},
+				}},
+			},
+		}},
+		expectedTaskSpec: v1beta1.TaskSpec{
+			StepTemplate: &v1beta1.StepTemplate{
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      "ws-mnq6l",
+					MountPath: "/workspace/custom",
+					ReadOnly:  true,
+				}},
+			},
+			Volumes: []corev1.Volume{{
+				Name: "ws-mnq6l",
+				VolumeSource: corev1.VolumeSource{
+					Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Path: "token",
+							},
+						}},
+					},
+				},
+			}},
+			Workspaces: []v1beta1.WorkspaceDeclaration{{
+				Name: "custom",
+			}},
+		},
+	}, {
+		name: "read-only binding marks volume mount readOnly",
+		ts: v1beta1.TaskSpec{
+			Workspaces: []v1beta1.WorkspaceDeclaration{{
+				Name: "custom",
+			}},
+		},
+		workspaces: []v1beta1.WorkspaceBinding{{
+			Name:     "custom",
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+			ReadOnly: true,
+		}},
+		expectedTaskSpec: v1beta1.TaskSpec{
+			StepTemplate: &v1beta1.StepTemplate{
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      "ws-hvpvf",
+					MountPath: "/workspace/custom",
+					ReadOnly:  true,
+				}},
+			},
+			Volumes: []corev1.Volume{{
+				Name: "ws-hvpvf",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			}},
+			Workspaces: []v1beta1.WorkspaceDeclaration{{
+				Name: "custom",
+			}},
+		},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
 			vols := workspace.CreateVolumes(tc.workspaces)
@@ -26,11 +26,11 @@ import (
 
 // ValidateBindings will return an error if the bound workspaces in binds don't satisfy the declared
 // workspaces in decls.
-func ValidateBindings(decls []v1beta1.WorkspaceDeclaration, binds []v1beta1.WorkspaceBinding) error {
+func ValidateBindings(ctx context.Context, decls []v1beta1.WorkspaceDeclaration, binds []v1beta1.WorkspaceBinding) error {
 	// This will also be validated at webhook time but in case the webhook isn't invoked for some
 	// reason we'll invoke the same validation here.
 	for _, b := range binds {
-		if err := b.Validate(context.Background()); err != nil {
+		if err := b.Validate(ctx); err != nil {
 			return fmt.Errorf("binding %q is invalid: %v", b.Name, err)
 		}
 	}
@@ -65,7 +65,9 @@ func ValidateBindings(decls []v1beta1.WorkspaceDeclaration, binds []v1beta1.Work
 // persistent volume claim.
 //
 // This is only useful to validate that WorkspaceBindings in TaskRuns are compatible
-// with affinity rules enforced by the AffinityAssistant.
+// with affinity rules enforced by the AffinityAssistant. The claims of Ephemeral
+// volumes are created for, and only used by, the pod of the TaskRun, so they are
+// not counted.
 func ValidateOnlyOnePVCIsUsed(wb []v1beta1.WorkspaceBinding) error {
 	workspaceVolumes := make(map[string]bool)
 	for _, w := range wb {
@@ -82,3 +84,17 @@ func ValidateOnlyOnePVCIsUsed(wb []v1beta1.WorkspaceBinding) error {
 	}
 	return nil
 }
+
+// IsReadOnlySource returns true if the volume mount of a WorkspaceBinding must
+// be marked read-only, i.e. if it is a Projected volume or a CSI volume marked
+// as read-only. The kubelet already mounts ConfigMap and Secret volumes
+// read-only, so their volume mounts are left as they are.
+func IsReadOnlySource(wb v1beta1.WorkspaceBinding) bool {
+	switch {
+	case wb.Projected != nil:
+		return true
+	case wb.CSI != nil:
+		return wb.CSI.ReadOnly != nil && *wb.CSI.ReadOnly
+	}
+	return false
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package workspace
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -77,7 +78,7 @@ func TestValidateBindingsValid(t *testing.T) {
 		bindings: []v1alpha1.WorkspaceBinding{},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
-			if err := ValidateBindings(tc.declarations, tc.bindings); err != nil {
+			if err := ValidateBindings(context.Background(), tc.declarations, tc.bindings); err != nil {
 				t.Errorf("didnt expect error for valid bindings but got: %v", err)
 			}
 		})
@@ -144,7 +145,7 @@ func TestValidateBindingsInvalid(t *testing.T) {
 		}},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
-			if err := ValidateBindings(tc.declarations, tc.bindings); err == nil {
+			if err := ValidateBindings(context.Background(), tc.declarations, tc.bindings); err == nil {
 				t.Errorf("expected error for invalid bindings but didn't get any!")
 			}
 		})
@@ -189,6 +190,18 @@ func TestValidateOnlyOnePVCIsUsed_Valid(t *testing.T) {
 				ClaimName: "foo",
 			},
 		}},
+	}, {
+		name: "an error is not returned when a PVC and an ephemeral volume are used",
+		bindings: []v1beta1.WorkspaceBinding{{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: "foo",
+			},
+		}, {
+			Name: "bar",
+			Ephemeral: &corev1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{},
+			},
+		}},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
 			if err := ValidateOnlyOnePVCIsUsed(tc.bindings); err != nil {
@@ -236,3 +249,68 @@ func TestValidateOnlyOnePVCIsUsed_Invalid(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReadOnlySource(t *testing.T) {
+	readOnly := true
+	for _, tc := range []struct {
+		name    string
+		binding v1beta1.WorkspaceBinding
+		want    bool
+	}{{
+		name: "pvc",
+		binding: v1beta1.WorkspaceBinding{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: "foo",
+			},
+		},
+		want: false,
+	}, {
+		name: "emptyDir",
+		binding: v1beta1.WorkspaceBinding{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+		want: false,
+	}, {
+		name: "ephemeral",
+		binding: v1beta1.WorkspaceBinding{
+			Ephemeral: &corev1.EphemeralVolumeSource{},
+		},
+		want: false,
+	}, {
+		name: "configMap",
+		binding: v1beta1.WorkspaceBinding{
+			ConfigMap: &corev1.ConfigMapVolumeSource{},
+		},
+		want: false,
+	}, {
+		name: "secret",
+		binding: v1beta1.WorkspaceBinding{
+			Secret: &corev1.SecretVolumeSource{},
+		},
+		want: false,
+	}, {
+		name: "projected",
+		binding: v1beta1.WorkspaceBinding{
+			Projected: &corev1.ProjectedVolumeSource{},
+		},
+		want: true,
+	}, {
+		name: "writable csi",
+		binding: v1beta1.WorkspaceBinding{
+			CSI: &corev1.CSIVolumeSource{Driver: "foo"},
+		},
+		want: false,
+	}, {
+		name: "read-only csi",
+		binding: v1beta1.WorkspaceBinding{
+			CSI: &corev1.CSIVolumeSource{Driver: "foo", ReadOnly: &readOnly},
+		},
+		want: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsReadOnlySource(tc.binding); got != tc.want {
+				t.Errorf("expected IsReadOnlySource to be %t but got %t", tc.want, got)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/tektoncd/pipeline/test/parse"
 
 	"github.com/tektoncd/pipeline/pkg/apis/config"
+	"github.com/tektoncd/pipeline/pkg/apis/resource/v1alpha1/storage"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,6 +43,8 @@ const (
 	systemNamespace  = "tekton-pipelines"
 	bucketSecretName = "bucket-secret"
 	bucketSecretKey  = "bucket-secret-key"
+	// s3Image is the image used to create the S3 bucket, as the one of the controller is only set by its flag
+	s3Image = "docker.io/amazon/aws-cli:2.7.35"
 )
 
 // TestStorageBucketPipelineRun is an integration test that will verify a pipeline
@@ -61,12 +64,6 @@ func TestStorageBucketPipelineRun(t *testing.T) {
 	knativetest.CleanupOnInterrupt(func() { tearDown(ctx, t, c, namespace) }, t.Logf)
 	defer tearDown(ctx, t, c, namespace)
 
-	helloworldResourceName := helpers.ObjectNameForTest(t)
-	addFileTaskName := helpers.ObjectNameForTest(t)
-	runFileTaskName := helpers.ObjectNameForTest(t)
-	bucketTestPipelineName := helpers.ObjectNameForTest(t)
-	bucketTestPipelineRunName := helpers.ObjectNameForTest(t)
-
 	bucketName := fmt.Sprintf("build-pipeline-test-%s-%d", namespace, time.Now().Unix())
 
 	t.Logf("Creating Secret %s", bucketSecretName)
@@ -140,6 +137,83 @@ spec:
 	}
 	defer resetConfigMap(ctx, t, c, systemNamespace, config.GetArtifactBucketConfigName(), originalConfigMapData)
 
+	runBucketPipeline(ctx, t, c, namespace)
+}
+
+// TestS3BucketPipelineRun is an integration test that will verify a pipeline
+// can use an S3-compatible bucket, such as a MinIO server, for temporary storage
+// of artifacts shared between tasks
+func TestS3BucketPipelineRun(t *testing.T) {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT variable is not set.")
+	}
+	region := os.Getenv("S3_TEST_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	c, namespace := setup(ctx, t)
+	// Bucket tests can't run in parallel without causing issues with other tests.
+
+	knativetest.CleanupOnInterrupt(func() { tearDown(ctx, t, c, namespace) }, t.Logf)
+	defer tearDown(ctx, t, c, namespace)
+
+	bucketName := fmt.Sprintf("build-pipeline-test-%s-%d", namespace, time.Now().Unix())
+
+	t.Logf("Creating Secret %s", bucketSecretName)
+	if _, err := c.KubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      bucketSecretName,
+		},
+		StringData: map[string]string{
+			storage.S3AccessKeyIDKey:     os.Getenv("S3_TEST_ACCESS_KEY_ID"),
+			storage.S3SecretAccessKeyKey: os.Getenv("S3_TEST_SECRET_ACCESS_KEY"),
+		},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create Secret %q: %v", bucketSecretName, err)
+	}
+	defer deleteBucketSecret(ctx, c, t, namespace)
+
+	t.Logf("Creating S3 bucket %s", bucketName)
+	runS3BucketTask(ctx, c, t, namespace, fmt.Sprintf("s3 mb s3://%s", bucketName), endpoint, region)
+	defer runS3BucketTask(ctx, c, t, namespace, fmt.Sprintf("s3 rb --force s3://%s", bucketName), endpoint, region)
+
+	originalConfigMap, err := c.KubeClient.CoreV1().ConfigMaps(systemNamespace).Get(ctx, config.GetArtifactBucketConfigName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get ConfigMap `%s`: %s", config.GetArtifactBucketConfigName(), err)
+	}
+	originalConfigMapData := originalConfigMap.Data
+
+	t.Logf("Creating ConfigMap %s", config.GetArtifactBucketConfigName())
+	configMapData := map[string]string{
+		config.BucketLocationKey:                fmt.Sprintf("s3://%s", bucketName),
+		config.BucketS3EndpointKey:              endpoint,
+		config.BucketS3RegionKey:                region,
+		config.BucketS3CredentialsSecretNameKey: bucketSecretName,
+	}
+	if err := updateConfigMap(ctx, c.KubeClient, systemNamespace, config.GetArtifactBucketConfigName(), configMapData); err != nil {
+		t.Fatal(err)
+	}
+	defer resetConfigMap(ctx, t, c, systemNamespace, config.GetArtifactBucketConfigName(), originalConfigMapData)
+
+	runBucketPipeline(ctx, t, c, namespace)
+}
+
+// runBucketPipeline runs a Pipeline passing a git output resource from one
+// Task to another through the configured artifact bucket.
+func runBucketPipeline(ctx context.Context, t *testing.T, c *clients, namespace string) {
+	t.Helper()
+	helloworldResourceName := helpers.ObjectNameForTest(t)
+	addFileTaskName := helpers.ObjectNameForTest(t)
+	runFileTaskName := helpers.ObjectNameForTest(t)
+	bucketTestPipelineName := helpers.ObjectNameForTest(t)
+	bucketTestPipelineRunName := helpers.ObjectNameForTest(t)
+
 	t.Logf("Creating Git PipelineResource %s", helloworldResourceName)
 	helloworldResource := parse.MustParsePipelineResource(t, fmt.Sprintf(`
 metadata:
@@ -354,3 +428,53 @@ spec:
 		t.Errorf("Error waiting for TaskRun %s to finish: %s", deletelbuckettaskrun.Name, err)
 	}
 }
+
+func runS3BucketTask(ctx context.Context, c *clients, t *testing.T, namespace, args, endpoint, region string) {
+	s3bucketTask := parse.MustParseTask(t, fmt.Sprintf(`
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  steps:
+  - name: step1
+    image: %s
+    command: ['/bin/bash']
+    args: ['-c', 'aws --endpoint-url %s %s']
+    env:
+    - name: AWS_DEFAULT_REGION
+      value: %s
+    - name: AWS_ACCESS_KEY_ID
+      valueFrom:
+        secretKeyRef:
+          name: %s
+          key: AWS_ACCESS_KEY_ID
+    - name: AWS_SECRET_ACCESS_KEY
+      valueFrom:
+        secretKeyRef:
+          name: %s
+          key: AWS_SECRET_ACCESS_KEY
+`, helpers.ObjectNameForTest(t), namespace, s3Image, endpoint, args, region, bucketSecretName, bucketSecretName))
+
+	t.Logf("Creating Task %s", s3bucketTask.Name)
+	if _, err := c.TaskClient.Create(ctx, s3bucketTask, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create Task `%s`: %s", s3bucketTask.Name, err)
+	}
+
+	s3bucketTaskRun := parse.MustParseTaskRun(t, fmt.Sprintf(`
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  taskRef:
+    name: %s
+`, helpers.ObjectNameForTest(t), namespace, s3bucketTask.Name))
+
+	t.Logf("Creating TaskRun %s", s3bucketTaskRun.Name)
+	if _, err := c.TaskRunClient.Create(ctx, s3bucketTaskRun, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create TaskRun `%s`: %s", s3bucketTaskRun.Name, err)
+	}
+
+	if err := WaitForTaskRunState(ctx, c, s3bucketTaskRun.Name, TaskRunSucceed(s3bucketTaskRun.Name), "TaskRunSuccess"); err != nil {
+		t.Errorf("Error waiting for TaskRun %s to finish: %s", s3bucketTaskRun.Name, err)
+	}
+}